@@ -4,13 +4,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"mime/multipart"
 	"net/http"
 	"net/url"
 	"reflect"
+	"slices"
 	"strconv"
 
 	"github.com/alexferl/zerohttp/internal/bind"
+	"github.com/alexferl/zerohttp/validator"
 )
 
 // FileHeader represents an uploaded file in a multipart form.
@@ -42,6 +45,8 @@ type Binder interface {
 	// JSON decodes JSON request body into the destination struct.
 	// It uses json.NewDecoder with DisallowUnknownFields enabled
 	// for safer JSON parsing that rejects unknown fields.
+	// Decoding errors are wrapped in a binding error (see [IsBindError]),
+	// so returning them from a handler responds with 400 Bad Request.
 	JSON(r io.Reader, dst any) error
 
 	// Form parses form data from the request body (application/x-www-form-urlencoded)
@@ -56,6 +61,13 @@ type Binder interface {
 	// File uploads are bound to fields of type FileHeader or []FileHeader.
 	MultipartForm(r *http.Request, dst any, maxMemory int64) error
 
+	// MultipartFiles parses multipart/form-data from the request and returns
+	// its uploaded files, ordered by field name then by upload order, and its
+	// form values, for handlers that process uploads without a struct.
+	// The maxMemory parameter works as in MultipartForm. Save files with
+	// SaveUploadedFile.
+	MultipartFiles(r *http.Request, maxMemory int64) ([]*multipart.FileHeader, map[string][]string, error)
+
 	// Query binds query parameters from the request URL to a destination struct.
 	// Uses `query` struct tags for field mapping. Fields without tags are mapped
 	// using snake_case conversion of the field name.
@@ -71,11 +83,15 @@ type defaultBinder struct{}
 
 // JSON decodes JSON request body into the destination struct.
 // It configures the decoder to disallow unknown fields for stricter validation.
-// Returns an error if the JSON is malformed or contains unknown fields.
+// Returns a binding error if the JSON is empty, malformed or contains
+// unknown fields.
 func (b *defaultBinder) JSON(r io.Reader, dst any) error {
 	decoder := json.NewDecoder(r)
 	decoder.DisallowUnknownFields()
-	return decoder.Decode(dst)
+	if err := decoder.Decode(dst); err != nil {
+		return &validator.BindError{Err: err}
+	}
+	return nil
 }
 
 // Form binds form data from a url.Values to a destination struct.
@@ -105,6 +121,23 @@ func (b *defaultBinder) MultipartForm(r *http.Request, dst any, maxMemory int64)
 	return BindMultipartFormFiles(r, dst)
 }
 
+// MultipartFiles parses multipart form data and returns its files and values.
+func (b *defaultBinder) MultipartFiles(r *http.Request, maxMemory int64) ([]*multipart.FileHeader, map[string][]string, error) {
+	if err := r.ParseMultipartForm(maxMemory); err != nil {
+		return nil, nil, fmt.Errorf("parse multipart form: %w", err)
+	}
+
+	if r.MultipartForm == nil {
+		return nil, nil, fmt.Errorf("no multipart form data")
+	}
+
+	var files []*multipart.FileHeader
+	for _, field := range slices.Sorted(maps.Keys(r.MultipartForm.File)) {
+		files = append(files, r.MultipartForm.File[field]...)
+	}
+	return files, r.MultipartForm.Value, nil
+}
+
 // Query binds query parameters from the request URL to a destination struct.
 // Uses `query` struct tags for field mapping. Fields without tags are mapped
 // using snake_case conversion of the field name.
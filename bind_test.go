@@ -1015,6 +1015,54 @@ func (e *errReader) Close() error {
 	return nil
 }
 
+func TestBinder_MultipartFiles(t *testing.T) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	_ = writer.WriteField("description", "Quarterly reports")
+	for _, f := range []struct{ field, name string }{
+		{"reports", "q1.csv"},
+		{"cover", "cover.png"},
+		{"reports", "q2.csv"},
+	} {
+		fileWriter, _ := writer.CreateFormFile(f.field, f.name)
+		_, _ = fileWriter.Write([]byte("content of " + f.name))
+	}
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set(httpx.HeaderContentType, writer.FormDataContentType())
+
+	files, values, err := B.MultipartFiles(req, 32<<20)
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertEqual(t, []string{"Quarterly reports"}, values["description"])
+	zhtest.AssertLen(t, files, 3)
+
+	// Ordered by field name, then by upload order
+	expectedNames := []string{"cover.png", "q1.csv", "q2.csv"}
+	for i, fh := range files {
+		zhtest.AssertEqual(t, expectedNames[i], fh.Filename)
+		zhtest.AssertEqual(t, int64(len("content of "+fh.Filename)), fh.Size)
+	}
+
+	t.Run("parse error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("not valid multipart"))
+		req.Header.Set(httpx.HeaderContentType, "multipart/form-data; boundary=xyz")
+
+		files, values, err := B.MultipartFiles(req, 32<<20)
+		zhtest.AssertError(t, err)
+		zhtest.AssertNil(t, files)
+		zhtest.AssertNil(t, values)
+	})
+
+	t.Run("not multipart", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("a=b"))
+		req.Header.Set(httpx.HeaderContentType, httpx.MIMEApplicationFormURLEncoded)
+
+		_, _, err := B.MultipartFiles(req, 32<<20)
+		zhtest.AssertError(t, err)
+	})
+}
+
 func TestBinder_MultipartForm_ParseError(t *testing.T) {
 	// Request with malformed multipart data
 	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("not valid multipart"))
@@ -0,0 +1,53 @@
+package zerohttp
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/alexferl/zerohttp/httpx"
+)
+
+// CheckIfMatch evaluates the If-Match precondition of r against the current
+// ETag of the resource, for optimistic concurrency control on updates.
+//
+// It reports ok when the request has an If-Match header that matches
+// currentETag, and precondFailed when it has one that doesn't, in which case
+// the handler should respond with 412 Precondition Failed without applying
+// the change. Both are false when the request has no If-Match header, so
+// handlers that require one can respond with 428 Precondition Required.
+//
+// As required for If-Match, ETags are compared with the strong comparison:
+// weak ETags never match. "*" matches any current ETag, strong or weak, and
+// pass an empty currentETag for a resource that doesn't exist.
+//
+// Example:
+//
+//	func UpdateUser(w http.ResponseWriter, r *http.Request) error {
+//	    user := loadUser(r.PathValue("id"))
+//	    if _, failed := zh.CheckIfMatch(r, user.ETag()); failed {
+//	        return zh.R.PreconditionFailed(w)
+//	    }
+//	    // apply the update
+//	}
+func CheckIfMatch(r *http.Request, currentETag string) (ok bool, precondFailed bool) {
+	ifMatch := r.Header.Values(httpx.HeaderIfMatch)
+	if len(ifMatch) == 0 {
+		return false, false
+	}
+
+	if currentETag == "" {
+		return false, true
+	}
+
+	// "*" matches any current representation, even with a weak ETag
+	strong := !strings.HasPrefix(currentETag, "W/")
+	for _, value := range ifMatch {
+		for candidate := range strings.SplitSeq(value, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || (strong && candidate == currentETag) {
+				return true, false
+			}
+		}
+	}
+	return false, true
+}
@@ -0,0 +1,44 @@
+package zerohttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestCheckIfMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		ifMatch     []string
+		currentETag string
+		wantOK      bool
+		wantFailed  bool
+	}{
+		{name: "no header", currentETag: `"v1"`},
+		{name: "matching", ifMatch: []string{`"v1"`}, currentETag: `"v1"`, wantOK: true},
+		{name: "stale", ifMatch: []string{`"v0"`}, currentETag: `"v1"`, wantFailed: true},
+		{name: "list", ifMatch: []string{`"v0", "v1"`}, currentETag: `"v1"`, wantOK: true},
+		{name: "multiple headers", ifMatch: []string{`"v0"`, `"v1"`}, currentETag: `"v1"`, wantOK: true},
+		{name: "wildcard", ifMatch: []string{"*"}, currentETag: `"v1"`, wantOK: true},
+		{name: "wildcard without resource", ifMatch: []string{"*"}, wantFailed: true},
+		{name: "wildcard with weak current ETag", ifMatch: []string{"*"}, currentETag: `W/"v1"`, wantOK: true},
+		{name: "weak request ETag", ifMatch: []string{`W/"v1"`}, currentETag: `"v1"`, wantFailed: true},
+		{name: "weak current ETag", ifMatch: []string{`W/"v1"`}, currentETag: `W/"v1"`, wantFailed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/users/1", nil)
+			for _, v := range tt.ifMatch {
+				req.Header.Add(httpx.HeaderIfMatch, v)
+			}
+
+			ok, precondFailed := CheckIfMatch(req, tt.currentETag)
+			zhtest.AssertEqual(t, tt.wantOK, ok)
+			zhtest.AssertEqual(t, tt.wantFailed, precondFailed)
+		})
+	}
+}
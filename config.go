@@ -18,13 +18,27 @@
 //	        KeyFile:  "server.key",
 //	    },
 //	})
+//
+// To serve several domains with their own certificates, selected by SNI:
+//
+//	app := zerohttp.New(zerohttp.Config{
+//	    TLS: zerohttp.TLSConfig{
+//	        Addr:         ":8443",
+//	        Certificates: []tls.Certificate{exampleCom, exampleOrg},
+//	    },
+//	})
 package zerohttp
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"os"
 	"reflect"
+	"time"
 
 	"github.com/alexferl/zerohttp/extensions/autocert"
 	"github.com/alexferl/zerohttp/extensions/http3"
@@ -51,16 +65,90 @@ type Config struct {
 	// Default: preconfigured server listening on "localhost:8080"
 	Server *http.Server
 
+	// ReadTimeout is the maximum duration for reading an entire request,
+	// including the body, on the default HTTP and HTTPS servers. It is ignored
+	// when Server or TLS.Server is set. A negative value disables the timeout.
+	// Default: 10s
+	ReadTimeout time.Duration
+
+	// ReadHeaderTimeout is the maximum duration for reading request headers
+	// on the default HTTP and HTTPS servers. It is ignored when Server or
+	// TLS.Server is set. A negative value disables the timeout.
+	// Default: 5s
+	ReadHeaderTimeout time.Duration
+
+	// WriteTimeout is the maximum duration before timing out writes of the
+	// response on the default HTTP and HTTPS servers. It is ignored when
+	// Server or TLS.Server is set. A negative value disables the timeout.
+	// Default: 15s
+	WriteTimeout time.Duration
+
+	// IdleTimeout is the maximum duration to wait for the next request when
+	// keep-alives are enabled on the default HTTP and HTTPS servers. It is
+	// ignored when Server or TLS.Server is set. A negative value disables the
+	// timeout.
+	// Default: 60s
+	IdleTimeout time.Duration
+
+	// MaxHeaderBytes is the maximum size of request headers on the default
+	// HTTP and HTTPS servers. It is ignored when Server or TLS.Server is set.
+	// Default: 16KB
+	MaxHeaderBytes int
+
 	// Listener allows specifying a custom net.Listener for HTTP traffic (optional).
 	// Default: nil (system default listener will be created)
 	Listener net.Listener
 
+	// UnixSocket is the path of a Unix domain socket for the HTTP server to
+	// listen on instead of Addr, e.g. for sidecar deployments. A stale socket
+	// left by a previous run is removed first, and the socket file is removed
+	// when the server shuts down. Ignored if Listener is set.
+	// Default: "" (listen on Addr over TCP)
+	UnixSocket string
+
+	// UnixSocketMode is the file mode of the Unix domain socket.
+	// Default: 0o660 (read and write for the owner and group)
+	UnixSocketMode os.FileMode
+
+	// H2C enables HTTP/2 over cleartext (h2c) on the HTTP server, for service
+	// meshes and gRPC-style clients that speak HTTP/2 without TLS. Clients
+	// must use prior knowledge, i.e. start with the HTTP/2 connection preface;
+	// the deprecated "Upgrade: h2c" mechanism isn't supported. HTTP/1.1
+	// requests are still served on the same listener.
+	// Default: false
+	H2C bool
+
+	// ProxyProtocol requires a PROXY protocol (v1 or v2) header at the start
+	// of every connection to the HTTP and HTTPS servers, as sent by load
+	// balancers such as HAProxy or AWS NLB, and uses the client address from
+	// the header as r.RemoteAddr. Connections without a valid header are
+	// rejected, so only enable it when all traffic comes through the proxy.
+	// It doesn't apply to a TLS.Listener, which is already wrapped in TLS.
+	// Default: false
+	ProxyProtocol bool
+
+	// ReusePort sets SO_REUSEPORT on the TCP listeners the server creates for
+	// HTTP and HTTPS, so a new process can bind the same address while the
+	// old one drains, for restarts without refused connections. See
+	// [Server.Drain]. Only supported on Linux and BSD-based systems,
+	// including macOS; elsewhere listening fails with an error.
+	// Default: false
+	ReusePort bool
+
 	// TLS holds the configuration for the HTTPS server.
 	TLS TLSConfig
 
 	// Lifecycle holds the server startup and shutdown hook configuration.
 	Lifecycle LifecycleConfig
 
+	// Startup holds the configuration for the page served until the
+	// application is marked as started.
+	Startup StartupConfig
+
+	// Static holds the configuration for static files served by
+	// Files, FilesDir, Static, StaticDir and StaticFS.
+	Static StaticConfig
+
 	// Logger is the logger instance used by the server and middlewares.
 	// Default: nil (a default logger will be created if nil)
 	Logger log.Logger
@@ -73,6 +161,51 @@ type Config struct {
 	// Default: nil (means use built-in defaults)
 	DefaultMiddlewares []MiddlewareFunc
 
+	// PrependMiddlewares are middlewares that run before the built-in
+	// defaults, in order, e.g. tracing that should wrap the request logger.
+	// They are applied even when DisableDefaultMiddlewares is set.
+	// Default: nil
+	PrependMiddlewares []MiddlewareFunc
+
+	// EmptyResponseStatus is the status written when a [HandlerFunc] returns
+	// nil without writing a response, which usually indicates a bug such as
+	// a missing render call. A warning is logged for each such response.
+	// For example, set it to http.StatusNoContent. It must be a final status,
+	// from 200 to 599.
+	// Default: 0 (disabled, the client receives an empty 200)
+	EmptyResponseStatus int
+
+	// DisablePanicGuard disables the last-resort panic recovery of the
+	// router. The guard catches panics that escape the middleware chain, e.g.
+	// with DisableDefaultMiddlewares or from middleware that runs before the
+	// recover middleware, logs them with their stack trace and responds with
+	// a 500 problem detail. The recover middleware still handles panics in
+	// handlers first when it's enabled.
+	// Default: false (the panic guard is enabled)
+	DisablePanicGuard bool
+
+	// DebugErrors includes the error message in 500-level problem details
+	// rendered for handler errors, with the stack trace captured by
+	// WithStack, and the panic and its stack trace in those of the recover
+	// middleware, to help debugging locally. Never enable it in production,
+	// since it exposes internal details to clients.
+	// Default: false
+	DebugErrors bool
+
+	// ErrorMappers convert errors returned by handlers into problem details.
+	// They are tried in order after errors that are already a ProblemDetail,
+	// and before the built-in handling of validation, binding and other
+	// errors. Each server has its own mappers.
+	// Default: nil
+	ErrorMappers []ErrorMapper
+
+	// ErrorMappings map errors matching their target, as reported by
+	// errors.Is, to a problem detail with their status. They are checked in
+	// order and take precedence over the default mappings of
+	// ProblemFromError. Each server has its own mappings.
+	// Default: nil
+	ErrorMappings []ErrorMapping
+
 	// Recover holds the configuration for the panic recovery middleware.
 	Recover recover.Config
 
@@ -126,6 +259,20 @@ type TLSConfig struct {
 	// Default: "" (no key loaded unless specified)
 	KeyFile string
 
+	// Certificates are served by the HTTPS server, which picks the one
+	// matching the server name (SNI) requested by each client, for hosting
+	// several domains with separate certificates. The first certificate is
+	// used for clients that don't send a matching server name. Takes
+	// precedence over CertFile and KeyFile.
+	// Default: nil
+	Certificates []tls.Certificate
+
+	// GetCertificate returns the certificate for a TLS handshake, for example
+	// to look up certificates of tenants by server name at runtime. Takes
+	// precedence over Certificates, CertFile and KeyFile.
+	// Default: nil
+	GetCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)
+
 	// RedirectHTTP enables automatic HTTP to HTTPS redirects when both HTTP and
 	// HTTPS servers are running. When enabled, all HTTP traffic is redirected
 	// to HTTPS with a 301 Moved Permanently status.
@@ -134,6 +281,11 @@ type TLSConfig struct {
 }
 
 type LifecycleConfig struct {
+	// ShutdownTimeout is how long [Server.Run] waits for in-flight requests
+	// to complete when shutting down on a signal or context cancellation.
+	// Default: 30s
+	ShutdownTimeout time.Duration
+
 	// PreStartupHooks are hooks that execute sequentially before any startup hooks.
 	// These run before the server begins initialization.
 	// Default: nil
@@ -155,6 +307,15 @@ type LifecycleConfig struct {
 	// Default: nil
 	PreShutdownHooks []ShutdownHookConfig
 
+	// DrainDelay is how long [Server.Shutdown] keeps serving requests after
+	// the pre-shutdown hooks, before servers stop accepting connections. Use
+	// it with a pre-shutdown hook that deregisters the server from service
+	// discovery or fails its readiness probe, so load balancers stop routing
+	// traffic to it before it goes away. The delay ends early if the shutdown
+	// context is done.
+	// Default: 0
+	DrainDelay time.Duration
+
 	// ShutdownHooks are hooks that execute concurrently with server shutdown.
 	// These run alongside the HTTP/HTTPS/HTTP3 server shutdown.
 	// Default: nil
@@ -166,6 +327,58 @@ type LifecycleConfig struct {
 	PostShutdownHooks []ShutdownHookConfig
 }
 
+// StartupConfig configures the page served while the application starts up.
+// Until [Server.MarkStarted] is called, requests for user-facing routes
+// receive Page and API requests receive a 503 problem detail, instead of
+// errors from dependencies that aren't ready yet.
+type StartupConfig struct {
+	// Page is the HTML served with a 503 status to user-facing routes until
+	// the server is marked as started. Setting it enables the startup page.
+	// Default: nil (disabled)
+	Page []byte
+
+	// APIPaths contains paths that receive a 503 problem detail instead of Page.
+	// Supports exact matches, prefixes (ending with /), and wildcards (ending with *).
+	// Default: ["/api/"]
+	APIPaths []string
+
+	// ExcludedPaths contains paths served normally during startup, such as
+	// health checks.
+	// Supports exact matches, prefixes (ending with /), and wildcards (ending with *).
+	// Default: ["/livez", "/readyz", "/startupz"]
+	ExcludedPaths []string
+
+	// RetryAfter is the value of the Retry-After header sent during startup,
+	// rounded up to whole seconds. Set to a negative value to omit the header.
+	// Default: 5s
+	RetryAfter time.Duration
+}
+
+type StaticConfig struct {
+	// MaxAge sets "Cache-Control: public, max-age=N" on static files.
+	// HTML files, such as index.html and the SPA fallback, get
+	// "Cache-Control: no-cache" instead, so that clients revalidate them
+	// and pick up new deployments. The header is only set on successful
+	// and 304 Not Modified responses.
+	// Default: 0 (no Cache-Control header)
+	MaxAge time.Duration
+
+	// Immutable sets "Cache-Control: public, max-age=31536000, immutable"
+	// on fingerprinted files, whose name contains a content hash, such as
+	// "app.3f2a9c1b.js" or "index-BkL9x2aF.css". Build tools change the
+	// name when the content changes, so these can be cached for good.
+	// Default: false
+	Immutable bool
+
+	// FallbackStatus is the status of the index.html served by Static,
+	// StaticDir and StaticFS with fallback enabled for paths that don't
+	// match a file, such as client-side routes of a SPA. For example, set
+	// it to http.StatusNotFound for a site whose index.html renders a
+	// "not found" page. Requests for "/" are always served with 200.
+	// Default: 0 (200 OK)
+	FallbackStatus int
+}
+
 type ExtensionsConfig struct {
 	// AutocertManager is an optional autocert manager for automatic certificate management (AutoTLS).
 	// Users can inject their own implementation (e.g., golang.org/x/crypto/acme/autocert.Manager)
@@ -203,12 +416,26 @@ type ExtensionsConfig struct {
 // DefaultConfig contains all default values used by Config.
 // Update this file if you want to change system-wide defaults.
 var DefaultConfig = Config{
-	Addr: "localhost:8080",
+	Addr:              "localhost:8080",
+	ReadTimeout:       DefaultReadTimeout,
+	ReadHeaderTimeout: DefaultReadHeaderTimeout,
+	WriteTimeout:      DefaultWriteTimeout,
+	IdleTimeout:       DefaultIdleTimeout,
+	MaxHeaderBytes:    DefaultMaxHeaderBytes,
+	UnixSocketMode:    0o660,
 	TLS: TLSConfig{
 		Addr:         "localhost:8443",
 		Server:       nil,
 		RedirectHTTP: true,
 	},
+	Lifecycle: LifecycleConfig{
+		ShutdownTimeout: 30 * time.Second,
+	},
+	Startup: StartupConfig{
+		APIPaths:      []string{"/api/"},
+		ExcludedPaths: []string{"/livez", "/readyz", "/startupz"},
+		RetryAfter:    5 * time.Second,
+	},
 	DisableDefaultMiddlewares: false,
 	DefaultMiddlewares:        nil, // means use DefaultMiddlewares
 	Recover:                   recover.DefaultConfig,
@@ -221,6 +448,51 @@ var DefaultConfig = Config{
 	Server:                    nil,
 }
 
+// Validate reports contradictory or out-of-range settings, such as a
+// certificate file without its key file, that would otherwise only surface
+// as obscure failures when the server starts. All problems found are joined
+// in the returned error. [New] panics if the merged config is invalid.
+func (c Config) Validate() error {
+	var errs []error
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		errs = append(errs, errors.New("TLS.CertFile and TLS.KeyFile must be set together"))
+	}
+	if c.Extensions.AutocertManager != nil && c.TLS.CertFile != "" {
+		errs = append(errs, errors.New("TLS.CertFile and Extensions.AutocertManager cannot both be set"))
+	}
+	if c.Extensions.AutocertManager != nil && (len(c.TLS.Certificates) > 0 || c.TLS.GetCertificate != nil) {
+		errs = append(errs, errors.New("TLS.Certificates or TLS.GetCertificate and Extensions.AutocertManager cannot both be set"))
+	}
+	if c.MaxHeaderBytes < 0 {
+		errs = append(errs, fmt.Errorf("MaxHeaderBytes must not be negative, got %d", c.MaxHeaderBytes))
+	}
+	if c.Lifecycle.ShutdownTimeout < 0 {
+		errs = append(errs, fmt.Errorf("Lifecycle.ShutdownTimeout must not be negative, got %s", c.Lifecycle.ShutdownTimeout))
+	}
+	if c.Lifecycle.DrainDelay < 0 {
+		errs = append(errs, fmt.Errorf("Lifecycle.DrainDelay must not be negative, got %s", c.Lifecycle.DrainDelay))
+	}
+	if c.Static.MaxAge < 0 {
+		errs = append(errs, fmt.Errorf("Static.MaxAge must not be negative, got %s", c.Static.MaxAge))
+	}
+	if c.EmptyResponseStatus != 0 && !isFinalStatus(c.EmptyResponseStatus) {
+		errs = append(errs, fmt.Errorf("EmptyResponseStatus %d is not a valid final HTTP status (200-599)", c.EmptyResponseStatus))
+	}
+	if c.Static.FallbackStatus != 0 && !isFinalStatus(c.Static.FallbackStatus) {
+		errs = append(errs, fmt.Errorf("Static.FallbackStatus %d is not a valid final HTTP status (200-599)", c.Static.FallbackStatus))
+	}
+
+	return errors.Join(errs...)
+}
+
+// isFinalStatus reports whether code is a valid status for a final
+// response. An informational status isn't a response, the client would
+// wait for one.
+func isFinalStatus(code int) bool {
+	return code >= 200 && code <= 599
+}
+
 // ============================================================================
 // Startup Hook Types
 // ============================================================================
@@ -115,6 +115,65 @@ func TestValidator(t *testing.T) {
 	zhtest.AssertEqual(t, mockVal.lastName, "custom")
 }
 
+func TestConfig_Validate(t *testing.T) {
+	zhtest.AssertNoError(t, DefaultConfig.Validate())
+
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		errMsg string
+	}{
+		{"cert without key", func(c *Config) { c.TLS.CertFile = "cert.pem" }, "TLS.CertFile and TLS.KeyFile must be set together"},
+		{"key without cert", func(c *Config) { c.TLS.KeyFile = "key.pem" }, "TLS.CertFile and TLS.KeyFile must be set together"},
+		{"cert files with autocert", func(c *Config) {
+			c.TLS.CertFile, c.TLS.KeyFile = "cert.pem", "key.pem"
+			c.Extensions.AutocertManager = &mockAutocertManager{}
+		}, "cannot both be set"},
+		{"negative max header bytes", func(c *Config) { c.MaxHeaderBytes = -1 }, "MaxHeaderBytes must not be negative"},
+		{"negative shutdown timeout", func(c *Config) { c.Lifecycle.ShutdownTimeout = -time.Second }, "Lifecycle.ShutdownTimeout"},
+		{"negative drain delay", func(c *Config) { c.Lifecycle.DrainDelay = -time.Second }, "Lifecycle.DrainDelay"},
+		{"negative static max age", func(c *Config) { c.Static.MaxAge = -time.Second }, "Static.MaxAge"},
+		{"invalid empty response status", func(c *Config) { c.EmptyResponseStatus = 1000 }, "EmptyResponseStatus 1000"},
+		{"informational empty response status", func(c *Config) { c.EmptyResponseStatus = http.StatusContinue }, "EmptyResponseStatus 100"},
+		{"negative empty response status", func(c *Config) { c.EmptyResponseStatus = -1 }, "EmptyResponseStatus -1"},
+		{"early hints empty response status", func(c *Config) { c.EmptyResponseStatus = http.StatusEarlyHints }, "EmptyResponseStatus 103"},
+		{"invalid fallback status", func(c *Config) { c.Static.FallbackStatus = 42 }, "Static.FallbackStatus 42"},
+		{"informational fallback status", func(c *Config) { c.Static.FallbackStatus = http.StatusEarlyHints }, "Static.FallbackStatus 103"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			zhtest.AssertError(t, err)
+			zhtest.AssertContains(t, err.Error(), tt.errMsg)
+		})
+	}
+
+	t.Run("joins all errors", func(t *testing.T) {
+		cfg := DefaultConfig
+		cfg.TLS.CertFile = "cert.pem"
+		cfg.MaxHeaderBytes = -1
+		err := cfg.Validate()
+		zhtest.AssertContains(t, err.Error(), "TLS.KeyFile")
+		zhtest.AssertContains(t, err.Error(), "MaxHeaderBytes")
+	})
+
+	t.Run("negative server timeouts disable them", func(t *testing.T) {
+		cfg := DefaultConfig
+		cfg.ReadTimeout = -1
+		cfg.WriteTimeout = -1
+		zhtest.AssertNoError(t, cfg.Validate())
+	})
+
+	t.Run("New panics on invalid config", func(t *testing.T) {
+		zhtest.AssertPanic(t, func() {
+			New(Config{TLS: TLSConfig{CertFile: "cert.pem"}})
+		})
+	})
+}
+
 func TestWebSocketUpgrader(t *testing.T) {
 	cfg := DefaultConfig
 	mockUpgrader := &mockWebSocketUpgrader{}
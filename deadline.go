@@ -0,0 +1,40 @@
+package zerohttp
+
+import (
+	"math"
+	"net/http"
+	"time"
+)
+
+// Deadline returns the deadline of the request context, such as set by the
+// timeout middleware, and whether it has one. It's a shortcut for
+// r.Context().Deadline().
+func Deadline(r *http.Request) (time.Time, bool) {
+	return r.Context().Deadline()
+}
+
+// RemainingTime returns how much time the request has left before its
+// context deadline, or 0 if the deadline has passed, so handlers can size
+// the timeouts of upstream calls to the time budget of the request. Without
+// a deadline it returns the largest time.Duration, so taking the minimum
+// with a default timeout always works.
+//
+// Example:
+//
+//	app.Use(timeout.New(timeout.Config{Duration: 5 * time.Second}))
+//
+//	app.GET("/orders", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+//	    // Leave time to render the response
+//	    budget := min(2*time.Second, zh.RemainingTime(r)-100*time.Millisecond)
+//	    ctx, cancel := context.WithTimeout(r.Context(), budget)
+//	    defer cancel()
+//	    orders, err := ordersClient.List(ctx)
+//	    ...
+//	}))
+func RemainingTime(r *http.Request) time.Duration {
+	deadline, ok := r.Context().Deadline()
+	if !ok {
+		return math.MaxInt64
+	}
+	return max(time.Until(deadline), 0)
+}
@@ -0,0 +1,60 @@
+package zerohttp
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alexferl/zerohttp/middleware/timeout"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestDeadline(t *testing.T) {
+	t.Run("no deadline", func(t *testing.T) {
+		req := zhtest.NewRequest(http.MethodGet, "/").Build()
+
+		_, ok := Deadline(req)
+		zhtest.AssertFalse(t, ok)
+		zhtest.AssertEqual(t, time.Duration(math.MaxInt64), RemainingTime(req))
+	})
+
+	t.Run("with deadline", func(t *testing.T) {
+		deadline := time.Now().Add(time.Minute)
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		defer cancel()
+		req := zhtest.NewRequest(http.MethodGet, "/").Build().WithContext(ctx)
+
+		got, ok := Deadline(req)
+		zhtest.AssertTrue(t, ok)
+		zhtest.AssertTrue(t, got.Equal(deadline))
+
+		remaining := RemainingTime(req)
+		zhtest.AssertTrue(t, remaining > 59*time.Second)
+		zhtest.AssertTrue(t, remaining <= time.Minute)
+	})
+
+	t.Run("deadline passed", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+		req := zhtest.NewRequest(http.MethodGet, "/").Build().WithContext(ctx)
+
+		zhtest.AssertEqual(t, time.Duration(0), RemainingTime(req))
+	})
+
+	t.Run("timeout middleware", func(t *testing.T) {
+		router := NewRouter(timeout.New(timeout.Config{Duration: 5 * time.Second}))
+		var remaining time.Duration
+		router.GET("/", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+			remaining = RemainingTime(r)
+			w.WriteHeader(http.StatusNoContent)
+			return nil
+		}))
+
+		w := zhtest.Serve(router, zhtest.NewRequest(http.MethodGet, "/").Build())
+		zhtest.AssertWith(t, w).Status(http.StatusNoContent)
+		zhtest.AssertTrue(t, remaining > 4*time.Second)
+		zhtest.AssertTrue(t, remaining <= 5*time.Second)
+	})
+}
@@ -50,6 +50,32 @@
 //	    api.GET("/admin/dashboard", dashboardHandler)
 //	})
 //
+// Route creates a group under a path prefix. NotFound and MethodNotAllowed
+// handlers set in it only apply to requests under the prefix, so an API can
+// return problem details while the rest of the app renders HTML pages:
+//
+//	app.NotFound(htmlNotFoundHandler)
+//	app.Route("/api", func(api zh.Router) {
+//	    api.NotFound(jsonNotFoundHandler)
+//	    api.GET("/users", listUsersHandler) // GET /api/users
+//	})
+//
+// Feature modules built as their own router with [NewRouter] are mounted
+// under a prefix with MountRouter, which adds their routes to the app's so
+// 404 and 405 responses work across both. Any other http.Handler is mounted
+// with Mount:
+//
+//	admin := zh.NewRouter()
+//	admin.GET("/users", listUsersHandler)
+//	app.MountRouter("/admin", admin) // GET /admin/users
+//
+//	app.Mount("/debug", debugMux)
+//
+// Routes lists the registered routes, and [ExportOpenAPI] turns them into an
+// OpenAPI skeleton with one operation per method and path:
+//
+//	spec, err := zh.ExportOpenAPI(app, zh.OpenAPIInfo{Title: "Users API", Version: "1.0.0"})
+//
 // # Handlers
 //
 // Handlers return errors for cleaner error handling. Errors are automatically
@@ -69,6 +95,38 @@
 //	    return zh.Render.JSON(w, http.StatusCreated, user)
 //	}))
 //
+// A handler that returns nil without writing anything sends an empty 200.
+// To catch such accidental empty responses, set [Config.EmptyResponseStatus]
+// to write a default status instead and log a warning:
+//
+//	app := zh.New(zh.Config{EmptyResponseStatus: http.StatusNoContent})
+//
+// JSON APIs can skip the response writer with [JSONHandler], which renders
+// the returned payload as JSON with the returned status code:
+//
+//	app.GET("/users/{id}", zh.JSONHandler(func(r *http.Request) (int, any, error) {
+//	    user, err := getUser(zh.Param(r, "id"))
+//	    if err != nil {
+//	        return 0, nil, err
+//	    }
+//	    return http.StatusOK, user, nil
+//	}))
+//
+// [Endpoint] goes further for typed handlers: the request is bound and
+// validated into the input type, and the result is rendered as JSON. Binding
+// and validation failures respond with 400 and 422 without calling the function:
+//
+//	app.POST("/users", zh.Endpoint(func(ctx context.Context, in CreateUserRequest) (*User, error) {
+//	    return createUser(ctx, in)
+//	}))
+//
+// Handlers calling upstream services can size their timeouts to the time
+// the request has left, such as under the timeout middleware, with
+// [RemainingTime], or read the deadline itself with [Deadline]:
+//
+//	ctx, cancel := context.WithTimeout(r.Context(), min(2*time.Second, zh.RemainingTime(r)))
+//	defer cancel()
+//
 // # Request Binding
 //
 // Bind request data to structs using [Bind]:
@@ -125,6 +183,27 @@
 //	    // Process file data...
 //	}
 //
+// Without a struct, MultipartFiles returns every uploaded file and the form
+// values, and SaveUploadedFile copies a file to disk, removing it if the copy
+// fails:
+//
+//	files, _, err := zh.B.MultipartFiles(r, 32<<20)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, fh := range files {
+//	    dst := filepath.Join(uploadDir, filepath.Base(fh.Filename))
+//	    if err := zh.SaveUploadedFile(fh, dst); err != nil {
+//	        return err
+//	    }
+//	}
+//
+// Serve uploaded files back with FilesDirSecure, which answers requests
+// resolving outside the directory, through ".." segments or symlinks, with
+// 403 Forbidden:
+//
+//	app.FilesDirSecure("/files/", uploadDir)
+//
 // # Query Parameter Binding
 //
 // Bind query parameters to structs with query tags:
@@ -168,12 +247,15 @@
 //	// Basic string extraction
 //	id := zh.Param(r, "id")
 //
-//	// Typed extraction (returns error if invalid)
+//	// Typed extraction, the returned error responds with 400 Bad Request
 //	itemID, err := zh.ParamAs[int](r, "itemID")
 //	if err != nil {
-//	    return zh.NewProblemDetail(http.StatusBadRequest, "Invalid itemID").Render(w)
+//	    return err
 //	}
 //
+//	// UUIDs in the canonical format
+//	orderID, err := zh.ParamUUID(r, "orderID")
+//
 //	// With default value
 //	category := zh.ParamOrDefault(r, "category", "all")
 //
@@ -190,6 +272,9 @@
 //	// Simple string
 //	sort := zh.QueryParam(r, "sort")
 //
+//	// All values of a repeated parameter, e.g. ?tag=go&tag=web
+//	tags := zh.QueryParams(r, "tag")
+//
 // # Custom Binders
 //
 // Implement the [Binder] interface for custom binding logic:
@@ -347,6 +432,30 @@
 //	// Redirect
 //	zh.Render.Redirect(w, r, "/new-path", http.StatusFound)
 //
+//	// 103 Early Hints, before a slow final response
+//	zh.Render.EarlyHints(w, r, []string{"</app.css>; rel=preload; as=style"})
+//
+//	// 503 or 429 problem detail with a Retry-After header
+//	zh.Render.Unavailable(w, 30*time.Second, "Database is unavailable")
+//	zh.Render.TooManyRequests(w, time.Minute, "Export quota exceeded")
+//
+// # Conditional Requests
+//
+// Answer conditional GET requests for dynamic resources with 304 Not
+// Modified, using a last-modified time or version the handler already has:
+//
+//	return zh.R.JSONModified(w, r, http.StatusOK, report.UpdatedAt, report)
+//	return zh.R.JSONWithETag(w, r, http.StatusOK, `"`+user.Version+`"`, user)
+//
+// # Conditional Updates
+//
+// Use [CheckIfMatch] for optimistic concurrency: updates sent with an
+// If-Match header holding a stale ETag are rejected with 412:
+//
+//	if _, failed := zh.CheckIfMatch(r, user.ETag); failed {
+//	    return zh.Render.PreconditionFailed(w)
+//	}
+//
 // # Error Handling
 //
 // zerohttp converts errors to RFC 9457 Problem Details responses:
@@ -354,9 +463,82 @@
 //	// Return custom problem detail
 //	return zh.NewProblemDetail(http.StatusNotFound, "User not found").Render(w)
 //
+//	// Or return it as an error, which renders it the same way
+//	return zh.NewProblemDetail(http.StatusNotFound, "User not found")
+//
 //	// Return validation errors (422 Unprocessable Entity)
 //	return zh.Validate.Struct(&req)
 //
+// For local debugging, [Config.DebugErrors] adds the error message to
+// 500-level problem details, including those from [ProblemFromError], and
+// the panic to those rendered by the recover middleware. Wrap an error with
+// [WithStack] where it happens to add its stack trace as well. Keep it off in
+// production:
+//
+//	app := zh.New(zh.Config{DebugErrors: os.Getenv("APP_ENV") == "dev"})
+//
+//	app.GET("/users/{id}", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+//	    user, err := store.Get(r.Context(), zh.Param(r, "id"))
+//	    if err != nil {
+//	        return zh.WithStack(err)
+//	    }
+//	    return zh.R.JSON(w, http.StatusOK, user)
+//	}))
+//
+// Panics are handled by the recover middleware. Those that escape the
+// middleware chain, e.g. with DisableDefaultMiddlewares, are caught by the
+// router, logged with their stack trace and answered with a 500 problem
+// detail, unless [Config.DisablePanicGuard] is set.
+//
+// Problem details are JSON by default. Clients that prefer XML, such as with
+// "Accept: application/problem+xml", get the RFC 7807 XML format from the
+// default 404 and 405 handlers, for problem details and other errors
+// returned by handlers, and from [Renderer.ProblemDetailAuto]:
+//
+//	return zh.R.ProblemDetailAuto(w, r, zh.NewProblemDetail(http.StatusNotFound, "User not found"))
+//
+// Browsers navigating to a missing page, which prefer text/html, get a
+// minimal HTML error page from the default 404 and 405 handlers instead.
+//
+// Problem details written by [Renderer.ProblemDetail], [Renderer.ProblemDetailAuto]
+// and the default 404 and 405 handlers carry the request ID as a "request_id"
+// member, matching the response header and the logs, for clients to quote in
+// support requests:
+//
+//	{"title":"Not Found","status":404,"detail":"User not found","request_id":"4bf92f35..."}
+//
+// Set your own handler for an error status with ErrorHandler, such as a
+// custom 403 or 429 page. It serves the requests handlers and middleware
+// would answer with a problem detail of that status, and for 404 and 405
+// also those matching no route, like NotFound and MethodNotAllowed:
+//
+//	app.ErrorHandler(http.StatusTooManyRequests, slowDownPageHandler)
+//
+// Common sentinel errors are mapped to matching statuses, e.g. sql.ErrNoRows
+// to 404 and context.DeadlineExceeded to 504; see [ProblemFromError]. Map
+// your own sentinel errors with Config.ErrorMappings:
+//
+//	app := zh.New(zh.Config{
+//	    ErrorMappings: []zh.ErrorMapping{
+//	        {Target: store.ErrConflict, Status: http.StatusConflict, Title: "Version Conflict"},
+//	    },
+//	})
+//
+// Set [ErrorMapper] functions in Config.ErrorMappers to convert your own
+// error types, so handlers can return them directly. Unmapped errors return a
+// 500 problem detail:
+//
+//	app := zh.New(zh.Config{
+//	    ErrorMappers: []zh.ErrorMapper{
+//	        func(err error) *zh.ProblemDetail {
+//	            if errors.Is(err, store.ErrNotFound) {
+//	                return zh.NewProblemDetail(http.StatusNotFound, "Resource not found")
+//	            }
+//	            return nil
+//	        },
+//	    },
+//	})
+//
 // # Middleware
 //
 // Apply middleware at application, group, or route level:
@@ -372,6 +554,23 @@
 //	    }),
 //	)
 //
+// Middleware added with Pre runs before routing, on every request, and can
+// rewrite the path that is routed:
+//
+//	app.Pre(cleanpath.New())
+//
+// A route can opt out of inherited middleware tagged with [Named] by passing
+// [Without]. The default middlewares are named after their packages:
+//
+//	app.Use(zh.Named("compress", compress.New()))
+//	app.GET("/events", eventsHandler, zh.Without("compress", "requestlogger"))
+//
+// The default middlewares run before those added with Use. Middleware that
+// must run before them, such as tracing that should wrap the request log,
+// goes in [Config.PrependMiddlewares]:
+//
+//	app := zh.New(zh.Config{PrependMiddlewares: []zh.MiddlewareFunc{tracer.New(t)}})
+//
 // Available middleware: cors, basicauth, jwtauth, ratelimit, compress,
 // requestlogger, circuitbreaker, timeout, and more in subpackages.
 // See package middleware for complete documentation.
@@ -538,6 +737,15 @@
 //	    },
 //	})
 //
+// To change only the timeouts while keeping the other server defaults, set
+// them on [Config] instead. They apply to both the HTTP and HTTPS servers,
+// and a negative duration disables a timeout:
+//
+//	app := zh.New(zh.Config{
+//	    ReadTimeout:  30 * time.Second,
+//	    WriteTimeout: -1, // e.g. for long-lived streaming responses
+//	})
+//
 // # Server Lifecycle
 //
 // Start the server with various methods:
@@ -546,11 +754,31 @@
 //	app.Start()              // Uses config.Addr or :8080
 //	app.ListenAndServe()     // Uses configured address
 //
+//	// Unix domain socket, e.g. for sidecars
+//	app := zh.New(zh.Config{UnixSocket: "/run/app/app.sock"})
+//	app.Start()
+//
+//	// HTTP/2 over cleartext (h2c), e.g. behind a proxy that terminates TLS.
+//	// Clients must use HTTP/2 with prior knowledge; HTTP/1.1 is still served.
+//	app := zh.New(zh.Config{H2C: true})
+//	app.Start()
+//
+//	// Behind a load balancer that sends the PROXY protocol (v1 or v2), so
+//	// r.RemoteAddr is the client's address. Connections without it are rejected.
+//	app := zh.New(zh.Config{ProxyProtocol: true})
+//	app.Start()
+//
 //	// HTTPS
 //	app.StartTLS("cert.pem", "key.pem")
 //	app.StartAutoTLS()       // Let's Encrypt
 //
-//	// With graceful shutdown
+//	// With graceful shutdown on SIGINT/SIGTERM or when ctx is cancelled,
+//	// waiting up to Lifecycle.ShutdownTimeout (30s) for in-flight requests
+//	if err := app.Run(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	// Or manage the signals and shutdown yourself
 //	go app.Start()
 //
 //	quit := make(chan os.Signal, 1)
@@ -563,6 +791,36 @@
 //	    log.Fatal(err)
 //	}
 //
+// To serve a "starting up" page while dependencies warm up, set
+// [StartupConfig.Page] and call [Server.MarkStarted] once ready. Until then,
+// API paths receive a 503 problem detail and health checks are served normally:
+//
+//	app := zh.New(zh.Config{
+//	    Startup: zh.StartupConfig{Page: startingHTML},
+//	})
+//	app.RegisterPostStartupHook("warm-cache", func(ctx context.Context) error {
+//	    defer app.MarkStarted()
+//	    return cache.Warm(ctx)
+//	})
+//
+// Requests still running shortly before the shutdown deadline are logged by
+// route pattern to show which endpoints are holding up shutdown. The current
+// counts are available at any time with [Server.ActiveRequests], and
+// [Server.Stats] returns connection, request and byte counters, e.g. for a
+// /debug/stats endpoint without a metrics stack.
+//
+// For zero-downtime restarts on Linux and BSD, set [Config.ReusePort] so the
+// new process can bind the address while the old one still listens. Once the
+// new process is ready, the old one calls [Server.Drain] to stop accepting
+// connections and finish the active requests, then shuts down:
+//
+//	app := zh.New(zh.Config{Addr: ":8080", ReusePort: true})
+//	go app.Start()
+//
+//	<-newProcessReady // e.g. SIGUSR2 sent by the new process
+//	_ = app.Drain(ctx)
+//	_ = app.Shutdown(ctx)
+//
 // # Testing
 //
 // The zhtest package provides fluent test helpers:
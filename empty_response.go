@@ -0,0 +1,70 @@
+package zerohttp
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+
+	"github.com/alexferl/zerohttp/internal/rwutil"
+	"github.com/alexferl/zerohttp/log"
+)
+
+// emptyResponseHandler wraps h so that a nil return without a response being
+// written is answered with status and logged as a warning, instead of the
+// implicit empty 200 the client would otherwise receive.
+func emptyResponseHandler(h HandlerFunc, status int, logger log.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ew := &emptyResponseWriter{ResponseWriter: w}
+		h.ServeHTTP(ew, r)
+
+		if ew.written {
+			return
+		}
+
+		logger.Warn("Handler returned without writing a response",
+			log.F("method", r.Method),
+			log.F("path", r.URL.Path),
+			log.F("pattern", r.Pattern),
+			log.F("status", status),
+		)
+		w.WriteHeader(status)
+	})
+}
+
+// emptyResponseWriter records whether anything was written to the response.
+type emptyResponseWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (e *emptyResponseWriter) WriteHeader(code int) {
+	if !rwutil.IsInformational(code) {
+		e.written = true
+	}
+	e.ResponseWriter.WriteHeader(code)
+}
+
+func (e *emptyResponseWriter) Write(p []byte) (int, error) {
+	e.written = true
+	return e.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher. Flushing commits the response headers.
+func (e *emptyResponseWriter) Flush() {
+	e.written = true
+	if f, ok := e.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker. A hijacked connection is owned by the
+// handler, so no default response is written.
+func (e *emptyResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	e.written = true
+	return http.NewResponseController(e.ResponseWriter).Hijack()
+}
+
+// Unwrap allows middleware to access the underlying ResponseWriter
+func (e *emptyResponseWriter) Unwrap() http.ResponseWriter {
+	return e.ResponseWriter
+}
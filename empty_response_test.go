@@ -0,0 +1,74 @@
+package zerohttp
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestEmptyResponseStatus(t *testing.T) {
+	mockLogger := &mockServerLogger{}
+	app := New(Config{
+		Logger:              mockLogger,
+		EmptyResponseStatus: http.StatusNoContent,
+	})
+
+	app.GET("/empty/{id}", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	}))
+	app.GET("/ok", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return R.Text(w, http.StatusOK, "ok")
+	}))
+	app.GET("/header-only", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusAccepted)
+		return nil
+	}))
+	app.GET("/error", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("boom")
+	}))
+	app.GET("/plain", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	w := zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/empty/1").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusNoContent).BodyEmpty()
+
+	var warned bool
+	for _, entry := range mockLogger.logs {
+		if entry.message != "Handler returned without writing a response" {
+			continue
+		}
+		warned = true
+		zhtest.AssertEqual(t, "warn", entry.level)
+		for _, f := range entry.fields {
+			if f.Key == "pattern" {
+				zhtest.AssertEqual(t, "GET /empty/{id}", f.Value)
+			}
+		}
+	}
+	zhtest.AssertTrue(t, warned)
+
+	// Responses that were written are left alone
+	w = zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/ok").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusOK).Body("ok")
+
+	w = zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/header-only").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusAccepted)
+
+	w = zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/error").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusInternalServerError)
+
+	// Plain http.Handlers are not checked
+	w = zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/plain").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusOK)
+}
+
+func TestEmptyResponseStatus_Disabled(t *testing.T) {
+	app := New()
+	app.GET("/empty", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	}))
+
+	w := zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/empty").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusOK).BodyEmpty()
+}
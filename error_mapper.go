@@ -0,0 +1,38 @@
+package zerohttp
+
+import "net/http"
+
+// ErrorMapper converts an error returned by a [HandlerFunc] into a problem
+// detail to render as the response. It returns nil for errors it doesn't
+// handle, leaving them to the next mapper or the built-in error handling.
+// Mappers are set with Config.ErrorMappers.
+//
+// Example:
+//
+//	app := zh.New(zh.Config{
+//	    ErrorMappers: []zh.ErrorMapper{
+//	        func(err error) *zh.ProblemDetail {
+//	            var notFound *store.NotFoundError
+//	            if errors.As(err, &notFound) {
+//	                return zh.NewProblemDetail(http.StatusNotFound, notFound.Error())
+//	            }
+//	            return nil
+//	        },
+//	    },
+//	})
+type ErrorMapper func(err error) *ProblemDetail
+
+// mapError returns the problem detail from the first mapper of the router
+// serving r that handles err, or nil if none does.
+func mapError(r *http.Request, err error) *ProblemDetail {
+	c := requestConfig(r)
+	if c == nil {
+		return nil
+	}
+	for _, m := range c.ErrorMappers {
+		if pd := m(err); pd != nil {
+			return pd
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package zerohttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+type notFoundError struct{ id string }
+
+func (e *notFoundError) Error() string { return "item " + e.id + " not found" }
+
+func TestHandlerFunc_ProblemDetailError(t *testing.T) {
+	router := NewRouter()
+	router.GET("/problem", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return NewProblemDetail(http.StatusConflict, "Version mismatch").Set("current", 3)
+	}))
+	router.GET("/wrapped", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return fmt.Errorf("updating: %w", NewProblemDetail(http.StatusForbidden, "Read-only"))
+	}))
+
+	w := zhtest.Serve(router, zhtest.NewRequest(http.MethodGet, "/problem").Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusConflict).
+		Header(httpx.HeaderContentType, httpx.MIMEApplicationProblemJSON).
+		BodyContains(`"detail":"Version mismatch"`).
+		BodyContains(`"current":3`)
+
+	w = zhtest.Serve(router, zhtest.NewRequest(http.MethodGet, "/wrapped").Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusForbidden).
+		BodyContains(`"detail":"Read-only"`)
+
+	t.Run("xml when preferred", func(t *testing.T) {
+		router.GET("/fail", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+			return errors.New("boom")
+		}))
+
+		for path, status := range map[string]int{"/problem": http.StatusConflict, "/fail": http.StatusInternalServerError} {
+			req := zhtest.NewRequest(http.MethodGet, path).
+				WithHeader(httpx.HeaderAccept, httpx.MIMEApplicationProblemXML).
+				Build()
+			w := zhtest.Serve(router, req)
+			zhtest.AssertWith(t, w).
+				Status(status).
+				Header(httpx.HeaderContentType, httpx.MIMEApplicationProblemXML).
+				BodyContains("<status>" + strconv.Itoa(status) + "</status>")
+		}
+	})
+}
+
+func TestErrorMappers(t *testing.T) {
+	app := New(Config{
+		ErrorMappers: []ErrorMapper{
+			func(err error) *ProblemDetail {
+				var nf *notFoundError
+				if errors.As(err, &nf) {
+					return NewProblemDetail(http.StatusNotFound, nf.Error())
+				}
+				return nil
+			},
+			func(err error) *ProblemDetail {
+				return NewProblemDetail(http.StatusTeapot, "second mapper")
+			},
+		},
+	})
+	items := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return fmt.Errorf("loading: %w", &notFoundError{id: r.PathValue("id")})
+	})
+	app.GET("/items/{id}", items)
+	app.GET("/other", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("other")
+	}))
+
+	// The first mapper that handles the error wins
+	w := zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/items/42").Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusNotFound).
+		BodyContains(`"detail":"item 42 not found"`)
+
+	w = zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/other").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusTeapot)
+
+	// Mappers belong to their server
+	other := New()
+	other.GET("/items/{id}", items)
+	w = zhtest.Serve(other, zhtest.NewRequest(http.MethodGet, "/items/42").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusInternalServerError)
+}
+
+func TestErrorMappers_Unhandled(t *testing.T) {
+	app := New(Config{
+		ErrorMappers: []ErrorMapper{func(err error) *ProblemDetail { return nil }},
+	})
+	app.GET("/error", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("boom")
+	}))
+
+	w := zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/error").Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusInternalServerError).
+		BodyContains("An unexpected error occurred")
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"log"
 	"net/http"
-	"strconv"
 	"sync"
 
 	zh "github.com/alexferl/zerohttp"
@@ -32,10 +31,9 @@ func NewUserStore() *UserStore {
 
 // GetUser handles GET /users/{id}
 func (s *UserStore) GetUser(w http.ResponseWriter, r *http.Request) error {
-	id := r.PathValue("id")
-	userID, err := strconv.Atoi(id)
+	userID, err := zh.ParamAs[int](r, "id")
 	if err != nil {
-		return zh.R.ProblemDetail(w, zh.NewProblemDetail(400, "Invalid user ID"))
+		return err
 	}
 
 	s.mu.RLock()
@@ -93,10 +91,9 @@ func (s *UserStore) CreateUser(w http.ResponseWriter, r *http.Request) error {
 
 // UpdateUser handles PUT /users/{id}
 func (s *UserStore) UpdateUser(w http.ResponseWriter, r *http.Request) error {
-	id := r.PathValue("id")
-	userID, err := strconv.Atoi(id)
+	userID, err := zh.ParamAs[int](r, "id")
 	if err != nil {
-		return zh.R.ProblemDetail(w, zh.NewProblemDetail(400, "Invalid user ID"))
+		return err
 	}
 
 	var req struct {
@@ -130,10 +127,9 @@ func (s *UserStore) UpdateUser(w http.ResponseWriter, r *http.Request) error {
 
 // DeleteUser handles DELETE /users/{id}
 func (s *UserStore) DeleteUser(w http.ResponseWriter, r *http.Request) error {
-	id := r.PathValue("id")
-	userID, err := strconv.Atoi(id)
+	userID, err := zh.ParamAs[int](r, "id")
 	if err != nil {
-		return zh.R.ProblemDetail(w, zh.NewProblemDetail(400, "Invalid user ID"))
+		return err
 	}
 
 	s.mu.Lock()
@@ -2,11 +2,11 @@ package main
 
 import (
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	zh "github.com/alexferl/zerohttp"
@@ -20,11 +20,6 @@ const (
 	maxFormSize = 32 << 20 // 32 MB total
 )
 
-type UploadForm struct {
-	Description string           `form:"description"`
-	Files       []*zh.FileHeader `form:"files"`
-}
-
 func main() {
 	app := zh.New(zh.Config{
 		RequestBodySize: requestbodysize.Config{
@@ -38,7 +33,7 @@ func main() {
 
 	app.GET("/", zh.HandlerFunc(uploadFormHandler))
 	app.POST("/upload", zh.HandlerFunc(uploadHandler))
-	app.GET("/files/{filename}", zh.HandlerFunc(downloadHandler))
+	app.FilesDirSecure("/files/", uploadDir)
 
 	log.Fatal(app.Start())
 }
@@ -58,65 +53,42 @@ func uploadFormHandler(w http.ResponseWriter, _ *http.Request) error {
 }
 
 func uploadHandler(w http.ResponseWriter, r *http.Request) error {
-	var form UploadForm
-
-	if err := zh.B.MultipartForm(r, &form, maxFormSize); err != nil {
+	files, values, err := zh.B.MultipartFiles(r, maxFormSize)
+	if err != nil {
 		return zh.R.ProblemDetail(w, zh.NewProblemDetail(400, "Failed to parse form"))
 	}
 
-	if len(form.Files) == 0 {
+	if len(files) == 0 {
 		return zh.R.ProblemDetail(w, zh.NewProblemDetail(400, "No files uploaded"))
 	}
 
 	var uploadedFiles []zh.M
 	var errors []string
 
-	for _, fileHeader := range form.Files {
+	for _, fileHeader := range files {
 		if fileHeader.Size > maxFileSize {
 			errors = append(errors, fmt.Sprintf("%s: file too large", fileHeader.Filename))
 			continue
 		}
 
-		file, err := fileHeader.Open()
-		if err != nil {
-			errors = append(errors, fmt.Sprintf("%s: %v", fileHeader.Filename, err))
+		filename := fmt.Sprintf("%d_%s", time.Now().UnixNano(), filepath.Base(fileHeader.Filename))
+		if err := zh.SaveUploadedFile(fileHeader, filepath.Join(uploadDir, filename)); err != nil {
+			errors = append(errors, fmt.Sprintf("%s: save failed", fileHeader.Filename))
 			continue
 		}
 
-		func() {
-			defer func() { _ = file.Close() }()
-
-			filename := fmt.Sprintf("%d_%s", time.Now().UnixNano(), fileHeader.Filename)
-			destPath := filepath.Join(uploadDir, filename)
-
-			dest, err := os.Create(destPath)
-			if err != nil {
-				errors = append(errors, fmt.Sprintf("%s: save failed", fileHeader.Filename))
-				return
-			}
-
-			if _, err = io.Copy(dest, file); err != nil {
-				_ = dest.Close()
-				_ = os.Remove(destPath)
-				errors = append(errors, fmt.Sprintf("%s: copy failed", fileHeader.Filename))
-				return
-			}
-
-			_ = dest.Close()
-
-			uploadedFiles = append(uploadedFiles, zh.M{
-				"filename":     filename,
-				"original":     fileHeader.Filename,
-				"size":         fileHeader.Size,
-				"download_url": fmt.Sprintf("/files/%s", filename),
-			})
-		}()
+		uploadedFiles = append(uploadedFiles, zh.M{
+			"filename":     filename,
+			"original":     fileHeader.Filename,
+			"size":         fileHeader.Size,
+			"download_url": fmt.Sprintf("/files/%s", filename),
+		})
 	}
 
 	response := zh.M{
-		"message":     fmt.Sprintf("Uploaded %d of %d files", len(uploadedFiles), len(form.Files)),
+		"message":     fmt.Sprintf("Uploaded %d of %d files", len(uploadedFiles), len(files)),
 		"files":       uploadedFiles,
-		"description": form.Description,
+		"description": strings.Join(values["description"], ""),
 	}
 
 	if len(errors) > 0 {
@@ -137,14 +109,3 @@ func uploadHandler(w http.ResponseWriter, r *http.Request) error {
 
 	return zh.R.JSON(w, statusCode, response)
 }
-
-func downloadHandler(w http.ResponseWriter, r *http.Request) error {
-	filename := filepath.Base(r.PathValue("filename"))
-	filePath := filepath.Join(uploadDir, filename)
-
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return zh.R.ProblemDetail(w, zh.NewProblemDetail(404, "File not found"))
-	}
-
-	return zh.R.File(w, r, filePath)
-}
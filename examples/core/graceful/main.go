@@ -2,11 +2,7 @@ package main
 
 import (
 	"context"
-	"errors"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	zh "github.com/alexferl/zerohttp"
@@ -14,7 +10,11 @@ import (
 )
 
 func main() {
-	app := zh.New()
+	app := zh.New(zh.Config{
+		Lifecycle: zh.LifecycleConfig{
+			ShutdownTimeout: 5 * time.Second,
+		},
+	})
 
 	app.GET("/", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
 		return zh.R.JSON(w, 200, zh.M{"message": "Hello, World!"})
@@ -33,20 +33,8 @@ func main() {
 		return zh.R.JSON(w, 200, zh.M{"message": "Slow response completed"})
 	}))
 
-	go func() {
-		if err := app.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			app.Logger().Fatal("Server failed to start", log.E(err))
-		}
-	}()
-
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
-	<-quit
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if err := app.Shutdown(ctx); err != nil {
-		app.Logger().Fatal("Server forced to shutdown", log.E(err))
+	// Run blocks until SIGINT or SIGTERM is received, then shuts down gracefully
+	if err := app.Run(context.Background()); err != nil {
+		app.Logger().Fatal("Server stopped with error", log.E(err))
 	}
 }
@@ -37,6 +37,12 @@ func main() {
 		return zh.R.Blob(w, http.StatusOK, "image/png", pngData)
 	}))
 
+	// In-memory file downloads
+	app.GET("/download", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		csv := []byte("id,name\n1,Alice\n2,Bob\n")
+		return zh.R.BlobDownload(w, "users.csv", "text/csv", csv)
+	}))
+
 	// Streaming responses
 	app.GET("/stream", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
 		reader := bytes.NewReader([]byte("This is streaming data line by line\nSecond line\nThird line"))
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	zh "github.com/alexferl/zerohttp"
+)
+
+func main() {
+	app := zh.New()
+
+	// Assets built at startup, e.g. by bundling and minifying sources
+	assets := zh.NewMemFS(map[string][]byte{
+		"index.html":       []byte(`<!DOCTYPE html><html><head><link rel="stylesheet" href="/assets/style.css"></head><body><h1>MemFS</h1><script src="/assets/app.js"></script></body></html>`),
+		"assets/app.js":    buildBundle(),
+		"assets/style.css": []byte(strings.Repeat("body { font-family: sans-serif; }\n", 20)),
+	})
+
+	// API routes (must be registered before StaticFS)
+	app.POST("/api/rebuild", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		// Replaced files get a new ETag and Last-Modified right away
+		assets.Set("assets/app.js", buildBundle())
+		return zh.R.JSON(w, http.StatusOK, zh.M{"status": "rebuilt"})
+	}))
+
+	// Serve the in-memory assets with SPA fallback to index.html
+	app.StaticFS(assets, true, "/api/")
+
+	log.Fatal(app.Start())
+}
+
+func buildBundle() []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// built at %s\n", time.Now().Format(time.RFC3339Nano))
+	for i := range 50 {
+		fmt.Fprintf(&b, "console.log('module %d loaded');\n", i)
+	}
+	return []byte(b.String())
+}
@@ -4,6 +4,7 @@ import (
 	"embed"
 	"log"
 	"net/http"
+	"time"
 
 	zh "github.com/alexferl/zerohttp"
 )
@@ -12,7 +13,14 @@ import (
 var spaFiles embed.FS
 
 func main() {
-	app := zh.New()
+	app := zh.New(zh.Config{
+		// Cache assets for a day and fingerprinted bundles such as
+		// assets/index-BkL9x2aF.js for good; index.html is always revalidated
+		Static: zh.StaticConfig{
+			MaxAge:    24 * time.Hour,
+			Immutable: true,
+		},
+	})
 
 	// API routes (must be registered before Static)
 	app.GET("/api/health", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
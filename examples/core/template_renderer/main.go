@@ -18,7 +18,9 @@ type PageData struct {
 }
 
 func main() {
-	tm := zh.NewTemplateManager(templatesFS, "templates/*.html")
+	tm := zh.NewTemplateManager(templatesFS, "templates/*.html", zh.TemplateConfig{
+		Layout: "base.html",
+	})
 
 	app := zh.New()
 
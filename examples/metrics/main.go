@@ -112,7 +112,7 @@ func main() {
 	}))
 
 	// Circuit breaker example - demonstrates circuit breaker metrics collection
-	// Records: circuit_breaker_state, circuit_breaker_requests_total, circuit_breaker_failures_total, circuit_breaker_trips_total
+	// Records: circuit_breaker_state, circuit_breaker_requests_total, circuit_breaker_failures_total, circuit_breaker_trips_total, circuit_breaker_state_changes_total
 	app.Group(func(flaky zh.Router) {
 		flaky.Use(circuitbreaker.New(circuitbreaker.Config{
 			FailureThreshold: 3,
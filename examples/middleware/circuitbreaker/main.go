@@ -4,6 +4,7 @@ import (
 	"log"
 	"net/http"
 	"sync/atomic"
+	"time"
 
 	zh "github.com/alexferl/zerohttp"
 	"github.com/alexferl/zerohttp/middleware/circuitbreaker"
@@ -14,10 +15,10 @@ var requestCount atomic.Int32
 func main() {
 	app := zh.New()
 
-	// Add circuit breaker middleware with custom config
-	app.Use(circuitbreaker.New(circuitbreaker.Config{
+	// Create a circuit breaker with custom config
+	breaker := circuitbreaker.NewBreaker(circuitbreaker.Config{
 		FailureThreshold:    3,
-		RecoveryTimeout:     5,
+		RecoveryTimeout:     5 * time.Second,
 		SuccessThreshold:    2,
 		MaxHalfOpenRequests: 1,
 		IsFailure: func(r *http.Request, statusCode int) bool {
@@ -25,7 +26,8 @@ func main() {
 		},
 		OpenStatusCode: http.StatusServiceUnavailable,
 		OpenMessage:    "Circuit breaker is OPEN - service temporarily unavailable",
-	}))
+	})
+	app.Use(breaker.Middleware)
 
 	// Endpoint that fails intermittently (to trigger circuit breaker)
 	app.GET("/flaky", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
@@ -52,5 +54,20 @@ func main() {
 		})
 	}))
 
+	// Per-endpoint circuit breaker counters
+	app.GET("/stats", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return zh.R.JSON(w, http.StatusOK, breaker.Stats())
+	}))
+
+	// Force circuits closed, e.g. once an upstream has recovered
+	app.POST("/reset", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		if key := r.URL.Query().Get("key"); key != "" {
+			breaker.Reset(key)
+		} else {
+			breaker.ResetAll()
+		}
+		return zh.R.JSON(w, http.StatusOK, breaker.Stats())
+	}))
+
 	log.Fatal(app.Start())
 }
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	zh "github.com/alexferl/zerohttp"
+	"github.com/alexferl/zerohttp/middleware/ipfilter"
+	"github.com/alexferl/zerohttp/middleware/realip"
+)
+
+func main() {
+	app := zh.New()
+
+	// Public endpoint, reachable from anywhere
+	app.GET("/", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return zh.R.JSON(w, http.StatusOK, zh.M{"message": "Hello, world!"})
+	}))
+
+	// Admin endpoints restricted to loopback and private networks.
+	// The default extractor ignores X-Forwarded-For so clients can't spoof their IP.
+	app.Group(func(admin zh.Router) {
+		admin.Use(ipfilter.New(ipfilter.Config{
+			Allow: []string{"127.0.0.0/8", "::1", "10.0.0.0/8", "192.168.0.0/16"},
+		}))
+
+		admin.GET("/admin", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+			return zh.R.JSON(w, http.StatusOK, zh.M{"message": "Welcome, admin"})
+		}))
+	})
+
+	// Blocklist a range, with allow winning for one trusted address inside it.
+	// X-Forwarded-For is honored only when the request comes from loopback,
+	// standing in for a reverse proxy.
+	app.GET("/api/data", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return zh.R.JSON(w, http.StatusOK, zh.M{"data": []string{"a", "b", "c"}})
+	}), ipfilter.New(ipfilter.Config{
+		Allow:       []string{"192.0.2.10"},
+		Deny:        []string{"192.0.2.0/24"},
+		Precedence:  ipfilter.AllowFirst,
+		IPExtractor: realip.TrustedProxiesIPExtractor([]string{"127.0.0.1", "::1"}),
+	}))
+
+	log.Fatal(app.Start())
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	zh "github.com/alexferl/zerohttp"
+	"github.com/alexferl/zerohttp/healthcheck"
+	"github.com/alexferl/zerohttp/middleware/maintenance"
+)
+
+func main() {
+	app := zh.New()
+
+	mw, mode := maintenance.New(maintenance.Config{
+		Message:       "We're upgrading, back in a few minutes",
+		RetryAfter:    2 * time.Minute,
+		ExcludedPaths: []string{"/livez", "/readyz", "/startupz", "/admin/"},
+	})
+	app.Use(mw)
+
+	healthcheck.New(app)
+
+	app.GET("/", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return zh.R.JSON(w, http.StatusOK, zh.M{"message": "Hello, world!"})
+	}))
+
+	// Admin endpoints stay reachable so maintenance can be turned off again.
+	// Protect these with authentication in a real application.
+	app.POST("/admin/maintenance/enable", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		mode.Enable()
+		return zh.R.JSON(w, http.StatusOK, zh.M{"maintenance": mode.Enabled()})
+	}))
+
+	app.POST("/admin/maintenance/disable", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		mode.Disable()
+		return zh.R.JSON(w, http.StatusOK, zh.M{"maintenance": mode.Enabled()})
+	}))
+
+	log.Fatal(app.Start())
+}
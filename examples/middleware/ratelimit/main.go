@@ -65,6 +65,16 @@ func main() {
 		IncludeHeaders: config.Bool(true),
 	}))
 
+	// Example 6: Smooth rate limiting (GCRA, 2 req/second with bursts of 2)
+	app.GET("/api/smooth", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return responseJSON(w, r)
+	}), ratelimit.New(ratelimit.Config{
+		Rate:           2,
+		Window:         time.Second,
+		Algorithm:      ratelimit.GCRA,
+		IncludeHeaders: config.Bool(true),
+	}))
+
 	log.Fatal(app.Start())
 }
 
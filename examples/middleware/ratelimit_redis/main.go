@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"log"
 	"net/http"
 	"time"
@@ -11,80 +13,101 @@ import (
 	"github.com/alexferl/zerohttp/config"
 	"github.com/alexferl/zerohttp/httpx"
 	"github.com/alexferl/zerohttp/middleware/ratelimit"
+	"github.com/alexferl/zerohttp/storage"
 	"github.com/redis/go-redis/v9"
 )
 
-// RedisStore implements config.RateLimitStore using Redis for distributed
-// rate limiting across multiple server instances.
-type RedisStore struct {
-	client    *redis.Client
-	window    time.Duration
-	rate      int
-	algorithm ratelimit.Algorithm
-	keyPrefix string
+// incrScript increments a counter and sets its TTL when it is created,
+// atomically, so the counter can't be left without an expiry.
+var incrScript = redis.NewScript(`
+local n = redis.call("INCR", KEYS[1])
+if n == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return n
+`)
+
+// unlockScript deletes a lock only if it is still held by the caller.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// RedisStorage implements storage.Storage, storage.Counter and storage.Locker
+// using Redis, so it can back every rate limit algorithm through
+// ratelimit.NewStorageAdapter.
+type RedisStorage struct {
+	client *redis.Client
 }
 
-// NewRedisStore creates a new Redis-backed rate limit store.
-// This allows rate limiting to work across multiple server instances.
-func NewRedisStore(client *redis.Client, algorithm ratelimit.Algorithm, window time.Duration, rate int) *RedisStore {
-	return &RedisStore{
-		client:    client,
-		window:    window,
-		rate:      rate,
-		algorithm: algorithm,
-		keyPrefix: "ratelimit:",
-	}
+// NewRedisStorage creates a new Redis-backed storage.
+func NewRedisStorage(client *redis.Client) *RedisStorage {
+	return &RedisStorage{client: client}
 }
 
-// CheckAndRecord implements config.RateLimitStore using Redis.
-// Uses sliding window algorithm with Redis sorted sets.
-func (s *RedisStore) CheckAndRecord(ctx context.Context, key string, now time.Time) (bool, int, time.Time) {
-	windowStart := now.Add(-s.window)
-	redisKey := s.keyPrefix + key
-
-	// Remove old entries outside the window
-	s.client.ZRemRangeByScore(ctx, redisKey, "0", fmt.Sprintf("%d", windowStart.UnixMilli()))
-
-	// Count current entries in window
-	count, err := s.client.ZCard(ctx, redisKey).Result()
+// Get retrieves a value by key.
+func (s *RedisStorage) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	val, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
 	if err != nil {
-		// On error, allow the request (fail open)
-		return true, s.rate - 1, now.Add(s.window)
+		return nil, false, err
 	}
+	return val, true, nil
+}
 
-	if int(count) >= s.rate {
-		// Rate limit exceeded
-		oldest, _ := s.client.ZRangeWithScores(ctx, redisKey, 0, 0).Result()
-		resetTime := now.Add(s.window)
-		if len(oldest) > 0 {
-			resetTime = time.UnixMilli(int64(oldest[0].Score)).Add(s.window)
-		}
-		return false, 0, resetTime
-	}
+// Set stores a value with the given TTL.
+func (s *RedisStorage) Set(ctx context.Context, key string, val []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, key, val, ttl).Err()
+}
 
-	// Add current request to the window
-	err = s.client.ZAdd(ctx, redisKey, redis.Z{
-		Score:  float64(now.UnixMilli()),
-		Member: now.UnixNano(),
-	}).Err()
-	if err != nil {
-		return true, s.rate - 1, now.Add(s.window)
-	}
+// Delete removes a key.
+func (s *RedisStorage) Delete(ctx context.Context, key string) error {
+	return s.client.Del(ctx, key).Err()
+}
 
-	// Set expiry on the key to auto-cleanup
-	s.client.Expire(ctx, redisKey, s.window)
+// Incr atomically increments a counter, setting its TTL on creation.
+func (s *RedisStorage) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return incrScript.Run(ctx, s.client, []string{key}, ttl.Milliseconds()).Int64()
+}
 
-	remaining := s.rate - int(count) - 1
-	resetTime := now.Add(s.window)
+// Lock acquires a lock with SET NX. The lock value is a random token so
+// Unlock only releases locks it owns.
+func (s *RedisStorage) Lock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, key, lockToken(key), ttl).Result()
+}
 
-	return true, remaining, resetTime
+// Unlock releases a lock acquired by this process.
+func (s *RedisStorage) Unlock(ctx context.Context, key string) error {
+	return unlockScript.Run(ctx, s.client, []string{key}, lockToken(key)).Err()
 }
 
-// Close releases resources associated with the store.
-func (s *RedisStore) Close() error {
+// Close releases resources associated with the storage.
+func (s *RedisStorage) Close() error {
 	return s.client.Close()
 }
 
+// processID identifies this process as a lock owner.
+var processID = func() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}()
+
+// lockToken returns the value stored in a lock owned by this process.
+func lockToken(key string) string {
+	return processID + ":" + key
+}
+
+var (
+	_ storage.Storage = (*RedisStorage)(nil)
+	_ storage.Counter = (*RedisStorage)(nil)
+	_ storage.Locker  = (*RedisStorage)(nil)
+)
+
 func main() {
 	// Create Redis client
 	client := redis.NewClient(&redis.Options{
@@ -101,12 +124,20 @@ func main() {
 	// Create Redis-backed rate limit store
 	rate := 10
 	window := time.Minute
-	store := NewRedisStore(client, ratelimit.SlidingWindow, window, rate)
+	store, err := ratelimit.NewStorageAdapter(NewRedisStorage(client), ratelimit.StorageAdapterConfig{
+		Algorithm: ratelimit.SlidingWindow,
+		Rate:      rate,
+		Window:    window,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// Configure the server with Redis store
 	app := zh.New()
 	app.Use(ratelimit.New(ratelimit.Config{
 		Store:          store,
+		Algorithm:      ratelimit.SlidingWindow,
 		Rate:           rate,
 		Window:         window,
 		IncludeHeaders: config.Bool(true),
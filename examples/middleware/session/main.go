@@ -0,0 +1,47 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	zh "github.com/alexferl/zerohttp"
+	"github.com/alexferl/zerohttp/config"
+	"github.com/alexferl/zerohttp/middleware/session"
+)
+
+func main() {
+	app := zh.New()
+
+	app.Use(session.New(session.Config{
+		CookieSecure: config.Bool(false), // Allow plain HTTP for local testing
+	}))
+
+	app.POST("/login", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		user := r.URL.Query().Get("user")
+		if user == "" {
+			return zh.R.JSON(w, http.StatusBadRequest, map[string]string{"error": "user is required"})
+		}
+
+		// Issue a new session ID on login to prevent session fixation
+		session.RenewID(r)
+		session.Set(r, "user", user)
+
+		return zh.R.JSON(w, http.StatusOK, map[string]string{"message": "logged in as " + user})
+	}))
+
+	app.GET("/me", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		user := session.Get(r, "user")
+		if user == "" {
+			return zh.R.JSON(w, http.StatusUnauthorized, map[string]string{"error": "not logged in"})
+		}
+
+		return zh.R.JSON(w, http.StatusOK, map[string]string{"user": user})
+	}))
+
+	app.POST("/logout", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		session.Destroy(r)
+		return zh.R.NoContent(w)
+	}))
+
+	log.Fatal(app.Start())
+}
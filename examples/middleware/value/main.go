@@ -8,23 +8,39 @@ import (
 	"github.com/alexferl/zerohttp/middleware/value"
 )
 
+// tenantKey is a typed key, which can't collide with keys of other packages
+var tenantKey = value.NewKey[string]("tenant")
+
 func main() {
 	app := zh.New()
 
 	app.GET("/user", zh.HandlerFunc(userHandler),
 		value.With("userID", 123),
 		value.With("role", "admin"),
+		tenantMiddleware,
 	)
 
 	log.Fatal(app.Start())
 }
 
+func tenantMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant := r.Header.Get("X-Tenant")
+		if tenant == "" {
+			tenant = "default"
+		}
+		next.ServeHTTP(w, r.WithContext(tenantKey.Set(r.Context(), tenant)))
+	})
+}
+
 func userHandler(w http.ResponseWriter, r *http.Request) error {
 	userID, _ := value.Get[int](r, "userID")
 	role, _ := value.Get[string](r, "role")
+	tenant, _ := tenantKey.Get(r)
 
 	return zh.R.JSON(w, http.StatusOK, zh.M{
 		"user_id": userID,
 		"role":    role,
+		"tenant":  tenant,
 	})
 }
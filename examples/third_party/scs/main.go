@@ -10,7 +10,6 @@ import (
 
 	"github.com/alexedwards/scs/v2"
 	zh "github.com/alexferl/zerohttp"
-	"github.com/alexferl/zerohttp/httpx"
 )
 
 var (
@@ -88,8 +87,7 @@ func renderHTML(w http.ResponseWriter, title string, content template.HTML, r *h
 		Content: content,
 	}
 
-	w.Header().Set(httpx.HeaderContentType, httpx.MIMETextHTML)
-	return templates.ExecuteTemplate(w, "", data)
+	return zh.R.Template(w, http.StatusOK, templates, "", data)
 }
 
 func homeHandler(w http.ResponseWriter, r *http.Request) error {
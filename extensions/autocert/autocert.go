@@ -5,6 +5,11 @@ import (
 	"net/http"
 )
 
+// ALPNProto is the ALPN protocol name used by the ACME TLS-ALPN-01
+// challenge, the same as golang.org/x/crypto/acme.ALPNProto. The server
+// advertises it so the Manager can answer challenges during TLS handshakes.
+const ALPNProto = "acme-tls/1"
+
 // Manager is the interface for automatic TLS certificate management.
 // Users can implement this interface or use golang.org/x/crypto/acme/autocert.Manager.
 type Manager interface {
@@ -19,11 +19,33 @@
 //	}
 //
 //	app := zh.New(zh.Config{
-//	    AutocertManager: mgr,
+//	    Extensions: zh.ExtensionsConfig{AutocertManager: mgr},
 //	})
 //
 //	log.Fatal(app.StartAutoTLS())
 //
+// # Challenges
+//
+// StartAutoTLS answers HTTP-01 challenges on the HTTP server and TLS-ALPN-01
+// challenges on the HTTPS server, so certificates can be issued even when
+// only port 443 is reachable.
+//
+// # Multiple Instances
+//
+// autocert.DirCache stores certificates on the local disk, so replicas
+// behind a load balancer each try to provision the same domains and can hit
+// Let's Encrypt rate limits. Share one certificate store between them by
+// setting Cache to any implementation of the autocert.Cache interface
+// (Get, Put and Delete by key), for example backed by Redis or S3, and set
+// Email to receive expiry notices:
+//
+//	mgr := &autocert.Manager{
+//	    Cache:      redisCache, // implements autocert.Cache
+//	    Email:      "ops@example.com",
+//	    Prompt:     autocert.AcceptTOS,
+//	    HostPolicy: autocert.HostWhitelist("example.com"),
+//	}
+//
 // The Manager interface is compatible with golang.org/x/crypto/acme/autocert.Manager,
 // so you can use that implementation directly or provide your own.
 package autocert
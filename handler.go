@@ -0,0 +1,83 @@
+package zerohttp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/validator"
+)
+
+// JSONHandler adapts a function returning a status code, a payload and an
+// error into a [HandlerFunc]. On success the payload is rendered with
+// [Renderer.JSON] using the status code. Errors are handled like those
+// returned by any HandlerFunc, so problem details, mapped errors and
+// validation errors produce the same responses.
+//
+// Example:
+//
+//	app.GET("/users/{id}", zh.JSONHandler(func(r *http.Request) (int, any, error) {
+//	    user, err := db.GetUser(r.Context(), zh.Param(r, "id"))
+//	    if err != nil {
+//	        return 0, nil, err
+//	    }
+//	    return http.StatusOK, user, nil
+//	}))
+func JSONHandler(fn func(r *http.Request) (int, any, error)) HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		status, payload, err := fn(r)
+		if err != nil {
+			return err
+		}
+		return R.JSON(w, status, payload)
+	}
+}
+
+// Endpoint adapts a typed function into a [HandlerFunc]. The request is bound
+// to a new In with [BindAndValidate] and validated, fn is called with the
+// request context, and its result is rendered as JSON with 200 OK. Requests
+// without a body or Content-Type, such as GET or DELETE, are bound from the
+// query string instead.
+//
+// Binding failures return 400 Bad Request and validation failures 422
+// Unprocessable Entity with the field errors, without calling fn. Errors
+// returned by fn are handled like those returned by any HandlerFunc.
+//
+// Example:
+//
+//	type CreateUserInput struct {
+//	    Name  string `json:"name" validate:"required,min=2"`
+//	    Email string `json:"email" validate:"required,email"`
+//	}
+//
+//	app.POST("/users", zh.Endpoint(func(ctx context.Context, in CreateUserInput) (*User, error) {
+//	    return store.CreateUser(ctx, in.Name, in.Email)
+//	}))
+func Endpoint[In, Out any](fn func(ctx context.Context, in In) (Out, error)) HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		var in In
+		if err := bindEndpointInput(r, &in); err != nil {
+			return err
+		}
+
+		out, err := fn(r.Context(), in)
+		if err != nil {
+			return err
+		}
+		return R.JSON(w, http.StatusOK, out)
+	}
+}
+
+// bindEndpointInput binds and validates the input of an [Endpoint]. Bodyless
+// requests are bound from the query string, since decoding an empty body as
+// JSON fails.
+func bindEndpointInput(r *http.Request, dst any) error {
+	if r.ContentLength != 0 || r.Header.Get(httpx.HeaderContentType) != "" {
+		return BindAndValidate(r, dst)
+	}
+
+	if err := Bind.Query(r, dst); err != nil {
+		return &validator.BindError{Err: err}
+	}
+	return V.Struct(dst)
+}
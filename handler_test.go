@@ -0,0 +1,109 @@
+package zerohttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestJSONHandler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		router := NewRouter()
+		router.POST("/users", JSONHandler(func(r *http.Request) (int, any, error) {
+			return http.StatusCreated, M{"id": "42"}, nil
+		}))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users", nil))
+
+		zhtest.AssertWith(t, w).
+			Status(http.StatusCreated).
+			Header(httpx.HeaderContentType, httpx.MIMEApplicationJSONCharset).
+			JSONEq(`{"id":"42"}`)
+	})
+
+	t.Run("problem detail error", func(t *testing.T) {
+		router := NewRouter()
+		router.GET("/users/{id}", JSONHandler(func(r *http.Request) (int, any, error) {
+			return 0, nil, NewProblemDetail(http.StatusNotFound, "User not found")
+		}))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+		zhtest.AssertWith(t, w).
+			Status(http.StatusNotFound).
+			IsProblemDetail().
+			BodyContains("User not found")
+	})
+
+	t.Run("other error", func(t *testing.T) {
+		router := NewRouter()
+		router.GET("/fail", JSONHandler(func(r *http.Request) (int, any, error) {
+			return http.StatusOK, M{"ignored": true}, errors.New("boom")
+		}))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+		zhtest.AssertWith(t, w).Status(http.StatusInternalServerError).IsProblemDetail()
+	})
+}
+
+func TestEndpoint(t *testing.T) {
+	type createInput struct {
+		Name string `json:"name" validate:"required,min=2"`
+	}
+	type createOutput struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	type listInput struct {
+		Page int `query:"page"`
+	}
+
+	router := NewRouter()
+	router.POST("/users", Endpoint(func(ctx context.Context, in createInput) (createOutput, error) {
+		if in.Name == "taken" {
+			return createOutput{}, NewProblemDetail(http.StatusConflict, "Name is taken")
+		}
+		return createOutput{ID: "1", Name: in.Name}, nil
+	}))
+	router.GET("/users", Endpoint(func(ctx context.Context, in listInput) ([]int, error) {
+		return []int{in.Page}, nil
+	}))
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		status   int
+		contains string
+	}{
+		{"bound and validated", http.MethodPost, "/users", `{"name":"alice"}`, http.StatusOK, `"name":"alice"`},
+		{"validation failure", http.MethodPost, "/users", `{"name":"a"}`, http.StatusUnprocessableEntity, `"errors"`},
+		{"binding failure", http.MethodPost, "/users", `{"name":`, http.StatusBadRequest, "Invalid request body"},
+		{"handler error", http.MethodPost, "/users", `{"name":"taken"}`, http.StatusConflict, "Name is taken"},
+		{"query binding", http.MethodGet, "/users?page=3", "", http.StatusOK, "[3]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := zhtest.NewRequest(tt.method, tt.target)
+			if tt.body != "" {
+				req = req.WithHeader(httpx.HeaderContentType, httpx.MIMEApplicationJSON).WithBody(strings.NewReader(tt.body))
+			}
+
+			w := zhtest.Serve(router, req.Build())
+
+			zhtest.AssertWith(t, w).Status(tt.status).BodyContains(tt.contains)
+		})
+	}
+}
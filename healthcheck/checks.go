@@ -0,0 +1,105 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	zh "github.com/alexferl/zerohttp"
+)
+
+// Check is a named dependency check run by the readiness probe,
+// such as a database ping.
+type Check struct {
+	// Name identifies the check in the response, e.g. "db".
+	Name string
+
+	// Func runs the check. It should return promptly once ctx is done.
+	Func func(ctx context.Context) error
+
+	// Timeout is the maximum time the check may take.
+	// Default: Config.CheckTimeout
+	Timeout time.Duration
+
+	// Optional marks a check whose failure degrades the service without
+	// making it unready. The probe still responds with 200.
+	Optional bool
+}
+
+// Overall statuses of the checks response.
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusFail     = "fail"
+
+	// StatusStarting is reported until the app is started when
+	// Config.WaitForStarted is set.
+	StatusStarting = "starting"
+)
+
+// errCheckTimeout is reported for checks that didn't finish within their timeout.
+var errCheckTimeout = errors.New("timed out")
+
+// checksHandler returns a handler that runs checks concurrently and responds
+// with their results, e.g. {"status":"ok","checks":{"db":"ok"}}. It responds
+// with 503 if a required check fails.
+func (rep *reporter) checksHandler(checks []Check, timeout time.Duration) zh.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		errs := runChecks(r.Context(), checks, timeout)
+
+		resp := &response{Status: StatusOK, Checks: make(map[string]string, len(checks))}
+		for i, check := range checks {
+			if errs[i] == nil {
+				resp.Checks[check.Name] = StatusOK
+				continue
+			}
+
+			resp.Checks[check.Name] = StatusFail + ": " + errs[i].Error()
+			if !check.Optional {
+				resp.Status = StatusFail
+			} else if resp.Status == StatusOK {
+				resp.Status = StatusDegraded
+			}
+		}
+
+		status := http.StatusOK
+		if resp.Status == StatusFail {
+			status = http.StatusServiceUnavailable
+		}
+		return rep.render(w, status, resp)
+	}
+}
+
+// runChecks runs checks concurrently and returns their errors in order.
+// Checks still running when their timeout expires are reported as failed.
+func runChecks(ctx context.Context, checks []Check, timeout time.Duration) []error {
+	errs := make([]error, len(checks))
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Go(func() {
+			d := check.Timeout
+			if d <= 0 {
+				d = timeout
+			}
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			// Buffered so that a check ignoring ctx doesn't leak the goroutine
+			// waiting to send its result
+			done := make(chan error, 1)
+			go func() { done <- check.Func(ctx) }()
+
+			select {
+			case errs[i] = <-done:
+			case <-ctx.Done():
+				errs[i] = errCheckTimeout
+			}
+		})
+	}
+	wg.Wait()
+
+	return errs
+}
@@ -0,0 +1,128 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	zh "github.com/alexferl/zerohttp"
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func ok(context.Context) error { return nil }
+
+func failing(context.Context) error { return errors.New("connection refused") }
+
+func TestChecks(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     []Check
+		wantStatus int
+		wantBody   response
+	}{
+		{
+			name:       "all checks pass",
+			checks:     []Check{{Name: "db", Func: ok}, {Name: "cache", Func: ok}},
+			wantStatus: http.StatusOK,
+			wantBody:   response{Status: StatusOK, Checks: map[string]string{"db": "ok", "cache": "ok"}},
+		},
+		{
+			name:       "required check fails",
+			checks:     []Check{{Name: "db", Func: failing}, {Name: "cache", Func: ok}},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   response{Status: StatusFail, Checks: map[string]string{"db": "fail: connection refused", "cache": "ok"}},
+		},
+		{
+			name:       "optional check fails",
+			checks:     []Check{{Name: "db", Func: ok}, {Name: "cache", Func: failing, Optional: true}},
+			wantStatus: http.StatusOK,
+			wantBody:   response{Status: StatusDegraded, Checks: map[string]string{"db": "ok", "cache": "fail: connection refused"}},
+		},
+		{
+			name:       "required and optional checks fail",
+			checks:     []Check{{Name: "db", Func: failing}, {Name: "cache", Func: failing, Optional: true}},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   response{Status: StatusFail, Checks: map[string]string{"db": "fail: connection refused", "cache": "fail: connection refused"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := zh.New()
+			New(app, Config{Checks: tt.checks})
+
+			w := zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/readyz").Build())
+			zhtest.AssertWith(t, w).
+				Status(tt.wantStatus).
+				HeaderContains(httpx.HeaderContentType, httpx.MIMEApplicationJSON)
+
+			var body response
+			zhtest.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			zhtest.AssertEqual(t, tt.wantBody, body)
+		})
+	}
+}
+
+func TestChecks_Timeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	app := zh.New()
+	New(app, Config{
+		CheckTimeout: 50 * time.Millisecond,
+		Checks: []Check{
+			{Name: "db", Func: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}},
+			// Checks that ignore their context are cut off too
+			{Name: "queue", Func: func(context.Context) error {
+				<-block
+				return nil
+			}},
+			{Name: "cache", Timeout: time.Second, Func: func(ctx context.Context) error {
+				time.Sleep(100 * time.Millisecond)
+				return nil
+			}},
+		},
+	})
+
+	start := time.Now()
+	w := zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/readyz").Build())
+	zhtest.AssertTrue(t, time.Since(start) < time.Second)
+
+	var body response
+	zhtest.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	zhtest.AssertWith(t, w).Status(http.StatusServiceUnavailable)
+	zhtest.AssertEqual(t, "fail: timed out", body.Checks["queue"])
+	zhtest.AssertEqual(t, "ok", body.Checks["cache"])
+	zhtest.AssertTrue(t, body.Checks["db"] != "ok")
+}
+
+func TestChecks_Concurrent(t *testing.T) {
+	slow := func(context.Context) error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	}
+
+	app := zh.New()
+	New(app, Config{Checks: []Check{{Name: "a", Func: slow}, {Name: "b", Func: slow}, {Name: "c", Func: slow}}})
+
+	start := time.Now()
+	w := zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/readyz").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusOK)
+	zhtest.AssertTrue(t, time.Since(start) < 250*time.Millisecond)
+}
+
+func TestChecks_Invalid(t *testing.T) {
+	zhtest.AssertPanic(t, func() {
+		New(zh.New(), Config{Checks: []Check{{Func: ok}}})
+	})
+	zhtest.AssertPanic(t, func() {
+		New(zh.New(), Config{Checks: []Check{{Name: "db"}}})
+	})
+}
@@ -29,6 +29,59 @@
 //	}
 //	healthcheck.Register(app, config)
 //
+// # Dependency Checks
+//
+// Register named checks for the dependencies the app needs. The readiness
+// probe runs them concurrently, each with its own timeout, and responds with
+// their results as JSON:
+//
+//	healthcheck.New(app, healthcheck.Config{
+//	    Checks: []healthcheck.Check{
+//	        {Name: "db", Func: db.PingContext},
+//	        {Name: "queue", Func: queue.Ping, Timeout: 2 * time.Second},
+//	        {Name: "cache", Func: cache.Ping, Optional: true},
+//	    },
+//	})
+//
+//	// GET /readyz
+//	{"status":"degraded","checks":{"db":"ok","queue":"ok","cache":"fail: connection refused"}}
+//
+// The probe responds with 503 and status "fail" if a required check fails.
+// Optional checks that fail only mark the status as "degraded", still with 200.
+// Checks time out after CheckTimeout (5s by default) unless they set Timeout.
+//
+// # Waiting for Startup
+//
+// An app that initializes asynchronously is up before it can serve traffic.
+// Set WaitForStarted to answer the readiness and startup probes with 503
+// until the app calls MarkStarted, while the liveness probe stays green:
+//
+//	healthcheck.New(app, healthcheck.Config{WaitForStarted: true})
+//
+//	app.RegisterPostStartupHook("warm-cache", func(ctx context.Context) error {
+//	    if err := cache.Warm(ctx); err != nil {
+//	        return err
+//	    }
+//	    app.MarkStarted()
+//	    return nil
+//	})
+//
+// # JSON Responses
+//
+// The default handlers respond with plain "ok". Set JSONResponse to respond
+// with JSON including the uptime and the app version instead:
+//
+//	healthcheck.New(app, healthcheck.Config{
+//	    JSONResponse: true,
+//	    Version:      buildVersion,
+//	})
+//
+//	// GET /livez
+//	{"status":"ok","uptime":"3h12m5s","version":"1.4.2"}
+//
+// Check results also include the uptime and version. Probes only look at the
+// status code, so JSON responses are mostly useful to dashboards and humans.
+//
 // # Configuration
 //
 // Customize endpoints and handlers:
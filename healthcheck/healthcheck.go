@@ -1,7 +1,9 @@
 package healthcheck
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	zh "github.com/alexferl/zerohttp"
 	"github.com/alexferl/zerohttp/internal/config"
@@ -22,9 +24,20 @@ type Config struct {
 	ReadinessEndpoint string
 
 	// ReadinessHandler is the handler for the readiness probe.
+	// Ignored when Checks is set.
 	// Default: returns "ok" with 200 status
 	ReadinessHandler zh.HandlerFunc
 
+	// Checks are dependency checks run concurrently by the readiness probe.
+	// When set, the readiness probe responds with the result of each check
+	// as JSON, with 503 status if a required check fails.
+	// Default: nil
+	Checks []Check
+
+	// CheckTimeout is the default maximum time each check may take.
+	// Default: 5s
+	CheckTimeout time.Duration
+
 	// StartupEndpoint is the path for the startup probe endpoint.
 	// Default: "/startupz"
 	StartupEndpoint string
@@ -32,10 +45,33 @@ type Config struct {
 	// StartupHandler is the handler for the startup probe.
 	// Default: returns "ok" with 200 status
 	StartupHandler zh.HandlerFunc
+
+	// WaitForStarted makes the readiness and startup probes respond with 503
+	// and a "starting" status until [zh.Server.MarkStarted] is called, for
+	// apps that keep initializing after the server starts listening, such as
+	// warming caches or running migrations. The liveness probe is not affected.
+	// Default: false
+	WaitForStarted bool
+
+	// JSONResponse makes the default handlers and the checks response emit
+	// a JSON body with the overall status, uptime and Version, such as
+	// {"status":"ok","uptime":"1h2m3s","version":"1.4.2"}, instead of "ok".
+	// Custom handlers are not affected.
+	// Default: false
+	JSONResponse bool
+
+	// Version is the version or build of the app included in JSON responses,
+	// e.g. "1.4.2" or a commit hash.
+	// Default: ""
+	Version string
 }
 
-// defaultHandler returns a simple "ok" response
+// defaultHandler returns a simple "ok" response, or the JSON response when
+// served by an endpoint registered by New with JSONResponse.
 func defaultHandler(w http.ResponseWriter, r *http.Request) error {
+	if rep, ok := r.Context().Value(reporterKey{}).(*reporter); ok {
+		return rep.render(w, http.StatusOK, &response{Status: StatusOK})
+	}
 	w.WriteHeader(http.StatusOK)
 	_, err := w.Write([]byte("ok"))
 	return err
@@ -48,6 +84,7 @@ var DefaultConfig = Config{
 	LivenessHandler:   defaultHandler,
 	ReadinessEndpoint: "/readyz",
 	ReadinessHandler:  defaultHandler,
+	CheckTimeout:      5 * time.Second,
 	StartupEndpoint:   "/startupz",
 	StartupHandler:    defaultHandler,
 }
@@ -74,12 +111,96 @@ var DefaultConfig = Config{
 //	    StartupEndpoint:   "/startupz",
 //	    StartupHandler:    myCustomHandler,
 //	})
+//
+//	// Readiness from dependency checks
+//	healthcheck.New(app, healthcheck.Config{
+//	    Checks: []healthcheck.Check{
+//	        {Name: "db", Func: db.PingContext},
+//	        {Name: "cache", Func: cache.Ping, Optional: true},
+//	    },
+//	})
 func New(app *zh.Server, cfg ...Config) {
 	c := DefaultConfig
 	if len(cfg) > 0 {
 		config.Merge(&c, cfg[0])
 	}
-	app.GET(c.LivenessEndpoint, c.LivenessHandler)
-	app.GET(c.ReadinessEndpoint, c.ReadinessHandler)
-	app.GET(c.StartupEndpoint, c.StartupHandler)
+
+	rep := &reporter{json: c.JSONResponse, version: c.Version, start: time.Now()}
+
+	readiness := rep.handler(c.ReadinessHandler)
+	if len(c.Checks) > 0 {
+		for _, check := range c.Checks {
+			if check.Name == "" || check.Func == nil {
+				panic("zerohttp: healthcheck checks must have a name and a func")
+			}
+		}
+		readiness = rep.checksHandler(c.Checks, c.CheckTimeout)
+	}
+
+	startup := rep.handler(c.StartupHandler)
+	if c.WaitForStarted {
+		readiness = rep.waitForStarted(app, readiness)
+		startup = rep.waitForStarted(app, startup)
+	}
+
+	app.GET(c.LivenessEndpoint, rep.handler(c.LivenessHandler))
+	app.GET(c.ReadinessEndpoint, readiness)
+	app.GET(c.StartupEndpoint, startup)
+}
+
+// reporter builds the health responses of the endpoints registered by New.
+type reporter struct {
+	json    bool
+	version string
+	start   time.Time
+}
+
+// response is the JSON body of health responses.
+type response struct {
+	Status  string            `json:"status"`
+	Checks  map[string]string `json:"checks,omitempty"`
+	Uptime  string            `json:"uptime,omitempty"`
+	Version string            `json:"version,omitempty"`
+}
+
+// reporterKey is the context key of the reporter serving a request, for the
+// default handler to respond with JSON.
+type reporterKey struct{}
+
+// handler returns h, with the reporter in the request context when JSON
+// responses are enabled so the default handler responds with JSON. Custom
+// handlers ignore it.
+func (rep *reporter) handler(h zh.HandlerFunc) zh.HandlerFunc {
+	if !rep.json {
+		return h
+	}
+	return func(w http.ResponseWriter, r *http.Request) error {
+		return h(w, r.WithContext(context.WithValue(r.Context(), reporterKey{}, rep)))
+	}
+}
+
+// waitForStarted returns a handler responding with 503 until app is marked
+// as started, and with h afterwards.
+func (rep *reporter) waitForStarted(app *zh.Server, h zh.HandlerFunc) zh.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if app.Started() {
+			return h(w, r)
+		}
+		if rep.json {
+			return rep.render(w, http.StatusServiceUnavailable, &response{Status: StatusStarting})
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, err := w.Write([]byte(StatusStarting))
+		return err
+	}
+}
+
+// render writes resp with the uptime and version when JSON responses are
+// enabled.
+func (rep *reporter) render(w http.ResponseWriter, code int, resp *response) error {
+	if rep.json {
+		resp.Uptime = time.Since(rep.start).Round(time.Second).String()
+		resp.Version = rep.version
+	}
+	return zh.R.JSON(w, code, resp)
 }
@@ -1,10 +1,16 @@
 package healthcheck
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	zh "github.com/alexferl/zerohttp"
+	"github.com/alexferl/zerohttp/httpx"
 	"github.com/alexferl/zerohttp/zhtest"
 )
 
@@ -139,6 +145,8 @@ func TestDefaultConfig(t *testing.T) {
 	zhtest.AssertNotNil(t, DefaultConfig.LivenessHandler)
 	zhtest.AssertNotNil(t, DefaultConfig.ReadinessHandler)
 	zhtest.AssertNotNil(t, DefaultConfig.StartupHandler)
+	zhtest.AssertEqual(t, 5*time.Second, DefaultConfig.CheckTimeout)
+	zhtest.AssertFalse(t, DefaultConfig.JSONResponse)
 }
 
 func TestNoConfig(t *testing.T) {
@@ -185,3 +193,79 @@ func TestPartialConfig(t *testing.T) {
 		zhtest.AssertWith(t, w).Status(http.StatusOK).Body("ok")
 	})
 }
+
+func TestJSONResponse(t *testing.T) {
+	app := zh.New()
+
+	cfg := DefaultConfig
+	cfg.JSONResponse = true
+	cfg.Version = "1.4.2"
+	cfg.StartupHandler = func(w http.ResponseWriter, r *http.Request) error {
+		return zh.R.Text(w, http.StatusOK, "started")
+	}
+	New(app, cfg)
+
+	for _, endpoint := range []string{"/livez", "/readyz"} {
+		t.Run(endpoint, func(t *testing.T) {
+			w := zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, endpoint).Build())
+			zhtest.AssertWith(t, w).
+				Status(http.StatusOK).
+				HeaderContains(httpx.HeaderContentType, httpx.MIMEApplicationJSON)
+
+			var body response
+			zhtest.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			zhtest.AssertEqual(t, StatusOK, body.Status)
+			zhtest.AssertEqual(t, "1.4.2", body.Version)
+			zhtest.AssertEqual(t, "0s", body.Uptime)
+			zhtest.AssertNil(t, body.Checks)
+		})
+	}
+
+	t.Run("custom handlers are not affected", func(t *testing.T) {
+		w := zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/startupz").Build())
+		zhtest.AssertWith(t, w).Status(http.StatusOK).Body("started")
+	})
+}
+
+func TestJSONResponse_WithChecks(t *testing.T) {
+	app := zh.New()
+	New(app, Config{
+		JSONResponse: true,
+		Version:      "abc123",
+		Checks: []Check{{Name: "db", Func: func(context.Context) error {
+			return errors.New("down")
+		}}},
+	})
+
+	w := zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/readyz").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusServiceUnavailable)
+
+	var body response
+	zhtest.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	zhtest.AssertEqual(t, response{
+		Status:  StatusFail,
+		Checks:  map[string]string{"db": "fail: down"},
+		Uptime:  "0s",
+		Version: "abc123",
+	}, body)
+}
+
+func TestWaitForStarted(t *testing.T) {
+	for _, jsonResponse := range []bool{false, true} {
+		app := zh.New()
+		New(app, Config{WaitForStarted: true, JSONResponse: jsonResponse})
+
+		serve := func(endpoint string) *httptest.ResponseRecorder {
+			return zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, endpoint).Build())
+		}
+
+		zhtest.AssertWith(t, serve("/livez")).Status(http.StatusOK)
+		zhtest.AssertWith(t, serve("/readyz")).Status(http.StatusServiceUnavailable).BodyContains(StatusStarting)
+		zhtest.AssertWith(t, serve("/startupz")).Status(http.StatusServiceUnavailable).BodyContains(StatusStarting)
+
+		app.MarkStarted()
+
+		zhtest.AssertWith(t, serve("/readyz")).Status(http.StatusOK).BodyContains(StatusOK)
+		zhtest.AssertWith(t, serve("/startupz")).Status(http.StatusOK).BodyContains(StatusOK)
+	}
+}
@@ -6,6 +6,8 @@
 //	[MIMETextHTML], [MIMETextPlain], [MIMEApplicationJSON]
 //	[MIMEApplicationProblemJSON], [MIMEApplicationFormURLEncoded]
 //
+// [DetectContentType] returns the content type of a file from its extension.
+//
 // Header name constants include (among others):
 //
 //	[HeaderContentType], [HeaderAuthorization], [HeaderXRequestId]
@@ -33,12 +35,21 @@ const (
 	MIMEApplicationJSONCharset    = "application/json; charset=utf-8"
 	MIMEApplicationJavaScript     = "application/javascript"
 	MIMEApplicationXML            = "application/xml"
+	MIMEApplicationXHTMLXML       = "application/xhtml+xml"
+	MIMEApplicationXMLCharset     = "application/xml; charset=utf-8"
+	MIMEApplicationOctetStream    = "application/octet-stream"
+	MIMEApplicationPDF            = "application/pdf"
 	MIMEApplicationProblemJSON    = "application/problem+json"
+	MIMEApplicationProblemXML     = "application/problem+xml"
 	MIMEApplicationFormURLEncoded = "application/x-www-form-urlencoded"
 	MIMEApplicationRSSXML         = "application/rss+xml"
 	MIMEApplicationAtomXML        = "application/atom+xml"
+	MIMEApplicationGRPCWeb        = "application/grpc-web"
+	MIMEApplicationGRPCWebText    = "application/grpc-web-text"
 	MIMEMultipartFormData         = "multipart/form-data"
 	MIMETextXML                   = "text/xml"
+	MIMETextCSV                   = "text/csv"
+	MIMETextCSVCharset            = "text/csv; charset=utf-8"
 	MIMEImageSVGXML               = "image/svg+xml"
 )
 
@@ -73,6 +84,7 @@ const (
 	HeaderRange              = "Range"
 	HeaderReferer            = "Referer"
 	HeaderTE                 = "TE"
+	HeaderTraceparent        = "Traceparent"
 	HeaderUserAgent          = "User-Agent"
 	HeaderUpgrade            = "Upgrade"
 	HeaderVia                = "Via"
@@ -99,6 +111,9 @@ const (
 	HeaderLink               = "Link"
 	HeaderLocation           = "Location"
 	HeaderProxyAuthenticate  = "Proxy-Authenticate"
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
 	HeaderRetryAfter         = "Retry-After"
 	HeaderServer             = "Server"
 	HeaderSetCookie          = "Set-Cookie"
@@ -133,7 +148,9 @@ const (
 	HeaderSecFetchSite                    = "Sec-Fetch-Site"
 	HeaderStrictTransportSecurity         = "Strict-Transport-Security"
 	HeaderXContentTypeOptions             = "X-Content-Type-Options"
+	HeaderXDNSPrefetchControl             = "X-DNS-Prefetch-Control"
 	HeaderXFrameOptions                   = "X-Frame-Options"
+	HeaderXPermittedCrossDomainPolicies   = "X-Permitted-Cross-Domain-Policies"
 	HeaderXXSSProtection                  = "X-XSS-Protection"
 )
 
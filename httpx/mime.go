@@ -0,0 +1,59 @@
+package httpx
+
+import (
+	"mime"
+	"path/filepath"
+	"strings"
+)
+
+// fallbackMIMETypes covers common web file extensions that are missing from
+// Go's built-in table and from the system MIME tables of minimal images,
+// such as distroless or scratch containers.
+var fallbackMIMETypes = map[string]string{
+	".csv":         MIMETextCSVCharset,
+	".txt":         MIMETextPlainCharset,
+	".md":          "text/markdown; charset=utf-8",
+	".ics":         "text/calendar; charset=utf-8",
+	".map":         MIMEApplicationJSONCharset,
+	".webmanifest": "application/manifest+json",
+	".ndjson":      "application/x-ndjson",
+	".yaml":        "application/yaml",
+	".yml":         "application/yaml",
+	".zip":         "application/zip",
+	".gz":          "application/gzip",
+	".ico":         "image/x-icon",
+	".woff":        "font/woff",
+	".woff2":       "font/woff2",
+	".ttf":         "font/ttf",
+	".otf":         "font/otf",
+	".mp3":         "audio/mpeg",
+	".mp4":         "video/mp4",
+	".webm":        "video/webm",
+}
+
+// DetectContentType returns the content type of a file from the extension of
+// filename, using [mime.TypeByExtension] and falling back to a table of common
+// web types when the system doesn't know the extension. JSON is reported with
+// its charset. Unknown extensions return [MIMEApplicationOctetStream].
+//
+// Example:
+//
+//	w.Header().Set(httpx.HeaderContentType, httpx.DetectContentType("report.csv"))
+//	// text/csv; charset=utf-8
+func DetectContentType(filename string) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == "" {
+		return MIMEApplicationOctetStream
+	}
+
+	if ctype := mime.TypeByExtension(ext); ctype != "" {
+		if ctype == MIMEApplicationJSON {
+			return MIMEApplicationJSONCharset
+		}
+		return ctype
+	}
+	if ctype, ok := fallbackMIMETypes[ext]; ok {
+		return ctype
+	}
+	return MIMEApplicationOctetStream
+}
@@ -0,0 +1,41 @@
+package httpx
+
+import (
+	"mime"
+	"testing"
+)
+
+func TestDetectContentType(t *testing.T) {
+	tests := []struct {
+		filename string
+		expected string
+	}{
+		{"index.html", MIMETextHTMLCharset},
+		{"data.json", MIMEApplicationJSONCharset},
+		{"DATA.JSON", MIMEApplicationJSONCharset},
+		{"report.pdf", MIMEApplicationPDF},
+		{"assets/logo.png", "image/png"},
+		{"archive.unknownext", MIMEApplicationOctetStream},
+		{"Makefile", MIMEApplicationOctetStream},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			if got := DetectContentType(tt.filename); got != tt.expected {
+				t.Errorf("DetectContentType(%q) = %q, want %q", tt.filename, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDetectContentType_Fallback(t *testing.T) {
+	// System MIME tables vary, so only check extensions they don't know
+	for ext, expected := range fallbackMIMETypes {
+		if mime.TypeByExtension(ext) != "" {
+			continue
+		}
+		if got := DetectContentType("file" + ext); got != expected {
+			t.Errorf("DetectContentType(%q) = %q, want %q", "file"+ext, got, expected)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package mwutil
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// PeekBody reads up to n bytes of the request body and puts them back in
+// front of the unread remainder, so the handler still reads the whole body
+// and large or streamed bodies aren't buffered in memory. The body is
+// restored even if reading fails.
+func PeekBody(r *http.Request, n int64) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, n))
+	r.Body = &replayBody{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+	return body, err
+}
+
+// replayBody is a request body whose already-read prefix is replayed before
+// the rest of the original body.
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
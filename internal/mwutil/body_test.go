@@ -0,0 +1,25 @@
+package mwutil
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestPeekBody(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hello world"))
+
+	body, err := PeekBody(r, 5)
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertEqual(t, "hello", string(body))
+
+	// The handler still reads the whole body
+	rest, err := io.ReadAll(r.Body)
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertEqual(t, "hello world", string(rest))
+	zhtest.AssertNoError(t, r.Body.Close())
+}
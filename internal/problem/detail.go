@@ -30,6 +30,21 @@ type Detail struct {
 
 	// Extensions contains additional problem-specific data
 	Extensions map[string]any `json:"-"`
+
+	// cause is the error the Detail was created from, if any
+	cause error
+}
+
+// WithCause records err as the error d was created from, so that debug
+// details can be added when d is rendered for a request. It returns d.
+func WithCause(d *Detail, err error) *Detail {
+	d.cause = err
+	return d
+}
+
+// Cause returns the error recorded with WithCause, or nil.
+func Cause(d *Detail) error {
+	return d.cause
 }
 
 // NewDetail creates a new Detail with the given status code and detail message.
@@ -43,6 +58,16 @@ func NewDetail(statusCode int, detail string) *Detail {
 	}
 }
 
+// Error implements the error interface so that a Detail can be returned
+// from a handler and rendered as the response.
+func (p *Detail) Error() string {
+	msg := strconv.Itoa(p.Status) + " " + p.Title
+	if p.Detail != "" {
+		msg += ": " + p.Detail
+	}
+	return msg
+}
+
 // MarshalJSON implements custom JSON marshaling to include extensions as top-level fields
 func (p *Detail) MarshalJSON() ([]byte, error) {
 	result := map[string]any{
@@ -85,9 +110,18 @@ func (p *Detail) Render(w http.ResponseWriter) error {
 }
 
 // RenderAuto writes the Detail as an HTTP response, automatically selecting the
-// content type based on the Accept header. Returns JSON if the client accepts
-// application/json or application/problem+json; otherwise returns plain text.
+// content type based on the Accept header. Returns XML if the client prefers
+// application/problem+xml or another XML type, JSON if it accepts
+// application/json or application/problem+json, and plain text otherwise.
+// If an error handler is set for the status in the request context, it
+// writes the response instead.
 func (p *Detail) RenderAuto(w http.ResponseWriter, r *http.Request) error {
+	if ServeError(w, r, p.Status) {
+		return nil
+	}
+	if AcceptsXML(r) {
+		return p.RenderXML(w)
+	}
 	if !AcceptsJSON(r) {
 		w.Header().Set(httpx.HeaderContentType, httpx.MIMETextPlainCharset)
 		w.WriteHeader(p.Status)
@@ -308,3 +308,10 @@ func equalValues(a, b any) bool {
 	}
 	return a == b
 }
+
+func TestDetail_Error(t *testing.T) {
+	var err error = NewDetail(http.StatusNotFound, "User not found")
+	zhtest.AssertEqual(t, "404 Not Found: User not found", err.Error())
+
+	zhtest.AssertEqual(t, "409 Conflict", NewDetail(http.StatusConflict, "").Error())
+}
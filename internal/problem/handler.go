@@ -0,0 +1,45 @@
+package problem
+
+import (
+	"context"
+	"net/http"
+)
+
+// errorHandlersKey is the context key of the ErrorHandlers of a request.
+type errorHandlersKey struct{}
+
+// ErrorHandlers returns the handler serving error responses with the given
+// status code, or nil to render the problem detail.
+type ErrorHandlers func(status int) http.Handler
+
+// WithErrorHandlers returns a copy of ctx in which error responses are served
+// by handlers, instead of problem details, by RenderAuto and ServeError.
+func WithErrorHandlers(ctx context.Context, handlers ErrorHandlers) context.Context {
+	return context.WithValue(ctx, errorHandlersKey{}, handlers)
+}
+
+// WithoutErrorHandlers returns r without the error handlers of its context,
+// or r itself if it has none, so that the problem details rendered by an
+// error handler don't loop back to it.
+func WithoutErrorHandlers(r *http.Request) *http.Request {
+	if handlers, _ := r.Context().Value(errorHandlersKey{}).(ErrorHandlers); handlers == nil {
+		return r
+	}
+	return r.WithContext(WithErrorHandlers(r.Context(), nil))
+}
+
+// ServeError serves the error response with the given status code with the
+// handler set for it in the request context, and reports whether there was
+// one. The handler writes the whole response, including the status code.
+func ServeError(w http.ResponseWriter, r *http.Request, status int) bool {
+	handlers, _ := r.Context().Value(errorHandlersKey{}).(ErrorHandlers)
+	if handlers == nil {
+		return false
+	}
+	h := handlers(status)
+	if h == nil {
+		return false
+	}
+	h.ServeHTTP(w, WithoutErrorHandlers(r))
+	return true
+}
@@ -0,0 +1,67 @@
+package problem
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestServeError(t *testing.T) {
+	forbidden := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Problem details rendered by the handler don't loop back to it
+		_ = NewDetail(http.StatusForbidden, "Nested").RenderAuto(w, r)
+	})
+	handlers := ErrorHandlers(func(status int) http.Handler {
+		if status == http.StatusForbidden {
+			return forbidden
+		}
+		return nil
+	})
+
+	t.Run("without handlers", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		zhtest.AssertFalse(t, ServeError(w, r, http.StatusForbidden))
+		zhtest.AssertEqual(t, r, WithoutErrorHandlers(r))
+	})
+
+	t.Run("status without handler", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r = r.WithContext(WithErrorHandlers(r.Context(), handlers))
+
+		zhtest.AssertFalse(t, ServeError(w, r, http.StatusNotFound))
+	})
+
+	t.Run("status with handler", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r = r.WithContext(WithErrorHandlers(r.Context(), handlers))
+
+		zhtest.AssertTrue(t, ServeError(w, r, http.StatusForbidden))
+		zhtest.AssertWith(t, w).
+			Status(http.StatusForbidden).
+			JSONPathEqual("detail", "Nested")
+	})
+}
+
+func TestDetail_RenderAuto_ErrorHandler(t *testing.T) {
+	handlers := ErrorHandlers(func(status int) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "custom page", status)
+		})
+	})
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r = r.WithContext(WithErrorHandlers(r.Context(), handlers))
+
+	err := NewDetail(http.StatusTooManyRequests, "Slow down").RenderAuto(w, r)
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertWith(t, w).
+		Status(http.StatusTooManyRequests).
+		BodyContains("custom page").
+		BodyNotContains("Slow down")
+}
@@ -0,0 +1,61 @@
+package problem
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+
+	"github.com/alexferl/zerohttp/httpx"
+)
+
+// htmlPage is the minimal error page written by RenderHTML.
+const htmlPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>%[1]s</title>
+</head>
+<body>
+<h1>%[1]s</h1>
+<p>%[2]s</p>
+</body>
+</html>
+`
+
+// RenderHTML writes the Detail as a minimal HTML error page showing the
+// status code, title and detail, for browsers navigating to an error.
+// Extensions are not shown.
+func (p *Detail) RenderHTML(w http.ResponseWriter) error {
+	w.Header().Set(httpx.HeaderContentType, httpx.MIMETextHTMLCharset)
+	w.WriteHeader(p.Status)
+
+	heading := strconv.Itoa(p.Status)
+	if p.Title != "" {
+		heading += " " + p.Title
+	}
+	_, err := fmt.Fprintf(w, htmlPage, html.EscapeString(heading), html.EscapeString(p.Detail))
+	return err
+}
+
+// AcceptsHTML checks if the client prefers an HTML response based on the
+// Accept header, as browsers do when navigating. Returns true only if
+// text/html or application/xhtml+xml is explicitly accepted with a higher
+// quality than JSON and XML, so that API clients sending "*/*" still get JSON.
+func AcceptsHTML(r *http.Request) bool {
+	accept := r.Header.Get(httpx.HeaderAccept)
+	if accept == "" {
+		return false
+	}
+
+	htmlQ, _ := parseAcceptQualityExact(accept, httpx.MIMETextHTML, httpx.MIMEApplicationXHTMLXML)
+	if htmlQ == 0 {
+		return false
+	}
+
+	jsonQ, _ := parseAcceptQualityExact(accept, httpx.MIMEApplicationJSON, httpx.MIMEApplicationProblemJSON)
+	vendorJSONQ, _ := parseAcceptVendorJSON(accept)
+	xmlQ, _ := parseAcceptQualityExact(accept, httpx.MIMEApplicationProblemXML, httpx.MIMEApplicationXML, httpx.MIMETextXML)
+
+	return htmlQ > max(jsonQ, vendorJSONQ, xmlQ)
+}
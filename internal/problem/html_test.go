@@ -0,0 +1,53 @@
+package problem
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestDetail_RenderHTML(t *testing.T) {
+	w := httptest.NewRecorder()
+	zhtest.AssertNoError(t, NewDetail(http.StatusNotFound, `No <b>"page"</b> here`).Set("code", "hidden").RenderHTML(w))
+
+	zhtest.AssertWith(t, w).
+		Status(http.StatusNotFound).
+		Header(httpx.HeaderContentType, httpx.MIMETextHTMLCharset).
+		BodyContains("<title>404 Not Found</title>").
+		BodyContains("<h1>404 Not Found</h1>").
+		BodyContains("<p>No &lt;b&gt;&#34;page&#34;&lt;/b&gt; here</p>").
+		BodyNotContains("hidden")
+}
+
+func TestAcceptsHTML(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"empty header", "", false},
+		{"text/html", "text/html", true},
+		{"xhtml", "application/xhtml+xml", true},
+		{"wildcard", "*/*", false},
+		{"json", "application/json", false},
+		{"browser accept header", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", true},
+		{"json preferred over html", "application/json, text/html;q=0.5", false},
+		{"equal quality defaults to json", "application/json, text/html", false},
+		{"vendor json preferred", "application/vnd.api+json, text/html;q=0.9", false},
+		{"xml preferred over html", "application/xml, text/html;q=0.5", false},
+		{"html refused", "text/html;q=0, */*", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(httpx.HeaderAccept, tt.header)
+			}
+			zhtest.AssertEqual(t, tt.want, AcceptsHTML(req))
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package problem
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/alexferl/zerohttp/httpx"
+)
+
+// xmlNamespace is the namespace of problem details in XML, per RFC 7807 Appendix A.
+const xmlNamespace = "urn:ietf:rfc:7807"
+
+// RenderXML writes the Detail as an application/problem+xml response.
+// Extensions are written as child elements; arrays are written as "i"
+// elements and objects as nested elements, per RFC 7807 Appendix A.
+func (p *Detail) RenderXML(w http.ResponseWriter) error {
+	w.Header().Set(httpx.HeaderContentType, httpx.MIMEApplicationProblemXML)
+	w.WriteHeader(p.Status)
+	return xml.NewEncoder(w).Encode(p)
+}
+
+// MarshalXML implements xml.Marshaler.
+func (p *Detail) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	root := xml.StartElement{
+		Name: xml.Name{Local: "problem"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: xmlNamespace}},
+	}
+	if err := e.EncodeToken(root); err != nil {
+		return err
+	}
+
+	if p.Type != "" {
+		if err := encodeXMLValue(e, "type", p.Type); err != nil {
+			return err
+		}
+	}
+	if err := encodeXMLValue(e, "title", p.Title); err != nil {
+		return err
+	}
+	if err := encodeXMLValue(e, "status", float64(p.Status)); err != nil {
+		return err
+	}
+	if p.Detail != "" {
+		if err := encodeXMLValue(e, "detail", p.Detail); err != nil {
+			return err
+		}
+	}
+	if p.Instance != "" {
+		if err := encodeXMLValue(e, "instance", p.Instance); err != nil {
+			return err
+		}
+	}
+
+	// Extensions are normalized through JSON so that structs are encoded
+	// with their JSON field names, as in the JSON representation
+	keys := make([]string, 0, len(p.Extensions))
+	for k := range p.Extensions {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		data, err := json.Marshal(p.Extensions[k])
+		if err != nil {
+			return err
+		}
+		var v any
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		if err := encodeXMLValue(e, k, v); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(root.End())
+}
+
+// encodeXMLValue writes v, a value decoded from JSON, as the element name.
+func encodeXMLValue(e *xml.Encoder, name string, v any) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case nil:
+	case string:
+		if err := e.EncodeToken(xml.CharData(v)); err != nil {
+			return err
+		}
+	case float64:
+		if err := e.EncodeToken(xml.CharData(strconv.FormatFloat(v, 'f', -1, 64))); err != nil {
+			return err
+		}
+	case bool:
+		if err := e.EncodeToken(xml.CharData(strconv.FormatBool(v))); err != nil {
+			return err
+		}
+	case []any:
+		for _, item := range v {
+			if err := encodeXMLValue(e, "i", item); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			if err := encodeXMLValue(e, k, v[k]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("problem: unsupported XML value of type %T", v)
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// AcceptsXML checks if the client prefers an XML response based on the Accept
+// header. Returns true only if application/problem+xml, application/xml or
+// text/xml is explicitly accepted with a higher quality than both JSON and
+// HTML, so that JSON remains the default and browsers, which accept XML at a
+// lower quality than HTML, are unaffected.
+func AcceptsXML(r *http.Request) bool {
+	accept := r.Header.Get(httpx.HeaderAccept)
+	if accept == "" {
+		return false
+	}
+
+	xmlQ, _ := parseAcceptQualityExact(accept, httpx.MIMEApplicationProblemXML, httpx.MIMEApplicationXML, httpx.MIMETextXML)
+	if xmlQ == 0 {
+		return false
+	}
+
+	jsonQ, _ := parseAcceptQualityExact(accept, httpx.MIMEApplicationJSON, httpx.MIMEApplicationProblemJSON)
+	vendorJSONQ, _ := parseAcceptVendorJSON(accept)
+	htmlQ, _ := parseAcceptQualityExact(accept, httpx.MIMETextHTML)
+
+	return xmlQ > max(jsonQ, vendorJSONQ, htmlQ)
+}
@@ -0,0 +1,87 @@
+package problem
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestDetail_RenderXML(t *testing.T) {
+	detail := NewValidationDetail("Validation failed", []ValidationError{
+		{Detail: "must be positive", Pointer: "#/age"},
+		{Detail: "is required", Field: "name"},
+	})
+	detail.Type = "https://example.com/probs/validation"
+	detail.Set("balance", 30).Set("accounts", []string{"/account/12345", "/account/67890"})
+
+	w := httptest.NewRecorder()
+	zhtest.AssertNoError(t, detail.RenderXML(w))
+
+	zhtest.AssertWith(t, w).
+		Status(http.StatusUnprocessableEntity).
+		Header(httpx.HeaderContentType, httpx.MIMEApplicationProblemXML).
+		Body(`<problem xmlns="urn:ietf:rfc:7807">` +
+			`<type>https://example.com/probs/validation</type>` +
+			`<title>Unprocessable Entity</title>` +
+			`<status>422</status>` +
+			`<detail>Validation failed</detail>` +
+			`<accounts><i>/account/12345</i><i>/account/67890</i></accounts>` +
+			`<balance>30</balance>` +
+			`<errors><i><detail>must be positive</detail><pointer>#/age</pointer></i>` +
+			`<i><detail>is required</detail><field>name</field></i></errors>` +
+			`</problem>`)
+}
+
+func TestDetail_RenderXML_Escaping(t *testing.T) {
+	w := httptest.NewRecorder()
+	zhtest.AssertNoError(t, NewDetail(http.StatusBadRequest, `a < b & "c"`).RenderXML(w))
+
+	zhtest.AssertWith(t, w).BodyContains(`<detail>a &lt; b &amp; &#34;c&#34;</detail>`)
+}
+
+func TestDetail_RenderAuto_XML(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(httpx.HeaderAccept, httpx.MIMEApplicationProblemXML)
+	w := httptest.NewRecorder()
+
+	zhtest.AssertNoError(t, NewDetail(http.StatusNotFound, "User not found").RenderAuto(w, r))
+
+	zhtest.AssertWith(t, w).
+		Status(http.StatusNotFound).
+		Header(httpx.HeaderContentType, httpx.MIMEApplicationProblemXML).
+		BodyContains("<detail>User not found</detail>")
+}
+
+func TestAcceptsXML(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"empty header", "", false},
+		{"application/problem+xml", "application/problem+xml", true},
+		{"application/xml", "application/xml", true},
+		{"text/xml", "text/xml", true},
+		{"wildcard", "*/*", false},
+		{"json", "application/json", false},
+		{"xml preferred over json", "application/xml, application/json;q=0.5", true},
+		{"json preferred over xml", "application/json, application/xml;q=0.5", false},
+		{"equal quality defaults to json", "application/json, application/xml", false},
+		{"vendor json preferred", "application/vnd.api+json, application/xml;q=0.9", false},
+		{"browser accept header", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", false},
+		{"xml refused", "application/xml;q=0, */*", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(httpx.HeaderAccept, tt.header)
+			}
+			zhtest.AssertEqual(t, tt.want, AcceptsXML(req))
+		})
+	}
+}
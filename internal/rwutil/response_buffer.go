@@ -44,13 +44,35 @@ func (b *ResponseBuffer) Reset(w http.ResponseWriter) {
 // WriteHeader captures the status code.
 // Does NOT forward to underlying writer - caller decides when to commit.
 func (b *ResponseBuffer) WriteHeader(status int) {
-	if b.HasWritten {
+	// Interim responses can't be sent ahead of a buffered final response
+	if b.HasWritten || IsInformational(status) {
 		return
 	}
 	b.HasWritten = true
 	b.Status = status
 }
 
+// dropsInterim marks the writers embedding a ResponseBuffer as dropping
+// interim responses. See [DropsInterim].
+func (b *ResponseBuffer) dropsInterim() {}
+
+// DropsInterim reports whether interim 1xx responses written to w are
+// dropped on their way to the client by a writer embedding a
+// [ResponseBuffer]. Writers are unwrapped with their Unwrap method, like
+// http.ResponseController does.
+func DropsInterim(w http.ResponseWriter) bool {
+	for {
+		if _, ok := w.(interface{ dropsInterim() }); ok {
+			return true
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return false
+		}
+		w = u.Unwrap()
+	}
+}
+
 // CommitHeader writes the status code to the underlying ResponseWriter.
 // Caller should set response headers before calling this.
 func (b *ResponseBuffer) CommitHeader() {
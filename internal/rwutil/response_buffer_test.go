@@ -56,6 +56,17 @@ func TestResponseBuffer_BasicWrite(t *testing.T) {
 	})
 }
 
+func TestResponseBuffer_InformationalDropped(t *testing.T) {
+	rec := httptest.NewRecorder()
+	buf := NewResponseBuffer(rec, 1024)
+
+	buf.WriteHeader(http.StatusEarlyHints)
+	zhtest.AssertFalse(t, buf.HasWritten)
+
+	buf.WriteHeader(http.StatusAccepted)
+	zhtest.AssertEqual(t, http.StatusAccepted, buf.Status)
+}
+
 func TestResponseBuffer_Commit(t *testing.T) {
 	t.Run("Commit writes buffered data", func(t *testing.T) {
 		rec := httptest.NewRecorder()
@@ -357,3 +368,22 @@ func BenchmarkResponseBuffer_Write(b *testing.B) {
 		}
 	})
 }
+
+// unwrapWriter is a pass-through writer exposing the writer it wraps.
+type unwrapWriter struct {
+	http.ResponseWriter
+}
+
+func (u *unwrapWriter) Unwrap() http.ResponseWriter {
+	return u.ResponseWriter
+}
+
+func TestDropsInterim(t *testing.T) {
+	rec := httptest.NewRecorder()
+	zhtest.AssertFalse(t, DropsInterim(rec))
+	zhtest.AssertFalse(t, DropsInterim(&unwrapWriter{rec}))
+
+	embedding := struct{ *ResponseBuffer }{NewResponseBuffer(rec, 0)}
+	zhtest.AssertTrue(t, DropsInterim(embedding))
+	zhtest.AssertTrue(t, DropsInterim(&unwrapWriter{embedding}))
+}
@@ -2,13 +2,14 @@ package rwutil
 
 import "net/http"
 
-// ResponseWriter wraps http.ResponseWriter to capture status code and
-// track whether the header has been written. This is a reusable component
+// ResponseWriter wraps http.ResponseWriter to capture status code, count
+// the bytes written and track whether the header has been written. This is a reusable component
 // used by multiple middlewares instead of duplicating the same code.
 type ResponseWriter struct {
 	http.ResponseWriter
 	statusCode    int
 	headerWritten bool
+	bytesWritten  int64
 }
 
 // NewResponseWriter creates a new ResponseWriter with default status code 200.
@@ -19,12 +20,24 @@ func NewResponseWriter(w http.ResponseWriter) *ResponseWriter {
 	}
 }
 
+// IsInformational reports whether code is an interim 1xx response, such as
+// 103 Early Hints, that is followed by the final response. 101 Switching
+// Protocols is final, since no other response follows it.
+func IsInformational(code int) bool {
+	return code >= 100 && code < 200 && code != http.StatusSwitchingProtocols
+}
+
 // WriteHeader captures the status code and forwards to the underlying ResponseWriter.
-// It ensures the header is only written once.
+// It ensures the header is only written once. Informational responses are
+// forwarded without being captured.
 func (rw *ResponseWriter) WriteHeader(code int) {
 	if rw.headerWritten {
 		return // Prevent multiple WriteHeader calls
 	}
+	if IsInformational(code) {
+		rw.ResponseWriter.WriteHeader(code)
+		return
+	}
 	rw.statusCode = code
 	rw.headerWritten = true
 	// WriteHeader does not return an error per http.ResponseWriter interface.
@@ -38,7 +51,9 @@ func (rw *ResponseWriter) Write(data []byte) (int, error) {
 	if !rw.headerWritten {
 		rw.WriteHeader(http.StatusOK)
 	}
-	return rw.ResponseWriter.Write(data)
+	n, err := rw.ResponseWriter.Write(data)
+	rw.bytesWritten += int64(n)
+	return n, err
 }
 
 // StatusCode returns the captured status code.
@@ -47,6 +62,11 @@ func (rw *ResponseWriter) StatusCode() int {
 	return rw.statusCode
 }
 
+// BytesWritten returns the number of body bytes written so far.
+func (rw *ResponseWriter) BytesWritten() int64 {
+	return rw.bytesWritten
+}
+
 // HeaderWritten returns true if WriteHeader has been called.
 func (rw *ResponseWriter) HeaderWritten() bool {
 	return rw.headerWritten
@@ -49,6 +49,35 @@ func TestResponseWriter_WriteHeader_MultipleCalls(t *testing.T) {
 	zhtest.AssertEqual(t, http.StatusNotFound, rec.Code)
 }
 
+func TestIsInformational(t *testing.T) {
+	zhtest.AssertTrue(t, IsInformational(http.StatusContinue))
+	zhtest.AssertTrue(t, IsInformational(http.StatusEarlyHints))
+	zhtest.AssertFalse(t, IsInformational(http.StatusSwitchingProtocols))
+	zhtest.AssertFalse(t, IsInformational(http.StatusOK))
+}
+
+func TestResponseWriter_WriteHeader_Informational(t *testing.T) {
+	var codes []int
+	rw := NewResponseWriter(&statusLogWriter{ResponseWriter: httptest.NewRecorder(), codes: &codes})
+
+	rw.WriteHeader(http.StatusEarlyHints)
+	zhtest.AssertFalse(t, rw.HeaderWritten())
+
+	rw.WriteHeader(http.StatusCreated)
+	zhtest.AssertEqual(t, http.StatusCreated, rw.StatusCode())
+	zhtest.AssertEqual(t, []int{http.StatusEarlyHints, http.StatusCreated}, codes)
+}
+
+// statusLogWriter records every status code written, including interim ones.
+type statusLogWriter struct {
+	http.ResponseWriter
+	codes *[]int
+}
+
+func (w *statusLogWriter) WriteHeader(code int) {
+	*w.codes = append(*w.codes, code)
+}
+
 func TestResponseWriter_Write(t *testing.T) {
 	rec := httptest.NewRecorder()
 	rw := NewResponseWriter(rec)
@@ -75,6 +104,18 @@ func TestResponseWriter_Write_WithHeader(t *testing.T) {
 	zhtest.AssertEqual(t, http.StatusCreated, rw.StatusCode())
 }
 
+func TestResponseWriter_BytesWritten(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rw := NewResponseWriter(rec)
+
+	zhtest.AssertEqual(t, int64(0), rw.BytesWritten())
+
+	_, _ = rw.Write([]byte("hello "))
+	_, _ = rw.Write([]byte("world"))
+
+	zhtest.AssertEqual(t, int64(11), rw.BytesWritten())
+}
+
 func TestResponseWriter_Header(t *testing.T) {
 	rec := httptest.NewRecorder()
 	rw := NewResponseWriter(rec)
@@ -0,0 +1,261 @@
+package zerohttp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemFS is an in-memory fs.FS for assets generated at runtime, such as bundles
+// built at startup, that can be served with StaticFS without writing them to disk.
+//
+// Every file records when it was last set and a hash of its content, which
+// StaticFS serves as the Last-Modified and ETag headers so clients can
+// revalidate cached copies. A gzip-compressed copy of each file is kept
+// alongside it as "<name>.gz" when compression makes it smaller, and
+// StaticFS serves it to clients that accept gzip.
+//
+// MemFS is safe for concurrent use. Files are read-only once set; Set
+// replaces a file as a whole.
+type MemFS struct {
+	mu    sync.RWMutex
+	files map[string]*memFileData
+}
+
+// memFileData is the content and metadata of a file in a MemFS.
+type memFileData struct {
+	data    []byte
+	modTime time.Time
+	etag    string
+}
+
+// NewMemFS creates an in-memory filesystem from a map of slash-separated
+// paths to file contents, such as "index.html" or "assets/app.js".
+// Panics if a path is not a valid fs.FS path.
+func NewMemFS(files map[string][]byte) *MemFS {
+	m := &MemFS{files: make(map[string]*memFileData, len(files)*2)}
+	for name, data := range files {
+		m.Set(name, data)
+	}
+	return m
+}
+
+// Set adds or replaces the file at name, updating its modification time,
+// ETag and compressed copy. The data must not be modified afterwards.
+// Panics if name is not a valid fs.FS path.
+func (m *MemFS) Set(name string, data []byte) {
+	if !fs.ValidPath(name) || name == "." {
+		panic("zerohttp: invalid MemFS path: " + name)
+	}
+
+	now := time.Now()
+	files := map[string]*memFileData{name: newMemFileData(data, now)}
+	if !isPrecompressed(name) {
+		if compressed, ok := gzipSmaller(data); ok {
+			files[name+".gz"] = newMemFileData(compressed, now)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.files, name+".gz")
+	for n, f := range files {
+		m.files[n] = f
+	}
+}
+
+// Remove removes the file at name and its compressed copy.
+func (m *MemFS) Remove(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.files, name)
+	delete(m.files, name+".gz")
+}
+
+// Open implements fs.FS.
+func (m *MemFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if f, ok := m.files[name]; ok {
+		return &memFile{
+			Reader: bytes.NewReader(f.data),
+			info:   memFileInfo{name: path.Base(name), data: f},
+		}, nil
+	}
+
+	entries := m.readDirLocked(name)
+	if entries == nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &memDir{name: path.Base(name), entries: entries}, nil
+}
+
+// ReadFile implements fs.ReadFileFS.
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrInvalid}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	f, ok := m.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrNotExist}
+	}
+	return slices.Clone(f.data), nil
+}
+
+// readDirLocked returns the sorted entries of the directory name, or nil if
+// no file lives under it. The root always exists.
+// Must be called with the lock held.
+func (m *MemFS) readDirLocked(name string) []fs.DirEntry {
+	prefix := ""
+	if name != "." {
+		prefix = name + "/"
+	}
+
+	seen := make(map[string]bool)
+	entries := []fs.DirEntry{}
+	for fileName, f := range m.files {
+		rest, ok := strings.CutPrefix(fileName, prefix)
+		if !ok {
+			continue
+		}
+
+		child, _, isDir := strings.Cut(rest, "/")
+		if seen[child] {
+			continue
+		}
+		seen[child] = true
+
+		if isDir {
+			entries = append(entries, fs.FileInfoToDirEntry(memDirInfo{name: child}))
+		} else {
+			entries = append(entries, fs.FileInfoToDirEntry(memFileInfo{name: child, data: f}))
+		}
+	}
+
+	if len(entries) == 0 && name != "." {
+		return nil
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries
+}
+
+func newMemFileData(data []byte, modTime time.Time) *memFileData {
+	sum := sha256.Sum256(data)
+	return &memFileData{
+		data:    data,
+		modTime: modTime,
+		etag:    `"` + hex.EncodeToString(sum[:16]) + `"`,
+	}
+}
+
+// gzipSmaller compresses data and reports whether the result is smaller.
+func gzipSmaller(data []byte) ([]byte, bool) {
+	var buf bytes.Buffer
+	gz, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	_, _ = gz.Write(data)
+	_ = gz.Close()
+
+	if buf.Len() >= len(data) {
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
+
+// memFile is an open regular file in a MemFS.
+type memFile struct {
+	*bytes.Reader
+	info memFileInfo
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Close() error               { return nil }
+
+// memFileInfo describes a regular file in a MemFS.
+type memFileInfo struct {
+	name string
+	data *memFileData
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return int64(len(i.data.data)) }
+func (i memFileInfo) Mode() fs.FileMode  { return 0o444 }
+func (i memFileInfo) ModTime() time.Time { return i.data.modTime }
+func (i memFileInfo) IsDir() bool        { return false }
+func (i memFileInfo) Sys() any           { return nil }
+
+// ETag returns the quoted content hash of the file.
+func (i memFileInfo) ETag() string { return i.data.etag }
+
+// memDir is an open directory in a MemFS.
+type memDir struct {
+	name    string
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return memDirInfo{name: d.name}, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+// ReadDir implements fs.ReadDirFile.
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+
+	n = min(n, len(remaining))
+	d.offset += n
+	return remaining[:n], nil
+}
+
+// memDirInfo describes a directory in a MemFS.
+type memDirInfo struct {
+	name string
+}
+
+func (i memDirInfo) Name() string       { return i.name }
+func (i memDirInfo) Size() int64        { return 0 }
+func (i memDirInfo) Mode() fs.FileMode  { return fs.ModeDir | 0o555 }
+func (i memDirInfo) ModTime() time.Time { return time.Time{} }
+func (i memDirInfo) IsDir() bool        { return true }
+func (i memDirInfo) Sys() any           { return nil }
+
+// Ensure interface compliance at compile time.
+var (
+	_ fs.ReadFileFS  = (*MemFS)(nil)
+	_ fs.ReadDirFile = (*memDir)(nil)
+	_ io.ReadSeeker  = (*memFile)(nil)
+	_ io.ReaderAt    = (*memFile)(nil)
+	_ fs.FileInfo    = memFileInfo{}
+	_ fs.FileInfo    = memDirInfo{}
+)
@@ -0,0 +1,210 @@
+package zerohttp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestMemFS(t *testing.T) {
+	appJS := []byte(strings.Repeat("console.log('hello');\n", 50))
+	m := NewMemFS(map[string][]byte{
+		"index.html":        []byte("<!DOCTYPE html><title>app</title>"),
+		"assets/app.js":     appJS,
+		"assets/img/a.png":  {0x89, 'P', 'N', 'G'},
+		"assets/styles.css": []byte("body{}"),
+	})
+
+	zhtest.AssertNoError(t, fstest.TestFS(m, "index.html", "assets/app.js", "assets/app.js.gz", "assets/img/a.png"))
+
+	data, err := fs.ReadFile(m, "assets/app.js")
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertEqual(t, appJS, data)
+
+	// Compressed copies are only kept when they are smaller
+	gz, err := fs.ReadFile(m, "assets/app.js.gz")
+	zhtest.AssertNoError(t, err)
+	zr, err := gzip.NewReader(bytes.NewReader(gz))
+	zhtest.AssertNoError(t, err)
+	decompressed, err := io.ReadAll(zr)
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertEqual(t, appJS, decompressed)
+
+	_, err = fs.Stat(m, "assets/img/a.png.gz")
+	zhtest.AssertErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = m.Open("missing.txt")
+	zhtest.AssertErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = m.Open("../index.html")
+	zhtest.AssertErrorIs(t, err, fs.ErrInvalid)
+}
+
+func TestMemFS_SetAndRemove(t *testing.T) {
+	m := NewMemFS(nil)
+
+	entries, err := fs.ReadDir(m, ".")
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertEqual(t, 0, len(entries))
+
+	m.Set("app.js", []byte(strings.Repeat("a", 1000)))
+	stat, err := fs.Stat(m, "app.js")
+	zhtest.AssertNoError(t, err)
+	etag1 := stat.(etagger).ETag()
+	_, err = fs.Stat(m, "app.js.gz")
+	zhtest.AssertNoError(t, err)
+
+	// Replacing the content changes the ETag and drops a stale compressed copy
+	m.Set("app.js", []byte("b"))
+	stat, err = fs.Stat(m, "app.js")
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertNotEqual(t, etag1, stat.(etagger).ETag())
+	zhtest.AssertTrue(t, strings.HasPrefix(stat.(etagger).ETag(), `"`))
+	_, err = fs.Stat(m, "app.js.gz")
+	zhtest.AssertErrorIs(t, err, fs.ErrNotExist)
+
+	// The same content has the same ETag
+	m.Set("copy.js", []byte("b"))
+	copyStat, err := fs.Stat(m, "copy.js")
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertEqual(t, stat.(etagger).ETag(), copyStat.(etagger).ETag())
+
+	m.Remove("app.js")
+	_, err = fs.Stat(m, "app.js")
+	zhtest.AssertErrorIs(t, err, fs.ErrNotExist)
+
+	zhtest.AssertPanic(t, func() { m.Set("/abs.js", nil) })
+	zhtest.AssertPanic(t, func() { m.Set(".", nil) })
+}
+
+func TestRouter_StaticFS(t *testing.T) {
+	appJS := []byte(strings.Repeat("console.log('hello');\n", 50))
+	m := NewMemFS(map[string][]byte{
+		"index.html":    []byte("<!DOCTYPE html><title>app</title>"),
+		"assets/app.js": appJS,
+	})
+
+	t.Run("serves files with ETag and Last-Modified", func(t *testing.T) {
+		router := NewRouter()
+		router.StaticFS(m, false)
+
+		req := httptest.NewRequest(http.MethodGet, "/assets/app.js", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		zhtest.AssertWith(t, w).
+			Status(http.StatusOK).
+			Body(string(appJS)).
+			HeaderContains(httpx.HeaderContentType, "javascript").
+			HeaderExists(httpx.HeaderETag).
+			HeaderExists(httpx.HeaderLastModified).
+			Header(httpx.HeaderVary, httpx.HeaderAcceptEncoding).
+			HeaderNotExists(httpx.HeaderContentEncoding)
+
+		// Revalidation with the ETag is answered with 304
+		req = httptest.NewRequest(http.MethodGet, "/assets/app.js", nil)
+		req.Header.Set(httpx.HeaderIfNoneMatch, w.Header().Get(httpx.HeaderETag))
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		zhtest.AssertWith(t, w).Status(http.StatusNotModified)
+	})
+
+	t.Run("serves gzip to clients that accept it", func(t *testing.T) {
+		router := NewRouter()
+		router.StaticFS(m, false)
+
+		req := httptest.NewRequest(http.MethodGet, "/assets/app.js", nil)
+		req.Header.Set(httpx.HeaderAcceptEncoding, "br, gzip")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		zhtest.AssertWith(t, w).
+			Status(http.StatusOK).
+			Header(httpx.HeaderContentEncoding, "gzip").
+			HeaderContains(httpx.HeaderContentType, "javascript").
+			Header(httpx.HeaderVary, httpx.HeaderAcceptEncoding).
+			HeaderExists(httpx.HeaderETag)
+
+		zr, err := gzip.NewReader(w.Body)
+		zhtest.AssertNoError(t, err)
+		body, err := io.ReadAll(zr)
+		zhtest.AssertNoError(t, err)
+		zhtest.AssertEqual(t, appJS, body)
+
+		// The compressed representation has its own ETag
+		plain := httptest.NewRecorder()
+		router.ServeHTTP(plain, httptest.NewRequest(http.MethodGet, "/assets/app.js", nil))
+		zhtest.AssertNotEqual(t, plain.Header().Get(httpx.HeaderETag), w.Header().Get(httpx.HeaderETag))
+
+		// gzip;q=0 is not acceptable
+		req = httptest.NewRequest(http.MethodGet, "/assets/app.js", nil)
+		req.Header.Set(httpx.HeaderAcceptEncoding, "gzip;q=0")
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		zhtest.AssertWith(t, w).
+			Status(http.StatusOK).
+			HeaderNotExists(httpx.HeaderContentEncoding).
+			Body(string(appJS))
+	})
+
+	t.Run("fallback serves index.html", func(t *testing.T) {
+		router := NewRouter()
+		router.StaticFS(m, true, "/api/")
+
+		for _, p := range []string{"/", "/dashboard"} {
+			req := httptest.NewRequest(http.MethodGet, p, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			zhtest.AssertWith(t, w).
+				Status(http.StatusOK).
+				BodyContains("<!DOCTYPE html>").
+				HeaderExists(httpx.HeaderETag)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		zhtest.AssertWith(t, w).Status(http.StatusNotFound)
+	})
+
+	t.Run("updated files are served immediately", func(t *testing.T) {
+		m := NewMemFS(map[string][]byte{"version.txt": []byte("v1")})
+		router := NewRouter()
+		router.StaticFS(m, false)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version.txt", nil))
+		zhtest.AssertWith(t, w).Status(http.StatusOK).Body("v1")
+		etag := w.Header().Get(httpx.HeaderETag)
+
+		m.Set("version.txt", []byte("v2"))
+
+		req := httptest.NewRequest(http.MethodGet, "/version.txt", nil)
+		req.Header.Set(httpx.HeaderIfNoneMatch, etag)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		zhtest.AssertWith(t, w).Status(http.StatusOK).Body("v2")
+	})
+
+	t.Run("panics on GET / conflict", func(t *testing.T) {
+		router := NewRouter()
+		router.GET("/", testHandler("root"))
+
+		zhtest.AssertPanic(t, func() {
+			router.StaticFS(m, false)
+		})
+	})
+}
@@ -67,8 +67,21 @@
 //   - http_requests_total - Total requests by method, status, path
 //   - http_request_duration_seconds - Request latency distribution
 //   - http_request_size_bytes - Request body size distribution
-//   - http_response_size_bytes - Response body size distribution
+//   - http_response_size_bytes - Response body size distribution, as sent on the wire
+//   - http_response_uncompressed_size_bytes - Response body size distribution before compression
 //   - http_requests_in_flight - Currently processing requests
 //
+// Request sizes use Content-Length, or count the bytes read by the handler for
+// bodies of unknown length. When the compress middleware runs inside the
+// metrics middleware, it reports the uncompressed size with
+// [RecordUncompressedResponseSize]; otherwise both response size metrics
+// are the same. The size metrics use SizeBuckets:
+//
+//	app := zh.New(zh.Config{
+//	    Metrics: metrics.Config{
+//	        SizeBuckets: []float64{512, 4096, 65536, 1048576},
+//	    },
+//	})
+//
 // [Prometheus]: https://prometheus.io/
 package metrics
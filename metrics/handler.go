@@ -20,7 +20,7 @@ func Handler(reg Registry) http.Handler {
 
 		families := reg.Gather()
 
-		w.Header().Set(httpx.HeaderContentType, "text/plain; charset=utf-8")
+		w.Header().Set(httpx.HeaderContentType, httpx.MIMETextPlainCharset)
 		w.WriteHeader(http.StatusOK)
 
 		for _, family := range families {
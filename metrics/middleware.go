@@ -1,13 +1,17 @@
 package metrics
 
 import (
+	"context"
+	"io"
 	"net/http"
 	"sort"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	zconfig "github.com/alexferl/zerohttp/internal/config"
+	"github.com/alexferl/zerohttp/internal/rwutil"
 )
 
 // responseWriter wraps http.ResponseWriter to capture status and size.
@@ -18,7 +22,7 @@ type responseWriter struct {
 }
 
 func (w *responseWriter) WriteHeader(code int) {
-	if w.statusCode == 0 {
+	if w.statusCode == 0 && !rwutil.IsInformational(code) {
 		w.statusCode = code
 	}
 	w.ResponseWriter.WriteHeader(code)
@@ -40,6 +44,39 @@ func (w *responseWriter) Flush() {
 	}
 }
 
+// countingBody wraps a request body of unknown length to count the bytes read.
+type countingBody struct {
+	io.ReadCloser
+	n int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+// uncompressedSizeKey is the context key for the uncompressed response size.
+type uncompressedSizeKey struct{}
+
+// uncompressedSize holds the response size reported by compression middleware.
+type uncompressedSize struct {
+	n        atomic.Int64
+	recorded atomic.Bool
+}
+
+// RecordUncompressedResponseSize reports the size in bytes of the response
+// body written by the handler, before compression. Compression middleware
+// calls it so that the metrics middleware can observe the uncompressed size
+// alongside the size on the wire. It does nothing if the request isn't
+// handled by the metrics middleware.
+func RecordUncompressedResponseSize(ctx context.Context, n int64) {
+	if s, ok := ctx.Value(uncompressedSizeKey{}).(*uncompressedSize); ok {
+		s.n.Store(n)
+		s.recorded.Store(true)
+	}
+}
+
 // labelSet holds pre-allocated label slices to avoid allocations per request.
 type labelSet struct {
 	inFlight  []string
@@ -54,7 +91,10 @@ type Middleware struct {
 	RequestDur   Histogram
 	RequestSize  Histogram
 	ResponseSize Histogram
-	InFlight     Gauge
+	// UncompressedResponseSize observes the response size before compression.
+	// It equals ResponseSize for responses that weren't compressed.
+	UncompressedResponseSize Histogram
+	InFlight                 Gauge
 
 	DurationBuckets []float64
 	SizeBuckets     []float64
@@ -104,7 +144,9 @@ func NewMiddleware(reg Registry, cfg ...Config) func(http.Handler) http.Handler
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Add registry to context so other middleware can access it
-			r = r.WithContext(WithRegistry(r.Context(), reg))
+			sizes := &uncompressedSize{}
+			ctx := WithRegistry(r.Context(), reg)
+			r = r.WithContext(context.WithValue(ctx, uncompressedSizeKey{}, sizes))
 
 			if _, excluded := mm.ExcludedPaths[r.URL.Path]; excluded {
 				next.ServeHTTP(w, r)
@@ -132,6 +174,33 @@ func NewMiddleware(reg Registry, cfg ...Config) func(http.Handler) http.Handler
 				statusCode:     0,
 			}
 
+			// Count bodies of unknown length, such as chunked uploads, as they are read
+			var body *countingBody
+			if r.ContentLength < 0 && r.Body != nil && r.Body != http.NoBody {
+				body = &countingBody{ReadCloser: r.Body}
+				r.Body = body
+			}
+			observeSizes := func() {
+				requestSize := r.ContentLength
+				if body != nil {
+					requestSize = body.n
+				}
+				if requestSize > 0 {
+					mm.RequestSize.WithLabelValues(labels.requestSz...).Observe(float64(requestSize))
+				}
+				if wrapped.size > 0 {
+					mm.ResponseSize.WithLabelValues(labels.request...).Observe(float64(wrapped.size))
+				}
+
+				uncompressed := wrapped.size
+				if sizes.recorded.Load() {
+					uncompressed = sizes.n.Load()
+				}
+				if uncompressed > 0 {
+					mm.UncompressedResponseSize.WithLabelValues(labels.request...).Observe(float64(uncompressed))
+				}
+			}
+
 			start := time.Now()
 
 			// Record metrics in defer to ensure they are captured even on panic.
@@ -152,12 +221,7 @@ func NewMiddleware(reg Registry, cfg ...Config) func(http.Handler) http.Handler
 					mm.Requests.WithLabelValues(labels.request...).Inc()
 					mm.RequestDur.WithLabelValues(labels.request...).Observe(duration)
 
-					if r.ContentLength > 0 {
-						mm.RequestSize.WithLabelValues(labels.requestSz...).Observe(float64(r.ContentLength))
-					}
-					if wrapped.size > 0 {
-						mm.ResponseSize.WithLabelValues(labels.request...).Observe(float64(wrapped.size))
-					}
+					observeSizes()
 
 					// Re-panic so Recover middleware can handle it properly
 					panic(rvr)
@@ -176,12 +240,7 @@ func NewMiddleware(reg Registry, cfg ...Config) func(http.Handler) http.Handler
 				mm.Requests.WithLabelValues(labels.request...).Inc()
 				mm.RequestDur.WithLabelValues(labels.request...).Observe(duration)
 
-				if r.ContentLength > 0 {
-					mm.RequestSize.WithLabelValues(labels.requestSz...).Observe(float64(r.ContentLength))
-				}
-				if wrapped.size > 0 {
-					mm.ResponseSize.WithLabelValues(labels.request...).Observe(float64(wrapped.size))
-				}
+				observeSizes()
 			}()
 
 			next.ServeHTTP(wrapped, r)
@@ -236,6 +295,7 @@ func (mm *Middleware) initMetrics(reg Registry, customLabelKeys []string) {
 	mm.RequestDur = reg.Histogram("http_request_duration_seconds", mm.DurationBuckets, requestLabels...)
 	mm.RequestSize = reg.Histogram("http_request_size_bytes", mm.SizeBuckets, sizeLabels...)
 	mm.ResponseSize = reg.Histogram("http_response_size_bytes", mm.SizeBuckets, requestLabels...)
+	mm.UncompressedResponseSize = reg.Histogram("http_response_uncompressed_size_bytes", mm.SizeBuckets, requestLabels...)
 	mm.InFlight = reg.Gauge("http_requests_in_flight", inFlightLabels...)
 }
 
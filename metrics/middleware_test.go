@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -288,6 +289,69 @@ func TestMiddleware_ResponseSize(t *testing.T) {
 	zhtest.AssertGreater(t, len(responseSizeHist.Metrics), 0)
 }
 
+// histogramSum returns the sum and count of the first series of the named histogram.
+func histogramSum(t *testing.T, reg Registry, name string) (float64, uint64) {
+	t.Helper()
+	for _, f := range reg.Gather() {
+		if f.Name == name && len(f.Metrics) > 0 {
+			return f.Metrics[0].Histogram.Sum, f.Metrics[0].Histogram.Count
+		}
+	}
+	t.Fatalf("histogram %s not found", name)
+	return 0, 0
+}
+
+func TestMiddleware_RequestSizeUnknownLength(t *testing.T) {
+	reg := NewRegistry()
+	wrapped := NewMiddleware(reg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// Chunked uploads have no Content-Length, so the bytes read are counted
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(strings.Repeat("x", 250)))
+	req.ContentLength = -1
+	wrapped.ServeHTTP(httptest.NewRecorder(), req)
+
+	sum, count := histogramSum(t, reg, "http_request_size_bytes")
+	zhtest.AssertEqual(t, float64(250), sum)
+	zhtest.AssertEqual(t, uint64(1), count)
+}
+
+func TestMiddleware_UncompressedResponseSize(t *testing.T) {
+	t.Run("defaults to the wire size", func(t *testing.T) {
+		reg := NewRegistry()
+		wrapped := NewMiddleware(reg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("hello world"))
+		}))
+		wrapped.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+		sum, _ := histogramSum(t, reg, "http_response_size_bytes")
+		zhtest.AssertEqual(t, float64(11), sum)
+		sum, _ = histogramSum(t, reg, "http_response_uncompressed_size_bytes")
+		zhtest.AssertEqual(t, float64(11), sum)
+	})
+
+	t.Run("reported by inner middleware", func(t *testing.T) {
+		reg := NewRegistry()
+		wrapped := NewMiddleware(reg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("small"))
+			RecordUncompressedResponseSize(r.Context(), 5000)
+		}))
+		wrapped.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+		sum, _ := histogramSum(t, reg, "http_response_size_bytes")
+		zhtest.AssertEqual(t, float64(5), sum)
+		sum, _ = histogramSum(t, reg, "http_response_uncompressed_size_bytes")
+		zhtest.AssertEqual(t, float64(5000), sum)
+	})
+
+	t.Run("no-op without middleware", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		RecordUncompressedResponseSize(req.Context(), 100)
+	})
+}
+
 func TestMiddleware_InFlightGauge(t *testing.T) {
 	reg := NewRegistry()
 	cfg := Config{
@@ -1,6 +1,9 @@
 package zerohttp
 
 import (
+	"net/http"
+	"slices"
+
 	"github.com/alexferl/zerohttp/log"
 	"github.com/alexferl/zerohttp/middleware/recover"
 	"github.com/alexferl/zerohttp/middleware/requestbodysize"
@@ -11,21 +14,76 @@ import (
 
 // DefaultMiddlewares returns the default set of middlewares with the provided configuration.
 // The returned middlewares are applied in the following order:
-//   - RequestID: Assigns a unique request ID to each request
+//   - RequestID: Assigns a unique request ID to each request. It runs first so
+//     the request ID is in the request context for all the other middlewares
 //   - Recover: Recovers from panics and logs errors
 //   - RequestBodySize: Limits the maximum request body size
 //   - SecurityHeaders: Adds security-related HTTP headers
 //   - RequestLogger: Logs HTTP requests and responses
+//
+// Each is [Named] with its package name ("requestid", "recover",
+// "requestbodysize", "securityheaders" and "requestlogger"), so a route can
+// opt out of it with [Without].
 func DefaultMiddlewares(cfg Config, logger log.Logger) []MiddlewareFunc {
 	// Sync RequestID header configuration with Recover config
 	recoverConfig := cfg.Recover
 	recoverConfig.RequestIDHeader = cfg.RequestID.Header
 
 	return []MiddlewareFunc{
-		requestid.New(cfg.RequestID),
-		recover.New(logger, recoverConfig),
-		requestbodysize.New(cfg.RequestBodySize),
-		securityheaders.New(cfg.SecurityHeaders),
-		requestlogger.New(logger, cfg.RequestLogger),
+		Named("requestid", requestid.New(cfg.RequestID)),
+		Named("recover", recover.New(logger, recoverConfig)),
+		Named("requestbodysize", requestbodysize.New(cfg.RequestBodySize)),
+		Named("securityheaders", securityheaders.New(cfg.SecurityHeaders)),
+		Named("requestlogger", requestlogger.New(logger, cfg.RequestLogger)),
+	}
+}
+
+// middlewareLink is passed to middleware in place of the next handler when
+// a route has its own middleware, which may opt out of inherited middleware
+// with [Without]. Route middleware is applied first, so the names it skips
+// are known by the time the inherited middleware is applied.
+type middlewareLink struct {
+	next http.Handler
+	skip *[]string
+}
+
+func (l *middlewareLink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	l.next.ServeHTTP(w, r)
+}
+
+// Named tags mw with a name, so that routes can opt out of it with [Without]
+// when it is inherited from the router or a group. Named middleware behaves
+// exactly like mw.
+//
+// Example:
+//
+//	app.Use(zh.Named("compress", compress.New()))
+func Named(name string, mw MiddlewareFunc) MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		if l, ok := next.(*middlewareLink); ok {
+			if slices.Contains(*l.skip, name) {
+				return l
+			}
+			return mw(l.next)
+		}
+		return mw(next)
+	}
+}
+
+// Without returns a route middleware that removes the inherited middleware
+// registered with [Named] under any of names from the route's chain, e.g. to
+// skip compression on a Server-Sent Events route. Names that don't match any
+// middleware are ignored.
+//
+// Example:
+//
+//	app.Use(zh.Named("compress", compress.New()))
+//	app.GET("/events", eventsHandler, zh.Without("compress"))
+func Without(names ...string) MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		if l, ok := next.(*middlewareLink); ok {
+			*l.skip = append(*l.skip, names...)
+		}
+		return next
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -113,7 +114,10 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 				if record.ETag != "" {
 					w.Header().Set(httpx.HeaderETag, record.ETag)
 				}
-				w.Header().Set(httpx.HeaderCacheControl, c.CacheControl)
+				// Directives set by the handler were replayed above
+				if _, ok := record.Headers[httpx.HeaderCacheControl]; !ok {
+					w.Header().Set(httpx.HeaderCacheControl, c.CacheControl)
+				}
 				w.WriteHeader(record.StatusCode)
 				if r.Method != http.MethodHead {
 					_, _ = w.Write(record.Body)
@@ -152,7 +156,11 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 					record.ETag = eTag
 				}
 
-				if err := store.Set(r.Context(), key, record, c.DefaultTTL); err != nil {
+				ttl := c.DefaultTTL
+				if recorder.maxAge > 0 {
+					ttl = recorder.maxAge
+				}
+				if err := store.Set(r.Context(), key, record, ttl); err != nil {
 					// Log error but don't fail the request
 					// (better to serve the response than fail because cache is unavailable)
 					log.GetGlobalLogger().Error("Cache store set failed", log.E(err), log.F("key", key))
@@ -160,11 +168,43 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 			}
 
 			// Finalize writes the response with proper headers
-			recorder.Finalize(eTag, c.CacheControl, config.BoolOrDefault(c.ETag, true), config.BoolOrDefault(c.LastModified, true), lastModified)
+			cacheControl := c.CacheControl
+			if recorder.handlerCacheControl {
+				cacheControl = ""
+			}
+			recorder.Finalize(eTag, cacheControl, config.BoolOrDefault(c.ETag, true), config.BoolOrDefault(c.LastModified, true), lastModified)
 		})
 	}
 }
 
+// parseCacheControl reads the response directives set by a handler. It reports
+// whether the response may be stored by a shared cache, and the max-age or
+// s-maxage (which takes precedence) it allows, or 0 if none is set.
+func parseCacheControl(cacheControl string) (bool, time.Duration) {
+	cacheable := true
+	var maxAge, sMaxAge time.Duration
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case httpx.CacheControlNoStore, httpx.CacheControlPrivate, httpx.CacheControlNoCache:
+			cacheable = false
+		case httpx.CacheControlMaxAge:
+			if n, err := strconv.Atoi(value); err == nil && n > 0 {
+				maxAge = time.Duration(n) * time.Second
+			}
+		case "s-maxage":
+			if n, err := strconv.Atoi(value); err == nil && n > 0 {
+				sMaxAge = time.Duration(n) * time.Second
+			}
+		}
+	}
+
+	if sMaxAge > 0 {
+		return cacheable, sMaxAge
+	}
+	return cacheable, maxAge
+}
+
 // cacheResponseRecorder captures response data for caching.
 type cacheResponseRecorder struct {
 	*rwutil.ResponseBuffer
@@ -172,16 +212,28 @@ type cacheResponseRecorder struct {
 	shouldCache   bool
 	statusCodeMap map[int]bool
 	hijacked      bool
+
+	// handlerCacheControl is true if the handler set its own Cache-Control,
+	// and maxAge is the duration it allows shared caches to keep the response.
+	handlerCacheControl bool
+	maxAge              time.Duration
 }
 
 // WriteHeader captures the status code and determines if response should be cached.
 func (c *cacheResponseRecorder) WriteHeader(statusCode int) {
-	if c.HasWritten || c.hijacked {
+	if c.HasWritten || c.hijacked || rwutil.IsInformational(statusCode) {
 		return
 	}
 	c.ResponseBuffer.WriteHeader(statusCode)
 	c.shouldCache = c.statusCodeMap[statusCode]
 
+	if cc := c.Header().Get(httpx.HeaderCacheControl); cc != "" {
+		c.handlerCacheControl = true
+		cacheable, maxAge := parseCacheControl(cc)
+		c.shouldCache = c.shouldCache && cacheable
+		c.maxAge = maxAge
+	}
+
 	if c.shouldCache {
 		c.headers = make(map[string][]string)
 		for k, v := range c.Header() {
@@ -269,6 +321,13 @@ func (c *cacheResponseRecorder) Flush() {
 	c.FlushTo(flusher, nil)
 }
 
+// Key returns the cache key of a request, built from its method, URL and the
+// values of the vary request headers, as used by the cache middleware.
+// HEAD and GET requests share the same key.
+func Key(r *http.Request, vary []string) string {
+	return generateCacheKey(r, vary)
+}
+
 // generateCacheKey creates a cache key from request method, URL, and vary headers.
 // GET and HEAD share the same cache key per RFC 7231.
 func generateCacheKey(r *http.Request, vary []string) string {
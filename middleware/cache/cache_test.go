@@ -805,6 +805,77 @@ func TestCache_IncludedPaths(t *testing.T) {
 	})
 }
 
+// recordingStore records the TTL of the last Set call.
+type recordingStore struct {
+	*MemoryStore
+	ttl time.Duration
+}
+
+func (s *recordingStore) Set(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	s.ttl = ttl
+	return s.MemoryStore.Set(ctx, key, record, ttl)
+}
+
+func TestCache_HandlerDirectives(t *testing.T) {
+	tests := []struct {
+		name         string
+		cacheControl string
+		wantCached   bool
+		wantTTL      time.Duration
+	}{
+		{"no directives uses config", "", true, time.Minute},
+		{"max-age sets TTL", "public, max-age=300", true, 5 * time.Minute},
+		{"s-maxage takes precedence", "public, max-age=300, s-maxage=30", true, 30 * time.Second},
+		{"no-store is not cached", "no-store", false, 0},
+		{"private is not cached", "private, max-age=300", false, 0},
+		{"no-cache is not cached", "no-cache", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callCount := 0
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				callCount++
+				if tt.cacheControl != "" {
+					w.Header().Set(httpx.HeaderCacheControl, tt.cacheControl)
+				}
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte("response"))
+			})
+
+			store := &recordingStore{MemoryStore: NewMemoryStore(100)}
+			cached := New(Config{
+				Store:        store,
+				DefaultTTL:   time.Minute,
+				CacheControl: "public, max-age=60",
+			})(handler)
+
+			wantCacheControl := tt.cacheControl
+			if wantCacheControl == "" {
+				wantCacheControl = "public, max-age=60"
+			}
+
+			for range 2 {
+				w := httptest.NewRecorder()
+				cached.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+				// The handler's directives are never overwritten
+				zhtest.AssertWith(t, w).
+					Status(http.StatusOK).
+					Body("response").
+					Header(httpx.HeaderCacheControl, wantCacheControl)
+			}
+
+			if tt.wantCached {
+				zhtest.AssertEqual(t, 1, callCount)
+				zhtest.AssertEqual(t, tt.wantTTL, store.ttl)
+			} else {
+				zhtest.AssertEqual(t, 2, callCount)
+			}
+		})
+	}
+}
+
 func TestCache_BothExcludedAndIncludedPathsPanics(t *testing.T) {
 	zhtest.AssertPanic(t, func() {
 		_ = New(Config{
@@ -814,3 +885,15 @@ func TestCache_BothExcludedAndIncludedPathsPanics(t *testing.T) {
 		})
 	})
 }
+
+func TestKey(t *testing.T) {
+	vary := []string{httpx.HeaderAcceptEncoding}
+
+	get := zhtest.NewRequest(http.MethodGet, "/items?page=1").WithHeader(httpx.HeaderAcceptEncoding, "gzip").Build()
+	head := zhtest.NewRequest(http.MethodHead, "/items?page=1").WithHeader(httpx.HeaderAcceptEncoding, "gzip").Build()
+	plain := zhtest.NewRequest(http.MethodGet, "/items?page=1").Build()
+
+	zhtest.AssertEqual(t, Key(get, vary), Key(head, vary))
+	zhtest.AssertNotEqual(t, Key(get, vary), Key(plain, vary))
+	zhtest.AssertEqual(t, Key(get, nil), Key(plain, nil))
+}
@@ -17,6 +17,23 @@
 //	    StatusCodes: []int{200, 201, 404},
 //	}))
 //
+// # Handler Directives
+//
+// Cache-Control directives set by a handler take precedence over the
+// configured CacheControl and TTL. Responses marked no-store, private or
+// no-cache are not stored, and max-age or s-maxage sets how long a response
+// is cached. The [zerohttp.Renderer] helpers set them:
+//
+//	app.GET("/stats", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+//	    zh.R.CacheFor(w, 30*time.Second)
+//	    return zh.R.JSON(w, http.StatusOK, stats)
+//	}))
+//
+//	app.GET("/me", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+//	    zh.R.Private(w) // Never shared between users
+//	    return zh.R.JSON(w, http.StatusOK, user)
+//	}))
+//
 // # Custom Store
 //
 // Implement the Store interface for Redis or other backends:
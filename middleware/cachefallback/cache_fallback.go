@@ -0,0 +1,284 @@
+package cachefallback
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/alexferl/zerohttp/httpx"
+	zconfig "github.com/alexferl/zerohttp/internal/config"
+	"github.com/alexferl/zerohttp/internal/mwutil"
+	"github.com/alexferl/zerohttp/internal/rwutil"
+	"github.com/alexferl/zerohttp/log"
+	"github.com/alexferl/zerohttp/middleware/cache"
+)
+
+// WarningStale is the Warning header of responses served from the cache
+// because the handler failed (RFC 7234 warn-code 110).
+const WarningStale = `110 - "Response is Stale"`
+
+// New creates a cache fallback middleware with the provided configuration.
+// It keeps the last successful (200) response to each GET request and, when
+// a later request to the same URL fails with a 5xx status, such as a 503
+// from an open circuit breaker, serves the kept response instead with a
+// "Warning: 110" header. Requests are keyed by URL and the request headers
+// of Config.Vary. Responses that set cookies, have a private or no-store
+// Cache-Control or vary on other headers are never kept, and neither are
+// event streams. Responses to requests with credentials, an Authorization
+// or Cookie header, are only kept if their Cache-Control is public or has
+// s-maxage, as RFC 9111 requires of shared caches. Only the headers set by
+// the handler are kept, so per-request headers of outer middleware, such as
+// X-Request-Id, stay those of the request served. Upgrade requests, such as
+// WebSockets, are passed through.
+func New(cfg ...Config) func(http.Handler) http.Handler {
+	c := DefaultConfig
+	if len(cfg) > 0 {
+		zconfig.Merge(&c, cfg[0])
+	}
+
+	mwutil.ValidatePathConfig(c.ExcludedPaths, c.IncludedPaths, "CacheFallback")
+
+	store := c.Store
+	if store == nil {
+		store = cache.NewMemoryStore(c.MaxEntries)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !mwutil.ShouldProcessMiddleware(r.URL.Path, c.IncludedPaths, c.ExcludedPaths) || isUpgrade(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := cache.Key(r, c.Vary)
+			entryHeader := w.Header().Clone()
+			fw := &fallbackWriter{
+				ResponseWriter: w,
+				header:         w.Header().Clone(),
+				ctx:            r.Context(),
+				store:          store,
+				key:            key,
+				maxBodySize:    c.MaxBodySize,
+			}
+
+			next.ServeHTTP(fw, r)
+
+			if fw.hijacked {
+				return
+			}
+			if !fw.wroteHeader {
+				fw.WriteHeader(http.StatusOK)
+			}
+
+			if fw.fallback != nil {
+				serveStale(w, r, *fw.fallback)
+				return
+			}
+
+			if r.Method == http.MethodGet && fw.status == http.StatusOK && !fw.overflow && cacheable(r, fw.header, c.Vary) {
+				record := cache.Record{
+					StatusCode: fw.status,
+					Headers:    handlerHeader(entryHeader, fw.header),
+					Body:       bytes.Clone(fw.body.Bytes()),
+				}
+				if err := store.Set(r.Context(), key, record, c.MaxStale); err != nil {
+					log.GetGlobalLogger().Error("Cache fallback store set failed", log.E(err), log.F("key", key))
+				}
+			}
+		})
+	}
+}
+
+// isUpgrade reports whether the request asks to switch protocols, such as
+// a WebSocket handshake.
+func isUpgrade(r *http.Request) bool {
+	for _, token := range strings.Split(r.Header.Get(httpx.HeaderConnection), ",") {
+		if strings.EqualFold(strings.TrimSpace(token), httpx.ConnectionUpgrade) {
+			return true
+		}
+	}
+	return false
+}
+
+// handlerHeader returns the headers of final that differ from those of
+// entry, set before the handler ran by outer middleware.
+func handlerHeader(entry, final http.Header) http.Header {
+	h := make(http.Header, len(final))
+	for k, v := range final {
+		if !slices.Equal(entry[k], v) {
+			h[k] = slices.Clone(v)
+		}
+	}
+	return h
+}
+
+// cacheable reports whether the response to r with the given headers can
+// be kept and served to other clients whose requests match on the vary
+// headers.
+func cacheable(r *http.Request, h http.Header, vary []string) bool {
+	if h.Get(httpx.HeaderSetCookie) != "" || strings.HasPrefix(h.Get(httpx.HeaderContentType), httpx.MIMETextEventStream) {
+		return false
+	}
+	shared := false
+	for _, directive := range strings.Split(h.Get(httpx.HeaderCacheControl), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		if directive == httpx.CacheControlPublic || strings.HasPrefix(directive, "s-maxage=") {
+			shared = true
+		}
+	}
+	if !shared && (r.Header.Get(httpx.HeaderAuthorization) != "" || r.Header.Get(httpx.HeaderCookie) != "") {
+		return false
+	}
+	for _, v := range h.Values(httpx.HeaderVary) {
+		for _, name := range strings.Split(v, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if !slices.ContainsFunc(vary, func(s string) bool { return strings.EqualFold(s, name) }) {
+				return false
+			}
+		}
+	}
+	for _, directive := range strings.Split(h.Get(httpx.HeaderCacheControl), ",") {
+		switch strings.ToLower(strings.TrimSpace(directive)) {
+		case httpx.CacheControlNoStore, httpx.CacheControlPrivate:
+			return false
+		}
+	}
+	return true
+}
+
+// serveStale writes a kept response in place of the failed one.
+func serveStale(w http.ResponseWriter, r *http.Request, record cache.Record) {
+	for k, v := range record.Headers {
+		w.Header()[k] = v
+	}
+	w.Header().Set(httpx.HeaderWarning, WarningStale)
+	w.WriteHeader(record.StatusCode)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(record.Body)
+	}
+}
+
+// fallbackWriter passes successful responses through while keeping a copy
+// of their body, and holds back server errors for which a kept response can
+// be served instead. Headers are set on a copy until the status is known, so
+// those of a held back error never reach the client.
+type fallbackWriter struct {
+	http.ResponseWriter
+	header      http.Header
+	ctx         context.Context
+	store       cache.Store
+	key         string
+	maxBodySize int64
+
+	status      int
+	wroteHeader bool
+	hijacked    bool
+	fallback    *cache.Record
+	body        bytes.Buffer
+	overflow    bool
+}
+
+func (fw *fallbackWriter) Header() http.Header {
+	return fw.header
+}
+
+func (fw *fallbackWriter) WriteHeader(code int) {
+	if fw.wroteHeader {
+		return
+	}
+
+	// Informational responses such as 103 Early Hints are passed through
+	if rwutil.IsInformational(code) {
+		fw.commitHeader()
+		fw.ResponseWriter.WriteHeader(code)
+		return
+	}
+
+	fw.status = code
+	fw.wroteHeader = true
+
+	if code >= http.StatusInternalServerError {
+		record, found, err := fw.store.Get(fw.ctx, fw.key)
+		if err != nil {
+			log.GetGlobalLogger().Error("Cache fallback store get failed", log.E(err), log.F("key", fw.key))
+		} else if found {
+			fw.fallback = &record
+			return
+		}
+	}
+
+	fw.commitHeader()
+	fw.ResponseWriter.WriteHeader(code)
+}
+
+// commitHeader copies the headers set by the handler to the response.
+func (fw *fallbackWriter) commitHeader() {
+	h := fw.ResponseWriter.Header()
+	for k := range h {
+		if _, ok := fw.header[k]; !ok {
+			delete(h, k)
+		}
+	}
+	for k, v := range fw.header {
+		h[k] = v
+	}
+}
+
+func (fw *fallbackWriter) Write(p []byte) (int, error) {
+	if !fw.wroteHeader {
+		fw.WriteHeader(http.StatusOK)
+	}
+	if fw.fallback != nil {
+		return len(p), nil
+	}
+
+	if fw.status == http.StatusOK && !fw.overflow {
+		if int64(fw.body.Len()+len(p)) > fw.maxBodySize {
+			fw.overflow = true
+			fw.body.Reset()
+		} else {
+			fw.body.Write(p)
+		}
+	}
+	return fw.ResponseWriter.Write(p)
+}
+
+func (fw *fallbackWriter) Flush() {
+	if !fw.wroteHeader {
+		fw.WriteHeader(http.StatusOK)
+	}
+	if fw.fallback != nil {
+		return
+	}
+	if f, ok := fw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker. The response of a hijacked connection
+// is neither kept nor replaced.
+func (fw *fallbackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	fw.commitHeader()
+	conn, rw, err := http.NewResponseController(fw.ResponseWriter).Hijack()
+	if err == nil {
+		fw.hijacked = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (fw *fallbackWriter) Unwrap() http.ResponseWriter {
+	return fw.ResponseWriter
+}
@@ -0,0 +1,320 @@
+package cachefallback
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/middleware/cache"
+	"github.com/alexferl/zerohttp/middleware/circuitbreaker"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+// upstream is a handler whose response can be switched between success and failure.
+type upstream struct {
+	status int
+	body   string
+	header map[string]string
+	calls  int
+}
+
+func (u *upstream) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	u.calls++
+	for k, v := range u.header {
+		w.Header().Set(k, v)
+	}
+	w.WriteHeader(u.status)
+	_, _ = w.Write([]byte(u.body))
+}
+
+func get(h http.Handler, target string) *httptest.ResponseRecorder {
+	return zhtest.Serve(h, zhtest.NewRequest(http.MethodGet, target).Build())
+}
+
+func TestCacheFallback(t *testing.T) {
+	up := &upstream{
+		status: http.StatusOK,
+		body:   `{"items":[1,2,3]}`,
+		header: map[string]string{httpx.HeaderContentType: httpx.MIMEApplicationJSON},
+	}
+	handler := New()(up)
+
+	w := get(handler, "/items?page=1")
+	zhtest.AssertWith(t, w).
+		Status(http.StatusOK).
+		Body(`{"items":[1,2,3]}`).
+		HeaderNotExists(httpx.HeaderWarning)
+
+	// The upstream fails: the last good response is served instead
+	up.status, up.body = http.StatusBadGateway, "upstream down"
+	up.header = map[string]string{httpx.HeaderRetryAfter: "30"}
+	w = get(handler, "/items?page=1")
+	zhtest.AssertWith(t, w).
+		Status(http.StatusOK).
+		Body(`{"items":[1,2,3]}`).
+		Header(httpx.HeaderWarning, WarningStale).
+		Header(httpx.HeaderContentType, httpx.MIMEApplicationJSON).
+		HeaderNotExists(httpx.HeaderRetryAfter)
+
+	// Nothing kept for another URL: the error passes through
+	w = get(handler, "/items?page=2")
+	zhtest.AssertWith(t, w).
+		Status(http.StatusBadGateway).
+		Body("upstream down").
+		Header(httpx.HeaderRetryAfter, "30")
+
+	// HEAD requests get the kept headers without a body
+	w = zhtest.Serve(handler, zhtest.NewRequest(http.MethodHead, "/items?page=1").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusOK).Header(httpx.HeaderWarning, WarningStale).Body("")
+
+	// Recovery replaces the kept response
+	up.status, up.body, up.header = http.StatusOK, `{"items":[4]}`, nil
+	zhtest.AssertWith(t, get(handler, "/items?page=1")).Status(http.StatusOK).Body(`{"items":[4]}`)
+	up.status = http.StatusServiceUnavailable
+	zhtest.AssertWith(t, get(handler, "/items?page=1")).Status(http.StatusOK).Body(`{"items":[4]}`)
+}
+
+func TestCacheFallback_NotKept(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		status int
+		header map[string]string
+	}{
+		{"client error", http.MethodGet, http.StatusNotFound, nil},
+		{"created", http.MethodGet, http.StatusCreated, nil},
+		{"post", http.MethodPost, http.StatusOK, nil},
+		{"no-store", http.MethodGet, http.StatusOK, map[string]string{httpx.HeaderCacheControl: "no-store"}},
+		{"private", http.MethodGet, http.StatusOK, map[string]string{httpx.HeaderCacheControl: "private, max-age=60"}},
+		{"set-cookie", http.MethodGet, http.StatusOK, map[string]string{httpx.HeaderSetCookie: "session=abc"}},
+		{"event stream", http.MethodGet, http.StatusOK, map[string]string{httpx.HeaderContentType: httpx.MIMETextEventStream}},
+		{"vary on other header", http.MethodGet, http.StatusOK, map[string]string{httpx.HeaderVary: "Accept-Encoding, Cookie"}},
+		{"vary any", http.MethodGet, http.StatusOK, map[string]string{httpx.HeaderVary: "*"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			up := &upstream{status: tt.status, body: "response", header: tt.header}
+			handler := New()(up)
+
+			zhtest.Serve(handler, zhtest.NewRequest(tt.method, "/resource").Build())
+
+			up.status, up.header = http.StatusInternalServerError, nil
+			w := zhtest.Serve(handler, zhtest.NewRequest(tt.method, "/resource").Build())
+			zhtest.AssertWith(t, w).Status(http.StatusInternalServerError).HeaderNotExists(httpx.HeaderWarning)
+		})
+	}
+}
+
+func TestCacheFallback_Vary(t *testing.T) {
+	up := &upstream{
+		status: http.StatusOK,
+		body:   "gzip bytes",
+		header: map[string]string{httpx.HeaderContentEncoding: "gzip", httpx.HeaderVary: httpx.HeaderAcceptEncoding},
+	}
+	handler := New()(up)
+
+	req := zhtest.NewRequest(http.MethodGet, "/asset").WithHeader(httpx.HeaderAcceptEncoding, "gzip").Build()
+	zhtest.AssertWith(t, zhtest.Serve(handler, req)).Status(http.StatusOK).Body("gzip bytes")
+
+	up.status, up.body, up.header = http.StatusInternalServerError, "upstream down", nil
+
+	// A client that doesn't accept gzip doesn't get the gzip response
+	w := get(handler, "/asset")
+	zhtest.AssertWith(t, w).Status(http.StatusInternalServerError).HeaderNotExists(httpx.HeaderWarning)
+
+	req = zhtest.NewRequest(http.MethodGet, "/asset").WithHeader(httpx.HeaderAcceptEncoding, "gzip").Build()
+	zhtest.AssertWith(t, zhtest.Serve(handler, req)).
+		Status(http.StatusOK).
+		Body("gzip bytes").
+		Header(httpx.HeaderContentEncoding, "gzip").
+		Header(httpx.HeaderWarning, WarningStale)
+}
+
+func TestCacheFallback_Credentials(t *testing.T) {
+	for _, header := range []string{httpx.HeaderAuthorization, httpx.HeaderCookie} {
+		t.Run(header, func(t *testing.T) {
+			up := &upstream{status: http.StatusOK, body: `{"secret":"alice"}`}
+			handler := New()(up)
+
+			req := zhtest.NewRequest(http.MethodGet, "/me").WithHeader(header, "alice").Build()
+			zhtest.AssertWith(t, zhtest.Serve(handler, req)).Status(http.StatusOK)
+
+			// Another client doesn't get the private response
+			up.status, up.body = http.StatusServiceUnavailable, "down"
+			zhtest.AssertWith(t, get(handler, "/me")).
+				Status(http.StatusServiceUnavailable).
+				Body("down").
+				HeaderNotExists(httpx.HeaderWarning)
+		})
+
+		t.Run(header+" with public response", func(t *testing.T) {
+			for _, cc := range []string{"public, max-age=60", "s-maxage=60"} {
+				up := &upstream{
+					status: http.StatusOK,
+					body:   "shared",
+					header: map[string]string{httpx.HeaderCacheControl: cc},
+				}
+				handler := New()(up)
+
+				req := zhtest.NewRequest(http.MethodGet, "/news").WithHeader(header, "alice").Build()
+				zhtest.AssertWith(t, zhtest.Serve(handler, req)).Status(http.StatusOK)
+
+				up.status, up.body, up.header = http.StatusServiceUnavailable, "down", nil
+				zhtest.AssertWith(t, get(handler, "/news")).Status(http.StatusOK).Body("shared")
+			}
+		})
+	}
+}
+
+func TestCacheFallback_OuterHeaders(t *testing.T) {
+	up := &upstream{
+		status: http.StatusOK,
+		body:   "ok",
+		header: map[string]string{httpx.HeaderContentType: httpx.MIMETextPlain},
+	}
+	fallback := New()(up)
+	requestID := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID++
+		w.Header().Set(httpx.HeaderXRequestId, strconv.Itoa(requestID))
+		fallback.ServeHTTP(w, r)
+	})
+
+	zhtest.AssertWith(t, get(handler, "/")).Header(httpx.HeaderXRequestId, "1")
+
+	// The stale response carries the ID of the request it answers
+	up.status, up.header = http.StatusServiceUnavailable, nil
+	zhtest.AssertWith(t, get(handler, "/")).
+		Status(http.StatusOK).
+		Header(httpx.HeaderWarning, WarningStale).
+		Header(httpx.HeaderContentType, httpx.MIMETextPlain).
+		Header(httpx.HeaderXRequestId, "2")
+}
+
+// hijackRecorder is a ResponseRecorder supporting Hijack, like the
+// ResponseWriter of an HTTP/1.1 connection.
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (h *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h.hijacked = true
+	server, client := net.Pipe()
+	_ = client.Close()
+	return server, bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server)), nil
+}
+
+func TestCacheFallback_Upgrade(t *testing.T) {
+	handler := New()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		zhtest.AssertNoError(t, err)
+		_ = conn.Close()
+	}))
+
+	w := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+	req := zhtest.NewRequest(http.MethodGet, "/ws").
+		WithHeader(httpx.HeaderConnection, "keep-alive, Upgrade").
+		WithHeader(httpx.HeaderUpgrade, "websocket").
+		Build()
+	handler.ServeHTTP(w, req)
+	zhtest.AssertTrue(t, w.hijacked)
+	zhtest.AssertFalse(t, w.Flushed)
+
+	// Hijacking without an upgrade request doesn't write a response either
+	fw := &fallbackWriter{ResponseWriter: &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}, header: http.Header{}}
+	_, _, err := fw.Hijack()
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertTrue(t, fw.hijacked)
+}
+
+func TestCacheFallback_MaxBodySize(t *testing.T) {
+	up := &upstream{status: http.StatusOK, body: strings.Repeat("a", 100)}
+	handler := New(Config{MaxBodySize: 10})(up)
+
+	// The response is passed through whole, but not kept
+	zhtest.AssertWith(t, get(handler, "/large")).Status(http.StatusOK).Body(strings.Repeat("a", 100))
+
+	up.status = http.StatusInternalServerError
+	zhtest.AssertWith(t, get(handler, "/large")).Status(http.StatusInternalServerError)
+}
+
+func TestCacheFallback_Paths(t *testing.T) {
+	up := &upstream{status: http.StatusOK, body: "ok"}
+	handler := New(Config{ExcludedPaths: []string{"/live/"}})(up)
+
+	get(handler, "/live/scores")
+	get(handler, "/catalog")
+
+	up.status = http.StatusInternalServerError
+	zhtest.AssertWith(t, get(handler, "/live/scores")).Status(http.StatusInternalServerError)
+	zhtest.AssertWith(t, get(handler, "/catalog")).Status(http.StatusOK).Body("ok")
+}
+
+func TestCacheFallback_CircuitBreaker(t *testing.T) {
+	up := &upstream{status: http.StatusOK, body: "catalog"}
+	handler := New()(circuitbreaker.New(circuitbreaker.Config{
+		FailureThreshold: 2,
+		RecoveryTimeout:  time.Minute,
+	})(up))
+
+	zhtest.AssertWith(t, get(handler, "/catalog")).Status(http.StatusOK).Body("catalog")
+
+	up.status = http.StatusInternalServerError
+	for range 2 {
+		zhtest.AssertWith(t, get(handler, "/catalog")).Status(http.StatusOK).Header(httpx.HeaderWarning, WarningStale)
+	}
+
+	// The circuit is open: the upstream isn't called and the 503 is replaced too
+	calls := up.calls
+	zhtest.AssertWith(t, get(handler, "/catalog")).
+		Status(http.StatusOK).
+		Body("catalog").
+		Header(httpx.HeaderWarning, WarningStale)
+	zhtest.AssertEqual(t, calls, up.calls)
+}
+
+// failingStore is a cache store whose operations fail.
+type failingStore struct{}
+
+func (failingStore) Get(context.Context, string) (cache.Record, bool, error) {
+	return cache.Record{}, false, errors.New("store down")
+}
+
+func (failingStore) Set(context.Context, string, cache.Record, time.Duration) error {
+	return errors.New("store down")
+}
+
+func (failingStore) Delete(context.Context, string) error { return nil }
+func (failingStore) Close() error                         { return nil }
+
+func TestCacheFallback_StoreErrors(t *testing.T) {
+	up := &upstream{status: http.StatusOK, body: "ok"}
+	handler := New(Config{Store: failingStore{}})(up)
+
+	zhtest.AssertWith(t, get(handler, "/")).Status(http.StatusOK).Body("ok")
+
+	up.status, up.body = http.StatusInternalServerError, "error"
+	zhtest.AssertWith(t, get(handler, "/")).Status(http.StatusInternalServerError).Body("error")
+}
+
+func TestCacheFallback_ImplicitStatusAndFlush(t *testing.T) {
+	handler := New()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("part 1, "))
+		http.NewResponseController(w).Flush()
+		_, _ = w.Write([]byte("part 2"))
+	}))
+
+	w := get(handler, "/stream")
+	zhtest.AssertWith(t, w).Status(http.StatusOK).Body("part 1, part 2")
+	zhtest.AssertTrue(t, w.Flushed)
+}
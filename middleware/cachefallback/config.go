@@ -0,0 +1,64 @@
+package cachefallback
+
+import (
+	"time"
+
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/middleware/cache"
+)
+
+// Config configures the cache fallback middleware.
+type Config struct {
+	// Store holds the last good responses. Any cache.Store works, such as
+	// cache.NewStorageAdapter over a shared backend, so replicas can fall
+	// back on responses cached by each other.
+	// If nil, an in-memory LRU store of MaxEntries entries is used.
+	// Default: nil
+	Store cache.Store
+
+	// MaxEntries is the maximum number of responses kept by the in-memory store.
+	// Default: 10000
+	MaxEntries int
+
+	// MaxStale is how long a response is kept to be served in place of an
+	// error. Each successful response replaces the previous one.
+	// Default: 24h
+	MaxStale time.Duration
+
+	// Vary lists the request headers responses are kept separately for, as
+	// with the cache middleware, so a response negotiated for one client,
+	// such as a gzip-encoded or translated one, is not served to another.
+	// Responses varying on other headers, according to their Vary header,
+	// are not kept.
+	// Default: ["Accept", "Accept-Encoding", "Accept-Language"]
+	Vary []string
+
+	// MaxBodySize is the maximum size of the response bodies to keep (in
+	// bytes). Larger responses are passed through but not kept.
+	// Default: 1MB
+	MaxBodySize int64
+
+	// ExcludedPaths are paths whose responses are never kept or replayed.
+	// Supports exact matches, prefixes (ending with /), and wildcards (ending with *).
+	// Cannot be used with IncludedPaths - setting both will panic.
+	// Default: []
+	ExcludedPaths []string
+
+	// IncludedPaths contains the only paths whose responses are kept and
+	// replayed. Supports exact matches, prefixes (ending with /), and
+	// wildcards (ending with *).
+	// If empty, all paths are handled (subject to ExcludedPaths).
+	// Cannot be used with ExcludedPaths - setting both will panic.
+	// Default: []
+	IncludedPaths []string
+}
+
+// DefaultConfig is the default configuration for the cache fallback middleware.
+var DefaultConfig = Config{
+	MaxEntries:    10000,
+	MaxStale:      24 * time.Hour,
+	Vary:          []string{httpx.HeaderAccept, httpx.HeaderAcceptEncoding, httpx.HeaderAcceptLanguage},
+	MaxBodySize:   1 << 20,
+	ExcludedPaths: []string{},
+	IncludedPaths: []string{},
+}
@@ -0,0 +1,26 @@
+package cachefallback
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestCacheFallbackConfig_DefaultValues(t *testing.T) {
+	cfg := DefaultConfig
+	zhtest.AssertNil(t, cfg.Store)
+	zhtest.AssertEqual(t, 10000, cfg.MaxEntries)
+	zhtest.AssertEqual(t, 24*time.Hour, cfg.MaxStale)
+	zhtest.AssertEqual(t, []string{httpx.HeaderAccept, httpx.HeaderAcceptEncoding, httpx.HeaderAcceptLanguage}, cfg.Vary)
+	zhtest.AssertEqual(t, int64(1<<20), cfg.MaxBodySize)
+	zhtest.AssertEqual(t, 0, len(cfg.ExcludedPaths))
+	zhtest.AssertEqual(t, 0, len(cfg.IncludedPaths))
+}
+
+func TestCacheFallbackConfig_BothPathsPanics(t *testing.T) {
+	zhtest.AssertPanic(t, func() {
+		New(Config{ExcludedPaths: []string{"/a"}, IncludedPaths: []string{"/b"}})
+	})
+}
@@ -0,0 +1,49 @@
+// Package cachefallback provides middleware serving stale responses when
+// handlers fail.
+//
+// Keeps the last successful response to each GET request and serves it,
+// with a "Warning: 110" header, in place of a later 5xx response for the
+// same URL. Read-heavy endpoints keep answering, with slightly stale data,
+// while an upstream they depend on is down.
+//
+// # Usage
+//
+//	import "github.com/alexferl/zerohttp/middleware/cachefallback"
+//
+//	// Use defaults (in-memory store, responses kept for 24h)
+//	app.Use(cachefallback.New())
+//
+//	// Only for the catalog, with a shared store
+//	app.Use(cachefallback.New(cachefallback.Config{
+//	    Store:         cache.NewStorageAdapter(redisStorage),
+//	    MaxStale:      time.Hour,
+//	    IncludedPaths: []string{"/catalog/"},
+//	}))
+//
+// # Circuit Breaker
+//
+// Register the middleware before the circuit breaker, so the 503 responses
+// of an open circuit are replaced too, without calling the handler:
+//
+//	app.Use(
+//	    cachefallback.New(),
+//	    circuitbreaker.New(circuitbreaker.Config{FailureThreshold: 5}),
+//	)
+//
+// # What Is Kept
+//
+// Responses are keyed by URL and the request headers listed in Vary
+// (Accept, Accept-Encoding and Accept-Language by default), so only 200
+// responses that can be shared between clients are kept: those setting
+// cookies, with a private or no-store Cache-Control, varying on other
+// request headers, or streaming events are not. Neither are responses to
+// requests with an Authorization or Cookie header, unless their
+// Cache-Control is public or has s-maxage. Bodies larger than
+// MaxBodySize are passed through but not kept. Server errors for which no
+// response was kept are passed through unchanged, and so are WebSocket and
+// other upgrade requests.
+//
+// Only the headers set by the handler are kept. A stale response keeps the
+// headers that middleware before the fallback set for the current request,
+// such as X-Request-Id.
+package cachefallback
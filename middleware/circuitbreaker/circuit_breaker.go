@@ -1,8 +1,10 @@
 package circuitbreaker
 
 import (
+	"maps"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	zconfig "github.com/alexferl/zerohttp/internal/config"
@@ -20,6 +22,32 @@ const (
 	StateHalfOpen
 )
 
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// stateChange is a state transition to report once the circuit lock is released.
+// The zero value, closed to closed, means no transition.
+type stateChange struct {
+	from CircuitState
+	to   CircuitState
+}
+
+// changed reports whether the state actually changed.
+func (sc stateChange) changed() bool {
+	return sc.from != sc.to
+}
+
 // circuit represents a single circuit breaker instance
 type circuit struct {
 	state            CircuitState
@@ -27,56 +55,132 @@ type circuit struct {
 	successCount     int
 	halfOpenInFlight int // Number of requests currently in flight in half-open state
 	lastFailureTime  time.Time
+	stats            Stats
 	mu               sync.RWMutex
 	config           Config
 }
 
-// circuitBreakerMiddleware manages multiple circuit breakers
-type circuitBreakerMiddleware struct {
+// Stats holds cumulative counters for a single circuit.
+type Stats struct {
+	// State is the state of the circuit when the snapshot was taken.
+	State CircuitState
+
+	// Successes is the number of requests that passed through and were not failures.
+	Successes uint64
+
+	// Failures is the number of requests that passed through and were failures.
+	Failures uint64
+
+	// Rejections is the number of requests rejected without calling the handler.
+	Rejections uint64
+
+	// Trips is the number of times the circuit transitioned to open.
+	Trips uint64
+}
+
+// Breaker manages the circuits for a circuit breaker middleware and exposes
+// their state for monitoring.
+type Breaker struct {
 	circuits map[string]*circuit
 	config   Config
 	mu       sync.RWMutex
+
+	// reg is the metrics registry of the first request with one, used to
+	// record the state changes of Reset and ResetAll
+	reg atomic.Pointer[metrics.Registry]
 }
 
-// New creates a circuit breaker middleware with the provided configuration
+// New creates a circuit breaker middleware with the provided configuration.
+// Use [NewBreaker] instead when the circuit state needs to be inspected.
 func New(cfg ...Config) func(http.Handler) http.Handler {
+	return NewBreaker(cfg...).Middleware
+}
+
+// NewBreaker creates a circuit breaker with the provided configuration.
+// Its Middleware method returns the middleware, and the breaker itself can
+// be used to read per-key statistics.
+func NewBreaker(cfg ...Config) *Breaker {
 	c := DefaultConfig
 	if len(cfg) > 0 {
 		zconfig.Merge(&c, cfg[0])
 	}
 
-	cbm := &circuitBreakerMiddleware{
+	return &Breaker{
 		circuits: make(map[string]*circuit),
 		config:   c,
 	}
+}
 
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			reg := metrics.SafeRegistry(metrics.GetRegistry(r.Context()))
+// Middleware wraps next with the circuit breaker.
+func (cbm *Breaker) Middleware(next http.Handler) http.Handler {
+	c := cbm.config
 
-			key := c.KeyExtractor(r)
-			circ := cbm.getCircuit(key)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawReg := metrics.GetRegistry(r.Context())
+		if rawReg != nil && cbm.reg.Load() == nil {
+			cbm.reg.CompareAndSwap(nil, &rawReg)
+		}
+		reg := metrics.SafeRegistry(rawReg)
 
-			reg.Gauge("circuit_breaker_state", "key").WithLabelValues(key).Set(float64(circ.getState()))
+		key := c.KeyExtractor(r)
+		circ := cbm.getCircuit(key)
 
-			if circ.isOpen() {
-				reg.Counter("circuit_breaker_requests_total", "key", "result").WithLabelValues(key, "rejected").Inc()
-				detail := problem.NewDetail(c.OpenStatusCode, c.OpenMessage)
-				_ = detail.RenderAuto(w, r) // Best effort - client may have disconnected
-				return
-			}
+		reg.Gauge("circuit_breaker_state", "key").WithLabelValues(key).Set(float64(circ.getState()))
 
-			wrapped := rwutil.NewResponseWriter(w)
+		blocked, change := circ.isOpen()
+		cbm.notify(key, change, reg)
+		if blocked {
+			reg.Counter("circuit_breaker_requests_total", "key", "result").WithLabelValues(key, "rejected").Inc()
+			detail := problem.NewDetail(c.OpenStatusCode, c.OpenMessage)
+			_ = detail.RenderAuto(w, r) // Best effort - client may have disconnected
+			return
+		}
 
-			next.ServeHTTP(wrapped, r)
+		wrapped := rwutil.NewResponseWriter(w)
 
-			circ.recordResult(r, wrapped.StatusCode(), reg, key)
-		})
+		next.ServeHTTP(wrapped, r)
+
+		change = circ.recordResult(r, wrapped.StatusCode(), reg, key)
+		cbm.notify(key, change, reg)
+	})
+}
+
+// notify records a state transition and calls OnStateChange.
+// It must be called without holding any circuit lock.
+func (cbm *Breaker) notify(key string, change stateChange, reg metrics.Registry) {
+	if !change.changed() {
+		return
+	}
+
+	reg.Counter("circuit_breaker_state_changes_total", "key", "from", "to").
+		WithLabelValues(key, change.from.String(), change.to.String()).Inc()
+	reg.Gauge("circuit_breaker_state", "key").WithLabelValues(key).Set(float64(change.to))
+
+	if cbm.config.OnStateChange != nil {
+		cbm.config.OnStateChange(key, change.from, change.to)
 	}
 }
 
+// Stats returns a snapshot of the counters for every known circuit, keyed
+// by the value returned from KeyExtractor. Each circuit is read under its
+// lock so the counters of a given key are consistent with each other.
+func (cbm *Breaker) Stats() map[string]Stats {
+	cbm.mu.RLock()
+	defer cbm.mu.RUnlock()
+
+	stats := make(map[string]Stats, len(cbm.circuits))
+	for key, c := range cbm.circuits {
+		c.mu.RLock()
+		s := c.stats
+		s.State = c.state
+		c.mu.RUnlock()
+		stats[key] = s
+	}
+	return stats
+}
+
 // getCircuit gets or creates a circuit breaker for the given key
-func (cbm *circuitBreakerMiddleware) getCircuit(key string) *circuit {
+func (cbm *Breaker) getCircuit(key string) *circuit {
 	cbm.mu.RLock()
 	c, exists := cbm.circuits[key]
 	cbm.mu.RUnlock()
@@ -106,42 +210,54 @@ func (c *circuit) getState() CircuitState {
 }
 
 // isOpen checks if the circuit is open or should transition to half-open.
-// Returns true if the request should be blocked.
+// Returns true if the request should be blocked, along with any state transition.
 // For half-open state, it checks if max concurrent requests limit is reached.
-func (c *circuit) isOpen() bool {
+func (c *circuit) isOpen() (bool, stateChange) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	switch c.state {
 	case StateClosed:
-		return false
+		return false, stateChange{}
 	case StateOpen:
 		// Check if we should transition to half-open
 		if time.Since(c.lastFailureTime) >= c.config.RecoveryTimeout {
 			c.state = StateHalfOpen
 			c.successCount = 0
 			c.halfOpenInFlight = 0
-			return false
+			return false, stateChange{from: StateOpen, to: StateHalfOpen}
 		}
-		return true
+		c.stats.Rejections++
+		return true, stateChange{}
 	case StateHalfOpen:
 		// Check if we've reached the max concurrent requests limit
 		if c.halfOpenInFlight >= c.config.MaxHalfOpenRequests {
-			return true // Treat as open (reject request)
+			c.stats.Rejections++
+			return true, stateChange{} // Treat as open (reject request)
 		}
 		c.halfOpenInFlight++
-		return false
+		return false, stateChange{}
 	}
 
-	return false
+	return false, stateChange{}
 }
 
-// recordResult records the result of a request and updates circuit state
-func (c *circuit) recordResult(r *http.Request, statusCode int, reg metrics.Registry, key string) {
+// recordResult records the result of a request and updates circuit state.
+// Returns the state transition caused by the result, if any.
+func (c *circuit) recordResult(r *http.Request, statusCode int, reg metrics.Registry, key string) stateChange {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	from := c.state
+
 	isFailure := c.config.IsFailure(r, statusCode)
+	if c.state != StateOpen {
+		if isFailure {
+			c.stats.Failures++
+		} else {
+			c.stats.Successes++
+		}
+	}
 
 	switch c.state {
 	case StateClosed:
@@ -151,6 +267,7 @@ func (c *circuit) recordResult(r *http.Request, statusCode int, reg metrics.Regi
 			if c.failureCount >= c.config.FailureThreshold {
 				c.state = StateOpen
 				c.lastFailureTime = time.Now()
+				c.stats.Trips++
 				reg.Counter("circuit_breaker_trips_total", "key").WithLabelValues(key).Inc()
 			}
 		} else {
@@ -168,6 +285,7 @@ func (c *circuit) recordResult(r *http.Request, statusCode int, reg metrics.Regi
 		if isFailure {
 			c.state = StateOpen
 			c.lastFailureTime = time.Now()
+			c.stats.Trips++
 			c.failureCount++
 			c.successCount = 0
 			c.halfOpenInFlight = 0
@@ -185,10 +303,12 @@ func (c *circuit) recordResult(r *http.Request, statusCode int, reg metrics.Regi
 			reg.Counter("circuit_breaker_requests_total", "key", "result").WithLabelValues(key, "allowed").Inc()
 		}
 	}
+
+	return stateChange{from: from, to: c.state}
 }
 
 // GetState returns the current state of a circuit (for monitoring)
-func (cbm *circuitBreakerMiddleware) GetState(key string) CircuitState {
+func (cbm *Breaker) GetState(key string) CircuitState {
 	cbm.mu.RLock()
 	c, exists := cbm.circuits[key]
 	cbm.mu.RUnlock()
@@ -202,17 +322,50 @@ func (cbm *circuitBreakerMiddleware) GetState(key string) CircuitState {
 	return c.state
 }
 
-// Reset manually resets a circuit breaker (for admin operations)
-func (cbm *circuitBreakerMiddleware) Reset(key string) {
-	cbm.mu.Lock()
-	defer cbm.mu.Unlock()
+// Reset forces the circuit for key closed, e.g. once an upstream is known to
+// have recovered, without waiting for RecoveryTimeout and SuccessThreshold.
+// Cumulative statistics are kept. OnStateChange is called if the circuit
+// wasn't already closed.
+func (cbm *Breaker) Reset(key string) {
+	cbm.mu.RLock()
+	c, exists := cbm.circuits[key]
+	cbm.mu.RUnlock()
+
+	if exists {
+		cbm.notify(key, c.reset(), cbm.registry())
+	}
+}
+
+// ResetAll forces every circuit closed. See [Breaker.Reset].
+func (cbm *Breaker) ResetAll() {
+	cbm.mu.RLock()
+	circuits := make(map[string]*circuit, len(cbm.circuits))
+	maps.Copy(circuits, cbm.circuits)
+	cbm.mu.RUnlock()
+
+	for key, c := range circuits {
+		cbm.notify(key, c.reset(), cbm.registry())
+	}
+}
 
-	if c, exists := cbm.circuits[key]; exists {
-		c.mu.Lock()
-		defer c.mu.Unlock()
-		c.state = StateClosed
-		c.failureCount = 0
-		c.successCount = 0
-		c.halfOpenInFlight = 0
+// registry returns the metrics registry the breaker was used with, or a
+// no-op registry if it hasn't served a request with one yet.
+func (cbm *Breaker) registry() metrics.Registry {
+	if reg := cbm.reg.Load(); reg != nil {
+		return *reg
 	}
+	return metrics.SafeRegistry(nil)
+}
+
+// reset closes the circuit and returns the resulting state transition.
+func (c *circuit) reset() stateChange {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	from := c.state
+	c.state = StateClosed
+	c.failureCount = 0
+	c.successCount = 0
+	c.halfOpenInFlight = 0
+	return stateChange{from: from, to: StateClosed}
 }
@@ -44,7 +44,7 @@ func BenchmarkCircuitBreaker_States(b *testing.B) {
 
 	for _, s := range states {
 		b.Run(s.name, func(b *testing.B) {
-			cbm := &circuitBreakerMiddleware{
+			cbm := &Breaker{
 				circuits: make(map[string]*circuit),
 				config:   DefaultConfig,
 			}
@@ -141,7 +141,7 @@ func BenchmarkCircuitBreaker_RecordResult(b *testing.B) {
 
 	for _, s := range states {
 		b.Run(s.name, func(b *testing.B) {
-			cbm := &circuitBreakerMiddleware{
+			cbm := &Breaker{
 				circuits: make(map[string]*circuit),
 				config:   DefaultConfig,
 			}
@@ -178,7 +178,7 @@ func BenchmarkCircuitBreaker_GetCircuit(b *testing.B) {
 
 	for _, s := range scenarios {
 		b.Run(s.name, func(b *testing.B) {
-			cbm := &circuitBreakerMiddleware{
+			cbm := &Breaker{
 				circuits: make(map[string]*circuit),
 				config:   DefaultConfig,
 			}
@@ -266,7 +266,7 @@ func BenchmarkCircuitBreaker_HalfOpenLimiting(b *testing.B) {
 
 	for _, limit := range limits {
 		b.Run(fmt.Sprintf("Limit%d", limit), func(b *testing.B) {
-			cbm := &circuitBreakerMiddleware{
+			cbm := &Breaker{
 				circuits: make(map[string]*circuit),
 				config: Config{
 					MaxHalfOpenRequests: limit,
@@ -320,7 +320,7 @@ func BenchmarkCircuitBreaker_StateTransition(b *testing.B) {
 			cfg := DefaultConfig
 			cfg.FailureThreshold = 5
 			cfg.SuccessThreshold = 5
-			cbm := &circuitBreakerMiddleware{
+			cbm := &Breaker{
 				circuits: make(map[string]*circuit),
 				config:   cfg,
 			}
@@ -400,7 +400,7 @@ func BenchmarkCircuitBreaker_MultipleCircuits(b *testing.B) {
 
 	for _, count := range circuitCounts {
 		b.Run(fmt.Sprintf("Circuits%d", count), func(b *testing.B) {
-			cbm := &circuitBreakerMiddleware{
+			cbm := &Breaker{
 				circuits: make(map[string]*circuit),
 				config:   DefaultConfig,
 			}
@@ -2,12 +2,15 @@ package circuitbreaker
 
 import (
 	"fmt"
+	"maps"
 	"net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/metrics"
 	"github.com/alexferl/zerohttp/zhtest"
 )
 
@@ -427,7 +430,7 @@ func TestCircuitBreaker_Metrics(t *testing.T) {
 }
 
 func TestCircuitBreaker_ConcurrentReset(t *testing.T) {
-	cbm := &circuitBreakerMiddleware{
+	cbm := &Breaker{
 		circuits: make(map[string]*circuit),
 		config:   DefaultConfig,
 	}
@@ -464,8 +467,132 @@ func TestCircuitBreaker_ConcurrentReset(t *testing.T) {
 	}
 }
 
+func TestCircuitBreaker_Reset(t *testing.T) {
+	handler := &circuitTestHandler{statusCode: http.StatusInternalServerError}
+	var changes []string
+	breaker := NewBreaker(Config{
+		FailureThreshold: 1,
+		RecoveryTimeout:  time.Hour,
+		OnStateChange: func(key string, from, to CircuitState) {
+			changes = append(changes, key+":"+from.String()+"->"+to.String())
+		},
+	})
+	middleware := breaker.Middleware(handler)
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		return zhtest.Serve(middleware, zhtest.NewRequest(http.MethodGet, path).Build())
+	}
+
+	// Open the circuits for two endpoints
+	serve("/a")
+	serve("/b")
+	zhtest.AssertWith(t, serve("/a")).Status(http.StatusServiceUnavailable)
+	zhtest.AssertWith(t, serve("/b")).Status(http.StatusServiceUnavailable)
+
+	// Reset closes a single circuit immediately
+	handler.statusCode = http.StatusOK
+	breaker.Reset("/a")
+	zhtest.AssertEqual(t, StateClosed, breaker.GetState("/a"))
+	zhtest.AssertEqual(t, StateOpen, breaker.GetState("/b"))
+
+	calls := handler.callCount
+	zhtest.AssertWith(t, serve("/a")).Status(http.StatusOK)
+	zhtest.AssertEqual(t, calls+1, handler.callCount)
+	zhtest.AssertWith(t, serve("/b")).Status(http.StatusServiceUnavailable)
+
+	// Statistics survive a reset
+	zhtest.AssertEqual(t, uint64(1), breaker.Stats()["/a"].Trips)
+
+	// Resetting a closed or unknown circuit is a no-op
+	breaker.Reset("/a")
+	breaker.Reset("/unknown")
+
+	breaker.ResetAll()
+	zhtest.AssertWith(t, serve("/b")).Status(http.StatusOK)
+
+	zhtest.AssertEqual(t, []string{
+		"/a:closed->open",
+		"/b:closed->open",
+		"/a:open->closed",
+		"/b:open->closed",
+	}, changes)
+}
+
+func TestCircuitBreaker_ResetMetrics(t *testing.T) {
+	reg := metrics.NewRegistry()
+	breaker := NewBreaker(Config{FailureThreshold: 1, RecoveryTimeout: time.Hour})
+	middleware := breaker.Middleware(&circuitTestHandler{statusCode: http.StatusInternalServerError})
+
+	req := zhtest.NewRequest(http.MethodGet, "/a").Build()
+	zhtest.Serve(middleware, req.WithContext(metrics.WithRegistry(req.Context(), reg)))
+	zhtest.AssertEqual(t, StateOpen, breaker.GetState("/a"))
+
+	metric := func(name string, labels map[string]string) *metrics.Metric {
+		for _, family := range reg.Gather() {
+			if family.Name != name {
+				continue
+			}
+			for _, m := range family.Metrics {
+				if maps.Equal(m.Labels, labels) {
+					return &m
+				}
+			}
+		}
+		return nil
+	}
+
+	// A manual reset is recorded in the registry the breaker was used with
+	breaker.Reset("/a")
+	state := metric("circuit_breaker_state", map[string]string{"key": "/a"})
+	zhtest.AssertNotNil(t, state)
+	zhtest.AssertEqual(t, float64(StateClosed), state.Gauge)
+	change := metric("circuit_breaker_state_changes_total", map[string]string{"key": "/a", "from": "open", "to": "closed"})
+	zhtest.AssertNotNil(t, change)
+	zhtest.AssertEqual(t, uint64(1), change.Counter)
+}
+
+func TestCircuitBreaker_ResetHalfOpen(t *testing.T) {
+	breaker := NewBreaker(Config{MaxHalfOpenRequests: 1})
+	c := breaker.getCircuit("/test")
+	c.mu.Lock()
+	c.state = StateHalfOpen
+	c.halfOpenInFlight = 1
+	c.mu.Unlock()
+
+	zhtest.AssertTrue(t, isBlocked(c))
+
+	breaker.Reset("/test")
+	zhtest.AssertFalse(t, isBlocked(c))
+	zhtest.AssertEqual(t, StateClosed, breaker.GetState("/test"))
+}
+
+func TestCircuitBreaker_ConcurrentResetAll(t *testing.T) {
+	handler := &circuitTestHandler{statusCode: http.StatusInternalServerError}
+	breaker := NewBreaker(Config{FailureThreshold: 1})
+	middleware := breaker.Middleware(handler)
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(2)
+		go func(id int) {
+			defer wg.Done()
+			zhtest.Serve(middleware, zhtest.NewRequest(http.MethodGet, fmt.Sprintf("/test-%d", id%5)).Build())
+		}(i)
+		go func() {
+			defer wg.Done()
+			breaker.ResetAll()
+		}()
+	}
+	wg.Wait()
+
+	breaker.ResetAll()
+	for key := range breaker.Stats() {
+		zhtest.AssertEqual(t, StateClosed, breaker.GetState(key))
+	}
+}
+
 func TestCircuitBreaker_GetState(t *testing.T) {
-	cbm := &circuitBreakerMiddleware{
+	cbm := &Breaker{
 		circuits: make(map[string]*circuit),
 		config: Config{
 			FailureThreshold: 3,
@@ -496,7 +623,7 @@ func TestCircuitBreaker_GetState(t *testing.T) {
 
 func TestCircuitBreaker_HalfOpenRequestLimit(t *testing.T) {
 	// Test that MaxHalfOpenRequests limits concurrent requests in half-open state
-	cbm := &circuitBreakerMiddleware{
+	cbm := &Breaker{
 		circuits: make(map[string]*circuit),
 		config: Config{
 			FailureThreshold:    1,
@@ -515,13 +642,13 @@ func TestCircuitBreaker_HalfOpenRequestLimit(t *testing.T) {
 	c.mu.Unlock()
 
 	// First request should be allowed
-	zhtest.AssertFalse(t, c.isOpen())
+	zhtest.AssertFalse(t, isBlocked(c))
 
 	// Second request should be allowed
-	zhtest.AssertFalse(t, c.isOpen())
+	zhtest.AssertFalse(t, isBlocked(c))
 
 	// Third request should be blocked (MaxHalfOpenRequests=2)
-	zhtest.AssertTrue(t, c.isOpen())
+	zhtest.AssertTrue(t, isBlocked(c))
 
 	// Simulate one request completing
 	c.mu.Lock()
@@ -529,12 +656,12 @@ func TestCircuitBreaker_HalfOpenRequestLimit(t *testing.T) {
 	c.mu.Unlock()
 
 	// Now another request should be allowed
-	zhtest.AssertFalse(t, c.isOpen())
+	zhtest.AssertFalse(t, isBlocked(c))
 }
 
 func TestCircuitBreaker_HalfOpenRequestLimit_Default(t *testing.T) {
 	// Test that default MaxHalfOpenRequests is 1
-	cbm := &circuitBreakerMiddleware{
+	cbm := &Breaker{
 		circuits: make(map[string]*circuit),
 		config: Config{
 			FailureThreshold:    1,
@@ -553,10 +680,10 @@ func TestCircuitBreaker_HalfOpenRequestLimit_Default(t *testing.T) {
 	c.mu.Unlock()
 
 	// First request should be allowed
-	zhtest.AssertFalse(t, c.isOpen())
+	zhtest.AssertFalse(t, isBlocked(c))
 
 	// Second request should be blocked (default MaxHalfOpenRequests=1)
-	zhtest.AssertTrue(t, c.isOpen())
+	zhtest.AssertTrue(t, isBlocked(c))
 
 	// Simulate request completing
 	c.mu.Lock()
@@ -564,5 +691,147 @@ func TestCircuitBreaker_HalfOpenRequestLimit_Default(t *testing.T) {
 	c.mu.Unlock()
 
 	// Now another request should be allowed
-	zhtest.AssertFalse(t, c.isOpen())
+	zhtest.AssertFalse(t, isBlocked(c))
+}
+
+func TestCircuitBreaker_Stats(t *testing.T) {
+	handler := &circuitTestHandler{statusCode: http.StatusInternalServerError}
+	breaker := NewBreaker(Config{
+		FailureThreshold: 2,
+		RecoveryTimeout:  time.Minute,
+	})
+	middleware := breaker.Middleware(handler)
+
+	zhtest.AssertEqual(t, 0, len(breaker.Stats()))
+
+	// Two failures trip the circuit, the next two requests are rejected
+	for range 4 {
+		req := zhtest.NewRequest(http.MethodGet, "/fail").Build()
+		zhtest.Serve(middleware, req)
+	}
+
+	handler.statusCode = http.StatusOK
+	req := zhtest.NewRequest(http.MethodGet, "/ok").Build()
+	zhtest.Serve(middleware, req)
+
+	stats := breaker.Stats()
+	zhtest.AssertEqual(t, 2, len(stats))
+	zhtest.AssertEqual(t, Stats{State: StateOpen, Failures: 2, Rejections: 2, Trips: 1}, stats["/fail"])
+	zhtest.AssertEqual(t, Stats{State: StateClosed, Successes: 1}, stats["/ok"])
+}
+
+func TestCircuitBreaker_StatsHalfOpen(t *testing.T) {
+	handler := &circuitTestHandler{statusCode: http.StatusInternalServerError}
+	breaker := NewBreaker(Config{
+		FailureThreshold: 1,
+		RecoveryTimeout:  10 * time.Millisecond,
+		SuccessThreshold: 1,
+	})
+	middleware := breaker.Middleware(handler)
+
+	req := zhtest.NewRequest(http.MethodGet, "/test").Build()
+	zhtest.Serve(middleware, req)
+
+	time.Sleep(20 * time.Millisecond)
+
+	// Failure in half-open trips the circuit again
+	req = zhtest.NewRequest(http.MethodGet, "/test").Build()
+	zhtest.Serve(middleware, req)
+
+	zhtest.AssertEqual(t, Stats{State: StateOpen, Failures: 2, Trips: 2}, breaker.Stats()["/test"])
+
+	time.Sleep(20 * time.Millisecond)
+
+	handler.statusCode = http.StatusOK
+	req = zhtest.NewRequest(http.MethodGet, "/test").Build()
+	zhtest.Serve(middleware, req)
+
+	zhtest.AssertEqual(t, Stats{State: StateClosed, Successes: 1, Failures: 2, Trips: 2}, breaker.Stats()["/test"])
+}
+
+func TestCircuitBreaker_StatsConcurrent(t *testing.T) {
+	handler := &circuitTestHandler{statusCode: http.StatusOK}
+	breaker := NewBreaker()
+	middleware := breaker.Middleware(handler)
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			req := zhtest.NewRequest(http.MethodGet, fmt.Sprintf("/test-%d", id%5)).Build()
+			zhtest.Serve(middleware, req)
+			_ = breaker.Stats()
+		}(i)
+	}
+	wg.Wait()
+
+	var total uint64
+	for _, s := range breaker.Stats() {
+		total += s.Successes
+	}
+	zhtest.AssertEqual(t, uint64(50), total)
+}
+
+func TestCircuitBreaker_OnStateChange(t *testing.T) {
+	type transition struct {
+		key      string
+		from, to CircuitState
+	}
+
+	var mu sync.Mutex
+	var transitions []transition
+
+	handler := &circuitTestHandler{statusCode: http.StatusInternalServerError}
+	var breaker *Breaker
+	breaker = NewBreaker(Config{
+		FailureThreshold: 1,
+		SuccessThreshold: 1,
+		RecoveryTimeout:  10 * time.Millisecond,
+		OnStateChange: func(key string, from, to CircuitState) {
+			// The breaker can be queried from the callback
+			zhtest.AssertEqual(t, to, breaker.GetState(key))
+
+			mu.Lock()
+			defer mu.Unlock()
+			transitions = append(transitions, transition{key, from, to})
+		},
+	})
+	middleware := breaker.Middleware(handler)
+
+	serve := func() {
+		zhtest.Serve(middleware, zhtest.NewRequest(http.MethodGet, "/test").Build())
+	}
+
+	serve() // closed -> open
+	serve() // rejected, no transition
+
+	time.Sleep(20 * time.Millisecond)
+	serve() // open -> half-open -> open
+
+	time.Sleep(20 * time.Millisecond)
+	handler.statusCode = http.StatusOK
+	serve() // open -> half-open -> closed
+	serve() // no transition
+
+	zhtest.AssertEqual(t, []transition{
+		{"/test", StateClosed, StateOpen},
+		{"/test", StateOpen, StateHalfOpen},
+		{"/test", StateHalfOpen, StateOpen},
+		{"/test", StateOpen, StateHalfOpen},
+		{"/test", StateHalfOpen, StateClosed},
+	}, transitions)
+}
+
+func TestCircuitState_String(t *testing.T) {
+	zhtest.AssertEqual(t, "closed", StateClosed.String())
+	zhtest.AssertEqual(t, "open", StateOpen.String())
+	zhtest.AssertEqual(t, "half-open", StateHalfOpen.String())
+	zhtest.AssertEqual(t, "unknown", CircuitState(42).String())
+}
+
+// isBlocked reports whether the circuit blocks a request, ignoring transitions.
+func isBlocked(c *circuit) bool {
+	blocked, _ := c.isOpen()
+	return blocked
 }
@@ -39,6 +39,13 @@ type Config struct {
 	// OpenMessage is the message to return when circuit is open.
 	// Default: "Service temporarily unavailable"
 	OpenMessage string
+
+	// OnStateChange is called after a circuit transitions between states,
+	// with the circuit key and the previous and new states. It runs
+	// synchronously outside of the circuit's lock, so it may query the
+	// breaker but should return quickly.
+	// Default: nil
+	OnStateChange func(key string, from, to CircuitState)
 }
 
 // DefaultConfig contains the default values for circuit breaker configuration.
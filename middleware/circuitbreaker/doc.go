@@ -14,8 +14,8 @@
 //	import "github.com/alexferl/zerohttp/middleware/circuitbreaker"
 //
 //	app.Use(circuitbreaker.New(circuitbreaker.Config{
-//	    Threshold:   5,                // Open after 5 failures
-//	    ResetTimeout: 30 * time.Second, // Try again after 30s
+//	    FailureThreshold: 5,                // Open after 5 failures
+//	    RecoveryTimeout:  30 * time.Second, // Try again after 30s
 //	}))
 //
 // # Per-Endpoint Circuits
@@ -25,4 +25,50 @@
 //	        return r.URL.Path // Separate circuit per endpoint
 //	    },
 //	}))
+//
+// # Statistics
+//
+// Use [NewBreaker] to keep a handle on the breaker and read per-key counters:
+//
+//	breaker := circuitbreaker.NewBreaker()
+//	app.Use(breaker.Middleware)
+//
+//	app.GET("/admin/breakers", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+//	    return zh.R.JSON(w, http.StatusOK, breaker.Stats())
+//	}))
+//
+// Each [Stats] value reports the current state along with cumulative counts
+// of successes, failures, rejections while open and trips to the open state.
+// [Breaker.GetState] returns the current state of a single circuit.
+//
+// # Manual Reset
+//
+// When an upstream is known to have recovered, force its circuit closed
+// instead of waiting for RecoveryTimeout and SuccessThreshold:
+//
+//	app.POST("/admin/breakers/reset", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+//	    breaker.Reset(r.URL.Query().Get("key")) // Or breaker.ResetAll()
+//	    return zh.R.NoContent(w)
+//	}))
+//
+// # State Changes
+//
+// Set OnStateChange to log or alert when a circuit opens or recovers:
+//
+//	app.Use(circuitbreaker.New(circuitbreaker.Config{
+//	    OnStateChange: func(key string, from, to circuitbreaker.CircuitState) {
+//	        log.Printf("circuit %s: %s -> %s", key, from, to)
+//	    },
+//	}))
+//
+// Transitions are also counted by the circuit_breaker_state_changes_total
+// metric, labeled with the key and the previous and new states.
+//
+// # Stale Responses
+//
+// For read-heavy endpoints, register the cachefallback middleware before the
+// circuit breaker to answer with the last successful response while the
+// circuit is open, instead of a 503:
+//
+//	app.Use(cachefallback.New(), circuitbreaker.New())
 package circuitbreaker
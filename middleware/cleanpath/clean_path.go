@@ -0,0 +1,77 @@
+package cleanpath
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	zconfig "github.com/alexferl/zerohttp/internal/config"
+)
+
+// New creates a path cleaning middleware with the provided configuration that
+// normalizes request paths: duplicate slashes are collapsed, "." and ".."
+// segments are resolved as by path.Clean and, optionally, the path is
+// lowercased. A trailing slash is kept, so the middleware can be combined
+// with the trailingslash middleware.
+//
+// Register it with Pre so it runs before routing. Middleware added with Use
+// runs after http.ServeMux has already redirected unclean paths.
+//
+//	app.Pre(cleanpath.New())
+func New(cfg ...Config) func(http.Handler) http.Handler {
+	c := DefaultConfig
+	if len(cfg) > 0 {
+		zconfig.Merge(&c, cfg[0])
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			escaped := r.URL.EscapedPath()
+			cleaned := Clean(escaped, c.Lowercase)
+			if cleaned == escaped {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			unescaped, err := url.PathUnescape(cleaned)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			newURL := *r.URL
+			newURL.Path = unescaped
+			newURL.RawPath = cleaned
+
+			if c.Action == RedirectAction && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+				http.Redirect(w, r, newURL.String(), c.RedirectCode)
+				return
+			}
+
+			r.URL = &newURL
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Clean returns the canonical form of the URL path p: rooted, without
+// duplicate slashes or "." and ".." segments, and lowercased if lowercase is
+// true. A trailing slash is kept.
+func Clean(p string, lowercase bool) string {
+	if lowercase {
+		p = strings.ToLower(p)
+	}
+	if p == "" {
+		return "/"
+	}
+	if p[0] != '/' {
+		p = "/" + p
+	}
+
+	cleaned := path.Clean(p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
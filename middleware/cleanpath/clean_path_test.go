@@ -0,0 +1,75 @@
+package cleanpath
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		path      string
+		lowercase bool
+		expected  string
+	}{
+		{"", false, "/"},
+		{"/", false, "/"},
+		{"//a//b/../c", false, "/a/c"},
+		{"/a/./b/", false, "/a/b/"},
+		{"a/b", false, "/a/b"},
+		{"/../..", false, "/"},
+		{"//evil.example", false, "/evil.example"},
+		{"/Users/Alice", false, "/Users/Alice"},
+		{"/Users//Alice/", true, "/users/alice/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			zhtest.AssertEqual(t, tt.expected, Clean(tt.path, tt.lowercase))
+		})
+	}
+}
+
+func TestCleanPath(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("path: " + r.URL.Path))
+	})
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		method   string
+		target   string
+		status   int
+		location string
+		body     string
+	}{
+		{"clean path passes", DefaultConfig, http.MethodGet, "/a/c", http.StatusOK, "", "path: /a/c"},
+		{"GET redirects", DefaultConfig, http.MethodGet, "//a//b/../c", http.StatusMovedPermanently, "/a/c", ""},
+		{"HEAD redirects", DefaultConfig, http.MethodHead, "//a//b/../c", http.StatusMovedPermanently, "/a/c", ""},
+		{"redirect keeps query", DefaultConfig, http.MethodGet, "/a//b?x=1", http.StatusMovedPermanently, "/a/b?x=1", ""},
+		{"redirect keeps trailing slash", DefaultConfig, http.MethodGet, "/a//b/", http.StatusMovedPermanently, "/a/b/", ""},
+		{"redirect keeps escapes", DefaultConfig, http.MethodGet, "/files//a%2Fb", http.StatusMovedPermanently, "/files/a%2Fb", ""},
+		{"POST is rewritten", DefaultConfig, http.MethodPost, "//a//b/../c", http.StatusOK, "", "path: /a/c"},
+		{"rewrite action", Config{Action: RewriteAction}, http.MethodGet, "//a//b/../c", http.StatusOK, "", "path: /a/c"},
+		{"custom redirect code", Config{RedirectCode: http.StatusPermanentRedirect}, http.MethodGet, "/a//c", http.StatusPermanentRedirect, "/a/c", ""},
+		{"lowercase", Config{Lowercase: true}, http.MethodGet, "/Users/Alice", http.StatusMovedPermanently, "/users/alice", ""},
+		{"lowercase rewrite", Config{Lowercase: true}, http.MethodPut, "/Users//Alice", http.StatusOK, "", "path: /users/alice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := zhtest.NewRequest(tt.method, tt.target).Build()
+			w := zhtest.Serve(New(tt.cfg)(handler), req)
+
+			zhtest.AssertWith(t, w).Status(tt.status)
+			if tt.location != "" {
+				zhtest.AssertWith(t, w).Header(httpx.HeaderLocation, tt.location)
+			} else {
+				zhtest.AssertWith(t, w).Body(tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package cleanpath
+
+import "net/http"
+
+// Action defines the action to take for GET and HEAD requests with a
+// non-canonical path
+type Action string
+
+const (
+	// RedirectAction redirects to the canonical path (default)
+	RedirectAction Action = "redirect"
+	// RewriteAction rewrites the request path and continues processing
+	RewriteAction Action = "rewrite"
+)
+
+// Config allows customization of path cleaning
+type Config struct {
+	// Action to take for GET and HEAD requests with a non-canonical path.
+	// Requests with other methods are always rewritten in place, since
+	// clients may not resend the body after a redirect.
+	// Default: RedirectAction
+	Action Action
+
+	// Lowercase converts paths to lowercase, for case-insensitive routing.
+	// Default: false
+	Lowercase bool
+
+	// RedirectCode for redirects.
+	// Default: 301 (Moved Permanently)
+	RedirectCode int
+}
+
+// DefaultConfig contains the default values for path cleaning configuration.
+var DefaultConfig = Config{
+	Action:       RedirectAction,
+	Lowercase:    false,
+	RedirectCode: http.StatusMovedPermanently,
+}
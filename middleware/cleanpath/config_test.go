@@ -0,0 +1,20 @@
+package cleanpath
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestCleanPathConfig_DefaultValues(t *testing.T) {
+	cfg := DefaultConfig
+	zhtest.AssertEqual(t, RedirectAction, cfg.Action)
+	zhtest.AssertFalse(t, cfg.Lowercase)
+	zhtest.AssertEqual(t, http.StatusMovedPermanently, cfg.RedirectCode)
+}
+
+func TestCleanPathConfig_ActionConstants(t *testing.T) {
+	zhtest.AssertEqual(t, "redirect", string(RedirectAction))
+	zhtest.AssertEqual(t, "rewrite", string(RewriteAction))
+}
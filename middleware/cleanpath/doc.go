@@ -0,0 +1,35 @@
+// Package cleanpath provides request path normalization middleware.
+//
+// Collapses duplicate slashes, resolves "." and ".." segments and optionally
+// lowercases the path, so that /api//users/../orders and /api/orders reach
+// the same route.
+//
+// # Usage
+//
+// Register the middleware with Pre so it runs before routing. Middleware
+// added with Use only runs once http.ServeMux has matched a route, and the
+// mux redirects unclean paths on its own before that:
+//
+//	import "github.com/alexferl/zerohttp/middleware/cleanpath"
+//
+//	// Redirect GET and HEAD requests to the clean path (default)
+//	app.Pre(cleanpath.New())
+//
+//	// Case-insensitive routing, rewriting instead of redirecting
+//	app.Pre(cleanpath.New(cleanpath.Config{
+//	    Lowercase: true,
+//	    Action:    cleanpath.RewriteAction,
+//	}))
+//
+// Requests with other methods, such as POST, are always rewritten in place,
+// since clients may not resend the body after a redirect.
+//
+// # Trailing Slashes
+//
+// A trailing slash is kept, so the trailingslash middleware still decides
+// whether paths end with a slash. A request for //users/ is first
+// redirected to /users/ and then, by trailingslash, to /users:
+//
+//	app.Pre(cleanpath.New())
+//	app.Use(trailingslash.New())
+package cleanpath
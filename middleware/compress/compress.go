@@ -9,12 +9,14 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/alexferl/zerohttp/httpx"
 	zconfig "github.com/alexferl/zerohttp/internal/config"
 	"github.com/alexferl/zerohttp/internal/mwutil"
+	"github.com/alexferl/zerohttp/internal/rwutil"
 	"github.com/alexferl/zerohttp/metrics"
 )
 
@@ -34,6 +36,7 @@ type Compressor struct {
 	algorithmOrder     []Algorithm        // Algorithm precedence order
 	excludedPaths      []string           // Paths to skip compression
 	includedPaths      []string           // Paths to allow compression (if set, only these paths)
+	minLength          int                // Minimum body size to compress
 }
 
 // NewCompressor creates a new Compressor that will handle encoding responses.
@@ -145,6 +148,7 @@ func (c *Compressor) Handler(next http.Handler) http.Handler {
 			encoding:         encoding,
 			compressible:     false,
 			isHeadRequest:    isHead,
+			minLength:        c.minLength,
 		}
 		// Don't use encoder for HEAD requests - it would set incorrect Content-Length
 		if encoder != nil && !isHead {
@@ -166,8 +170,16 @@ func (c *Compressor) Handler(next http.Handler) http.Handler {
 			}
 			reg.Counter("compress_requests_total", "encoding").WithLabelValues(enc).Inc()
 		}()
+		defer func() {
+			if cw.compressible {
+				metrics.RecordUncompressedResponseSize(r.Context(), cw.uncompressedSize)
+			}
+		}()
 
 		next.ServeHTTP(cw, r)
+
+		// Not deferred so that a panicking handler's buffered body isn't sent
+		cw.finish()
 	})
 }
 
@@ -244,6 +256,14 @@ type compressResponseWriter struct {
 	wroteHeader      bool
 	compressible     bool
 	isHeadRequest    bool
+	uncompressedSize int64
+	minLength        int
+
+	// While pending, the status code and the start of the body are held back
+	// until the body is known to reach minLength.
+	pending bool
+	code    int
+	buf     []byte
 }
 
 func (cw *compressResponseWriter) isCompressible() bool {
@@ -296,19 +316,32 @@ func (cw *compressResponseWriter) WriteHeader(code int) {
 	if cw.wroteHeader {
 		return
 	}
+	if rwutil.IsInformational(code) {
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
 	cw.wroteHeader = true
 
 	if cw.shouldCompress(code) {
 		isCompressible := cw.isCompressible()
 		contentType := cw.Header().Get(httpx.HeaderContentType)
 
+		if isCompressible && cw.minLength > 0 {
+			if length, err := strconv.ParseInt(cw.Header().Get(httpx.HeaderContentLength), 10, 64); err == nil {
+				isCompressible = length >= int64(cw.minLength)
+			} else {
+				// The size is unknown until enough of the body is written
+				cw.pending = true
+				cw.code = code
+				return
+			}
+		}
+
 		// Set Content-Encoding header if:
 		// 1. Content is compressible, OR
 		// 2. No Content-Type is set (e.g., HEAD request)
 		if isCompressible || contentType == "" {
-			cw.Header().Set(httpx.HeaderContentEncoding, cw.encoding)
-			cw.Header().Add(httpx.HeaderVary, httpx.HeaderAcceptEncoding)
-			cw.Header().Del(httpx.HeaderContentLength)
+			cw.startEncoding()
 		}
 
 		if isCompressible {
@@ -319,10 +352,57 @@ func (cw *compressResponseWriter) WriteHeader(code int) {
 	cw.ResponseWriter.WriteHeader(code)
 }
 
+// startEncoding sets the headers of a compressed response.
+func (cw *compressResponseWriter) startEncoding() {
+	cw.Header().Set(httpx.HeaderContentEncoding, cw.encoding)
+	cw.Header().Add(httpx.HeaderVary, httpx.HeaderAcceptEncoding)
+	cw.Header().Del(httpx.HeaderContentLength)
+}
+
+// commit ends the pending state, writing the held back status code and body
+// compressed or not.
+func (cw *compressResponseWriter) commit(compress bool) error {
+	cw.pending = false
+	if compress {
+		cw.startEncoding()
+		cw.compressible = true
+	}
+	cw.ResponseWriter.WriteHeader(cw.code)
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 || cw.isHeadRequest {
+		return nil
+	}
+	n, err := cw.writer().Write(buf)
+	if cw.compressible {
+		cw.uncompressedSize += int64(n)
+	}
+	return err
+}
+
+// finish sends a response that ended before reaching minLength uncompressed.
+// A HEAD response without a body is assumed to be compressed like the
+// matching GET response.
+func (cw *compressResponseWriter) finish() {
+	if cw.pending {
+		_ = cw.commit(cw.isHeadRequest && len(cw.buf) == 0)
+	}
+}
+
 func (cw *compressResponseWriter) Write(p []byte) (int, error) {
 	if !cw.wroteHeader {
 		cw.WriteHeader(http.StatusOK)
 	}
+	if cw.pending {
+		if len(cw.buf)+len(p) < cw.minLength {
+			cw.buf = append(cw.buf, p...)
+			return len(p), nil
+		}
+		if err := cw.commit(true); err != nil {
+			return 0, err
+		}
+	}
 	// For HEAD requests, don't write body to response.
 	// We can't set Content-Length correctly because:
 	// 1. WriteHeader is already called by this point
@@ -330,7 +410,12 @@ func (cw *compressResponseWriter) Write(p []byte) (int, error) {
 	if cw.isHeadRequest {
 		return len(p), nil
 	}
-	return cw.writer().Write(p)
+	if cw.compressible {
+		n, err := cw.w.Write(p)
+		cw.uncompressedSize += int64(n)
+		return n, err
+	}
+	return cw.ResponseWriter.Write(p)
 }
 
 func (cw *compressResponseWriter) writer() io.Writer {
@@ -345,6 +430,10 @@ type compressFlusher interface {
 }
 
 func (cw *compressResponseWriter) Flush() {
+	// A flushed response is streamed, so it is compressed whatever its size
+	if cw.pending {
+		_ = cw.commit(true)
+	}
 	if f, ok := cw.writer().(http.Flusher); ok {
 		f.Flush()
 	}
@@ -409,6 +498,7 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 	compressor := NewCompressor(c.Level, c.Types...)
 	compressor.excludedPaths = c.ExcludedPaths
 	compressor.includedPaths = c.IncludedPaths
+	compressor.minLength = c.MinLength
 
 	// Set allowed algorithms and their precedence order
 	compressor.algorithms = make(map[Algorithm]bool)
@@ -29,7 +29,8 @@ func TestCompressETag_OrderIndependent(t *testing.T) {
 	// Order 1: Compress -> ETag (Compress inner, ETag outer - RECOMMENDED)
 	// ETag captures compressed content from inner Compress and computes correct ETag
 	compressMw := New(Config{
-		Types: []string{"text/plain"},
+		Types:     []string{"text/plain"},
+		MinLength: -1,
 	})
 	etagMw := etag.New()
 	chain1 := etagMw(compressMw(handler))
@@ -153,7 +154,8 @@ func TestCompressETag_HeadRequest(t *testing.T) {
 	})
 
 	compressMw := New(Config{
-		Types: []string{"text/plain"},
+		MinLength: -1,
+		Types:     []string{"text/plain"},
 	})
 	etagMw := etag.New()
 	chain := compressMw(etagMw(handler))
@@ -196,7 +198,8 @@ func TestCompressETag_ETagRecomputedFromCompressedBytes(t *testing.T) {
 
 	// Now get ETag with compression using RECOMMENDED order (ETag wraps Compress)
 	compressMw := New(Config{
-		Types: []string{"text/plain"},
+		MinLength: -1,
+		Types:     []string{"text/plain"},
 	})
 	chainWithCompress := etagMw(compressMw(handler))
 
@@ -261,7 +264,8 @@ func TestCompressETag_VaryHeaderAdded(t *testing.T) {
 	})
 
 	compressMw := New(Config{
-		Types: []string{"text/plain"},
+		MinLength: -1,
+		Types:     []string{"text/plain"},
 	})
 	etagMw := etag.New()
 	chain := compressMw(etagMw(handler))
@@ -1,6 +1,7 @@
 package compress
 
 import (
+	"bufio"
 	"compress/flate"
 	"compress/gzip"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	"github.com/alexferl/zerohttp/config"
 	"github.com/alexferl/zerohttp/httpx"
 	"github.com/alexferl/zerohttp/metrics"
+	"github.com/alexferl/zerohttp/sse"
 	"github.com/alexferl/zerohttp/zhtest"
 )
 
@@ -67,8 +69,9 @@ func TestMatchAcceptEncoding(t *testing.T) {
 
 func TestCompress(t *testing.T) {
 	middleware := New(Config{
-		Types: []string{"text/html", "application/json"},
-		Level: 9,
+		MinLength: -1,
+		Types:     []string{"text/html", "application/json"},
+		Level:     9,
 	})
 
 	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -100,6 +103,7 @@ func TestCompress(t *testing.T) {
 
 func TestCompressExcludedPaths(t *testing.T) {
 	middleware := New(Config{
+		MinLength:     -1,
 		ExcludedPaths: []string{"/health", "/metrics", "/api/internal/"},
 	})
 
@@ -171,6 +175,7 @@ func TestCompressAlgorithms(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			middleware := New(Config{
+				MinLength:  -1,
 				Algorithms: tt.algorithms,
 			})
 
@@ -194,6 +199,7 @@ func TestCompressAlgorithms(t *testing.T) {
 func TestCompressAllOptions(t *testing.T) {
 	// Test all options working together
 	middleware := New(Config{
+		MinLength:     -1,
 		Level:         9,
 		Types:         []string{"text/html", "application/json"},
 		Algorithms:    []Algorithm{Gzip},
@@ -695,6 +701,34 @@ func TestCompress_Metrics(t *testing.T) {
 	zhtest.AssertTrue(t, hasGzip)
 }
 
+func TestCompress_MetricsResponseSize(t *testing.T) {
+	reg := metrics.NewRegistry()
+	body := strings.Repeat("test content ", 100)
+
+	handler := New(Config{Types: []string{"text/plain"}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(httpx.HeaderContentType, httpx.MIMETextPlain)
+		_, _ = w.Write([]byte(body))
+	}))
+	wrapped := metrics.NewMiddleware(reg)(handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(httpx.HeaderAcceptEncoding, httpx.ContentEncodingGzip)
+	rr := httptest.NewRecorder()
+	wrapped.ServeHTTP(rr, req)
+
+	sums := make(map[string]float64)
+	for _, f := range reg.Gather() {
+		if len(f.Metrics) > 0 && f.Metrics[0].Histogram != nil {
+			sums[f.Name] = f.Metrics[0].Histogram.Sum
+		}
+	}
+
+	// The wire size is the compressed body, the uncompressed size is what the handler wrote
+	zhtest.AssertEqual(t, float64(rr.Body.Len()), sums["http_response_size_bytes"])
+	zhtest.AssertEqual(t, float64(len(body)), sums["http_response_uncompressed_size_bytes"])
+	zhtest.AssertTrue(t, rr.Body.Len() < len(body))
+}
+
 func TestCompress_WriteHeader_MultipleCalls(t *testing.T) {
 	mw := New(Config{
 		Types: []string{"text/html"},
@@ -772,7 +806,8 @@ func TestCompress_HEADRequest_NoContentLength(t *testing.T) {
 	// Test that HEAD requests don't have Content-Length set for compressed responses
 	// since we can't know the compressed size without actually compressing
 	mw := New(Config{
-		Types: []string{"text/html"},
+		MinLength: -1,
+		Types:     []string{"text/html"},
 	})
 
 	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -798,6 +833,7 @@ func TestCompress_HEADRequest_NoContentLength(t *testing.T) {
 
 func TestCompress_IncludedPaths(t *testing.T) {
 	middleware := New(Config{
+		MinLength:     -1,
 		IncludedPaths: []string{"/api/", "/compress/*"},
 	})
 
@@ -854,6 +890,7 @@ func TestCompressionProvider(t *testing.T) {
 		}}
 
 		mw := New(Config{
+			MinLength:  -1,
 			Types:      []string{"text/plain"},
 			Algorithms: []Algorithm{"nop", Gzip},
 			Providers:  []Provider{provider},
@@ -878,6 +915,7 @@ func TestCompressionProvider(t *testing.T) {
 		provider := &testProvider{encoders: map[string]Encoder{}}
 
 		mw := New(Config{
+			MinLength:  -1,
 			Types:      []string{"text/plain"},
 			Algorithms: []Algorithm{Gzip},
 			Providers:  []Provider{provider},
@@ -905,6 +943,7 @@ func TestCompressionProvider(t *testing.T) {
 		}}
 
 		mw := New(Config{
+			MinLength:  -1,
 			Types:      []string{"text/plain"},
 			Algorithms: []Algorithm{"testlevel"},
 			Level:      9,
@@ -934,6 +973,7 @@ func TestCompressionProvider(t *testing.T) {
 		}}
 
 		mw := New(Config{
+			MinLength:  -1,
 			Types:      []string{"text/plain"},
 			Algorithms: []Algorithm{"custom1", "custom2"},
 			Providers:  []Provider{provider},
@@ -966,6 +1006,7 @@ func TestCompressionProvider(t *testing.T) {
 		}}
 
 		mw := New(Config{
+			MinLength:  -1,
 			Types:      []string{"text/plain"},
 			Algorithms: []Algorithm{Gzip, "custom"},
 			Providers:  []Provider{provider},
@@ -1002,6 +1043,7 @@ func TestCompressionProvider(t *testing.T) {
 		}}
 
 		mw := New(Config{
+			MinLength:  -1,
 			Types:      []string{"text/plain"},
 			Algorithms: []Algorithm{"brotli", "zstd"},
 			Providers:  []Provider{provider1, provider2},
@@ -1029,6 +1071,7 @@ func TestCompressionProvider(t *testing.T) {
 
 	t.Run("nil provider uses defaults only", func(t *testing.T) {
 		mw := New(Config{
+			MinLength:  -1,
 			Types:      []string{"text/plain"},
 			Algorithms: []Algorithm{Gzip},
 			Providers:  nil,
@@ -1062,6 +1105,7 @@ func TestCompressionProvider(t *testing.T) {
 
 		// Test with zstd first in algorithms - it should be preferred
 		mw := New(Config{
+			MinLength:  -1,
 			Types:      []string{"text/plain"},
 			Algorithms: []Algorithm{"zstd", "br"},
 			Providers:  []Provider{brotliProvider, zstdProvider},
@@ -1081,6 +1125,7 @@ func TestCompressionProvider(t *testing.T) {
 
 		// Test with brotli first in algorithms - it should be preferred
 		mw2 := New(Config{
+			MinLength:  -1,
 			Types:      []string{"text/plain"},
 			Algorithms: []Algorithm{"br", "zstd"},
 			Providers:  []Provider{zstdProvider, brotliProvider},
@@ -1147,7 +1192,8 @@ func (p *testProvider) GetEncoder(encoding string) Encoder {
 func TestCompress_WriteHeaderBeforeWrite(t *testing.T) {
 	t.Run("status code is preserved when WriteHeader is called before Write", func(t *testing.T) {
 		mw := New(Config{
-			Types: []string{"text/html"},
+			MinLength: -1,
+			Types:     []string{"text/html"},
 		})
 
 		handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1168,7 +1214,8 @@ func TestCompress_WriteHeaderBeforeWrite(t *testing.T) {
 
 	t.Run("non-200 status codes work correctly", func(t *testing.T) {
 		mw := New(Config{
-			Types: []string{"application/json"},
+			MinLength: -1,
+			Types:     []string{"application/json"},
 		})
 
 		testCases := []int{
@@ -1200,7 +1247,8 @@ func TestCompress_WriteHeaderBeforeWrite(t *testing.T) {
 
 	t.Run("multiple WriteHeader calls use first status", func(t *testing.T) {
 		mw := New(Config{
-			Types: []string{"text/plain"},
+			MinLength: -1,
+			Types:     []string{"text/plain"},
 		})
 
 		handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1221,7 +1269,8 @@ func TestCompress_WriteHeaderBeforeWrite(t *testing.T) {
 
 	t.Run("implicit 200 when Write is called without WriteHeader", func(t *testing.T) {
 		mw := New(Config{
-			Types: []string{"text/html"},
+			MinLength: -1,
+			Types:     []string{"text/html"},
 		})
 
 		handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1242,7 +1291,8 @@ func TestCompress_WriteHeaderBeforeWrite(t *testing.T) {
 
 	t.Run("non-compressible types are not compressed", func(t *testing.T) {
 		mw := New(Config{
-			Types: []string{"text/html"}, // Only HTML, not images
+			MinLength: -1,
+			Types:     []string{"text/html"}, // Only HTML, not images
 		})
 
 		handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1265,7 +1315,8 @@ func TestCompress_WriteHeaderBeforeWrite(t *testing.T) {
 
 	t.Run("no compression when Accept-Encoding not set", func(t *testing.T) {
 		mw := New(Config{
-			Types: []string{"text/html"},
+			MinLength: -1,
+			Types:     []string{"text/html"},
 		})
 
 		handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1283,3 +1334,223 @@ func TestCompress_WriteHeaderBeforeWrite(t *testing.T) {
 		zhtest.AssertEqual(t, "", rr.Header().Get(httpx.HeaderContentEncoding))
 	})
 }
+
+func TestCompress_MinLength(t *testing.T) {
+	large := strings.Repeat("a", 300)
+
+	tests := []struct {
+		name           string
+		minLength      int
+		handler        http.HandlerFunc
+		wantCompressed bool
+		wantBody       string
+	}{
+		{
+			name: "small body is not compressed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(httpx.HeaderContentType, httpx.MIMEApplicationJSON)
+				_, _ = w.Write([]byte(`{"ok":true}`))
+			},
+			wantBody: `{"ok":true}`,
+		},
+		{
+			name: "body reaching the threshold over several writes is compressed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(httpx.HeaderContentType, httpx.MIMETextPlain)
+				for range 3 {
+					_, _ = w.Write([]byte(large[:100]))
+				}
+			},
+			wantCompressed: true,
+			wantBody:       large,
+		},
+		{
+			name: "small Content-Length is not compressed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(httpx.HeaderContentType, httpx.MIMETextPlain)
+				w.Header().Set(httpx.HeaderContentLength, "5")
+				_, _ = w.Write([]byte("hello"))
+			},
+			wantBody: "hello",
+		},
+		{
+			name: "large Content-Length is compressed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(httpx.HeaderContentType, httpx.MIMETextPlain)
+				w.Header().Set(httpx.HeaderContentLength, "300")
+				_, _ = w.Write([]byte(large))
+			},
+			wantCompressed: true,
+			wantBody:       large,
+		},
+		{
+			name: "flushed body is compressed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(httpx.HeaderContentType, httpx.MIMETextPlain)
+				_, _ = w.Write([]byte("hello"))
+				w.(http.Flusher).Flush()
+			},
+			wantCompressed: true,
+			wantBody:       "hello",
+		},
+		{
+			name: "small body after WriteHeader is not compressed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(httpx.HeaderContentType, httpx.MIMETextPlain)
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte("created"))
+			},
+			wantBody: "created",
+		},
+		{
+			name:      "custom threshold",
+			minLength: 4,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(httpx.HeaderContentType, httpx.MIMETextPlain)
+				_, _ = w.Write([]byte("hello"))
+			},
+			wantCompressed: true,
+			wantBody:       "hello",
+		},
+		{
+			name:      "negative threshold compresses everything",
+			minLength: -1,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(httpx.HeaderContentType, httpx.MIMETextPlain)
+				_, _ = w.Write([]byte("hi"))
+			},
+			wantCompressed: true,
+			wantBody:       "hi",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := New(Config{MinLength: tt.minLength})(tt.handler)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(httpx.HeaderAcceptEncoding, httpx.ContentEncodingGzip)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if !tt.wantCompressed {
+				zhtest.AssertWith(t, rr).
+					HeaderNotExists(httpx.HeaderContentEncoding).
+					HeaderNotExists(httpx.HeaderVary).
+					Body(tt.wantBody)
+				return
+			}
+
+			zhtest.AssertWith(t, rr).
+				Header(httpx.HeaderContentEncoding, httpx.ContentEncodingGzip).
+				Header(httpx.HeaderVary, httpx.HeaderAcceptEncoding).
+				HeaderNotExists(httpx.HeaderContentLength)
+
+			reader, err := gzip.NewReader(rr.Body)
+			zhtest.AssertNoError(t, err)
+			body, err := io.ReadAll(reader)
+			zhtest.AssertNoError(t, err)
+			zhtest.AssertEqual(t, tt.wantBody, string(body))
+		})
+	}
+
+	t.Run("status code is kept for empty bodies", func(t *testing.T) {
+		handler := New()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(httpx.HeaderContentType, httpx.MIMETextPlain)
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(httpx.HeaderAcceptEncoding, httpx.ContentEncodingGzip)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		zhtest.AssertWith(t, rr).Status(http.StatusCreated).BodyEmpty()
+	})
+
+	t.Run("panicking handler sends nothing", func(t *testing.T) {
+		handler := New()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(httpx.HeaderContentType, httpx.MIMETextPlain)
+			_, _ = w.Write([]byte("partial"))
+			panic("boom")
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(httpx.HeaderAcceptEncoding, httpx.ContentEncodingGzip)
+		rr := httptest.NewRecorder()
+		zhtest.AssertPanic(t, func() { handler.ServeHTTP(rr, req) })
+
+		zhtest.AssertFalse(t, rr.Flushed)
+		zhtest.AssertWith(t, rr).BodyEmpty()
+	})
+}
+
+func TestCompress_StreamsFlushedWrites(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding string
+		reader   func(io.Reader) (io.Reader, error)
+	}{
+		{"gzip", httpx.ContentEncodingGzip, func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+		{"deflate", httpx.ContentEncodingDeflate, func(r io.Reader) (io.Reader, error) { return flate.NewReader(r), nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := make(chan struct{})
+			handler := New(Config{
+				Algorithms: []Algorithm{Gzip, Deflate},
+				Types:      []string{httpx.MIMETextEventStream},
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				stream, err := sse.New(w, r)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				defer func() { _ = stream.Close() }()
+
+				for i := range 3 {
+					if err := stream.Send(sse.Event{Data: []byte(fmt.Sprintf("event %d", i))}); err != nil {
+						return
+					}
+					// The next event is only sent once the client got this one
+					select {
+					case <-next:
+					case <-r.Context().Done():
+						return
+					}
+				}
+			}))
+
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			zhtest.AssertNoError(t, err)
+			req.Header.Set(httpx.HeaderAcceptEncoding, tt.encoding)
+
+			resp, err := (&http.Transport{DisableCompression: true}).RoundTrip(req)
+			zhtest.AssertNoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			zhtest.AssertEqual(t, tt.encoding, resp.Header.Get(httpx.HeaderContentEncoding))
+			zhtest.AssertEqual(t, httpx.MIMETextEventStream, resp.Header.Get(httpx.HeaderContentType))
+
+			body, err := tt.reader(resp.Body)
+			zhtest.AssertNoError(t, err)
+			lines := bufio.NewScanner(body)
+
+			for i := range 3 {
+				var event string
+				for lines.Scan() {
+					if data, ok := strings.CutPrefix(lines.Text(), "data: "); ok {
+						event = data
+						break
+					}
+				}
+				zhtest.AssertEqual(t, fmt.Sprintf("event %d", i), event)
+				next <- struct{}{}
+			}
+		})
+	}
+}
@@ -105,6 +105,14 @@ type Config struct {
 	// Default: []
 	IncludedPaths []string
 
+	// MinLength is the minimum response body size in bytes to compress.
+	// Smaller responses are sent uncompressed since compression would barely
+	// shrink them or even make them larger. The start of the body is buffered
+	// until the threshold is reached unless Content-Length is set.
+	// Set to a negative value to compress regardless of size.
+	// Default: 256
+	MinLength int
+
 	// Providers are optional custom compression providers.
 	// If set, the providers' encoders will be used in addition to built-in gzip/deflate.
 	// This allows users to add Brotli, zstd, or other algorithms without core dependencies.
@@ -119,4 +127,5 @@ var DefaultConfig = Config{
 	Algorithms:    []Algorithm{Gzip, Deflate},
 	ExcludedPaths: []string{},
 	IncludedPaths: []string{},
+	MinLength:     256,
 }
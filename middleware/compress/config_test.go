@@ -13,6 +13,7 @@ func TestCompressConfig_DefaultValues(t *testing.T) {
 	zhtest.AssertEqual(t, 2, len(cfg.Algorithms))
 	zhtest.AssertEqual(t, 0, len(cfg.ExcludedPaths))
 	zhtest.AssertEqual(t, 0, len(cfg.IncludedPaths))
+	zhtest.AssertEqual(t, 256, cfg.MinLength)
 
 	expectedAlgorithms := []Algorithm{Gzip, Deflate}
 	zhtest.AssertEqual(t, expectedAlgorithms, cfg.Algorithms)
@@ -24,4 +24,32 @@
 //	app.Use(compress.New(compress.Config{
 //	    Types: []string{"text/html", "application/json", "application/xml"},
 //	}))
+//
+// # Minimum Length
+//
+// Responses smaller than MinLength (256 bytes by default) are sent
+// uncompressed since compressing them saves little or even makes them larger.
+// When Content-Length isn't set, the start of the body is buffered until it
+// reaches the threshold:
+//
+//	app.Use(compress.New(compress.Config{
+//	    MinLength: 1024,
+//	}))
+//
+// # Streaming
+//
+// Responses are compressed as they are written, never buffered until the
+// handler returns, apart from the first MinLength bytes. The encoder holds
+// back data until it has enough to compress, so streaming handlers such as
+// Server-Sent Events or progress updates should flush after each chunk:
+// Flush, including through http.ResponseController, flushes the encoder and
+// then the connection, so the client can decode everything sent so far.
+// A flushed response is compressed even if it's shorter than MinLength.
+//
+// text/event-stream isn't compressed by default. Add it to Types to
+// compress SSE streams:
+//
+//	app.Use(compress.New(compress.Config{
+//	    Types: append(slices.Clone(compress.DefaultCompressTypes), httpx.MIMETextEventStream),
+//	}))
 package compress
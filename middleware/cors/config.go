@@ -13,6 +13,11 @@ type OriginValidator func(origin string) bool
 // Config allows customization of CORS behavior
 type Config struct {
 	// AllowedOrigins is a list of allowed origins. Use ["*"] to allow all origins.
+	// An origin may contain a single wildcard to match a subdomain or a port,
+	// such as "https://*.example.com" or "http://localhost:*". The wildcard
+	// doesn't match the bare domain, so list "https://example.com" separately
+	// to allow it. Allowed requests receive their own origin in
+	// Access-Control-Allow-Origin, never the pattern.
 	// Default: ["*"]
 	AllowedOrigins []string
 
@@ -54,7 +59,8 @@ type Config struct {
 	// Default: []
 	IncludedPaths []string
 
-	// AllowOriginFunc is a custom function to validate origins dynamically.
+	// AllowOriginFunc is a custom function to validate origins dynamically,
+	// for logic that patterns can't express such as regular expressions.
 	// If set, this takes precedence over AllowedOrigins matching.
 	AllowOriginFunc OriginValidator
 }
@@ -1,7 +1,9 @@
 package cors
 
 import (
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -22,12 +24,17 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 	mwutil.ValidatePathConfig(c.ExcludedPaths, c.IncludedPaths, "CORS")
 
 	allowedOriginMap := make(map[string]bool)
+	var allowedOriginPatterns []originPattern
 	allowAllOrigins := false
 	for _, origin := range c.AllowedOrigins {
 		if origin == "*" {
 			allowAllOrigins = true
 			break
 		}
+		if strings.Contains(origin, "*") {
+			allowedOriginPatterns = append(allowedOriginPatterns, newOriginPattern(origin))
+			continue
+		}
 		allowedOriginMap[strings.ToLower(origin)] = true
 	}
 
@@ -84,6 +91,12 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 				originAllowed = true
 			} else if allowedOriginMap[strings.ToLower(origin)] {
 				originAllowed = true
+			} else if len(allowedOriginPatterns) > 0 {
+				// The response depends on the origin matching a pattern
+				w.Header().Add(httpx.HeaderVary, httpx.HeaderOrigin)
+				originAllowed = slices.ContainsFunc(allowedOriginPatterns, func(p originPattern) bool {
+					return p.match(origin)
+				})
 			}
 
 			if originAllowed {
@@ -173,3 +186,33 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// originPattern is an allowed origin containing a wildcard, such as
+// "https://*.example.com".
+type originPattern struct {
+	prefix string
+	suffix string
+}
+
+// newOriginPattern parses an allowed origin with a single wildcard.
+// Panics if the origin contains more than one.
+func newOriginPattern(origin string) originPattern {
+	prefix, suffix, _ := strings.Cut(strings.ToLower(origin), "*")
+	if strings.Contains(suffix, "*") {
+		panic(fmt.Sprintf("zerohttp: CORS origin %q may contain only one wildcard", origin))
+	}
+	return originPattern{prefix: prefix, suffix: suffix}
+}
+
+// match reports whether origin matches the pattern. The wildcard matches one
+// or more characters within the host or port, so it can't be used to match
+// another scheme or smuggle in a different host.
+func (p originPattern) match(origin string) bool {
+	origin = strings.ToLower(origin)
+	if len(origin) <= len(p.prefix)+len(p.suffix) ||
+		!strings.HasPrefix(origin, p.prefix) || !strings.HasSuffix(origin, p.suffix) {
+		return false
+	}
+	wildcard := origin[len(p.prefix) : len(origin)-len(p.suffix)]
+	return !strings.ContainsAny(wildcard, "/@?#")
+}
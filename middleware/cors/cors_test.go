@@ -132,6 +132,63 @@ func TestCORSCustomOrigins(t *testing.T) {
 	}
 }
 
+func TestCORSWildcardOrigins(t *testing.T) {
+	tests := []struct {
+		origin, expectOrigin string
+	}{
+		{"https://api.example.com", "https://api.example.com"},
+		{"https://a.b.example.com", "https://a.b.example.com"},
+		{"HTTPS://API.EXAMPLE.COM", "HTTPS://API.EXAMPLE.COM"},
+		{"https://example.com", "https://example.com"},
+		{"https://.example.com", ""},
+		{"http://api.example.com", ""},
+		{"https://api.example.com.evil.com", ""},
+		{"https://evil.com/.example.com", ""},
+		{"https://evilexample.com", ""},
+		{"http://localhost:3000", "http://localhost:3000"},
+		{"http://localhost", ""},
+	}
+	mw := New(Config{
+		AllowedOrigins:   []string{"https://example.com", "https://*.example.com", "http://localhost:*"},
+		AllowCredentials: true,
+	})
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set(httpx.HeaderOrigin, tt.origin)
+			rr := httptest.NewRecorder()
+			mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})).ServeHTTP(rr, req)
+
+			zhtest.AssertWith(t, rr).Header(httpx.HeaderAccessControlAllowOrigin, tt.expectOrigin)
+			if tt.expectOrigin != "" {
+				zhtest.AssertWith(t, rr).Header(httpx.HeaderAccessControlAllowCredentials, "true")
+			}
+		})
+	}
+
+	t.Run("preflight", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/test", nil)
+		req.Header.Set(httpx.HeaderOrigin, "https://app.example.com")
+		req.Header.Set(httpx.HeaderAccessControlRequestMethod, http.MethodPut)
+		rr := httptest.NewRecorder()
+		mw(http.NotFoundHandler()).ServeHTTP(rr, req)
+
+		zhtest.AssertWith(t, rr).
+			Status(http.StatusNoContent).
+			Header(httpx.HeaderAccessControlAllowOrigin, "https://app.example.com").
+			Header(httpx.HeaderVary, httpx.HeaderOrigin).
+			HeaderExists(httpx.HeaderAccessControlAllowMethods)
+	})
+
+	t.Run("more than one wildcard panics", func(t *testing.T) {
+		zhtest.AssertPanic(t, func() {
+			_ = New(Config{AllowedOrigins: []string{"https://*.*.example.com"}})
+		})
+	})
+}
+
 func TestCORSCredentials(t *testing.T) {
 	mw := New(Config{
 		AllowedOrigins:   []string{"https://example.com"},
@@ -19,10 +19,16 @@
 //	    MaxAge: 3600,
 //	}))
 //
+//	// Wildcard origins, matching any subdomain
+//	app.Use(cors.New(cors.Config{
+//	    AllowedOrigins:   []string{"https://example.com", "https://*.example.com"},
+//	    AllowCredentials: true,
+//	}))
+//
 //	// Dynamic origin validation
 //	app.Use(cors.New(cors.Config{
 //	    AllowOriginFunc: func(origin string) bool {
-//	        return strings.HasSuffix(origin, ".example.com")
+//	        return previewOrigin.MatchString(origin) // e.g. a *regexp.Regexp
 //	    },
 //	}))
 package cors
@@ -0,0 +1,25 @@
+package disconnect
+
+// Config allows customization of the disconnect middleware
+type Config struct {
+	// ExcludedPaths contains paths whose context isn't canceled with
+	// [ErrClientDisconnected].
+	// Supports exact matches, prefixes (ending with /), and wildcards (ending with *).
+	// Cannot be used with IncludedPaths - setting both will panic.
+	// Default: []
+	ExcludedPaths []string
+
+	// IncludedPaths contains paths where the middleware is explicitly applied.
+	// If set, only requests matching these patterns are watched.
+	// Supports exact matches, prefixes (ending with /), and wildcards (ending with *).
+	// If empty, all paths are watched (subject to ExcludedPaths).
+	// Cannot be used with ExcludedPaths - setting both will panic.
+	// Default: []
+	IncludedPaths []string
+}
+
+// DefaultConfig contains the default values for disconnect configuration.
+var DefaultConfig = Config{
+	ExcludedPaths: []string{},
+	IncludedPaths: []string{},
+}
@@ -0,0 +1,19 @@
+package disconnect
+
+import (
+	"testing"
+
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestDisconnectConfig_DefaultValues(t *testing.T) {
+	cfg := DefaultConfig
+	zhtest.AssertEqual(t, 0, len(cfg.ExcludedPaths))
+	zhtest.AssertEqual(t, 0, len(cfg.IncludedPaths))
+}
+
+func TestDisconnectConfig_BothPathsPanics(t *testing.T) {
+	zhtest.AssertPanic(t, func() {
+		New(Config{ExcludedPaths: []string{"/a"}, IncludedPaths: []string{"/b"}})
+	})
+}
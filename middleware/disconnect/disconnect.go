@@ -0,0 +1,89 @@
+package disconnect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	zconfig "github.com/alexferl/zerohttp/internal/config"
+	"github.com/alexferl/zerohttp/internal/mwutil"
+)
+
+// ErrClientDisconnected is the cause of the request context canceled by the
+// middleware when the client goes away before the response is complete.
+var ErrClientDisconnected = errors.New("client disconnected")
+
+// ErrServerShutdown is the cause of the request context canceled by the
+// server when it shuts down. It is not reported as a disconnect.
+var ErrServerShutdown = errors.New("server shutting down")
+
+// New creates a middleware with the provided configuration that cancels the
+// request context with [ErrClientDisconnected] as its cause when the client
+// disconnects, so downstream work such as database queries and upstream
+// calls aborts and can tell a disconnect apart from a timeout.
+//
+// The server cancels the request context without a cause when the
+// connection is closed or the HTTP/2 stream is reset. The middleware turns
+// that cancellation into [ErrClientDisconnected]; deadlines and
+// cancellations carrying their own cause, such as [ErrServerShutdown], are
+// passed through as is. Add it
+// before any middleware that cancels the request context, such as the
+// timeout middleware.
+func New(cfg ...Config) func(http.Handler) http.Handler {
+	c := DefaultConfig
+	if len(cfg) > 0 {
+		zconfig.Merge(&c, cfg[0])
+	}
+
+	mwutil.ValidatePathConfig(c.ExcludedPaths, c.IncludedPaths, "Disconnect")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !mwutil.ShouldProcessMiddleware(r.URL.Path, c.IncludedPaths, c.ExcludedPaths) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Detach from the server context so its cancellation can be
+			// replaced with one carrying the right cause
+			parent := r.Context()
+			ctx, cancel := context.WithCancelCause(context.WithoutCancel(parent))
+			defer cancel(nil)
+			if deadline, ok := parent.Deadline(); ok {
+				var cancelDeadline context.CancelFunc
+				ctx, cancelDeadline = context.WithDeadline(ctx, deadline)
+				defer cancelDeadline()
+			}
+
+			// Deadlines are left to the context above, so its error stays
+			// context.DeadlineExceeded
+			stop := context.AfterFunc(parent, func() {
+				if !errors.Is(parent.Err(), context.DeadlineExceeded) {
+					cancel(cause(parent))
+				}
+			})
+			defer stop()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Disconnected reports whether ctx was canceled because the client went
+// away, either by the middleware or by the server, which cancels request
+// contexts without a cause. A context canceled by a shutdown, with
+// [ErrServerShutdown] as its cause, is not disconnected.
+func Disconnected(ctx context.Context) bool {
+	return errors.Is(cause(ctx), ErrClientDisconnected)
+}
+
+// cause returns the cause of the cancellation of ctx, with the
+// cancellation by the server for a closed connection replaced by
+// [ErrClientDisconnected]. A shutdown keeps [ErrServerShutdown].
+func cause(ctx context.Context) error {
+	err := context.Cause(ctx)
+	if err == context.Canceled {
+		return ErrClientDisconnected
+	}
+	return err
+}
@@ -0,0 +1,127 @@
+package disconnect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+// causeHandler records the cause of the cancellation of the request context.
+func causeHandler(cancel func(), cause chan<- error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-r.Context().Done()
+		cause <- context.Cause(r.Context())
+	})
+}
+
+func TestDisconnect_Cause(t *testing.T) {
+	t.Run("canceled by the server", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cause := make(chan error, 1)
+		req := zhtest.NewRequest(http.MethodGet, "/").Build().WithContext(ctx)
+		zhtest.Serve(New()(causeHandler(cancel, cause)), req)
+
+		err := <-cause
+		zhtest.AssertTrue(t, errors.Is(err, ErrClientDisconnected))
+		zhtest.AssertTrue(t, Disconnected(ctx))
+	})
+
+	t.Run("canceled with a cause", func(t *testing.T) {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		canceled := errors.New("canceled")
+		cause := make(chan error, 1)
+		req := zhtest.NewRequest(http.MethodGet, "/").Build().WithContext(ctx)
+		zhtest.Serve(New()(causeHandler(func() { cancel(canceled) }, cause)), req)
+
+		zhtest.AssertEqual(t, canceled, <-cause)
+		zhtest.AssertFalse(t, Disconnected(ctx))
+	})
+
+	t.Run("server shutdown", func(t *testing.T) {
+		base, cancel := context.WithCancelCause(context.Background())
+		ctx, cancelReq := context.WithCancel(base)
+		defer cancelReq()
+		cause := make(chan error, 1)
+		req := zhtest.NewRequest(http.MethodGet, "/").Build().WithContext(ctx)
+		zhtest.Serve(New()(causeHandler(func() { cancel(ErrServerShutdown) }, cause)), req)
+
+		zhtest.AssertEqual(t, ErrServerShutdown, <-cause)
+		zhtest.AssertFalse(t, Disconnected(ctx))
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		var deadline time.Time
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			deadline, _ = r.Context().Deadline()
+			<-r.Context().Done()
+			zhtest.AssertTrue(t, errors.Is(r.Context().Err(), context.DeadlineExceeded))
+		})
+		req := zhtest.NewRequest(http.MethodGet, "/").Build().WithContext(ctx)
+		zhtest.Serve(New()(handler), req)
+
+		want, _ := ctx.Deadline()
+		zhtest.AssertEqual(t, want, deadline)
+		zhtest.AssertFalse(t, Disconnected(ctx))
+	})
+
+	t.Run("excluded path", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cause := make(chan error, 1)
+		req := zhtest.NewRequest(http.MethodGet, "/health").Build().WithContext(ctx)
+		zhtest.Serve(New(Config{ExcludedPaths: []string{"/health"}})(causeHandler(cancel, cause)), req)
+
+		zhtest.AssertEqual(t, context.Canceled, <-cause)
+	})
+
+	t.Run("values are kept", func(t *testing.T) {
+		type key struct{}
+		ctx := context.WithValue(context.Background(), key{}, "v")
+		var value any
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			value = r.Context().Value(key{})
+		})
+		req := zhtest.NewRequest(http.MethodGet, "/").Build().WithContext(ctx)
+		zhtest.Serve(New()(handler), req)
+
+		zhtest.AssertEqual(t, "v", value)
+	})
+}
+
+func TestDisconnect_RealConnection(t *testing.T) {
+	cause := make(chan error, 1)
+	started := make(chan struct{})
+	server := httptest.NewServer(New()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+		cause <- context.Cause(r.Context())
+	})))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	zhtest.AssertNoError(t, err)
+	go func() {
+		resp, err := http.DefaultClient.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case err := <-cause:
+		zhtest.AssertEqual(t, ErrClientDisconnected, err)
+	case <-time.After(2 * time.Second):
+		zhtest.AssertFail(t, "request context was not canceled on disconnect")
+	}
+}
@@ -0,0 +1,34 @@
+// Package disconnect provides middleware that cancels the request context
+// when the client disconnects.
+//
+// The server already cancels the request context when the connection is
+// closed. The middleware gives that cancellation [ErrClientDisconnected] as
+// its cause, so handlers can tell a client that went away apart from a
+// timeout and stop their work early.
+//
+// # Usage
+//
+//	import "github.com/alexferl/zerohttp/middleware/disconnect"
+//
+//	app.Use(disconnect.New())
+//
+//	app.GET("/report", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+//	    rows, err := db.QueryContext(r.Context(), query)
+//	    if errors.Is(context.Cause(r.Context()), disconnect.ErrClientDisconnected) {
+//	        return nil // Nobody is left to answer
+//	    }
+//	    ...
+//	}))
+//
+// A graceful shutdown cancels request contexts with [ErrServerShutdown] as
+// their cause, which is not reported as a disconnect.
+//
+// Add it before middleware that cancels the request context themselves,
+// such as the timeout middleware, so their cancellations aren't mistaken
+// for disconnects.
+//
+// # Logging
+//
+// The request logger adds a client_disconnected field to the requests whose
+// client went away before the response was complete.
+package disconnect
@@ -39,15 +39,21 @@
 //   - [github.com/alexferl/zerohttp/middleware/securityheaders] - Security headers (CSP, HSTS, X-Frame-Options, etc.)
 //   - [github.com/alexferl/zerohttp/middleware/requestbodysize] - Request body size limiting
 //   - [github.com/alexferl/zerohttp/middleware/host] - Host header validation
+//   - [github.com/alexferl/zerohttp/middleware/ipfilter] - IP allowlist/blocklist with CIDR ranges
+//   - [github.com/alexferl/zerohttp/middleware/session] - Cookie sessions with in-memory, encrypted cookie or custom stores
 //
 // Traffic Management:
 //   - [github.com/alexferl/zerohttp/middleware/ratelimit] - Token bucket or sliding window rate limiting
 //   - [github.com/alexferl/zerohttp/middleware/circuitbreaker] - Circuit breaker pattern for fault tolerance
+//   - [github.com/alexferl/zerohttp/middleware/cachefallback] - Stale responses served in place of server errors
 //   - [github.com/alexferl/zerohttp/middleware/timeout] - Request timeout handling
+//   - [github.com/alexferl/zerohttp/middleware/disconnect] - Request context cancellation on client disconnect
+//   - [github.com/alexferl/zerohttp/middleware/maintenance] - Runtime-toggleable maintenance mode
 //   - [github.com/alexferl/zerohttp/middleware/reverseproxy] - Reverse proxy with load balancing
 //
 // Observability:
 //   - [github.com/alexferl/zerohttp/middleware/requestlogger] - HTTP request/response logging
+//   - [github.com/alexferl/zerohttp/middleware/requestdump] - Raw request dumps at Debug level for debugging
 //   - [github.com/alexferl/zerohttp/middleware/requestid] - Request ID generation and propagation
 //   - [github.com/alexferl/zerohttp/middleware/realip] - Client IP extraction from proxy headers
 //   - [github.com/alexferl/zerohttp/middleware/tracer] - Distributed tracing support
@@ -58,6 +64,7 @@
 //   - [github.com/alexferl/zerohttp/middleware/contentencoding] - Content-Encoding negotiation
 //   - [github.com/alexferl/zerohttp/middleware/contentcharset] - Content-Type charset validation
 //   - [github.com/alexferl/zerohttp/middleware/mediatype] - Media type negotiation with wildcard and suffix support
+//   - [github.com/alexferl/zerohttp/middleware/locale] - Accept-Language negotiation with query and cookie overrides
 //
 // Caching:
 //   - [github.com/alexferl/zerohttp/middleware/cache] - HTTP caching with ETag and Last-Modified
@@ -67,9 +74,10 @@
 // Utilities:
 //   - [github.com/alexferl/zerohttp/middleware/recover] - Panic recovery middleware
 //   - [github.com/alexferl/zerohttp/middleware/trailingslash] - Trailing slash normalization
+//   - [github.com/alexferl/zerohttp/middleware/cleanpath] - Path cleaning and lowercasing before routing
 //   - [github.com/alexferl/zerohttp/middleware/setheader] - Custom response header injection
 //   - [github.com/alexferl/zerohttp/middleware/idempotency] - Idempotent request handling
-//   - [github.com/alexferl/zerohttp/middleware/value] - Context value injection
+//   - [github.com/alexferl/zerohttp/middleware/value] - Context value injection and typed context keys
 //
 // # Creating Custom Middleware
 //
@@ -86,7 +86,7 @@ func (ew *etagResponseWriter) release() {
 // writeHeaderLocked captures the status code and checks if New generation should be skipped.
 // Caller must hold ew.mu.
 func (ew *etagResponseWriter) writeHeaderLocked(status int) {
-	if ew.HasWritten {
+	if ew.HasWritten || rwutil.IsInformational(status) {
 		return
 	}
 	ew.HasWritten = true
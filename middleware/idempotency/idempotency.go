@@ -183,7 +183,7 @@ type idempotencyResponseRecorder struct {
 }
 
 func (i *idempotencyResponseRecorder) WriteHeader(statusCode int) {
-	if i.HasWritten {
+	if i.HasWritten || rwutil.IsInformational(statusCode) {
 		return
 	}
 	i.ResponseBuffer.WriteHeader(statusCode)
@@ -0,0 +1,75 @@
+package ipfilter
+
+import (
+	"net/http"
+
+	"github.com/alexferl/zerohttp/middleware/realip"
+)
+
+// Precedence determines which list wins when a client IP matches both
+// an allow and a deny rule.
+type Precedence string
+
+const (
+	// DenyFirst rejects an IP that matches any deny rule, even if it is also allowed.
+	DenyFirst Precedence = "deny_first"
+	// AllowFirst accepts an IP that matches any allow rule, even if it is also denied.
+	AllowFirst Precedence = "allow_first"
+)
+
+// Config allows customization of IP filtering
+type Config struct {
+	// Allow is a list of IP addresses or CIDR ranges (IPv4 or IPv6) that are permitted.
+	// If non-empty, only matching client IPs are allowed through.
+	// Default: []
+	Allow []string
+
+	// Deny is a list of IP addresses or CIDR ranges (IPv4 or IPv6) that are rejected.
+	// Default: []
+	Deny []string
+
+	// Precedence decides the outcome when an IP matches both Allow and Deny.
+	// Default: DenyFirst
+	Precedence Precedence
+
+	// IPExtractor resolves the client IP from the request.
+	// The default uses the connection address and ignores proxy headers.
+	// Behind a proxy, use realip.TrustedProxiesIPExtractor so forwarding
+	// headers are only honored when they come from the proxy.
+	// Default: realip.RemoteAddrIPExtractor
+	IPExtractor realip.IPExtractor
+
+	// StatusCode is the HTTP status code returned for rejected IPs.
+	// Default: 403 (Forbidden)
+	StatusCode int
+
+	// Message is the error message returned for rejected IPs.
+	// Default: "Access denied"
+	Message string
+
+	// ExcludedPaths contains paths to skip IP filtering.
+	// Supports exact matches, prefixes (ending with /), and wildcards (ending with *).
+	// Cannot be used with IncludedPaths - setting both will panic.
+	// Default: []
+	ExcludedPaths []string
+
+	// IncludedPaths contains paths where IP filtering is explicitly applied.
+	// If set, IP filtering will only occur for paths matching these patterns.
+	// Supports exact matches, prefixes (ending with /), and wildcards (ending with *).
+	// If empty, IP filtering applies to all paths (subject to ExcludedPaths).
+	// Cannot be used with ExcludedPaths - setting both will panic.
+	// Default: []
+	IncludedPaths []string
+}
+
+// DefaultConfig contains the default values for IP filtering
+var DefaultConfig = Config{
+	Allow:         []string{},
+	Deny:          []string{},
+	Precedence:    DenyFirst,
+	IPExtractor:   realip.RemoteAddrIPExtractor,
+	StatusCode:    http.StatusForbidden,
+	Message:       "Access denied",
+	ExcludedPaths: []string{},
+	IncludedPaths: []string{},
+}
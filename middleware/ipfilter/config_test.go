@@ -0,0 +1,39 @@
+package ipfilter
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestIPFilterConfig_DefaultValues(t *testing.T) {
+	cfg := DefaultConfig
+
+	zhtest.AssertEqual(t, 0, len(cfg.Allow))
+	zhtest.AssertEqual(t, 0, len(cfg.Deny))
+	zhtest.AssertEqual(t, DenyFirst, cfg.Precedence)
+	zhtest.AssertNotNil(t, cfg.IPExtractor)
+	zhtest.AssertEqual(t, http.StatusForbidden, cfg.StatusCode)
+	zhtest.AssertEqual(t, "Access denied", cfg.Message)
+	zhtest.AssertEqual(t, 0, len(cfg.ExcludedPaths))
+	zhtest.AssertEqual(t, 0, len(cfg.IncludedPaths))
+}
+
+func TestIPFilterConfig_CustomValues(t *testing.T) {
+	cfg := Config{
+		Allow:         []string{"10.0.0.0/8", "2001:db8::/32"},
+		Deny:          []string{"10.0.0.1"},
+		Precedence:    AllowFirst,
+		StatusCode:    http.StatusNotFound,
+		Message:       "Not here",
+		ExcludedPaths: []string{"/health"},
+	}
+
+	zhtest.AssertEqual(t, 2, len(cfg.Allow))
+	zhtest.AssertEqual(t, 1, len(cfg.Deny))
+	zhtest.AssertEqual(t, AllowFirst, cfg.Precedence)
+	zhtest.AssertEqual(t, http.StatusNotFound, cfg.StatusCode)
+	zhtest.AssertEqual(t, "Not here", cfg.Message)
+	zhtest.AssertEqual(t, 1, len(cfg.ExcludedPaths))
+}
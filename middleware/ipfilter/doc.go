@@ -0,0 +1,47 @@
+// Package ipfilter provides IP allowlist and blocklist middleware.
+//
+// Restricts access to routes based on the client IP address using
+// IPv4 and IPv6 addresses or CIDR ranges. Rejected requests receive
+// a 403 Forbidden problem detail response.
+//
+// # Usage
+//
+//	import "github.com/alexferl/zerohttp/middleware/ipfilter"
+//
+//	// Restrict admin routes to internal networks
+//	app.Group(func(admin zh.Router) {
+//	    admin.Use(ipfilter.New(ipfilter.Config{
+//	        Allow: []string{"10.0.0.0/8", "fd00::/8"},
+//	    }))
+//	    admin.GET("/admin", adminHandler)
+//	})
+//
+//	// Block specific addresses everywhere except health checks
+//	app.Use(ipfilter.New(ipfilter.Config{
+//	    Deny:          []string{"203.0.113.0/24"},
+//	    ExcludedPaths: []string{"/health"},
+//	}))
+//
+// # Precedence
+//
+// When an IP matches both lists, Precedence decides the outcome.
+// The default, DenyFirst, rejects it; AllowFirst accepts it:
+//
+//	app.Use(ipfilter.New(ipfilter.Config{
+//	    Allow:      []string{"10.0.0.5"},
+//	    Deny:       []string{"10.0.0.0/8"},
+//	    Precedence: ipfilter.AllowFirst,
+//	}))
+//
+// # Client IP Resolution
+//
+// The client IP is the connection address (realip.RemoteAddrIPExtractor).
+// X-Forwarded-For and similar headers are ignored by default because clients
+// can forge them. Behind a reverse proxy, trust the headers only when the
+// request comes from the proxy:
+//
+//	app.Use(ipfilter.New(ipfilter.Config{
+//	    Allow:       []string{"203.0.113.0/24"},
+//	    IPExtractor: realip.TrustedProxiesIPExtractor([]string{"10.0.0.0/8"}),
+//	}))
+package ipfilter
@@ -0,0 +1,130 @@
+package ipfilter
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	zconfig "github.com/alexferl/zerohttp/internal/config"
+	"github.com/alexferl/zerohttp/internal/mwutil"
+	"github.com/alexferl/zerohttp/internal/problem"
+)
+
+// New creates an IP filtering middleware with the provided configuration that
+// allows or rejects requests based on the client IP address.
+//
+// Rules are evaluated as follows:
+//   - An IP matching both lists is resolved using Precedence
+//   - An IP matching only Deny is rejected
+//   - An IP matching only Allow is accepted
+//   - An IP matching neither is rejected if Allow is non-empty, accepted otherwise
+//   - An IP that cannot be parsed is always rejected
+//
+// New panics if any entry in Allow or Deny is not a valid IP address or CIDR range.
+//
+// Example:
+//
+//	ipfilter.New(ipfilter.Config{
+//	    Allow: []string{"10.0.0.0/8", "2001:db8::/32"},
+//	    Deny:  []string{"10.0.0.13"},
+//	})
+func New(cfg ...Config) func(http.Handler) http.Handler {
+	c := DefaultConfig
+	if len(cfg) > 0 {
+		zconfig.Merge(&c, cfg[0])
+	}
+
+	if c.IPExtractor == nil {
+		c.IPExtractor = DefaultConfig.IPExtractor
+	}
+
+	mwutil.ValidatePathConfig(c.ExcludedPaths, c.IncludedPaths, "IPFilter")
+
+	allow := parsePrefixes(c.Allow, "Allow")
+	deny := parsePrefixes(c.Deny, "Deny")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !mwutil.ShouldProcessMiddleware(r.URL.Path, c.IncludedPaths, c.ExcludedPaths) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ip, ok := parseClientIP(c.IPExtractor(r))
+			if !ok || !isAllowed(ip, allow, deny, c.Precedence) {
+				detail := problem.NewDetail(c.StatusCode, c.Message)
+				_ = detail.RenderAuto(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isAllowed applies the allow/deny rules to ip.
+func isAllowed(ip netip.Addr, allow, deny []netip.Prefix, precedence Precedence) bool {
+	allowed := containsIP(allow, ip)
+	denied := containsIP(deny, ip)
+
+	switch {
+	case allowed && denied:
+		return precedence == AllowFirst
+	case denied:
+		return false
+	case allowed:
+		return true
+	default:
+		return len(allow) == 0
+	}
+}
+
+// containsIP reports whether ip falls within any of the prefixes.
+func containsIP(prefixes []netip.Prefix, ip netip.Addr) bool {
+	for _, p := range prefixes {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePrefixes converts IP addresses and CIDR ranges into prefixes.
+// Bare addresses are treated as single-host ranges. Panics on invalid entries.
+func parsePrefixes(entries []string, field string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if strings.Contains(entry, "/") {
+			p, err := netip.ParsePrefix(entry)
+			if err != nil {
+				panic(fmt.Sprintf("zerohttp: IPFilter invalid %s CIDR %q: %v", field, entry, err))
+			}
+			prefixes = append(prefixes, p.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			panic(fmt.Sprintf("zerohttp: IPFilter invalid %s IP %q: %v", field, entry, err))
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes
+}
+
+// parseClientIP parses the extracted client IP, tolerating a port,
+// brackets around IPv6 addresses, and IPv4-mapped IPv6 addresses.
+func parseClientIP(s string) (netip.Addr, bool) {
+	s = strings.TrimSpace(s)
+	if addrPort, err := netip.ParseAddrPort(s); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
@@ -0,0 +1,260 @@
+package ipfilter
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/middleware/realip"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func serveFrom(t *testing.T, handler http.Handler, remoteAddr string, headers map[string]string) *zhtest.Assertions {
+	t.Helper()
+	req := zhtest.NewRequest(http.MethodGet, "/").WithHeaders(headers).Build()
+	req.RemoteAddr = remoteAddr
+	return zhtest.AssertWith(t, zhtest.Serve(handler, req))
+}
+
+func TestIPFilter_NoRules(t *testing.T) {
+	handler := New()(okHandler)
+
+	req := zhtest.NewRequest(http.MethodGet, "/").Build()
+	req.RemoteAddr = "203.0.113.1:1234"
+	w := zhtest.Serve(handler, req)
+
+	zhtest.AssertWith(t, w).Status(http.StatusOK)
+}
+
+func TestIPFilter_Allow(t *testing.T) {
+	handler := New(Config{
+		Allow: []string{"10.0.0.0/8", "192.168.1.10", "2001:db8::/32"},
+	})(okHandler)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		wantStatus int
+	}{
+		{"IPv4 in CIDR", "10.1.2.3:1234", http.StatusOK},
+		{"IPv4 exact", "192.168.1.10:1234", http.StatusOK},
+		{"IPv4 neighbour of exact", "192.168.1.11:1234", http.StatusForbidden},
+		{"IPv4 outside", "203.0.113.1:1234", http.StatusForbidden},
+		{"IPv6 in CIDR", "[2001:db8::1]:1234", http.StatusOK},
+		{"IPv6 outside", "[2001:db9::1]:1234", http.StatusForbidden},
+		{"IPv4-mapped IPv6", "[::ffff:10.0.0.1]:1234", http.StatusOK},
+		{"no port", "10.0.0.1", http.StatusOK},
+		{"unparseable", "not-an-ip", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := zhtest.NewRequest(http.MethodGet, "/").Build()
+			req.RemoteAddr = tt.remoteAddr
+			w := zhtest.Serve(handler, req)
+
+			zhtest.AssertWith(t, w).Status(tt.wantStatus)
+		})
+	}
+}
+
+func TestIPFilter_Deny(t *testing.T) {
+	handler := New(Config{
+		Deny: []string{"203.0.113.0/24", "2001:db8:bad::/48"},
+	})(okHandler)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		wantStatus int
+	}{
+		{"IPv4 denied", "203.0.113.50:1234", http.StatusForbidden},
+		{"IPv4 not denied", "198.51.100.1:1234", http.StatusOK},
+		{"IPv6 denied", "[2001:db8:bad::1]:1234", http.StatusForbidden},
+		{"IPv6 not denied", "[2001:db8:900d::1]:1234", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := zhtest.NewRequest(http.MethodGet, "/").Build()
+			req.RemoteAddr = tt.remoteAddr
+			w := zhtest.Serve(handler, req)
+
+			zhtest.AssertWith(t, w).Status(tt.wantStatus)
+		})
+	}
+}
+
+func TestIPFilter_Precedence(t *testing.T) {
+	tests := []struct {
+		name       string
+		precedence Precedence
+		remoteAddr string
+		wantStatus int
+	}{
+		{"deny first rejects overlap", DenyFirst, "10.0.0.5:1234", http.StatusForbidden},
+		{"deny first allows non-overlap", DenyFirst, "10.0.0.6:1234", http.StatusOK},
+		{"allow first accepts overlap", AllowFirst, "10.0.0.5:1234", http.StatusOK},
+		{"allow first still rejects unlisted", AllowFirst, "192.168.0.1:1234", http.StatusForbidden},
+		{"default is deny first", "", "10.0.0.5:1234", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := New(Config{
+				Allow:      []string{"10.0.0.0/8"},
+				Deny:       []string{"10.0.0.5"},
+				Precedence: tt.precedence,
+			})(okHandler)
+
+			req := zhtest.NewRequest(http.MethodGet, "/").Build()
+			req.RemoteAddr = tt.remoteAddr
+			w := zhtest.Serve(handler, req)
+
+			zhtest.AssertWith(t, w).Status(tt.wantStatus)
+		})
+	}
+}
+
+func TestIPFilter_XForwardedForSpoofing(t *testing.T) {
+	allow := []string{"10.0.0.0/8"}
+
+	t.Run("default extractor ignores spoofed header", func(t *testing.T) {
+		handler := New(Config{Allow: allow})(okHandler)
+
+		serveFrom(t, handler, "203.0.113.1:1234", map[string]string{
+			httpx.HeaderXForwardedFor: "10.0.0.1",
+		}).Status(http.StatusForbidden)
+
+		serveFrom(t, handler, "10.0.0.1:1234", map[string]string{
+			httpx.HeaderXForwardedFor: "203.0.113.1",
+		}).Status(http.StatusOK)
+	})
+
+	t.Run("spoofed header cannot bypass deny", func(t *testing.T) {
+		handler := New(Config{Deny: []string{"203.0.113.0/24"}})(okHandler)
+
+		serveFrom(t, handler, "203.0.113.1:1234", map[string]string{
+			httpx.HeaderXForwardedFor: "10.0.0.1",
+			httpx.HeaderXRealIP:       "10.0.0.1",
+		}).Status(http.StatusForbidden)
+	})
+
+	t.Run("trusted proxy forwards client IP", func(t *testing.T) {
+		handler := New(Config{
+			Allow:       allow,
+			IPExtractor: realip.TrustedProxiesIPExtractor([]string{"192.0.2.0/24"}),
+		})(okHandler)
+
+		serveFrom(t, handler, "192.0.2.1:1234", map[string]string{
+			httpx.HeaderXForwardedFor: "10.0.0.1",
+		}).Status(http.StatusOK)
+
+		serveFrom(t, handler, "203.0.113.1:1234", map[string]string{
+			httpx.HeaderXForwardedFor: "10.0.0.1",
+		}).Status(http.StatusForbidden)
+	})
+
+	t.Run("spoofed chain uses first entry", func(t *testing.T) {
+		handler := New(Config{
+			Deny:        []string{"203.0.113.0/24"},
+			IPExtractor: realip.DefaultIPExtractor,
+		})(okHandler)
+
+		serveFrom(t, handler, "10.0.0.1:1234", map[string]string{
+			httpx.HeaderXForwardedFor: "203.0.113.9, 10.0.0.1",
+		}).Status(http.StatusForbidden)
+	})
+
+	t.Run("garbage forwarded value is rejected", func(t *testing.T) {
+		handler := New(Config{
+			Deny:        []string{"203.0.113.0/24"},
+			IPExtractor: realip.DefaultIPExtractor,
+		})(okHandler)
+
+		serveFrom(t, handler, "10.0.0.1:1234", map[string]string{
+			httpx.HeaderXForwardedFor: "<script>",
+		}).Status(http.StatusForbidden)
+	})
+
+	t.Run("bracketed IPv6 in forwarded header", func(t *testing.T) {
+		handler := New(Config{
+			Allow:       []string{"2001:db8::/32"},
+			IPExtractor: realip.DefaultIPExtractor,
+		})(okHandler)
+
+		serveFrom(t, handler, "192.0.2.1:1234", map[string]string{
+			httpx.HeaderXForwardedFor: "[2001:db8::3]",
+		}).Status(http.StatusOK)
+	})
+}
+
+func TestIPFilter_ProblemDetail(t *testing.T) {
+	handler := New(Config{
+		Deny:       []string{"203.0.113.1"},
+		StatusCode: http.StatusNotFound,
+		Message:    "Nothing to see here",
+	})(okHandler)
+
+	req := zhtest.NewRequest(http.MethodGet, "/").Build()
+	req.RemoteAddr = "203.0.113.1:1234"
+	w := zhtest.Serve(handler, req)
+
+	zhtest.AssertWith(t, w).
+		Status(http.StatusNotFound).
+		IsProblemDetail().
+		ProblemDetailDetail("Nothing to see here")
+}
+
+func TestIPFilter_ExcludedPaths(t *testing.T) {
+	handler := New(Config{
+		Allow:         []string{"10.0.0.0/8"},
+		ExcludedPaths: []string{"/health"},
+	})(okHandler)
+
+	req := zhtest.NewRequest(http.MethodGet, "/health").Build()
+	req.RemoteAddr = "203.0.113.1:1234"
+	zhtest.AssertWith(t, zhtest.Serve(handler, req)).Status(http.StatusOK)
+
+	req = zhtest.NewRequest(http.MethodGet, "/admin").Build()
+	req.RemoteAddr = "203.0.113.1:1234"
+	zhtest.AssertWith(t, zhtest.Serve(handler, req)).Status(http.StatusForbidden)
+}
+
+func TestIPFilter_IncludedPaths(t *testing.T) {
+	handler := New(Config{
+		Allow:         []string{"10.0.0.0/8"},
+		IncludedPaths: []string{"/admin/"},
+	})(okHandler)
+
+	req := zhtest.NewRequest(http.MethodGet, "/admin/users").Build()
+	req.RemoteAddr = "203.0.113.1:1234"
+	zhtest.AssertWith(t, zhtest.Serve(handler, req)).Status(http.StatusForbidden)
+
+	req = zhtest.NewRequest(http.MethodGet, "/public").Build()
+	req.RemoteAddr = "203.0.113.1:1234"
+	zhtest.AssertWith(t, zhtest.Serve(handler, req)).Status(http.StatusOK)
+}
+
+func TestIPFilter_InvalidRulesPanic(t *testing.T) {
+	zhtest.AssertPanicContains(t, func() {
+		New(Config{Allow: []string{"10.0.0.0/33"}})
+	}, "invalid Allow CIDR")
+
+	zhtest.AssertPanicContains(t, func() {
+		New(Config{Deny: []string{"not-an-ip"}})
+	}, "invalid Deny IP")
+}
+
+func TestIPFilter_BothExcludedAndIncludedPathsPanics(t *testing.T) {
+	zhtest.AssertPanic(t, func() {
+		New(Config{
+			ExcludedPaths: []string{"/health"},
+			IncludedPaths: []string{"/admin"},
+		})
+	})
+}
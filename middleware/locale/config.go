@@ -0,0 +1,43 @@
+package locale
+
+// Config allows customization of locale negotiation.
+type Config struct {
+	// Supported is the list of supported language tags, such as "en",
+	// "en-US" or "fr-CA". Tags are matched case-insensitively and the
+	// negotiated locale is returned as written here.
+	// Default: ["en"]
+	Supported []string
+
+	// Default is the locale used when no supported tag matches the request.
+	// It must be one of Supported.
+	// Default: the first of Supported
+	Default string
+
+	// QueryParam is the name of a query parameter that overrides the
+	// Accept-Language header, such as ?lang=fr. Unsupported values are ignored.
+	// Default: "lang"
+	QueryParam string
+
+	// QueryParamOverride enables the QueryParam override. Use a pointer to
+	// distinguish between "not set" and "explicitly false".
+	// Default: true
+	QueryParamOverride *bool
+
+	// Cookie is the name of a cookie that overrides the Accept-Language
+	// header, typically set when a user picks a language. The query parameter
+	// takes precedence over the cookie. Unsupported values are ignored.
+	// Default: "lang"
+	Cookie string
+
+	// CookieOverride enables the Cookie override. Use a pointer to
+	// distinguish between "not set" and "explicitly false".
+	// Default: true
+	CookieOverride *bool
+}
+
+// DefaultConfig contains the default values for locale configuration.
+var DefaultConfig = Config{
+	Supported:  []string{"en"},
+	QueryParam: "lang",
+	Cookie:     "lang",
+}
@@ -0,0 +1,17 @@
+package locale
+
+import (
+	"testing"
+
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestLocaleConfig_DefaultValues(t *testing.T) {
+	cfg := DefaultConfig
+	zhtest.AssertEqual(t, []string{"en"}, cfg.Supported)
+	zhtest.AssertEqual(t, "", cfg.Default)
+	zhtest.AssertEqual(t, "lang", cfg.QueryParam)
+	zhtest.AssertEqual(t, "lang", cfg.Cookie)
+	zhtest.AssertNil(t, cfg.QueryParamOverride)
+	zhtest.AssertNil(t, cfg.CookieOverride)
+}
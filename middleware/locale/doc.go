@@ -0,0 +1,41 @@
+// Package locale provides Accept-Language negotiation middleware.
+//
+// Picks the best supported locale for each request and stores it in the
+// request context. An override query parameter or cookie, such as one set by
+// a language picker, takes precedence over the Accept-Language header.
+// The response gets a Content-Language header and Vary: Accept-Language.
+//
+// # Usage
+//
+//	import "github.com/alexferl/zerohttp/middleware/locale"
+//
+//	app.Use(locale.New(locale.Config{
+//	    Supported: []string{"en", "fr", "fr-CA", "de"},
+//	    Default:   "en",
+//	}))
+//
+// # Accessing the Locale
+//
+// Retrieve the negotiated locale in handlers, for example to pick the
+// template to render:
+//
+//	tag := locale.Get(r) // "fr-CA"
+//
+// # Matching
+//
+// Language ranges from Accept-Language are tried in order of their q-value.
+// An exact match wins, then the base language ("fr" for "fr-CA"), then a
+// regional variant ("en-US" for "en"). When nothing matches, Default is used.
+//
+// # Overrides
+//
+// By default ?lang=fr and a "lang" cookie override the header. Change the
+// names with QueryParam and Cookie, or disable them with QueryParamOverride
+// and CookieOverride:
+//
+//	app.Use(locale.New(locale.Config{
+//	    Supported:          []string{"en", "fr"},
+//	    QueryParamOverride: config.Bool(false),
+//	    Cookie:             "locale",
+//	}))
+package locale
@@ -0,0 +1,155 @@
+package locale
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alexferl/zerohttp/config"
+	"github.com/alexferl/zerohttp/httpx"
+	zconfig "github.com/alexferl/zerohttp/internal/config"
+)
+
+// contextKey is the context key type for the negotiated locale.
+type contextKey struct{}
+
+// New creates a locale middleware with the provided configuration that
+// negotiates the locale of each request from the override query parameter,
+// the override cookie and the Accept-Language header, in that order.
+// The locale is stored in the request context and can be retrieved with Get.
+// Panics if Supported is empty or Default is not one of Supported.
+func New(cfg ...Config) func(http.Handler) http.Handler {
+	c := DefaultConfig
+	if len(cfg) > 0 {
+		zconfig.Merge(&c, cfg[0])
+	}
+
+	if len(c.Supported) == 0 {
+		panic("zerohttp: locale Supported must not be empty")
+	}
+	if c.Default == "" {
+		c.Default = c.Supported[0]
+	}
+	if match(c.Default, c.Supported) != c.Default {
+		panic("zerohttp: locale Default " + c.Default + " is not one of Supported")
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tag := negotiate(r, c)
+
+			w.Header().Add(httpx.HeaderVary, httpx.HeaderAcceptLanguage)
+			w.Header().Set(httpx.HeaderContentLanguage, tag)
+
+			ctx := context.WithValue(r.Context(), contextKey{}, tag)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Get returns the locale negotiated by the locale middleware, as written in
+// Config.Supported, or "" if the middleware didn't run.
+func Get(r *http.Request) string {
+	if tag, ok := r.Context().Value(contextKey{}).(string); ok {
+		return tag
+	}
+	return ""
+}
+
+// negotiate returns the supported locale for r.
+func negotiate(r *http.Request, c Config) string {
+	if config.BoolOrDefault(c.QueryParamOverride, true) {
+		if v := r.URL.Query().Get(c.QueryParam); v != "" {
+			if tag := match(v, c.Supported); tag != "" {
+				return tag
+			}
+		}
+	}
+
+	if config.BoolOrDefault(c.CookieOverride, true) {
+		if cookie, err := r.Cookie(c.Cookie); err == nil {
+			if tag := match(cookie.Value, c.Supported); tag != "" {
+				return tag
+			}
+		}
+	}
+
+	for _, tag := range parseAcceptLanguage(r.Header.Get(httpx.HeaderAcceptLanguage)) {
+		if tag == "*" {
+			return c.Default
+		}
+		if supported := match(tag, c.Supported); supported != "" {
+			return supported
+		}
+	}
+	return c.Default
+}
+
+// match returns the supported tag matching tag, or "" if none does. An exact
+// match is preferred, then a supported tag for the base language of tag
+// ("fr" for "fr-CA"), then a regional variant of tag ("en-US" for "en").
+func match(tag string, supported []string) string {
+	tag = strings.ReplaceAll(tag, "_", "-")
+	base, _, _ := strings.Cut(tag, "-")
+
+	for _, s := range supported {
+		if strings.EqualFold(s, tag) {
+			return s
+		}
+	}
+	for _, s := range supported {
+		if strings.EqualFold(s, base) {
+			return s
+		}
+	}
+	for _, s := range supported {
+		if sBase, _, _ := strings.Cut(s, "-"); strings.EqualFold(sBase, base) {
+			return s
+		}
+	}
+	return ""
+}
+
+// parseAcceptLanguage returns the language ranges of an Accept-Language
+// header, most preferred first. Ranges with q=0 are not acceptable and are
+// left out.
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var ranges []weighted
+	for part := range strings.SplitSeq(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		for param := range strings.SplitSeq(params, ";") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			ranges = append(ranges, weighted{tag, q})
+		}
+	}
+
+	slices.SortStableFunc(ranges, func(a, b weighted) int {
+		return cmp.Compare(b.q, a.q)
+	})
+
+	tags := make([]string, len(ranges))
+	for i, r := range ranges {
+		tags[i] = r.tag
+	}
+	return tags
+}
@@ -0,0 +1,100 @@
+package locale
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/alexferl/zerohttp/config"
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestLocale(t *testing.T) {
+	cfg := Config{Supported: []string{"en-US", "fr", "fr-CA", "de"}}
+
+	tests := []struct {
+		name     string
+		target   string
+		header   string
+		cookie   string
+		expected string
+	}{
+		{"no header uses default", "/", "", "", "en-US"},
+		{"exact match", "/", "fr-CA", "", "fr-CA"},
+		{"case insensitive", "/", "FR-ca", "", "fr-CA"},
+		{"base language", "/", "fr-BE", "", "fr"},
+		{"regional variant", "/", "en", "", "en-US"},
+		{"q-values", "/", "de;q=0.5, fr;q=0.9, es", "", "fr"},
+		{"q=0 is not acceptable", "/", "fr;q=0, de;q=0.1", "", "de"},
+		{"wildcard uses default", "/", "es, *;q=0.5", "", "en-US"},
+		{"no match uses default", "/", "es, it", "", "en-US"},
+		{"cookie overrides header", "/", "fr", "de", "de"},
+		{"query overrides cookie", "/?lang=fr-CA", "fr", "de", "fr-CA"},
+		{"underscore in override", "/?lang=fr_ca", "", "", "fr-CA"},
+		{"unsupported override ignored", "/?lang=es", "fr", "it", "fr"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := New(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = Get(r)
+			}))
+
+			req := zhtest.NewRequest(http.MethodGet, tt.target).Build()
+			if tt.header != "" {
+				req.Header.Set(httpx.HeaderAcceptLanguage, tt.header)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "lang", Value: tt.cookie})
+			}
+			w := zhtest.Serve(handler, req)
+
+			zhtest.AssertEqual(t, tt.expected, got)
+			zhtest.AssertWith(t, w).
+				Header(httpx.HeaderContentLanguage, tt.expected).
+				Header(httpx.HeaderVary, httpx.HeaderAcceptLanguage)
+		})
+	}
+}
+
+func TestLocale_DisabledOverrides(t *testing.T) {
+	var got string
+	handler := New(Config{
+		Supported:          []string{"en", "fr"},
+		Default:            "fr",
+		QueryParamOverride: config.Bool(false),
+		CookieOverride:     config.Bool(false),
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = Get(r)
+	}))
+
+	req := zhtest.NewRequest(http.MethodGet, "/?lang=en").Build()
+	req.AddCookie(&http.Cookie{Name: "lang", Value: "en"})
+	zhtest.Serve(handler, req)
+
+	zhtest.AssertEqual(t, "fr", got)
+
+	t.Run("query parameter only", func(t *testing.T) {
+		handler := New(Config{
+			Supported:          []string{"en", "fr", "de"},
+			QueryParamOverride: config.Bool(false),
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = Get(r)
+		}))
+
+		req := zhtest.NewRequest(http.MethodGet, "/?lang=fr").Build()
+		req.AddCookie(&http.Cookie{Name: "lang", Value: "de"})
+		zhtest.Serve(handler, req)
+
+		zhtest.AssertEqual(t, "de", got)
+	})
+}
+
+func TestLocale_InvalidConfig(t *testing.T) {
+	zhtest.AssertPanic(t, func() { New(Config{Supported: []string{"en"}, Default: "fr"}) })
+}
+
+func TestGet_WithoutMiddleware(t *testing.T) {
+	zhtest.AssertEqual(t, "", Get(zhtest.NewRequest(http.MethodGet, "/").Build()))
+}
@@ -0,0 +1,52 @@
+package maintenance
+
+import (
+	"net/http"
+	"time"
+)
+
+// Config allows customization of maintenance mode behavior
+type Config struct {
+	// Enabled sets the initial maintenance state. The state can be changed
+	// at runtime through the Mode returned by New.
+	// Default: false
+	Enabled bool
+
+	// StatusCode is the HTTP status code returned while in maintenance mode.
+	// Default: 503 (Service Unavailable)
+	StatusCode int
+
+	// Message is the problem detail message returned while in maintenance mode.
+	// Default: "Service is under maintenance"
+	Message string
+
+	// RetryAfter is the value of the Retry-After header sent while in maintenance
+	// mode, rounded up to whole seconds. Set to a negative value to omit the header.
+	// Default: 5m
+	RetryAfter time.Duration
+
+	// ExcludedPaths contains paths that stay available during maintenance,
+	// such as health checks.
+	// Supports exact matches, prefixes (ending with /), and wildcards (ending with *).
+	// Cannot be used with IncludedPaths - setting both will panic.
+	// Default: ["/livez", "/readyz", "/startupz"]
+	ExcludedPaths []string
+
+	// IncludedPaths contains paths that are affected by maintenance mode.
+	// If set, only paths matching these patterns are put in maintenance.
+	// Supports exact matches, prefixes (ending with /), and wildcards (ending with *).
+	// If empty, maintenance mode applies to all paths (subject to ExcludedPaths).
+	// Cannot be used with ExcludedPaths - setting both will panic.
+	// Default: []
+	IncludedPaths []string
+}
+
+// DefaultConfig contains the default values for maintenance mode configuration.
+var DefaultConfig = Config{
+	Enabled:       false,
+	StatusCode:    http.StatusServiceUnavailable,
+	Message:       "Service is under maintenance",
+	RetryAfter:    5 * time.Minute,
+	ExcludedPaths: []string{"/livez", "/readyz", "/startupz"},
+	IncludedPaths: []string{},
+}
@@ -0,0 +1,20 @@
+package maintenance
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestMaintenanceConfig_DefaultValues(t *testing.T) {
+	cfg := DefaultConfig
+
+	zhtest.AssertFalse(t, cfg.Enabled)
+	zhtest.AssertEqual(t, http.StatusServiceUnavailable, cfg.StatusCode)
+	zhtest.AssertEqual(t, "Service is under maintenance", cfg.Message)
+	zhtest.AssertEqual(t, 5*time.Minute, cfg.RetryAfter)
+	zhtest.AssertDeepEqual(t, []string{"/livez", "/readyz", "/startupz"}, cfg.ExcludedPaths)
+	zhtest.AssertEqual(t, 0, len(cfg.IncludedPaths))
+}
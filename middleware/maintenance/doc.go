@@ -0,0 +1,29 @@
+// Package maintenance provides maintenance mode middleware.
+//
+// Maintenance mode can be toggled at runtime without restarting the server.
+// While enabled, requests receive a 503 Service Unavailable problem detail
+// with a Retry-After header, except for excluded paths such as health checks.
+//
+// # Usage
+//
+//	import "github.com/alexferl/zerohttp/middleware/maintenance"
+//
+//	mw, mode := maintenance.New(maintenance.Config{
+//	    Message:    "Upgrading the database, back soon",
+//	    RetryAfter: 10 * time.Minute,
+//	})
+//	app.Use(mw)
+//
+//	// Toggle from anywhere, safe while serving requests
+//	mode.Enable()
+//	mode.Disable()
+//
+// # Excluded Paths
+//
+// The health check endpoints /livez, /readyz and /startupz stay available by default.
+// Override ExcludedPaths to change them:
+//
+//	mw, mode := maintenance.New(maintenance.Config{
+//	    ExcludedPaths: []string{"/health", "/admin/"},
+//	})
+package maintenance
@@ -0,0 +1,85 @@
+package maintenance
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/alexferl/zerohttp/httpx"
+	zconfig "github.com/alexferl/zerohttp/internal/config"
+	"github.com/alexferl/zerohttp/internal/mwutil"
+	"github.com/alexferl/zerohttp/internal/problem"
+)
+
+// Mode is a handle for toggling maintenance mode at runtime.
+// All methods are safe for concurrent use while requests are being served.
+type Mode struct {
+	enabled atomic.Bool
+}
+
+// Enable puts the application in maintenance mode.
+func (m *Mode) Enable() {
+	m.enabled.Store(true)
+}
+
+// Disable takes the application out of maintenance mode.
+func (m *Mode) Disable() {
+	m.enabled.Store(false)
+}
+
+// Enabled reports whether maintenance mode is active.
+func (m *Mode) Enabled() bool {
+	return m.enabled.Load()
+}
+
+// New creates a maintenance mode middleware with the provided configuration.
+// It returns the middleware and a Mode handle used to toggle maintenance at runtime.
+// While enabled, non-excluded requests receive a problem detail response with
+// a Retry-After header.
+//
+// Example:
+//
+//	mw, mode := maintenance.New()
+//	app.Use(mw)
+//
+//	// Later, e.g. from an admin endpoint or signal handler
+//	mode.Enable()
+//	defer mode.Disable()
+func New(cfg ...Config) (func(http.Handler) http.Handler, *Mode) {
+	c := DefaultConfig
+	if len(cfg) > 0 {
+		zconfig.Merge(&c, cfg[0])
+		// Allow clearing the default exclusions when IncludedPaths is used
+		if len(cfg[0].IncludedPaths) > 0 && len(cfg[0].ExcludedPaths) == 0 {
+			c.ExcludedPaths = nil
+		}
+	}
+
+	mwutil.ValidatePathConfig(c.ExcludedPaths, c.IncludedPaths, "Maintenance")
+
+	retryAfter := ""
+	if c.RetryAfter >= 0 {
+		retryAfter = strconv.Itoa(int(math.Ceil(c.RetryAfter.Seconds())))
+	}
+
+	mode := &Mode{}
+	mode.enabled.Store(c.Enabled)
+
+	mw := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !mode.Enabled() || !mwutil.ShouldProcessMiddleware(r.URL.Path, c.IncludedPaths, c.ExcludedPaths) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if retryAfter != "" {
+				w.Header().Set(httpx.HeaderRetryAfter, retryAfter)
+			}
+			detail := problem.NewDetail(c.StatusCode, c.Message)
+			_ = detail.RenderAuto(w, r)
+		})
+	}
+
+	return mw, mode
+}
@@ -0,0 +1,154 @@
+package maintenance
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func TestMaintenance_DisabledByDefault(t *testing.T) {
+	mw, mode := New()
+	handler := mw(okHandler)
+
+	zhtest.AssertFalse(t, mode.Enabled())
+
+	req := zhtest.NewRequest(http.MethodGet, "/").Build()
+	zhtest.AssertWith(t, zhtest.Serve(handler, req)).
+		Status(http.StatusOK).
+		HeaderNotExists(httpx.HeaderRetryAfter)
+}
+
+func TestMaintenance_Toggle(t *testing.T) {
+	mw, mode := New()
+	handler := mw(okHandler)
+
+	mode.Enable()
+	zhtest.AssertTrue(t, mode.Enabled())
+
+	req := zhtest.NewRequest(http.MethodGet, "/api/users").Build()
+	zhtest.AssertWith(t, zhtest.Serve(handler, req)).
+		Status(http.StatusServiceUnavailable).
+		Header(httpx.HeaderRetryAfter, "300").
+		IsProblemDetail().
+		ProblemDetailDetail("Service is under maintenance")
+
+	mode.Disable()
+	zhtest.AssertFalse(t, mode.Enabled())
+
+	req = zhtest.NewRequest(http.MethodGet, "/api/users").Build()
+	zhtest.AssertWith(t, zhtest.Serve(handler, req)).Status(http.StatusOK)
+}
+
+func TestMaintenance_InitiallyEnabled(t *testing.T) {
+	mw, mode := New(Config{Enabled: true})
+	handler := mw(okHandler)
+
+	zhtest.AssertTrue(t, mode.Enabled())
+
+	req := zhtest.NewRequest(http.MethodGet, "/").Build()
+	zhtest.AssertWith(t, zhtest.Serve(handler, req)).Status(http.StatusServiceUnavailable)
+}
+
+func TestMaintenance_HealthChecksStayUp(t *testing.T) {
+	mw, _ := New(Config{Enabled: true})
+	handler := mw(okHandler)
+
+	for _, path := range []string{"/livez", "/readyz", "/startupz"} {
+		t.Run(path, func(t *testing.T) {
+			req := zhtest.NewRequest(http.MethodGet, path).Build()
+			zhtest.AssertWith(t, zhtest.Serve(handler, req)).Status(http.StatusOK)
+		})
+	}
+}
+
+func TestMaintenance_CustomResponse(t *testing.T) {
+	mw, _ := New(Config{
+		Enabled:       true,
+		StatusCode:    http.StatusTeapot,
+		Message:       "Back at noon",
+		RetryAfter:    1500 * time.Millisecond,
+		ExcludedPaths: []string{"/health"},
+	})
+	handler := mw(okHandler)
+
+	req := zhtest.NewRequest(http.MethodGet, "/").Build()
+	zhtest.AssertWith(t, zhtest.Serve(handler, req)).
+		Status(http.StatusTeapot).
+		Header(httpx.HeaderRetryAfter, "2").
+		ProblemDetailDetail("Back at noon")
+
+	req = zhtest.NewRequest(http.MethodGet, "/health").Build()
+	zhtest.AssertWith(t, zhtest.Serve(handler, req)).Status(http.StatusOK)
+
+	// Default exclusions are replaced, not merged
+	req = zhtest.NewRequest(http.MethodGet, "/livez").Build()
+	zhtest.AssertWith(t, zhtest.Serve(handler, req)).Status(http.StatusTeapot)
+}
+
+func TestMaintenance_NoRetryAfter(t *testing.T) {
+	mw, _ := New(Config{Enabled: true, RetryAfter: -1})
+	handler := mw(okHandler)
+
+	req := zhtest.NewRequest(http.MethodGet, "/").Build()
+	zhtest.AssertWith(t, zhtest.Serve(handler, req)).
+		Status(http.StatusServiceUnavailable).
+		HeaderNotExists(httpx.HeaderRetryAfter)
+}
+
+func TestMaintenance_IncludedPaths(t *testing.T) {
+	mw, _ := New(Config{
+		Enabled:       true,
+		IncludedPaths: []string{"/api/"},
+	})
+	handler := mw(okHandler)
+
+	req := zhtest.NewRequest(http.MethodGet, "/api/orders").Build()
+	zhtest.AssertWith(t, zhtest.Serve(handler, req)).Status(http.StatusServiceUnavailable)
+
+	req = zhtest.NewRequest(http.MethodGet, "/docs").Build()
+	zhtest.AssertWith(t, zhtest.Serve(handler, req)).Status(http.StatusOK)
+}
+
+func TestMaintenance_BothExcludedAndIncludedPathsPanics(t *testing.T) {
+	zhtest.AssertPanic(t, func() {
+		New(Config{
+			ExcludedPaths: []string{"/health"},
+			IncludedPaths: []string{"/api/"},
+		})
+	})
+}
+
+func TestMaintenance_ConcurrentToggle(t *testing.T) {
+	mw, mode := New()
+	handler := mw(okHandler)
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if i%2 == 0 {
+				mode.Enable()
+			} else {
+				mode.Disable()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			req := zhtest.NewRequest(http.MethodGet, "/").Build()
+			w := zhtest.Serve(handler, req)
+			if w.Code != http.StatusOK && w.Code != http.StatusServiceUnavailable {
+				t.Errorf("unexpected status %d", w.Code)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,342 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	zconfig "github.com/alexferl/zerohttp/internal/config"
+	"github.com/alexferl/zerohttp/log"
+	"github.com/alexferl/zerohttp/storage"
+)
+
+// errLockTimeout is returned when the token bucket lock can't be acquired.
+var errLockTimeout = errors.New("ratelimit: timed out acquiring storage lock")
+
+// StorageAdapterConfig configures the StorageAdapter.
+// Algorithm, Rate and Window must match the middleware Config.
+type StorageAdapterConfig struct {
+	// Algorithm to use.
+	// Default: TokenBucket
+	Algorithm Algorithm
+
+	// Rate is requests per window.
+	// Default: 100
+	Rate int
+
+	// Window is the time window duration.
+	// Default: 1 minute
+	Window time.Duration
+
+	// KeyPrefix is the prefix for rate limit keys.
+	// Default: "ratelimit:"
+	KeyPrefix string
+
+	// LockTTL is the TTL for the per-key lock used by the token bucket algorithm.
+	// Default: 1s
+	LockTTL time.Duration
+
+	// LockRetries is how many times to retry acquiring a held token bucket lock
+	// before denying the request with a Retry-After of LockTTL.
+	// Default: 10
+	LockRetries int
+
+	// LockRetryDelay is the delay between lock attempts.
+	// Default: 5ms
+	LockRetryDelay time.Duration
+}
+
+// DefaultStorageAdapterConfig is the default configuration for StorageAdapter.
+var DefaultStorageAdapterConfig = StorageAdapterConfig{
+	Algorithm:      TokenBucket,
+	Rate:           100,
+	Window:         time.Minute,
+	KeyPrefix:      "ratelimit:",
+	LockTTL:        time.Second,
+	LockRetries:    10,
+	LockRetryDelay: 5 * time.Millisecond,
+}
+
+// StorageAdapter wraps a storage.Storage to implement the ratelimit.Store interface,
+// allowing rate limits to be shared between instances.
+//
+// The fixed and sliding window algorithms are built on atomic counters and
+// require the storage to implement storage.Counter. The sliding window uses
+// the weighted two-window counter approximation rather than per-request
+// timestamps, and windows start at multiples of Window so that all instances
+// agree on the boundaries. Rejected requests are counted too. The token
+// bucket and GCRA algorithms keep their state with Get/Set under a per-key
+// lock and require storage.Locker.
+//
+// Storage errors fail open: the request is allowed and the error is logged.
+type StorageAdapter struct {
+	store   storage.Storage
+	counter storage.Counter
+	locker  storage.Locker
+	config  StorageAdapterConfig
+}
+
+// bucketState is the persisted state of a token bucket.
+type bucketState struct {
+	Tokens     float64 `json:"tokens"`
+	LastRefill int64   `json:"last_refill"`
+}
+
+// NewStorageAdapter creates a ratelimit.Store from a storage.Storage.
+// Returns storage.ErrCounterNotSupported or storage.ErrLockNotSupported if
+// the storage lacks the capability required by the configured algorithm.
+func NewStorageAdapter(s storage.Storage, cfg ...StorageAdapterConfig) (Store, error) {
+	c := DefaultStorageAdapterConfig
+	if len(cfg) > 0 {
+		zconfig.Merge(&c, cfg[0])
+	}
+
+	a := &StorageAdapter{
+		store:  s,
+		config: c,
+	}
+
+	switch c.Algorithm {
+	case FixedWindow, SlidingWindow:
+		counter, ok := s.(storage.Counter)
+		if !ok {
+			return nil, storage.ErrCounterNotSupported
+		}
+		a.counter = counter
+	default:
+		locker, ok := s.(storage.Locker)
+		if !ok {
+			return nil, storage.ErrLockNotSupported
+		}
+		a.locker = locker
+	}
+
+	return a, nil
+}
+
+// CheckAndRecord implements Store.
+func (a *StorageAdapter) CheckAndRecord(ctx context.Context, key string, now time.Time) (bool, int, time.Time) {
+	var (
+		allowed   bool
+		remaining int
+		resetTime time.Time
+		err       error
+	)
+
+	switch a.config.Algorithm {
+	case FixedWindow:
+		allowed, remaining, resetTime, err = a.checkFixedWindow(ctx, key, now)
+	case SlidingWindow:
+		allowed, remaining, resetTime, err = a.checkSlidingWindow(ctx, key, now)
+	case GCRA:
+		allowed, remaining, resetTime, err = a.checkGCRA(ctx, key, now)
+	default:
+		allowed, remaining, resetTime, err = a.checkTokenBucket(ctx, key, now)
+	}
+
+	if errors.Is(err, errLockTimeout) {
+		// The key is busy, not the storage. Deny until the lock has surely expired.
+		return false, 0, now.Add(a.config.LockTTL)
+	}
+	if err != nil {
+		log.GetGlobalLogger().Error("Rate limit storage failed, allowing request", log.E(err))
+		return true, a.config.Rate - 1, now.Add(a.config.Window)
+	}
+	return allowed, remaining, resetTime
+}
+
+// windowStart returns the start of the aligned window containing now.
+func (a *StorageAdapter) windowStart(now time.Time) time.Time {
+	return now.Truncate(a.config.Window)
+}
+
+func (a *StorageAdapter) windowKey(key string, start time.Time) string {
+	return a.config.KeyPrefix + key + ":" + strconv.FormatInt(start.UnixNano(), 10)
+}
+
+func (a *StorageAdapter) checkFixedWindow(ctx context.Context, key string, now time.Time) (bool, int, time.Time, error) {
+	start := a.windowStart(now)
+	resetTime := start.Add(a.config.Window)
+
+	count, err := a.counter.Incr(ctx, a.windowKey(key, start), a.config.Window)
+	if err != nil {
+		return false, 0, time.Time{}, err
+	}
+
+	if count > int64(a.config.Rate) {
+		return false, 0, resetTime, nil
+	}
+	return true, a.config.Rate - int(count), resetTime, nil
+}
+
+func (a *StorageAdapter) checkSlidingWindow(ctx context.Context, key string, now time.Time) (bool, int, time.Time, error) {
+	window := a.config.Window
+	rate := float64(a.config.Rate)
+	start := a.windowStart(now)
+
+	// The current counter must outlive the next window, where it is read as the previous one
+	curr, err := a.counter.Incr(ctx, a.windowKey(key, start), 2*window)
+	if err != nil {
+		return false, 0, time.Time{}, err
+	}
+
+	var prev int64
+	data, found, err := a.store.Get(ctx, a.windowKey(key, start.Add(-window)))
+	if err != nil {
+		return false, 0, time.Time{}, err
+	}
+	if found {
+		prev, err = strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			return false, 0, time.Time{}, err
+		}
+	}
+
+	// Weight the previous window by how much of it still overlaps the sliding window
+	elapsed := float64(now.Sub(start)) / float64(window)
+	estimated := float64(prev)*(1-elapsed) + float64(curr)
+
+	if estimated <= rate {
+		remaining := int(rate - math.Ceil(estimated))
+		return true, max(remaining, 0), start.Add(window), nil
+	}
+
+	return false, 0, a.slidingWindowReset(start, float64(prev), float64(curr)), nil
+}
+
+// slidingWindowReset estimates when the next request would be allowed,
+// i.e. when prev*(1-elapsed) + curr + 1 <= rate.
+func (a *StorageAdapter) slidingWindowReset(start time.Time, prev, curr float64) time.Time {
+	window := float64(a.config.Window)
+	rate := float64(a.config.Rate)
+
+	// Still within the current window once enough of the previous one slides out
+	if curr+1 <= rate && prev > 0 {
+		elapsed := 1 - (rate-1-curr)/prev
+		return start.Add(time.Duration(elapsed * window))
+	}
+
+	// Otherwise wait until enough of the current window slides out in the next one
+	elapsed := 0.0
+	if curr > 0 {
+		elapsed = max(1-(rate-1)/curr, 0)
+	}
+	return start.Add(a.config.Window).Add(time.Duration(elapsed * window))
+}
+
+func (a *StorageAdapter) checkTokenBucket(ctx context.Context, key string, now time.Time) (bool, int, time.Time, error) {
+	bucketKey := a.config.KeyPrefix + key
+
+	unlock, err := a.lock(ctx, bucketKey+":lock")
+	if err != nil {
+		return false, 0, time.Time{}, err
+	}
+	defer unlock()
+
+	capacity := float64(a.config.Rate)
+	rate := capacity / a.config.Window.Seconds()
+
+	state := bucketState{Tokens: capacity, LastRefill: now.UnixNano()}
+	data, found, err := a.store.Get(ctx, bucketKey)
+	if err != nil {
+		return false, 0, time.Time{}, err
+	}
+	if found {
+		if err := json.Unmarshal(data, &state); err != nil {
+			return false, 0, time.Time{}, err
+		}
+	}
+
+	elapsed := now.Sub(time.Unix(0, state.LastRefill)).Seconds()
+	state.Tokens = min(capacity, state.Tokens+max(elapsed, 0)*rate)
+	state.LastRefill = now.UnixNano()
+
+	allowed := state.Tokens >= 1.0
+	if allowed {
+		state.Tokens--
+	}
+
+	data, err = json.Marshal(state)
+	if err != nil {
+		return false, 0, time.Time{}, err
+	}
+	// A bucket idle for a full window is full again, so the state can expire
+	if err := a.store.Set(ctx, bucketKey, data, a.config.Window); err != nil {
+		return false, 0, time.Time{}, err
+	}
+
+	if allowed {
+		return true, int(state.Tokens), now.Add(refillDuration(capacity-state.Tokens, rate)), nil
+	}
+	return false, 0, now.Add(refillDuration(1.0-state.Tokens, rate)), nil
+}
+
+func (a *StorageAdapter) checkGCRA(ctx context.Context, key string, now time.Time) (bool, int, time.Time, error) {
+	tatKey := a.config.KeyPrefix + key
+
+	unlock, err := a.lock(ctx, tatKey+":lock")
+	if err != nil {
+		return false, 0, time.Time{}, err
+	}
+	defer unlock()
+
+	tat := now
+	data, found, err := a.store.Get(ctx, tatKey)
+	if err != nil {
+		return false, 0, time.Time{}, err
+	}
+	if found {
+		nanos, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			return false, 0, time.Time{}, err
+		}
+		tat = time.Unix(0, nanos)
+	}
+
+	newTat, allowed, remaining, resetTime := gcra(tat, now, a.config.Window, a.config.Rate)
+	if !allowed {
+		return false, 0, resetTime, nil
+	}
+
+	// Once the theoretical arrival time has passed the key is back to its initial state
+	value := []byte(strconv.FormatInt(newTat.UnixNano(), 10))
+	if err := a.store.Set(ctx, tatKey, value, newTat.Sub(now)); err != nil {
+		return false, 0, time.Time{}, err
+	}
+	return true, remaining, resetTime, nil
+}
+
+// lock acquires the per-key lock, retrying while it is held by another request.
+// The returned function releases it.
+func (a *StorageAdapter) lock(ctx context.Context, lockKey string) (func(), error) {
+	for attempt := 0; ; attempt++ {
+		ok, err := a.locker.Lock(ctx, lockKey, a.config.LockTTL)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return func() {
+				if err := a.locker.Unlock(context.WithoutCancel(ctx), lockKey); err != nil {
+					log.GetGlobalLogger().Error("Rate limit storage unlock failed", log.E(err))
+				}
+			}, nil
+		}
+		if attempt >= a.config.LockRetries {
+			return nil, errLockTimeout
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(a.config.LockRetryDelay):
+		}
+	}
+}
+
+// Close releases resources associated with the underlying storage.
+func (a *StorageAdapter) Close() error {
+	return a.store.Close()
+}
@@ -0,0 +1,356 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexferl/zerohttp/storage"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+// mockStorage is a thread-safe test implementation of storage.Storage,
+// storage.Counter and storage.Locker. TTLs are recorded but not enforced.
+type mockStorage struct {
+	mu      sync.Mutex
+	data    map[string][]byte
+	ttlVals map[string]time.Duration
+	locks   map[string]bool
+	err     error
+}
+
+func newMockStorage() *mockStorage {
+	return &mockStorage{
+		data:    make(map[string][]byte),
+		ttlVals: make(map[string]time.Duration),
+		locks:   make(map[string]bool),
+	}
+}
+
+func (m *mockStorage) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, false, m.err
+	}
+	val, ok := m.data[key]
+	return val, ok, nil
+}
+
+func (m *mockStorage) Set(_ context.Context, key string, val []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.data[key] = val
+	m.ttlVals[key] = ttl
+	return nil
+}
+
+func (m *mockStorage) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, key)
+	delete(m.ttlVals, key)
+	return nil
+}
+
+func (m *mockStorage) Close() error {
+	return nil
+}
+
+func (m *mockStorage) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return 0, m.err
+	}
+	n, _ := strconv.ParseInt(string(m.data[key]), 10, 64)
+	n++
+	if _, ok := m.data[key]; !ok {
+		m.ttlVals[key] = ttl
+	}
+	m.data[key] = []byte(strconv.FormatInt(n, 10))
+	return n, nil
+}
+
+func (m *mockStorage) Lock(_ context.Context, key string, _ time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.locks[key] {
+		return false, nil
+	}
+	m.locks[key] = true
+	return true, nil
+}
+
+func (m *mockStorage) Unlock(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.locks, key)
+	return nil
+}
+
+// plainStorage only implements storage.Storage.
+type plainStorage struct{ storage.Storage }
+
+func TestNewStorageAdapter_RequiredCapabilities(t *testing.T) {
+	_, err := NewStorageAdapter(plainStorage{}, StorageAdapterConfig{Algorithm: TokenBucket})
+	zhtest.AssertErrorIs(t, err, storage.ErrLockNotSupported)
+
+	_, err = NewStorageAdapter(plainStorage{}, StorageAdapterConfig{Algorithm: FixedWindow})
+	zhtest.AssertErrorIs(t, err, storage.ErrCounterNotSupported)
+
+	_, err = NewStorageAdapter(plainStorage{}, StorageAdapterConfig{Algorithm: SlidingWindow})
+	zhtest.AssertErrorIs(t, err, storage.ErrCounterNotSupported)
+
+	_, err = NewStorageAdapter(plainStorage{}, StorageAdapterConfig{Algorithm: GCRA})
+	zhtest.AssertErrorIs(t, err, storage.ErrLockNotSupported)
+
+	for _, algorithm := range []Algorithm{TokenBucket, FixedWindow, SlidingWindow, GCRA} {
+		store, err := NewStorageAdapter(newMockStorage(), StorageAdapterConfig{Algorithm: algorithm})
+		zhtest.AssertNoError(t, err)
+		zhtest.AssertNotNil(t, store)
+	}
+}
+
+func TestStorageAdapter_FixedWindow(t *testing.T) {
+	s := newMockStorage()
+	store, err := NewStorageAdapter(s, StorageAdapterConfig{Algorithm: FixedWindow, Rate: 3, Window: time.Minute})
+	zhtest.AssertNoError(t, err)
+
+	ctx := context.Background()
+	start := time.Now().Truncate(time.Minute)
+	now := start.Add(10 * time.Second)
+
+	for i := 2; i >= 0; i-- {
+		allowed, remaining, resetTime := store.CheckAndRecord(ctx, "key1", now)
+		zhtest.AssertTrue(t, allowed)
+		zhtest.AssertEqual(t, i, remaining)
+		zhtest.AssertEqual(t, start.Add(time.Minute), resetTime)
+	}
+
+	allowed, remaining, resetTime := store.CheckAndRecord(ctx, "key1", now)
+	zhtest.AssertFalse(t, allowed)
+	zhtest.AssertEqual(t, 0, remaining)
+	zhtest.AssertEqual(t, start.Add(time.Minute), resetTime)
+
+	// Other keys are independent
+	allowed, _, _ = store.CheckAndRecord(ctx, "key2", now)
+	zhtest.AssertTrue(t, allowed)
+
+	// Counter keys expire with the window
+	zhtest.AssertEqual(t, time.Minute, s.ttlVals["ratelimit:key1:"+strconv.FormatInt(start.UnixNano(), 10)])
+
+	// Next window starts fresh
+	allowed, remaining, _ = store.CheckAndRecord(ctx, "key1", start.Add(time.Minute))
+	zhtest.AssertTrue(t, allowed)
+	zhtest.AssertEqual(t, 2, remaining)
+}
+
+func TestStorageAdapter_SlidingWindow(t *testing.T) {
+	s := newMockStorage()
+	store, err := NewStorageAdapter(s, StorageAdapterConfig{Algorithm: SlidingWindow, Rate: 10, Window: time.Minute})
+	zhtest.AssertNoError(t, err)
+
+	ctx := context.Background()
+	start := time.Now().Truncate(time.Minute)
+	prevKey := "ratelimit:key1:" + strconv.FormatInt(start.Add(-time.Minute).UnixNano(), 10)
+	s.data[prevKey] = []byte("10")
+
+	// Halfway through the window, the previous window still counts for 5
+	now := start.Add(30 * time.Second)
+	for i := 4; i >= 0; i-- {
+		allowed, remaining, _ := store.CheckAndRecord(ctx, "key1", now)
+		zhtest.AssertTrue(t, allowed)
+		zhtest.AssertEqual(t, i, remaining)
+	}
+
+	allowed, remaining, resetTime := store.CheckAndRecord(ctx, "key1", now)
+	zhtest.AssertFalse(t, allowed)
+	zhtest.AssertEqual(t, 0, remaining)
+
+	// With 6 counted in the current window, one more fits once the previous
+	// window weighs 3 or less, i.e. 70% into the current window
+	zhtest.AssertEqual(t, start.Add(42*time.Second), resetTime.Round(time.Millisecond))
+
+	allowed, _, _ = store.CheckAndRecord(ctx, "key1", start.Add(43*time.Second))
+	zhtest.AssertTrue(t, allowed)
+}
+
+func TestStorageAdapter_SlidingWindowResetInNextWindow(t *testing.T) {
+	store, err := NewStorageAdapter(newMockStorage(), StorageAdapterConfig{Algorithm: SlidingWindow, Rate: 2, Window: time.Minute})
+	zhtest.AssertNoError(t, err)
+
+	ctx := context.Background()
+	start := time.Now().Truncate(time.Minute)
+
+	for range 2 {
+		allowed, _, _ := store.CheckAndRecord(ctx, "key1", start)
+		zhtest.AssertTrue(t, allowed)
+	}
+
+	allowed, _, resetTime := store.CheckAndRecord(ctx, "key1", start)
+	zhtest.AssertFalse(t, allowed)
+
+	// 3 counted in this window: the next window must slide past 2/3 of it
+	zhtest.AssertEqual(t, start.Add(time.Minute+40*time.Second), resetTime.Round(time.Millisecond))
+}
+
+func TestStorageAdapter_TokenBucket(t *testing.T) {
+	s := newMockStorage()
+	cfg := StorageAdapterConfig{Algorithm: TokenBucket, Rate: 2, Window: 2 * time.Second}
+
+	// Two instances sharing the same storage share the limit
+	store1, err := NewStorageAdapter(s, cfg)
+	zhtest.AssertNoError(t, err)
+	store2, err := NewStorageAdapter(s, cfg)
+	zhtest.AssertNoError(t, err)
+
+	ctx := context.Background()
+	now := time.Now()
+
+	allowed, remaining, resetTime := store1.CheckAndRecord(ctx, "key1", now)
+	zhtest.AssertTrue(t, allowed)
+	zhtest.AssertEqual(t, 1, remaining)
+	zhtest.AssertEqual(t, now.Add(time.Second), resetTime)
+
+	allowed, remaining, _ = store2.CheckAndRecord(ctx, "key1", now)
+	zhtest.AssertTrue(t, allowed)
+	zhtest.AssertEqual(t, 0, remaining)
+
+	allowed, remaining, resetTime = store1.CheckAndRecord(ctx, "key1", now)
+	zhtest.AssertFalse(t, allowed)
+	zhtest.AssertEqual(t, 0, remaining)
+	zhtest.AssertEqual(t, now.Add(time.Second), resetTime)
+
+	// One token refills per second
+	allowed, _, _ = store2.CheckAndRecord(ctx, "key1", now.Add(time.Second))
+	zhtest.AssertTrue(t, allowed)
+
+	zhtest.AssertEqual(t, 2*time.Second, s.ttlVals["ratelimit:key1"])
+	zhtest.AssertEqual(t, 0, len(s.locks))
+}
+
+func TestStorageAdapter_TokenBucketConcurrent(t *testing.T) {
+	store, err := NewStorageAdapter(newMockStorage(), StorageAdapterConfig{
+		Algorithm:      TokenBucket,
+		Rate:           10,
+		Window:         time.Hour,
+		LockRetries:    1000,
+		LockRetryDelay: time.Microsecond,
+	})
+	zhtest.AssertNoError(t, err)
+
+	ctx := context.Background()
+	now := time.Now()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	allowedCount := 0
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			allowed, _, _ := store.CheckAndRecord(ctx, "key1", now)
+			if allowed {
+				mu.Lock()
+				allowedCount++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	zhtest.AssertEqual(t, 10, allowedCount)
+}
+
+func TestStorageAdapter_GCRA(t *testing.T) {
+	s := newMockStorage()
+	cfg := StorageAdapterConfig{Algorithm: GCRA, Rate: 2, Window: 2 * time.Second}
+
+	// Two instances sharing the same storage share the limit
+	store1, err := NewStorageAdapter(s, cfg)
+	zhtest.AssertNoError(t, err)
+	store2, err := NewStorageAdapter(s, cfg)
+	zhtest.AssertNoError(t, err)
+
+	ctx := context.Background()
+	now := time.Now().Round(0) // Stored timestamps have no monotonic reading
+
+	allowed, remaining, resetTime := store1.CheckAndRecord(ctx, "key1", now)
+	zhtest.AssertTrue(t, allowed)
+	zhtest.AssertEqual(t, 1, remaining)
+	zhtest.AssertEqual(t, now.Add(time.Second), resetTime)
+
+	allowed, remaining, resetTime = store2.CheckAndRecord(ctx, "key1", now)
+	zhtest.AssertTrue(t, allowed)
+	zhtest.AssertEqual(t, 0, remaining)
+	zhtest.AssertEqual(t, now.Add(2*time.Second), resetTime)
+
+	allowed, _, resetTime = store1.CheckAndRecord(ctx, "key1", now)
+	zhtest.AssertFalse(t, allowed)
+	zhtest.AssertEqual(t, now.Add(time.Second), resetTime)
+
+	// A single timestamp is stored, expiring once the key is fully restored
+	zhtest.AssertEqual(t, strconv.FormatInt(now.Add(2*time.Second).UnixNano(), 10), string(s.data["ratelimit:key1"]))
+	zhtest.AssertEqual(t, 2*time.Second, s.ttlVals["ratelimit:key1"])
+	zhtest.AssertEqual(t, 0, len(s.locks))
+
+	allowed, _, _ = store2.CheckAndRecord(ctx, "key1", now.Add(time.Second))
+	zhtest.AssertTrue(t, allowed)
+}
+
+func TestStorageAdapter_FailOpen(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, algorithm := range []Algorithm{TokenBucket, FixedWindow, SlidingWindow, GCRA} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			s := newMockStorage()
+			store, err := NewStorageAdapter(s, StorageAdapterConfig{Algorithm: algorithm, Rate: 1, Window: time.Minute})
+			zhtest.AssertNoError(t, err)
+
+			s.err = errors.New("connection refused")
+			for range 3 {
+				allowed, remaining, resetTime := store.CheckAndRecord(ctx, "key1", now)
+				zhtest.AssertTrue(t, allowed)
+				zhtest.AssertEqual(t, 0, remaining)
+				zhtest.AssertEqual(t, now.Add(time.Minute), resetTime)
+			}
+		})
+	}
+}
+
+func TestStorageAdapter_LockHeld(t *testing.T) {
+	s := newMockStorage()
+	store, err := NewStorageAdapter(s, StorageAdapterConfig{
+		Algorithm:      TokenBucket,
+		Rate:           1,
+		LockRetries:    2,
+		LockRetryDelay: time.Millisecond,
+	})
+	zhtest.AssertNoError(t, err)
+
+	s.locks["ratelimit:key1:lock"] = true
+
+	// Denies until the lock expires without touching the bucket
+	now := time.Now()
+	allowed, remaining, resetTime := store.CheckAndRecord(context.Background(), "key1", now)
+	zhtest.AssertFalse(t, allowed)
+	zhtest.AssertEqual(t, 0, remaining)
+	zhtest.AssertEqual(t, now.Add(time.Second), resetTime)
+	_, ok := s.data["ratelimit:key1"]
+	zhtest.AssertFalse(t, ok)
+	zhtest.AssertTrue(t, s.locks["ratelimit:key1:lock"])
+}
+
+func TestStorageAdapter_Close(t *testing.T) {
+	store, err := NewStorageAdapter(newMockStorage())
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertNoError(t, store.Close())
+}
@@ -29,6 +29,9 @@ const (
 	SlidingWindow Algorithm = "sliding_window"
 	// FixedWindow uses fixed window algorithm
 	FixedWindow Algorithm = "fixed_window"
+	// GCRA uses the generic cell rate algorithm, a leaky bucket that only
+	// stores one timestamp per key
+	GCRA Algorithm = "gcra"
 )
 
 // KeyExtractor defines a function to extract rate limit key from request
@@ -65,6 +68,12 @@ type Config struct {
 	// Default: true
 	IncludeHeaders *bool
 
+	// StandardHeaders adds the IETF draft standard rate limit headers
+	// (RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset) to responses.
+	// These can be combined with the X-RateLimit-* headers from IncludeHeaders.
+	// Default: false
+	StandardHeaders bool
+
 	// ExcludedPaths contains paths to skip rate limiting.
 	// Supports exact matches, prefixes (ending with /), and wildcards (ending with *).
 	// Cannot be used with IncludedPaths - setting both will panic.
@@ -87,18 +96,27 @@ type Config struct {
 	// in-memory store. Set to 0 for unlimited (not recommended).
 	// Default: 10000
 	MaxKeys int
+
+	// CleanupInterval is how often the default in-memory store starts
+	// removing entries whose window has fully expired. The sweep runs in
+	// small steps during requests. Set to a negative value to disable
+	// cleanup.
+	// Default: 1m
+	CleanupInterval time.Duration
 }
 
 // DefaultConfig contains the default values for rate limit configuration.
 // The default KeyExtractor is IP-based (via ratelimit.IPKeyExtractor).
 var DefaultConfig = Config{
-	Rate:           100,
-	Window:         time.Minute,
-	Algorithm:      TokenBucket,
-	KeyExtractor:   nil, // Uses ratelimit.IPKeyExtractor() by default
-	StatusCode:     http.StatusTooManyRequests,
-	Message:        "Rate limit exceeded",
-	IncludeHeaders: config.Bool(true),
-	ExcludedPaths:  []string{},
-	IncludedPaths:  []string{},
+	Rate:            100,
+	Window:          time.Minute,
+	Algorithm:       TokenBucket,
+	KeyExtractor:    nil, // Uses ratelimit.IPKeyExtractor() by default
+	StatusCode:      http.StatusTooManyRequests,
+	Message:         "Rate limit exceeded",
+	IncludeHeaders:  config.Bool(true),
+	StandardHeaders: false,
+	ExcludedPaths:   []string{},
+	IncludedPaths:   []string{},
+	CleanupInterval: DefaultCleanupInterval,
 }
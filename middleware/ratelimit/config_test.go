@@ -19,6 +19,8 @@ func TestRateLimitConfig_DefaultValues(t *testing.T) {
 	zhtest.AssertEqual(t, http.StatusTooManyRequests, cfg.StatusCode)
 	zhtest.AssertEqual(t, "Rate limit exceeded", cfg.Message)
 	zhtest.AssertTrue(t, *cfg.IncludeHeaders)
+	zhtest.AssertFalse(t, cfg.StandardHeaders)
+	zhtest.AssertEqual(t, DefaultCleanupInterval, cfg.CleanupInterval)
 	zhtest.AssertEqual(t, 0, len(cfg.ExcludedPaths))
 	zhtest.AssertEqual(t, 0, len(cfg.IncludedPaths))
 }
@@ -74,7 +76,7 @@ func TestRateLimitConfig_StructAssignment(t *testing.T) {
 	})
 
 	t.Run("all algorithms", func(t *testing.T) {
-		algorithms := []Algorithm{TokenBucket, SlidingWindow, FixedWindow}
+		algorithms := []Algorithm{TokenBucket, SlidingWindow, FixedWindow, GCRA}
 		for _, algorithm := range algorithms {
 			cfg := Config{
 				Algorithm: algorithm,
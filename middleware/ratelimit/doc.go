@@ -1,6 +1,6 @@
 // Package ratelimit provides rate limiting middleware.
 //
-// Supports token bucket and sliding window algorithms with configurable
+// Supports token bucket, fixed window, sliding window and GCRA algorithms with configurable
 // key extractors for per-client, per-user, or custom rate limiting.
 //
 // # Usage
@@ -13,6 +13,35 @@
 //	    Window: time.Minute,     // per minute
 //	}))
 //
+// # GCRA
+//
+// The generic cell rate algorithm spaces requests evenly at Window/Rate
+// intervals while still allowing a burst of up to Rate requests. It behaves
+// like a leaky bucket but only stores a single timestamp per key, and has no
+// burst at window boundaries like the fixed window:
+//
+//	app.Use(ratelimit.New(ratelimit.Config{
+//	    Algorithm: ratelimit.GCRA,
+//	    Rate:      10,
+//	    Window:    time.Second,
+//	}))
+//
+// # Response Headers
+//
+// By default, responses include X-RateLimit-Limit, X-RateLimit-Remaining,
+// X-RateLimit-Reset, and X-RateLimit-Window headers. Rejected requests also
+// get a Retry-After header with the number of seconds until the next request
+// will be allowed: the next token refill for token bucket, the end of the
+// window for fixed window, the expiry of the oldest request for sliding window,
+// and the next conforming arrival time for GCRA.
+//
+// Enable StandardHeaders to also emit the IETF draft RateLimit-Limit,
+// RateLimit-Remaining, and RateLimit-Reset headers. Both styles can be used together:
+//
+//	app.Use(ratelimit.New(ratelimit.Config{
+//	    StandardHeaders: true,
+//	}))
+//
 // # Key Extractors
 //
 // Rate limit by different criteria:
@@ -35,11 +64,50 @@
 //	    KeyExtractor: ratelimit.ContextKeyExtractor("user_id"),
 //	}))
 //
+// # Memory Cleanup
+//
+// The default in-memory store removes entries whose window has fully expired
+// once per cleanup interval, so keys from clients that stopped sending
+// requests don't accumulate. The sweep runs in small steps during requests
+// rather than in a background goroutine, so there is nothing to stop:
+//
+//	app.Use(ratelimit.New(ratelimit.Config{
+//	    CleanupInterval: 30 * time.Second, // Negative disables cleanup
+//	}))
+//
+// A [MemoryStore] created directly with [NewMemoryStore] cleans up every
+// [DefaultCleanupInterval].
+//
 // # Custom Store
 //
-// Use Redis for distributed rate limiting:
+// Implement the [Store] interface for a custom backend:
 //
 //	app.Use(ratelimit.New(ratelimit.Config{
 //	    Store: myRedisRateLimitStore,
 //	}))
+//
+// # Storage Adapter
+//
+// Use [NewStorageAdapter] to share limits between instances through a
+// [storage.Storage] implementation such as Redis. The fixed and sliding
+// window algorithms require [storage.Counter]; the token bucket and GCRA
+// require [storage.Locker]. Storage errors fail open, but a request that
+// can't acquire a busy key's lock after LockRetries is denied. Algorithm, Rate
+// and Window must match the middleware:
+//
+//	store, err := ratelimit.NewStorageAdapter(myRedisStorage, ratelimit.StorageAdapterConfig{
+//	    Algorithm: ratelimit.FixedWindow,
+//	    Rate:      100,
+//	    Window:    time.Minute,
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	app.Use(ratelimit.New(ratelimit.Config{
+//	    Algorithm: ratelimit.FixedWindow,
+//	    Rate:      100,
+//	    Window:    time.Minute,
+//	    Store:     store,
+//	}))
 package ratelimit
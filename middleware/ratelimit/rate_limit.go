@@ -1,6 +1,7 @@
 package ratelimit
 
 import (
+	"math"
 	"net"
 	"net/http"
 	"strconv"
@@ -40,7 +41,9 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 		if maxKeys == 0 {
 			maxKeys = DefaultMaxKeys
 		}
-		store = NewMemoryStore(c.Algorithm, c.Window, c.Rate, maxKeys)
+		memoryStore := NewMemoryStore(c.Algorithm, c.Window, c.Rate, maxKeys)
+		memoryStore.cleanupInterval = c.CleanupInterval
+		store = memoryStore
 	}
 
 	return func(next http.Handler) http.Handler {
@@ -66,11 +69,18 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 				w.Header().Set(httpx.HeaderXRateLimitWindow, c.Window.String())
 			}
 
+			if c.StandardHeaders && !isSSE {
+				// The draft standard uses delta-seconds for reset, unlike the Unix timestamp of X-RateLimit-Reset
+				w.Header().Set(httpx.HeaderRateLimitLimit, strconv.Itoa(c.Rate))
+				w.Header().Set(httpx.HeaderRateLimitRemaining, strconv.Itoa(remaining))
+				w.Header().Set(httpx.HeaderRateLimitReset, strconv.Itoa(retryAfterSeconds(resetTime, now)))
+			}
+
 			reg.Gauge("ratelimit_remaining", "key").WithLabelValues(key).Set(float64(remaining))
 
 			if !allowed {
 				reg.Counter("ratelimit_rejected_total", "key").WithLabelValues(key).Inc()
-				w.Header().Set(httpx.HeaderRetryAfter, strconv.Itoa(int(time.Until(resetTime).Seconds())))
+				w.Header().Set(httpx.HeaderRetryAfter, strconv.Itoa(retryAfterSeconds(resetTime, now)))
 				detail := problem.NewDetail(c.StatusCode, c.Message)
 				_ = detail.RenderAuto(w, r)
 				return
@@ -82,6 +92,16 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 	}
 }
 
+// retryAfterSeconds returns the number of whole seconds a client must wait
+// before resetTime, rounding up so clients never retry too early.
+func retryAfterSeconds(resetTime, now time.Time) int {
+	wait := resetTime.Sub(now)
+	if wait <= 0 {
+		return 0
+	}
+	return int(math.Ceil(wait.Seconds()))
+}
+
 // KeyExtractor helpers for common rate limiting scenarios.
 // These are convenience wrappers around config.KeyExtractor.
 
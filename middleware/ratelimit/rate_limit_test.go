@@ -179,6 +179,83 @@ func TestRateLimitHeaders(t *testing.T) {
 		Header("X-RateLimit-Window", "1m0s")
 }
 
+func TestRateLimitRetryAfter(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm Algorithm
+		rate      int
+		window    time.Duration
+		want      string
+	}{
+		{"token bucket next token", TokenBucket, 2, 10 * time.Second, "5"},
+		{"token bucket rounds up", TokenBucket, 3, 10 * time.Second, "4"},
+		{"fixed window end", FixedWindow, 2, 10 * time.Second, "10"},
+		{"sliding window oldest expiry", SlidingWindow, 2, 10 * time.Second, "10"},
+		{"gcra next conforming arrival", GCRA, 2, 10 * time.Second, "5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := New(Config{
+				Rate:      tt.rate,
+				Window:    tt.window,
+				Algorithm: tt.algorithm,
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }))
+
+			for range tt.rate {
+				req := zhtest.NewRequest(http.MethodGet, "/test").Build()
+				req.RemoteAddr = "127.0.0.1:12345"
+				zhtest.AssertWith(t, zhtest.Serve(handler, req)).Status(http.StatusOK).HeaderNotExists(httpx.HeaderRetryAfter)
+			}
+
+			req := zhtest.NewRequest(http.MethodGet, "/test").Build()
+			req.RemoteAddr = "127.0.0.1:12345"
+			w := zhtest.Serve(handler, req)
+
+			zhtest.AssertWith(t, w).
+				Status(http.StatusTooManyRequests).
+				Header(httpx.HeaderRetryAfter, tt.want)
+		})
+	}
+}
+
+func TestRateLimitLockContention(t *testing.T) {
+	s := newMockStorage()
+	store, err := NewStorageAdapter(s, StorageAdapterConfig{
+		Algorithm:      TokenBucket,
+		Rate:           10,
+		LockTTL:        2 * time.Second,
+		LockRetries:    1,
+		LockRetryDelay: time.Millisecond,
+	})
+	zhtest.AssertNoError(t, err)
+	s.locks["ratelimit:127.0.0.1:lock"] = true
+
+	handler := New(Config{
+		Rate:      10,
+		Algorithm: TokenBucket,
+		Store:     store,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }))
+
+	req := zhtest.NewRequest(http.MethodGet, "/test").Build()
+	req.RemoteAddr = "127.0.0.1:12345"
+	w := zhtest.Serve(handler, req)
+
+	zhtest.AssertWith(t, w).
+		Status(http.StatusTooManyRequests).
+		Header(httpx.HeaderRetryAfter, "2")
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	now := time.Now()
+
+	zhtest.AssertEqual(t, 1, retryAfterSeconds(now.Add(time.Millisecond), now))
+	zhtest.AssertEqual(t, 1, retryAfterSeconds(now.Add(time.Second), now))
+	zhtest.AssertEqual(t, 2, retryAfterSeconds(now.Add(1500*time.Millisecond), now))
+	zhtest.AssertEqual(t, 0, retryAfterSeconds(now, now))
+	zhtest.AssertEqual(t, 0, retryAfterSeconds(now.Add(-time.Second), now))
+}
+
 func TestRateLimitNoHeaders(t *testing.T) {
 	middleware := New(Config{
 		Rate:           2,
@@ -197,6 +274,75 @@ func TestRateLimitNoHeaders(t *testing.T) {
 	}
 }
 
+func TestRateLimitStandardHeaders(t *testing.T) {
+	t.Run("coexist with X- headers", func(t *testing.T) {
+		handler := New(Config{
+			Rate:            2,
+			Window:          10 * time.Second,
+			Algorithm:       FixedWindow,
+			StandardHeaders: true,
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }))
+
+		req := zhtest.NewRequest(http.MethodGet, "/test").Build()
+		req.RemoteAddr = "127.0.0.1:12345"
+		w := zhtest.Serve(handler, req)
+
+		zhtest.AssertWith(t, w).
+			Status(http.StatusOK).
+			Header(httpx.HeaderRateLimitLimit, "2").
+			Header(httpx.HeaderRateLimitRemaining, "1").
+			Header(httpx.HeaderRateLimitReset, "10").
+			Header(httpx.HeaderXRateLimitLimit, "2").
+			Header(httpx.HeaderXRateLimitRemaining, "1")
+
+		// Values derive from the same state as the X- headers
+		req = zhtest.NewRequest(http.MethodGet, "/test").Build()
+		req.RemoteAddr = "127.0.0.1:12345"
+		zhtest.Serve(handler, req)
+
+		req = zhtest.NewRequest(http.MethodGet, "/test").Build()
+		req.RemoteAddr = "127.0.0.1:12345"
+		w = zhtest.Serve(handler, req)
+
+		zhtest.AssertWith(t, w).
+			Status(http.StatusTooManyRequests).
+			Header(httpx.HeaderRateLimitRemaining, "0").
+			Header(httpx.HeaderXRateLimitRemaining, "0").
+			Header(httpx.HeaderRateLimitReset, w.Header().Get(httpx.HeaderRetryAfter))
+	})
+
+	t.Run("standard headers only", func(t *testing.T) {
+		handler := New(Config{
+			Rate:            5,
+			Window:          time.Minute,
+			IncludeHeaders:  config.Bool(false),
+			StandardHeaders: true,
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }))
+
+		req := zhtest.NewRequest(http.MethodGet, "/test").Build()
+		req.RemoteAddr = "127.0.0.1:12345"
+		w := zhtest.Serve(handler, req)
+
+		zhtest.AssertWith(t, w).
+			Header(httpx.HeaderRateLimitLimit, "5").
+			Header(httpx.HeaderRateLimitRemaining, "4").
+			HeaderExists(httpx.HeaderRateLimitReset).
+			HeaderNotExists(httpx.HeaderXRateLimitLimit)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		handler := New()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }))
+
+		req := zhtest.NewRequest(http.MethodGet, "/test").Build()
+		req.RemoteAddr = "127.0.0.1:12345"
+		w := zhtest.Serve(handler, req)
+
+		zhtest.AssertWith(t, w).
+			HeaderExists(httpx.HeaderXRateLimitLimit).
+			HeaderNotExists(httpx.HeaderRateLimitLimit)
+	})
+}
+
 func TestRateLimitCustomKeyExtractor(t *testing.T) {
 	middleware := New(Config{
 		Rate:      2,
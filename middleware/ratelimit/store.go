@@ -3,6 +3,7 @@ package ratelimit
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -31,8 +32,27 @@ type windowEntry struct {
 	mutex      sync.Mutex
 }
 
+// gcraEntry holds the theoretical arrival time of the next request.
+type gcraEntry struct {
+	tat        time.Time
+	lastAccess time.Time
+	mutex      sync.Mutex
+}
+
+// DefaultCleanupInterval is the default interval at which MemoryStore
+// removes expired entries.
+const DefaultCleanupInterval = time.Minute
+
 // MemoryStore is a secure in-memory implementation of Store
 // with automatic expiration and max keys limit.
+//
+// Entries whose window has fully expired are removed by a sweep that starts
+// on the first CheckAndRecord call after each cleanup interval. Each call
+// examines a bounded number of entries, and the sweep continues on the
+// following calls while it keeps finding expired entries, so no single
+// request pays for sweeping every key. The sweep runs on the request path
+// instead of in a background goroutine, so a store that is no longer used
+// holds no goroutine and needs no Close.
 type MemoryStore struct {
 	algorithm Algorithm
 	window    time.Duration
@@ -42,8 +62,12 @@ type MemoryStore struct {
 	buckets  map[string]*bucketEntry
 	counters map[string]*counterEntry
 	windows  map[string]*windowEntry
+	cells    map[string]*gcraEntry
 
 	mu sync.RWMutex
+
+	cleanupInterval time.Duration
+	nextCleanup     atomic.Int64 // Unix nanoseconds, 0 until the first call
 }
 
 // NewMemoryStore creates a new in-memory rate limit store.
@@ -61,11 +85,16 @@ func NewMemoryStore(algorithm Algorithm, window time.Duration, rate, maxKeys int
 		buckets:   make(map[string]*bucketEntry),
 		counters:  make(map[string]*counterEntry),
 		windows:   make(map[string]*windowEntry),
+		cells:     make(map[string]*gcraEntry),
+
+		cleanupInterval: DefaultCleanupInterval,
 	}
 }
 
 // CheckAndRecord implements Store.
 func (s *MemoryStore) CheckAndRecord(_ context.Context, key string, now time.Time) (bool, int, time.Time) {
+	s.maybeCleanup(now)
+
 	switch s.algorithm {
 	case TokenBucket:
 		return s.checkTokenBucket(key, now)
@@ -73,6 +102,8 @@ func (s *MemoryStore) CheckAndRecord(_ context.Context, key string, now time.Tim
 		return s.checkFixedWindow(key, now)
 	case SlidingWindow:
 		return s.checkSlidingWindow(key, now)
+	case GCRA:
+		return s.checkGCRA(key, now)
 	default:
 		return s.checkTokenBucket(key, now)
 	}
@@ -113,14 +144,20 @@ func (s *MemoryStore) checkTokenBucket(key string, now time.Time) (bool, int, ti
 	entry.tokens = min(entry.capacity, entry.tokens+elapsed*entry.rate)
 	entry.lastRefill = now
 
-	resetTime := now.Add(time.Duration((entry.capacity-entry.tokens)/entry.rate) * time.Second)
-
 	if entry.tokens >= 1.0 {
 		entry.tokens--
-		return true, int(entry.tokens), resetTime
+		// Reset is when the bucket will be full again
+		return true, int(entry.tokens), now.Add(refillDuration(entry.capacity-entry.tokens, entry.rate))
 	}
 
-	return false, 0, resetTime
+	// Reset is when the next whole token becomes available
+	return false, 0, now.Add(refillDuration(1.0-entry.tokens, entry.rate))
+}
+
+// refillDuration returns how long it takes to refill the given number of tokens
+// at rate tokens per second, without truncating to whole seconds.
+func refillDuration(tokens, rate float64) time.Duration {
+	return time.Duration(tokens / rate * float64(time.Second))
 }
 
 func (s *MemoryStore) checkFixedWindow(key string, now time.Time) (bool, int, time.Time) {
@@ -217,6 +254,55 @@ func (s *MemoryStore) checkSlidingWindow(key string, now time.Time) (bool, int,
 	return false, 0, resetTime
 }
 
+func (s *MemoryStore) checkGCRA(key string, now time.Time) (bool, int, time.Time) {
+	s.mu.Lock()
+
+	entry, exists := s.cells[key]
+	if !exists {
+		if len(s.cells) >= s.maxKeys {
+			s.evictOldestCell()
+		}
+		entry = &gcraEntry{tat: now}
+		s.cells[key] = entry
+	}
+	entry.lastAccess = now
+
+	// Release store lock before acquiring entry lock to maintain consistent
+	// lock ordering and prevent potential deadlocks
+	s.mu.Unlock()
+
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+
+	tat, allowed, remaining, resetTime := gcra(entry.tat, now, s.window, s.rate)
+	entry.tat = tat
+	return allowed, remaining, resetTime
+}
+
+// gcra applies the generic cell rate algorithm to the theoretical arrival
+// time tat, allowing rate requests per window with bursts of up to rate.
+// Returns the new theoretical arrival time along with the Store results.
+// When allowed, the reset time is when the limit is fully restored; when
+// rejected, it is when the next request will be allowed.
+func gcra(tat, now time.Time, window time.Duration, rate int) (time.Time, bool, int, time.Time) {
+	interval := max(window/time.Duration(rate), 1)
+
+	if tat.Before(now) {
+		tat = now
+	}
+	newTat := tat.Add(interval)
+
+	// The request conforms if it arrives no earlier than one window before its
+	// theoretical arrival time
+	allowAt := newTat.Add(-window)
+	if now.Before(allowAt) {
+		return tat, false, 0, allowAt
+	}
+
+	remaining := int((window - newTat.Sub(now)) / interval)
+	return newTat, true, max(remaining, 0), newTat
+}
+
 // entryWithLastAccess is an interface for entries that have a lastAccess field.
 type entryWithLastAccess interface {
 	getLastAccess() time.Time
@@ -225,6 +311,7 @@ type entryWithLastAccess interface {
 func (e *bucketEntry) getLastAccess() time.Time  { return e.lastAccess }
 func (e *counterEntry) getLastAccess() time.Time { return e.lastAccess }
 func (e *windowEntry) getLastAccess() time.Time  { return e.lastAccess }
+func (e *gcraEntry) getLastAccess() time.Time    { return e.lastAccess }
 
 // evictOldest removes the entry with the oldest lastAccess time from the map.
 // If multiple entries have the same lastAccess, the lexicographically smaller key is chosen.
@@ -250,9 +337,83 @@ func evictOldest[M ~map[string]E, E entryWithLastAccess](m M) {
 func (s *MemoryStore) evictOldestBucket()  { evictOldest(s.buckets) }
 func (s *MemoryStore) evictOldestCounter() { evictOldest(s.counters) }
 func (s *MemoryStore) evictOldestWindow()  { evictOldest(s.windows) }
+func (s *MemoryStore) evictOldestCell()    { evictOldest(s.cells) }
+
+// cleanupBatch is the number of entries of each map examined by a cleanup
+// step, which bounds the time a request spends sweeping with the store lock
+// held regardless of the number of keys.
+const cleanupBatch = 64
+
+// maybeCleanup runs a cleanup step at now once the cleanup interval has
+// passed since the previous sweep, unless cleanup is disabled. Only one of
+// the concurrent callers past the interval sweeps. While steps keep finding
+// mostly expired entries, the next call runs another step instead of waiting
+// for the interval, so large backlogs are removed over several requests.
+func (s *MemoryStore) maybeCleanup(now time.Time) {
+	if s.cleanupInterval <= 0 {
+		return
+	}
+
+	next := s.nextCleanup.Load()
+	if next == 0 {
+		s.nextCleanup.CompareAndSwap(0, now.Add(s.cleanupInterval).UnixNano())
+		return
+	}
+	if now.UnixNano() < next || !s.nextCleanup.CompareAndSwap(next, now.Add(s.cleanupInterval).UnixNano()) {
+		return
+	}
+	if s.cleanup(now) {
+		s.nextCleanup.Store(now.UnixNano())
+	}
+}
+
+// cleanup removes entries whose window has fully expired at now, examining
+// at most cleanupBatch entries of each map. Such entries would be recreated
+// from scratch on their next access anyway. It reports whether more than a
+// quarter of a full batch had expired, in which case more expired entries
+// likely remain.
+func (s *MemoryStore) cleanup(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	more := sweepExpired(s.buckets, func(e *bucketEntry) bool {
+		return now.Sub(e.lastAccess) > s.window
+	})
+	more = sweepExpired(s.counters, func(e *counterEntry) bool {
+		return now.Sub(e.windowStart) >= s.window
+	}) || more
+	more = sweepExpired(s.windows, func(e *windowEntry) bool {
+		return now.Sub(e.lastAccess) > s.window
+	}) || more
+	more = sweepExpired(s.cells, func(e *gcraEntry) bool {
+		e.mutex.Lock()
+		defer e.mutex.Unlock()
+		return !e.tat.After(now)
+	}) || more
+	return more
+}
+
+// sweepExpired deletes the expired entries among up to cleanupBatch entries
+// of m. Map iteration starts at a random position, so successive calls
+// examine different entries. It reports whether a full batch was examined
+// and more than a quarter of it had expired.
+func sweepExpired[M ~map[string]E, E any](m M, expired func(E) bool) bool {
+	examined, removed := 0, 0
+	for key, entry := range m {
+		if examined == cleanupBatch {
+			break
+		}
+		examined++
+		if expired(entry) {
+			delete(m, key)
+			removed++
+		}
+	}
+	return examined == cleanupBatch && removed*4 > examined
+}
 
 // Close releases resources associated with the store.
-// For MemoryStore, this is a no-op.
+// For MemoryStore, this is a no-op since cleanup needs no goroutine.
 func (s *MemoryStore) Close() error {
 	return nil
 }
@@ -2,6 +2,7 @@ package ratelimit
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -310,3 +311,207 @@ func TestInMemoryStore_Close(t *testing.T) {
 	err := store.Close()
 	zhtest.AssertNoError(t, err)
 }
+
+// TestInMemoryStore_ResetTimeOnDenial tests that the reset time of a denied
+// request reflects when the next request will be allowed
+func TestInMemoryStore_ResetTimeOnDenial(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	t.Run("token bucket waits for next token", func(t *testing.T) {
+		// 2 tokens per 10s refills one token every 5s
+		store := NewMemoryStore(TokenBucket, 10*time.Second, 2, 100)
+		store.CheckAndRecord(ctx, "key1", now)
+		store.CheckAndRecord(ctx, "key1", now)
+
+		allowed, _, resetTime := store.CheckAndRecord(ctx, "key1", now)
+		zhtest.AssertFalse(t, allowed)
+		zhtest.AssertEqual(t, 5*time.Second, resetTime.Sub(now).Round(time.Millisecond))
+
+		// Partially refilled bucket needs less time
+		later := now.Add(2 * time.Second)
+		allowed, _, resetTime = store.CheckAndRecord(ctx, "key1", later)
+		zhtest.AssertFalse(t, allowed)
+		zhtest.AssertEqual(t, 3*time.Second, resetTime.Sub(later).Round(time.Millisecond))
+
+		// Request is allowed once the reset time is reached
+		allowed, _, _ = store.CheckAndRecord(ctx, "key1", resetTime.Add(time.Millisecond))
+		zhtest.AssertTrue(t, allowed)
+	})
+
+	t.Run("token bucket keeps sub-second precision", func(t *testing.T) {
+		// 4 tokens per second refills one token every 250ms
+		store := NewMemoryStore(TokenBucket, time.Second, 4, 100)
+		for range 4 {
+			store.CheckAndRecord(ctx, "key1", now)
+		}
+
+		_, _, resetTime := store.CheckAndRecord(ctx, "key1", now)
+		zhtest.AssertEqual(t, 250*time.Millisecond, resetTime.Sub(now).Round(time.Millisecond))
+	})
+
+	t.Run("fixed window waits for window end", func(t *testing.T) {
+		store := NewMemoryStore(FixedWindow, 10*time.Second, 1, 100)
+		store.CheckAndRecord(ctx, "key1", now)
+
+		later := now.Add(4 * time.Second)
+		allowed, _, resetTime := store.CheckAndRecord(ctx, "key1", later)
+		zhtest.AssertFalse(t, allowed)
+		zhtest.AssertEqual(t, 6*time.Second, resetTime.Sub(later))
+	})
+
+	t.Run("sliding window waits for oldest request to expire", func(t *testing.T) {
+		store := NewMemoryStore(SlidingWindow, 10*time.Second, 2, 100)
+		store.CheckAndRecord(ctx, "key1", now)
+		store.CheckAndRecord(ctx, "key1", now.Add(3*time.Second))
+
+		later := now.Add(4 * time.Second)
+		allowed, _, resetTime := store.CheckAndRecord(ctx, "key1", later)
+		zhtest.AssertFalse(t, allowed)
+		zhtest.AssertEqual(t, 6*time.Second, resetTime.Sub(later))
+
+		allowed, _, _ = store.CheckAndRecord(ctx, "key1", now.Add(10*time.Second+time.Millisecond))
+		zhtest.AssertTrue(t, allowed)
+	})
+}
+
+func TestInMemoryStore_Cleanup(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, algorithm := range []Algorithm{TokenBucket, FixedWindow, SlidingWindow} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			store := NewMemoryStore(algorithm, time.Second, 10, 100)
+			defer func() { _ = store.Close() }()
+
+			for _, key := range []string{"a", "b", "c"} {
+				store.CheckAndRecord(ctx, key, now)
+			}
+			store.CheckAndRecord(ctx, "c", now.Add(800*time.Millisecond))
+
+			size := func() int {
+				store.mu.RLock()
+				defer store.mu.RUnlock()
+				return len(store.buckets) + len(store.counters) + len(store.windows)
+			}
+			zhtest.AssertEqual(t, 3, size())
+
+			// Nothing has expired yet
+			store.cleanup(now.Add(500 * time.Millisecond))
+			zhtest.AssertEqual(t, 3, size())
+
+			// Only the recently used key survives, except for fixed windows
+			// which expire a full window after they started
+			store.cleanup(now.Add(1100 * time.Millisecond))
+			if algorithm == FixedWindow {
+				zhtest.AssertEqual(t, 0, size())
+			} else {
+				zhtest.AssertEqual(t, 1, size())
+			}
+
+			store.cleanup(now.Add(2 * time.Second))
+			zhtest.AssertEqual(t, 0, size())
+		})
+	}
+}
+
+func TestInMemoryStore_GCRA(t *testing.T) {
+	store := NewMemoryStore(GCRA, 10*time.Second, 5, 100)
+	ctx := context.Background()
+	now := time.Now()
+
+	// A full burst is allowed, each request pushing the reset back by one interval
+	for i := 4; i >= 0; i-- {
+		allowed, remaining, resetTime := store.CheckAndRecord(ctx, "key1", now)
+		zhtest.AssertTrue(t, allowed)
+		zhtest.AssertEqual(t, i, remaining)
+		zhtest.AssertEqual(t, time.Duration(5-i)*2*time.Second, resetTime.Sub(now))
+	}
+
+	// The next request conforms one interval later
+	allowed, remaining, resetTime := store.CheckAndRecord(ctx, "key1", now)
+	zhtest.AssertFalse(t, allowed)
+	zhtest.AssertEqual(t, 0, remaining)
+	zhtest.AssertEqual(t, 2*time.Second, resetTime.Sub(now))
+
+	// Rejected requests don't consume capacity
+	allowed, _, _ = store.CheckAndRecord(ctx, "key1", now.Add(time.Second))
+	zhtest.AssertFalse(t, allowed)
+
+	allowed, remaining, _ = store.CheckAndRecord(ctx, "key1", now.Add(2*time.Second))
+	zhtest.AssertTrue(t, allowed)
+	zhtest.AssertEqual(t, 0, remaining)
+
+	// Capacity is restored gradually rather than at a window boundary
+	allowed, remaining, _ = store.CheckAndRecord(ctx, "key1", now.Add(7*time.Second))
+	zhtest.AssertTrue(t, allowed)
+	zhtest.AssertEqual(t, 1, remaining)
+
+	// Other keys are independent
+	allowed, remaining, _ = store.CheckAndRecord(ctx, "key2", now)
+	zhtest.AssertTrue(t, allowed)
+	zhtest.AssertEqual(t, 4, remaining)
+}
+
+func TestInMemoryStore_GCRACleanup(t *testing.T) {
+	store := NewMemoryStore(GCRA, time.Second, 2, 100)
+	defer func() { _ = store.Close() }()
+	ctx := context.Background()
+	now := time.Now()
+
+	store.CheckAndRecord(ctx, "a", now)
+	store.CheckAndRecord(ctx, "b", now)
+	store.CheckAndRecord(ctx, "b", now)
+
+	// Keys are removed once their theoretical arrival time has passed
+	store.cleanup(now.Add(600 * time.Millisecond))
+	zhtest.AssertEqual(t, 1, len(store.cells))
+
+	store.cleanup(now.Add(time.Second))
+	zhtest.AssertEqual(t, 0, len(store.cells))
+}
+
+func TestInMemoryStore_PeriodicCleanup(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(TokenBucket, 20*time.Millisecond, 10, 1000)
+	store.cleanupInterval = time.Second
+	now := time.Now()
+
+	for i := range 100 {
+		store.CheckAndRecord(ctx, fmt.Sprintf("key-%d", i), now)
+	}
+	zhtest.AssertEqual(t, 100, len(store.buckets))
+
+	// Expired, but the cleanup interval hasn't passed yet
+	store.CheckAndRecord(ctx, "other", now.Add(500*time.Millisecond))
+	zhtest.AssertEqual(t, 101, len(store.buckets))
+
+	// Calls after the interval sweep the expired keys a batch at a time
+	later := now.Add(time.Second)
+	store.CheckAndRecord(ctx, "new", later)
+	zhtest.AssertEqual(t, 101-cleanupBatch+1, len(store.buckets))
+
+	// The sweep continues on the next call, then waits for the interval
+	// once a batch is no longer full
+	store.CheckAndRecord(ctx, "new", later)
+	zhtest.AssertEqual(t, 1, len(store.buckets))
+	zhtest.AssertEqual(t, later.Add(time.Second).UnixNano(), store.nextCleanup.Load())
+
+	t.Run("disabled", func(t *testing.T) {
+		store := NewMemoryStore(TokenBucket, 20*time.Millisecond, 10, 1000)
+		store.cleanupInterval = -1
+
+		store.CheckAndRecord(ctx, "key", now)
+		store.CheckAndRecord(ctx, "other", now.Add(time.Hour))
+		zhtest.AssertEqual(t, 2, len(store.buckets))
+	})
+}
+
+func TestInMemoryStore_CloseBeforeUse(t *testing.T) {
+	store := NewMemoryStore(FixedWindow, time.Minute, 1, 100)
+	zhtest.AssertNoError(t, store.Close())
+
+	// The store keeps working after Close
+	allowed, _, _ := store.CheckAndRecord(context.Background(), "key", time.Now())
+	zhtest.AssertTrue(t, allowed)
+}
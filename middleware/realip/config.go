@@ -1,8 +1,10 @@
 package realip
 
 import (
+	"fmt"
 	"net"
 	"net/http"
+	"net/netip"
 	"strings"
 
 	"github.com/alexferl/zerohttp/httpx"
@@ -16,14 +18,25 @@ type Config struct {
 	// IPExtractor function to extract real client IP.
 	// Default: DefaultIPExtractor
 	IPExtractor IPExtractor
+
+	// TrustedProxies is a list of IP addresses or CIDR ranges of the proxies
+	// in front of the server. When set and IPExtractor is not, the client IP
+	// is extracted with TrustedProxiesIPExtractor, so forwarding headers are
+	// only honored on requests from these proxies.
+	// Default: []
+	TrustedProxies []string
 }
 
 // DefaultConfig contains the default values for real IP configuration.
 var DefaultConfig = Config{
-	IPExtractor: DefaultIPExtractor,
+	IPExtractor:    DefaultIPExtractor,
+	TrustedProxies: []string{},
 }
 
-// DefaultIPExtractor extracts the real client IP from various headers
+// DefaultIPExtractor extracts the real client IP from various headers.
+// It trusts the headers on every request, so clients that reach the server
+// directly can spoof their IP. Use TrustedProxies when the server is behind
+// known proxies.
 func DefaultIPExtractor(r *http.Request) string {
 	// Check X-Forwarded-For header first (most common)
 	if xff := r.Header.Get(httpx.HeaderXForwardedFor); xff != "" {
@@ -95,3 +108,79 @@ func XRealIPExtractor(r *http.Request) string {
 	}
 	return RemoteAddrIPExtractor(r)
 }
+
+// TrustedProxiesIPExtractor returns an extractor that only honors forwarding
+// headers on requests whose RemoteAddr is one of the trusted proxies, given
+// as IP addresses or CIDR ranges. X-Forwarded-For is walked from right to
+// left, skipping trusted hops, and the first untrusted address is the client.
+// X-Real-IP is used when X-Forwarded-For is absent. Requests from other
+// addresses use RemoteAddr.
+// Panics if any entry is not a valid IP address or CIDR range.
+func TrustedProxiesIPExtractor(proxies []string) IPExtractor {
+	trusted := parseTrustedProxies(proxies)
+	isTrusted := func(ip netip.Addr) bool {
+		for _, p := range trusted {
+			if p.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(r *http.Request) string {
+		remote := RemoteAddrIPExtractor(r)
+		ip, err := netip.ParseAddr(remote)
+		if err != nil || !isTrusted(ip.Unmap()) {
+			return remote
+		}
+
+		var hops []string
+		for _, xff := range r.Header.Values(httpx.HeaderXForwardedFor) {
+			hops = append(hops, strings.Split(xff, ",")...)
+		}
+		if len(hops) == 0 {
+			if xri, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get(httpx.HeaderXRealIP))); err == nil {
+				return xri.String()
+			}
+			return remote
+		}
+
+		client := remote
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				// Anything left of a malformed hop can't be trusted
+				break
+			}
+			client = hop.String()
+			if !isTrusted(hop.Unmap()) {
+				break
+			}
+		}
+		return client
+	}
+}
+
+// parseTrustedProxies converts IP addresses and CIDR ranges into prefixes.
+// Bare addresses are treated as single-host ranges. Panics on invalid entries.
+func parseTrustedProxies(entries []string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if strings.Contains(entry, "/") {
+			p, err := netip.ParsePrefix(entry)
+			if err != nil {
+				panic(fmt.Sprintf("zerohttp: RealIP invalid TrustedProxies CIDR %q: %v", entry, err))
+			}
+			prefixes = append(prefixes, p.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			panic(fmt.Sprintf("zerohttp: RealIP invalid TrustedProxies IP %q: %v", entry, err))
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes
+}
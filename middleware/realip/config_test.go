@@ -354,3 +354,44 @@ func TestRealIPConfig_ExtractorComparison(t *testing.T) {
 		})
 	}
 }
+
+func TestTrustedProxiesIPExtractor(t *testing.T) {
+	extractor := TrustedProxiesIPExtractor([]string{"10.0.0.0/8", "192.168.1.1", "2001:db8::/32"})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string][]string
+		expectedIP string
+	}{
+		{"untrusted remote ignores headers", "203.0.113.9:1234", map[string][]string{"X-Forwarded-For": {"198.51.100.1"}, "X-Real-IP": {"198.51.100.2"}}, "203.0.113.9"},
+		{"trusted remote uses X-Forwarded-For", "10.0.0.1:1234", map[string][]string{"X-Forwarded-For": {"198.51.100.1"}}, "198.51.100.1"},
+		{"spoofed entries left of the client are ignored", "10.0.0.1:1234", map[string][]string{"X-Forwarded-For": {"1.2.3.4, 198.51.100.1, 10.0.0.2"}}, "198.51.100.1"},
+		{"multiple X-Forwarded-For headers", "10.0.0.1:1234", map[string][]string{"X-Forwarded-For": {"1.2.3.4", "198.51.100.1, 192.168.1.1"}}, "198.51.100.1"},
+		{"all hops trusted", "10.0.0.1:1234", map[string][]string{"X-Forwarded-For": {"10.0.0.3, 10.0.0.2"}}, "10.0.0.3"},
+		{"malformed hop", "10.0.0.1:1234", map[string][]string{"X-Forwarded-For": {"not-an-ip, 10.0.0.2"}}, "10.0.0.2"},
+		{"malformed only hop", "10.0.0.1:1234", map[string][]string{"X-Forwarded-For": {"not-an-ip"}}, "10.0.0.1"},
+		{"X-Real-IP without X-Forwarded-For", "10.0.0.1:1234", map[string][]string{"X-Real-IP": {"198.51.100.2"}}, "198.51.100.2"},
+		{"no headers", "10.0.0.1:1234", nil, "10.0.0.1"},
+		{"IPv6 proxy", "[2001:db8::1]:1234", map[string][]string{"X-Forwarded-For": {"2001:db9::5"}}, "2001:db9::5"},
+		{"IPv4-mapped remote", "[::ffff:10.0.0.1]:1234", map[string][]string{"X-Forwarded-For": {"198.51.100.1"}}, "198.51.100.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, values := range tt.headers {
+				for _, v := range values {
+					req.Header.Add(k, v)
+				}
+			}
+			zhtest.AssertEqual(t, tt.expectedIP, extractor(req))
+		})
+	}
+
+	t.Run("invalid entries panic", func(t *testing.T) {
+		zhtest.AssertPanic(t, func() { TrustedProxiesIPExtractor([]string{"10.0.0.0/33"}) })
+		zhtest.AssertPanic(t, func() { TrustedProxiesIPExtractor([]string{"proxy"}) })
+	})
+}
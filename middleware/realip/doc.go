@@ -3,6 +3,13 @@
 // Extracts the real client IP from X-Forwarded-For, X-Real-Ip headers,
 // or other proxy headers. Configurable trusted proxies.
 //
+// By default the headers are trusted on every request, so a client that
+// reaches the server directly can spoof its IP. Set TrustedProxies to the
+// addresses of your proxies so the headers are only honored on requests from
+// them. X-Forwarded-For is then walked from right to left, skipping trusted
+// hops, and the first untrusted address is the client. This matters for
+// anything keyed on the client IP, such as rate limiting and IP filtering.
+//
 // # Usage
 //
 //	import "github.com/alexferl/zerohttp/middleware/realip"
@@ -15,9 +22,29 @@
 //	    TrustedProxies: []string{"10.0.0.0/8", "172.16.0.0/12"},
 //	}))
 //
-// # Custom Headers
+// # Accessing the Client IP
+//
+// The middleware sets r.RemoteAddr to the client IP and stores the IP in the
+// request context. Retrieve it in handlers without re-running the extractor:
+//
+//	ip := realip.Get(r)
+//
+// # Custom Extractors
+//
+// The same extraction can be shared with middleware that takes an
+// IPExtractor, such as ipfilter:
+//
+//	extractor := realip.TrustedProxiesIPExtractor([]string{"10.0.0.0/8"})
+//	app.Use(ipfilter.New(ipfilter.Config{
+//	    Allow:       []string{"203.0.113.0/24"},
+//	    IPExtractor: extractor,
+//	}))
+//
+// Or extract the IP from a header set by your CDN:
 //
 //	app.Use(realip.New(realip.Config{
-//	    Headers: []string{"X-Forwarded-For", "CF-Connecting-IP"},
+//	    IPExtractor: func(r *http.Request) string {
+//	        return r.Header.Get("CF-Connecting-IP")
+//	    },
 //	}))
 package realip
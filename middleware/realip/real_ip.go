@@ -1,21 +1,30 @@
 package realip
 
 import (
+	"context"
 	"net"
 	"net/http"
 
 	zconfig "github.com/alexferl/zerohttp/internal/config"
 )
 
+// contextKey is the context key type for the client IP.
+type contextKey struct{}
+
 // New creates a real IP middleware with the provided configuration that sets
-// r.RemoteAddr to the extracted real client IP.
+// r.RemoteAddr to the extracted real client IP. The IP is also stored in the
+// request context and can be retrieved with Get.
+// Panics if TrustedProxies contains an invalid IP address or CIDR range.
 func New(cfg ...Config) func(http.Handler) http.Handler {
 	c := DefaultConfig
 	if len(cfg) > 0 {
 		zconfig.Merge(&c, cfg[0])
 	}
 
-	if c.IPExtractor == nil {
+	switch {
+	case len(c.TrustedProxies) > 0 && (len(cfg) == 0 || cfg[0].IPExtractor == nil):
+		c.IPExtractor = TrustedProxiesIPExtractor(c.TrustedProxies)
+	case c.IPExtractor == nil:
 		c.IPExtractor = DefaultConfig.IPExtractor
 	}
 
@@ -29,7 +38,19 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 			} else {
 				r.RemoteAddr = realIP
 			}
-			next.ServeHTTP(w, r)
+
+			ctx := context.WithValue(r.Context(), contextKey{}, realIP)
+			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// Get returns the client IP extracted by the real IP middleware, exactly as
+// computed by its IPExtractor. Returns an empty string if the middleware
+// didn't run for the request.
+func Get(r *http.Request) string {
+	if ip, ok := r.Context().Value(contextKey{}).(string); ok {
+		return ip
+	}
+	return ""
+}
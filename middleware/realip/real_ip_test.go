@@ -75,6 +75,40 @@ func TestRealIPNilExtractor(t *testing.T) {
 	zhtest.TestMiddlewareWithHandler(middleware, next, req)
 }
 
+func TestGet(t *testing.T) {
+	t.Run("returns the extracted IP", func(t *testing.T) {
+		var got string
+		req := zhtest.NewRequest(http.MethodGet, "/test").WithHeader("X-Forwarded-For", "203.0.113.1, 198.51.100.1").Build()
+		req.RemoteAddr = "192.168.1.1:12345"
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = Get(r)
+			w.WriteHeader(http.StatusOK)
+		})
+		zhtest.TestMiddlewareWithHandler(New(), next, req)
+
+		zhtest.AssertEqual(t, "203.0.113.1", got)
+	})
+
+	t.Run("returns the custom extractor's value", func(t *testing.T) {
+		var got string
+		middleware := New(Config{IPExtractor: XRealIPExtractor})
+		req := zhtest.NewRequest(http.MethodGet, "/test").WithHeader("X-Forwarded-For", "203.0.113.1").Build()
+		req.RemoteAddr = "192.168.1.1:12345"
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = Get(r)
+			w.WriteHeader(http.StatusOK)
+		})
+		zhtest.TestMiddlewareWithHandler(middleware, next, req)
+
+		zhtest.AssertEqual(t, "192.168.1.1", got)
+	})
+
+	t.Run("empty without the middleware", func(t *testing.T) {
+		req := zhtest.NewRequest(http.MethodGet, "/test").Build()
+		zhtest.AssertEqual(t, "", Get(req))
+	})
+}
+
 func TestRemoteAddrIPExtractor(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -211,3 +245,45 @@ func TestRealIPEdgeCases(t *testing.T) {
 		zhtest.TestMiddlewareWithHandler(middleware, next, req)
 	})
 }
+
+func TestRealIPTrustedProxies(t *testing.T) {
+	middleware := New(Config{TrustedProxies: []string{"10.0.0.0/8"}})
+
+	tests := []struct {
+		name           string
+		remoteAddr     string
+		expectedRemote string
+	}{
+		{"trusted proxy", "10.0.0.1:12345", "203.0.113.1:12345"},
+		{"untrusted client", "198.51.100.7:12345", "198.51.100.7:12345"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := zhtest.NewRequest(http.MethodGet, "/test").WithHeader("X-Forwarded-For", "203.0.113.1").Build()
+			req.RemoteAddr = tt.remoteAddr
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				zhtest.AssertEqual(t, tt.expectedRemote, r.RemoteAddr)
+				w.WriteHeader(http.StatusOK)
+			})
+			zhtest.TestMiddlewareWithHandler(middleware, next, req)
+		})
+	}
+
+	t.Run("custom extractor takes precedence", func(t *testing.T) {
+		middleware := New(Config{
+			TrustedProxies: []string{"10.0.0.0/8"},
+			IPExtractor:    RemoteAddrIPExtractor,
+		})
+		req := zhtest.NewRequest(http.MethodGet, "/test").WithHeader("X-Forwarded-For", "203.0.113.1").Build()
+		req.RemoteAddr = "10.0.0.1:12345"
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			zhtest.AssertEqual(t, "10.0.0.1:12345", r.RemoteAddr)
+			w.WriteHeader(http.StatusOK)
+		})
+		zhtest.TestMiddlewareWithHandler(middleware, next, req)
+	})
+
+	t.Run("invalid proxy panics", func(t *testing.T) {
+		zhtest.AssertPanic(t, func() { New(Config{TrustedProxies: []string{"10.0.0.0/99"}}) })
+	})
+}
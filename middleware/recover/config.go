@@ -1,6 +1,11 @@
 package recover
 
-import "github.com/alexferl/zerohttp/config"
+import (
+	"net/http"
+	"time"
+
+	"github.com/alexferl/zerohttp/config"
+)
 
 // Config allows customization of panic recovery
 type Config struct {
@@ -17,6 +22,28 @@ type Config struct {
 	// This should match the header configured in RequestIDConfig.
 	// Default: "X-Request-Id"
 	RequestIDHeader string
+
+	// DedupeWindow enables deduplication of repeated panics. The first panic
+	// from a given call stack is logged in full; further panics from the same
+	// stack within the window are counted and logged as a single summary with
+	// a panic_count field when the window ends. Summaries repeat every window
+	// while the panic keeps recurring.
+	// Default: 0 (disabled, every panic is logged in full)
+	DedupeWindow time.Duration
+
+	// DebugErrors includes the panic value and stack trace in the 500
+	// response. Only enable it in development, since it exposes internals.
+	// Default: false
+	DebugErrors bool
+
+	// PanicHandler replaces the default problem detail response, for example
+	// to render an HTML error page to browsers or to report the panic to an
+	// error tracker. It is called with the recovered value and the stack
+	// trace of the panicking goroutine, truncated to StackSize, after the
+	// panic is logged and the request ID response header is set. It is also
+	// called for upgrade requests, which get no default response.
+	// Default: nil
+	PanicHandler func(w http.ResponseWriter, r *http.Request, recovered any, stack []byte)
 }
 
 // DefaultConfig contains the default panic recovery configuration
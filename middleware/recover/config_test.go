@@ -2,6 +2,7 @@ package recover
 
 import (
 	"testing"
+	"time"
 
 	"github.com/alexferl/zerohttp/config"
 	"github.com/alexferl/zerohttp/zhtest"
@@ -11,6 +12,7 @@ func TestRecoverConfig_DefaultValues(t *testing.T) {
 	cfg := DefaultConfig
 	zhtest.AssertEqual(t, int64(4<<10), cfg.StackSize)
 	zhtest.AssertTrue(t, *cfg.EnableStackTrace)
+	zhtest.AssertEqual(t, time.Duration(0), cfg.DedupeWindow)
 
 	// Verify the 4KB calculation
 	expectedSize := int64(4096)
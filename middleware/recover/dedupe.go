@@ -0,0 +1,92 @@
+package recover
+
+import (
+	"hash/fnv"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alexferl/zerohttp/log"
+)
+
+// panicDeduper counts panics by call stack so that repeated panics are
+// summarized instead of logged in full every time.
+type panicDeduper struct {
+	mu      sync.Mutex
+	window  time.Duration
+	logger  log.Logger
+	entries map[uint64]*panicEntry
+}
+
+// panicEntry tracks the panics from one call stack seen during the current window.
+type panicEntry struct {
+	count int
+	last  any
+}
+
+func newPanicDeduper(logger log.Logger, window time.Duration) *panicDeduper {
+	return &panicDeduper{
+		window:  window,
+		logger:  logger,
+		entries: make(map[uint64]*panicEntry),
+	}
+}
+
+// record registers a panic from the stack identified by hash and reports
+// whether it is the first occurrence and should be logged in full.
+func (d *panicDeduper) record(hash uint64, rvr any) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if e, ok := d.entries[hash]; ok {
+		e.count++
+		e.last = rvr
+		return false
+	}
+
+	d.entries[hash] = &panicEntry{}
+	time.AfterFunc(d.window, func() { d.flush(hash) })
+	return true
+}
+
+// flush logs a summary of the panics suppressed during the window that just
+// ended. The entry is kept for another window while the panic recurs, and
+// dropped otherwise so the next occurrence is logged in full again.
+func (d *panicDeduper) flush(hash uint64) {
+	d.mu.Lock()
+	e := d.entries[hash]
+	if e.count == 0 {
+		delete(d.entries, hash)
+		d.mu.Unlock()
+		return
+	}
+	count, last := e.count, e.last
+	e.count, e.last = 0, nil
+	d.mu.Unlock()
+
+	d.logger.Error("Recovered from repeated panic",
+		log.P(last),
+		log.F("panic_count", count),
+		log.F("stack_hash", strconv.FormatUint(hash, 16)),
+	)
+	time.AfterFunc(d.window, func() { d.flush(hash) })
+}
+
+// stackHash hashes the program counters of the panicking goroutine's stack.
+// Unlike the formatted stack trace, the counters don't include goroutine IDs
+// or argument values, so the same panic site always has the same hash.
+func stackHash(skip int) uint64 {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(skip, pcs)
+
+	h := fnv.New64a()
+	var buf [8]byte
+	for _, pc := range pcs[:n] {
+		for i := range buf {
+			buf[i] = byte(pc >> (8 * i))
+		}
+		_, _ = h.Write(buf[:])
+	}
+	return h.Sum64()
+}
@@ -1,26 +1,63 @@
 // Package recover provides panic recovery middleware.
 //
 // Catches panics from handlers and converts them to HTTP 500 errors
-// with optional stack trace logging.
+// with optional stack trace logging. The response is an
+// application/problem+json problem detail (or XML, per the Accept header)
+// with a request_id member, and the request ID is also set as a response
+// header so the failure can be matched with the logged panic.
 //
 // # Usage
 //
 //	import "github.com/alexferl/zerohttp/middleware/recover"
 //
 //	// Use defaults
-//	app.Use(recover.New())
+//	app.Use(recover.New(logger))
 //
-//	// Custom error handler
-//	app.Use(recover.New(recover.Config{
-//	    ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
-//	        log.Printf("Panic: %v\n%s", err, debug.Stack())
+// # Custom Panic Response
+//
+// PanicHandler replaces the default problem detail response. The panic is
+// still logged first, and the handler gets the recovered value and the
+// captured stack trace:
+//
+//	app.Use(recover.New(logger, recover.Config{
+//	    PanicHandler: func(w http.ResponseWriter, r *http.Request, recovered any, stack []byte) {
+//	        sentry.CaptureException(fmt.Errorf("panic: %v\n%s", recovered, stack))
+//	        if strings.Contains(r.Header.Get("Accept"), "text/html") {
+//	            w.WriteHeader(http.StatusInternalServerError)
+//	            _ = errorPage.Execute(w, nil)
+//	            return
+//	        }
 //	        http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 //	    },
 //	}))
 //
 // # Disable Stack Traces
 //
-//	app.Use(recover.New(recover.Config{
-//	    LogStack: false,
+//	app.Use(recover.New(logger, recover.Config{
+//	    EnableStackTrace: config.Bool(false),
+//	}))
+//
+// # Deduplicating Repeated Panics
+//
+// A panic in a hot path can flood the logs with identical stack traces.
+// Set DedupeWindow to log the first panic from a given call stack in full
+// and summarize the repeats:
+//
+//	app.Use(recover.New(logger, recover.Config{
+//	    DedupeWindow: time.Minute,
+//	}))
+//
+// Repeats within the window are counted and logged once when it ends as
+// "Recovered from repeated panic" with a panic_count field. Both the full
+// log and the summaries carry a stack_hash field to correlate them.
+//
+// # Debug Errors
+//
+// In development, set DebugErrors to include the panic value and stack
+// trace in the 500 response. It is set automatically when the server's
+// Config.DebugErrors is enabled. Never enable it in production.
+//
+//	app.Use(recover.New(logger, recover.Config{
+//	    DebugErrors: true,
 //	}))
 package recover
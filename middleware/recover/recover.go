@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
+	"runtime/debug"
+	"strconv"
 	"time"
 
 	"github.com/alexferl/zerohttp/config"
@@ -16,7 +18,8 @@ import (
 
 // New creates a recover middleware with the provided configuration that recovers from panics,
 // logs the panic (and a backtrace), and returns HTTP 500 if possible.
-// It prints a request ID if one is provided.
+// The 500 response is a problem detail carrying the request ID, which is also
+// set as a response header. A request ID is generated if the request has none.
 //
 // Note: Handler errors are handled directly by the router without panic.
 // This middleware only catches actual panics from unexpected errors or explicit panic() calls.
@@ -26,6 +29,11 @@ func New(logger log.Logger, cfg ...Config) func(http.Handler) http.Handler {
 		zconfig.Merge(&c, cfg[0])
 	}
 
+	var deduper *panicDeduper
+	if c.DedupeWindow > 0 {
+		deduper = newPanicDeduper(logger, c.DedupeWindow)
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
@@ -47,16 +55,44 @@ func New(logger log.Logger, cfg ...Config) func(http.Handler) http.Handler {
 						log.F("request_id", reqID),
 					}
 
-					if config.BoolOrDefault(c.EnableStackTrace, true) {
-						stack := make([]byte, c.StackSize)
-						length := runtime.Stack(stack, false)
-						fields = append(fields, log.F("stack", string(stack[:length])))
+					// Repeated panics are counted and summarized by the deduper
+					logFull := true
+					if deduper != nil {
+						hash := stackHash(3)
+						logFull = deduper.record(hash, rvr)
+						fields = append(fields, log.F("stack_hash", strconv.FormatUint(hash, 16)))
 					}
 
-					logger.Error("Recovered from panic", fields...)
+					logStack := logFull && config.BoolOrDefault(c.EnableStackTrace, true)
+					var stack []byte
+					if logStack || c.PanicHandler != nil {
+						stack = make([]byte, c.StackSize)
+						stack = stack[:runtime.Stack(stack, false)]
+					}
+
+					if logFull {
+						if logStack {
+							fields = append(fields, log.F("stack", string(stack)))
+						}
+
+						logger.Error("Recovered from panic", fields...)
+					}
+
+					// The request ID lets clients report the failure so it can be
+					// matched with the log entry
+					w.Header().Set(c.RequestIDHeader, reqID)
+
+					if c.PanicHandler != nil {
+						c.PanicHandler(w, r, rvr, stack)
+						return
+					}
 
 					if r.Header.Get(httpx.HeaderConnection) != httpx.ConnectionUpgrade {
-						detail := problem.NewDetail(http.StatusInternalServerError, "Internal server error")
+						detail := problem.NewDetail(http.StatusInternalServerError, "Internal server error").
+							Set("request_id", reqID)
+						if c.DebugErrors {
+							detail.Set("error", fmt.Sprint(rvr)).Set("stack", string(debug.Stack()))
+						}
 						_ = detail.RenderAuto(w, r)
 					}
 				}
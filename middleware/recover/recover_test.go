@@ -2,11 +2,14 @@ package recover
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/alexferl/zerohttp/config"
 	"github.com/alexferl/zerohttp/httpx"
@@ -16,6 +19,7 @@ import (
 )
 
 type mockLogger struct {
+	mu          sync.Mutex
 	debugLogs   []string
 	infoLogs    []string
 	errorLogs   []string
@@ -26,6 +30,8 @@ func (m *mockLogger) Debug(msg string, fields ...log.Field) { m.debugLogs = appe
 func (m *mockLogger) Info(msg string, fields ...log.Field)  { m.infoLogs = append(m.infoLogs, msg) }
 func (m *mockLogger) Warn(msg string, fields ...log.Field)  {}
 func (m *mockLogger) Error(msg string, fields ...log.Field) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.errorLogs = append(m.errorLogs, msg)
 	m.errorFields = append(m.errorFields, fields)
 }
@@ -377,3 +383,137 @@ func TestRecover_CustomRequestIDHeader(t *testing.T) {
 	}
 	zhtest.AssertFalse(t, foundDefaultID)
 }
+
+func TestRecover_DedupeWindow(t *testing.T) {
+	logger := &mockLogger{}
+	mw := New(logger, Config{DedupeWindow: 100 * time.Millisecond})
+	handler := mw(panicHandler("nil deref"))
+	other := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("other")
+	}))
+
+	for range 5 {
+		w := zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/").Build())
+		zhtest.AssertWith(t, w).Status(http.StatusInternalServerError)
+	}
+	zhtest.Serve(other, zhtest.NewRequest(http.MethodGet, "/").Build())
+
+	// Only the first panic from each stack is logged in full
+	logger.mu.Lock()
+	zhtest.AssertEqual(t, []string{"Recovered from panic", "Recovered from panic"}, logger.errorLogs)
+	keys := map[string]bool{}
+	for _, f := range logger.errorFields[0] {
+		keys[f.Key] = true
+	}
+	zhtest.AssertTrue(t, keys["stack"])
+	zhtest.AssertTrue(t, keys["stack_hash"])
+	logger.mu.Unlock()
+
+	time.Sleep(200 * time.Millisecond)
+
+	// The suppressed panics are summarized once the window ends
+	logger.mu.Lock()
+	zhtest.AssertEqual(t, 3, len(logger.errorLogs))
+	zhtest.AssertEqual(t, "Recovered from repeated panic", logger.errorLogs[2])
+	for _, f := range logger.errorFields[2] {
+		switch f.Key {
+		case "panic":
+			zhtest.AssertEqual(t, "nil deref", f.Value)
+		case "panic_count":
+			zhtest.AssertEqual(t, 4, f.Value)
+		}
+	}
+	logger.mu.Unlock()
+
+	time.Sleep(200 * time.Millisecond)
+
+	// Once the panic stops recurring, the next one is logged in full again
+	zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/").Build())
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	zhtest.AssertEqual(t, 4, len(logger.errorLogs))
+	zhtest.AssertEqual(t, "Recovered from panic", logger.errorLogs[3])
+}
+
+func TestRecover_DebugErrors(t *testing.T) {
+	handler := New(&mockLogger{}, Config{DebugErrors: true})(panicHandler("nil map write"))
+	w := zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/").Build())
+
+	zhtest.AssertWith(t, w).
+		Status(http.StatusInternalServerError).
+		BodyContains(`"error":"nil map write"`).
+		BodyContains(`"stack":"goroutine`)
+
+	// Disabled by default
+	handler = New(&mockLogger{})(panicHandler("nil map write"))
+	w = zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/").Build())
+
+	zhtest.AssertWith(t, w).Status(http.StatusInternalServerError)
+	zhtest.AssertFalse(t, strings.Contains(w.Body.String(), "nil map write"))
+}
+
+func TestRecover_ProblemDetailResponse(t *testing.T) {
+	handler := New(&mockLogger{})(panicHandler("boom"))
+
+	t.Run("with request ID", func(t *testing.T) {
+		req := zhtest.NewRequest(http.MethodGet, "/").WithHeader("X-Request-Id", "req-789").Build()
+		w := zhtest.Serve(handler, req)
+
+		zhtest.AssertWith(t, w).
+			Status(http.StatusInternalServerError).
+			Header(httpx.HeaderContentType, httpx.MIMEApplicationProblemJSON).
+			Header("X-Request-Id", "req-789")
+
+		var body map[string]any
+		zhtest.AssertNoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		zhtest.AssertEqual(t, float64(http.StatusInternalServerError), body["status"])
+		zhtest.AssertEqual(t, "Internal Server Error", body["title"])
+		zhtest.AssertEqual(t, "req-789", body["request_id"])
+	})
+
+	t.Run("generated request ID", func(t *testing.T) {
+		w := zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/").Build())
+
+		reqID := w.Header().Get("X-Request-Id")
+		zhtest.AssertTrue(t, strings.HasPrefix(reqID, "recover-"))
+		zhtest.AssertWith(t, w).BodyContains(`"request_id":"` + reqID + `"`)
+	})
+}
+
+func TestRecover_PanicHandler(t *testing.T) {
+	logger := &mockLogger{}
+	var (
+		gotRecovered any
+		gotStack     []byte
+	)
+	handler := New(logger, Config{
+		PanicHandler: func(w http.ResponseWriter, r *http.Request, recovered any, stack []byte) {
+			gotRecovered = recovered
+			gotStack = stack
+			w.Header().Set(httpx.HeaderContentType, httpx.MIMETextHTMLCharset)
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("<h1>Something went wrong</h1>"))
+		},
+	})(panicHandler("boom"))
+
+	req := zhtest.NewRequest(http.MethodGet, "/").WithHeader("X-Request-Id", "req-1").Build()
+	w := zhtest.Serve(handler, req)
+
+	zhtest.AssertWith(t, w).
+		Status(http.StatusInternalServerError).
+		Header(httpx.HeaderContentType, httpx.MIMETextHTMLCharset).
+		Header("X-Request-Id", "req-1").
+		Body("<h1>Something went wrong</h1>")
+	zhtest.AssertEqual(t, "boom", gotRecovered)
+	zhtest.AssertTrue(t, strings.HasPrefix(string(gotStack), "goroutine"))
+
+	// The panic is still logged with its stack trace
+	zhtest.AssertEqual(t, 1, len(logger.errorLogs))
+	var logged bool
+	for _, field := range logger.errorFields[0] {
+		if field.Key == "stack" {
+			logged = true
+		}
+	}
+	zhtest.AssertTrue(t, logged)
+}
@@ -15,6 +15,24 @@
 //	    MaxBytes: 5 * 1024 * 1024, // 5MB
 //	}))
 //
+// # Per-Route Limits
+//
+// Use the middleware on a route to give it its own limit. The route's limit
+// replaces the global one, so large uploads can be allowed on a single
+// route without raising the limit for the whole app:
+//
+//	app.POST("/upload", uploadHandler, requestbodysize.New(requestbodysize.Config{
+//	    MaxBytes: 32 << 20, // 32MB
+//	}))
+//
+// # Error Responses
+//
+// Reading a body over the limit fails with an *http.MaxBytesError. Handlers
+// returning it from a zh.HandlerFunc, directly or from binding, respond with
+// a 413 problem detail. When a handler responds to it with its own error
+// status instead, such as a 400 with "http: request body too large", the
+// middleware replaces the response with the same 413 problem detail.
+//
 // # Skip Specific Paths
 //
 //	app.Use(requestbodysize.New(requestbodysize.Config{
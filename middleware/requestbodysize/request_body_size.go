@@ -1,14 +1,32 @@
 package requestbodysize
 
 import (
+	"context"
+	"io"
 	"net/http"
+	"sync/atomic"
 
+	"github.com/alexferl/zerohttp/httpx"
 	zconfig "github.com/alexferl/zerohttp/internal/config"
 	"github.com/alexferl/zerohttp/internal/mwutil"
+	"github.com/alexferl/zerohttp/internal/problem"
+	"github.com/alexferl/zerohttp/internal/rwutil"
 	"github.com/alexferl/zerohttp/metrics"
 )
 
-// New creates a request size limiting middleware with the provided configuration
+// New creates a request size limiting middleware with the provided configuration.
+//
+// The middleware can also be used as route middleware to set a different
+// limit for a route. Its limit replaces the limit of the global middleware,
+// so it can be higher:
+//
+//	app.POST("/upload", uploadHandler, requestbodysize.New(requestbodysize.Config{
+//	    MaxBytes: 32 << 20, // 32MB for uploads, 1MB for other routes
+//	}))
+//
+// When the handler fails to read a body over the limit and responds with an
+// error status, the response is replaced by a 413 Payload Too Large problem
+// detail, so the client doesn't get a vague error about the body.
 func New(cfg ...Config) func(http.Handler) http.Handler {
 	c := DefaultConfig
 	if len(cfg) > 0 {
@@ -37,25 +55,112 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 				reg:            reg,
 			}
 
-			r.Body = http.MaxBytesReader(lrw, r.Body, c.MaxBytes)
+			// A limit set by an outer instance, such as the global middleware,
+			// is replaced so that route middleware can raise it. The outer body
+			// is found through the context because middleware in between, such
+			// as the request logger, may have wrapped it.
+			if outer, ok := r.Context().Value(limitBodyKey{}).(*limitBody); ok {
+				outer.limit = c.MaxBytes
+				outer.exceeded = &lrw.exceeded
+				next.ServeHTTP(lrw, r)
+				return
+			}
+
+			body := &limitBody{
+				orig:     r.Body,
+				limit:    c.MaxBytes,
+				exceeded: &lrw.exceeded,
+			}
+			r.Body = body
+			r = r.WithContext(context.WithValue(r.Context(), limitBodyKey{}, body))
 			next.ServeHTTP(lrw, r)
 		})
 	}
 }
 
+// limitBodyKey is the context key for the limitBody of the outermost instance.
+type limitBodyKey struct{}
+
+// limitBody limits the request body to limit bytes and records when reading
+// fails because the body is over it. Unlike http.MaxBytesReader, the limit
+// can be raised after it was reached without losing bytes.
+type limitBody struct {
+	orig     io.ReadCloser
+	limit    int64
+	n        int64  // bytes read so far
+	over     []byte // read past the limit, returned first if it is raised
+	exceeded *atomic.Bool
+}
+
+func (b *limitBody) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if b.n >= b.limit {
+		// Probe for one more byte to tell a body of exactly limit bytes
+		// from one that is over it
+		if len(b.over) == 0 {
+			var one [1]byte
+			n, err := b.orig.Read(one[:])
+			if n == 0 {
+				return 0, err
+			}
+			b.over = one[:n]
+		}
+		b.exceeded.Store(true)
+		return 0, &http.MaxBytesError{Limit: b.limit}
+	}
+
+	p = p[:min(int64(len(p)), b.limit-b.n)]
+	if len(b.over) > 0 {
+		n := copy(p, b.over)
+		b.over = b.over[n:]
+		b.n += int64(n)
+		return n, nil
+	}
+
+	n, err := b.orig.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+func (b *limitBody) Close() error {
+	return b.orig.Close()
+}
+
 // limitResponseWriter wraps ResponseWriter to detect when MaxBytesReader triggers a 413
 type limitResponseWriter struct {
 	http.ResponseWriter
-	reg   metrics.Registry
-	wrote bool
+	reg      metrics.Registry
+	wrote    bool
+	exceeded atomic.Bool
+	discard  bool
 }
 
 func (lrw *limitResponseWriter) WriteHeader(code int) {
-	if !lrw.wrote {
-		lrw.wrote = true
-		if code == http.StatusRequestEntityTooLarge {
-			lrw.reg.Counter("request_body_size_rejected_total").Inc()
+	if lrw.wrote || rwutil.IsInformational(code) {
+		if !lrw.discard {
+			lrw.ResponseWriter.WriteHeader(code)
 		}
+		return
+	}
+	lrw.wrote = true
+
+	// The handler failed on the body over the limit with its own error,
+	// respond with 413 instead and drop the handler's error body
+	if lrw.exceeded.Load() && code >= http.StatusBadRequest && code != http.StatusRequestEntityTooLarge {
+		lrw.discard = true
+		lrw.reg.Counter("request_body_size_rejected_total").Inc()
+		lrw.Header().Del(httpx.HeaderContentLength)
+		pd := problem.NewDetail(http.StatusRequestEntityTooLarge, "Request body exceeds maximum allowed size")
+		pd.Title = "Payload Too Large"
+		_ = pd.Render(lrw.ResponseWriter)
+		return
+	}
+
+	if code == http.StatusRequestEntityTooLarge {
+		lrw.reg.Counter("request_body_size_rejected_total").Inc()
 	}
 	lrw.ResponseWriter.WriteHeader(code)
 }
@@ -64,6 +169,9 @@ func (lrw *limitResponseWriter) Write(p []byte) (int, error) {
 	if !lrw.wrote {
 		lrw.WriteHeader(http.StatusOK)
 	}
+	if lrw.discard {
+		return len(p), nil
+	}
 	return lrw.ResponseWriter.Write(p)
 }
 
@@ -10,7 +10,9 @@ import (
 
 	"github.com/alexferl/zerohttp/config"
 	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/log"
 	"github.com/alexferl/zerohttp/metrics"
+	"github.com/alexferl/zerohttp/middleware/requestlogger"
 	"github.com/alexferl/zerohttp/zhtest"
 )
 
@@ -352,3 +354,112 @@ func TestRequestBodySize_BothExcludedAndIncludedPathsPanics(t *testing.T) {
 		IncludedPaths: []string{"/api"},
 	})
 }
+
+func TestRequestBodySize_ProblemDetail(t *testing.T) {
+	body := strings.Repeat("a", 100)
+
+	t.Run("handler error is replaced with 413", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, err := io.ReadAll(r.Body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		})
+		req := zhtest.NewRequest(http.MethodPost, "/").WithBody(strings.NewReader(body)).Build()
+		w := zhtest.Serve(New(Config{MaxBytes: 10})(handler), req)
+
+		zhtest.AssertWith(t, w).
+			Status(http.StatusRequestEntityTooLarge).
+			Header(httpx.HeaderContentType, httpx.MIMEApplicationProblemJSON).
+			BodyContains(`"title":"Payload Too Large"`).
+			BodyContains(`"status":413`)
+		zhtest.AssertFalse(t, strings.Contains(w.Body.String(), "http: request body too large"))
+	})
+
+	t.Run("handler 413 is kept", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.ReadAll(r.Body)
+			http.Error(w, "custom", http.StatusRequestEntityTooLarge)
+		})
+		req := zhtest.NewRequest(http.MethodPost, "/").WithBody(strings.NewReader(body)).Build()
+		w := zhtest.Serve(New(Config{MaxBytes: 10})(handler), req)
+
+		zhtest.AssertWith(t, w).
+			Status(http.StatusRequestEntityTooLarge).
+			BodyContains("custom")
+	})
+
+	t.Run("other errors are kept", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.ReadAll(r.Body)
+			http.Error(w, "invalid", http.StatusBadRequest)
+		})
+		req := zhtest.NewRequest(http.MethodPost, "/").WithBody(strings.NewReader("short")).Build()
+		w := zhtest.Serve(New(Config{MaxBytes: 10})(handler), req)
+
+		zhtest.AssertWith(t, w).
+			Status(http.StatusBadRequest).
+			BodyContains("invalid")
+	})
+}
+
+func TestRequestBodySize_RouteOverride(t *testing.T) {
+	body := strings.Repeat("a", 50)
+
+	tests := []struct {
+		name        string
+		outer       int64
+		inner       int64
+		expectError bool
+	}{
+		{"raises the global limit", 10, 100, false},
+		{"lowers the global limit", 100, 10, true},
+		{"same limit", 50, 50, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &requestBodySizeTestHandler{}
+			chain := New(Config{MaxBytes: tt.outer})(New(Config{MaxBytes: tt.inner})(handler))
+			req := zhtest.NewRequest(http.MethodPost, "/").WithBody(strings.NewReader(body)).Build()
+			zhtest.Serve(chain, req)
+
+			zhtest.AssertTrue(t, handler.called)
+			zhtest.AssertEqual(t, tt.expectError, handler.bodyError != nil)
+			if !tt.expectError {
+				zhtest.AssertEqual(t, body, string(handler.bodyRead))
+			}
+		})
+	}
+
+	t.Run("bytes read before the override count against it", func(t *testing.T) {
+		handler := &requestBodySizeTestHandler{}
+		consume := func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				buf := make([]byte, 40)
+				_, _ = io.ReadFull(r.Body, buf)
+				next.ServeHTTP(w, r)
+			})
+		}
+		chain := New(Config{MaxBytes: 100})(consume(New(Config{MaxBytes: 45})(handler)))
+		req := zhtest.NewRequest(http.MethodPost, "/").WithBody(strings.NewReader(body)).Build()
+		zhtest.Serve(chain, req)
+
+		zhtest.AssertError(t, handler.bodyError)
+	})
+
+	t.Run("request logger in between", func(t *testing.T) {
+		handler := &requestBodySizeTestHandler{}
+		logger := requestlogger.New(&log.NoopLogger{}, requestlogger.Config{LogRequestBody: true})
+		chain := New(Config{MaxBytes: 10})(logger(New(Config{MaxBytes: 100})(handler)))
+		req := zhtest.NewRequest(http.MethodPost, "/").
+			WithHeader(httpx.HeaderContentType, "text/plain").
+			WithBody(strings.NewReader(body)).
+			Build()
+		zhtest.Serve(chain, req)
+
+		zhtest.AssertNoError(t, handler.bodyError)
+		zhtest.AssertEqual(t, body, string(handler.bodyRead))
+	})
+}
@@ -0,0 +1,31 @@
+package requestdump
+
+import "github.com/alexferl/zerohttp/middleware/requestlogger"
+
+// Config allows customization of request dumping.
+type Config struct {
+	// MaxBodySize is the maximum number of bytes of the request body to dump.
+	// Longer bodies are truncated in the dump, not for the handler, which
+	// still reads the whole body. A negative value leaves the body out.
+	// Default: 4096 (4KB)
+	MaxBodySize int
+
+	// RedactHeaders contains header names (case-insensitive) whose values
+	// are dumped as "***".
+	// Default: requestlogger.DefaultRedactHeaders
+	RedactHeaders []string
+
+	// IncludedPaths contains the paths to dump requests for, so only the
+	// routes being debugged are dumped. Supports exact matches, prefixes
+	// (ending with /), and wildcards (ending with *).
+	// If empty, requests to all paths are dumped.
+	// Default: []
+	IncludedPaths []string
+}
+
+// DefaultConfig contains the default values for request dumping.
+var DefaultConfig = Config{
+	MaxBodySize:   4096,
+	RedactHeaders: requestlogger.DefaultRedactHeaders,
+	IncludedPaths: []string{},
+}
@@ -0,0 +1,15 @@
+package requestdump
+
+import (
+	"testing"
+
+	"github.com/alexferl/zerohttp/middleware/requestlogger"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestRequestDumpConfig_DefaultValues(t *testing.T) {
+	cfg := DefaultConfig
+	zhtest.AssertEqual(t, 4096, cfg.MaxBodySize)
+	zhtest.AssertEqual(t, requestlogger.DefaultRedactHeaders, cfg.RedactHeaders)
+	zhtest.AssertEqual(t, 0, len(cfg.IncludedPaths))
+}
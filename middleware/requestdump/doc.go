@@ -0,0 +1,43 @@
+// Package requestdump provides request dumping middleware for debugging.
+//
+// Logs each request as sent on the wire, with its request line, headers and
+// body, at Debug level, to debug clients sending unexpected requests. The
+// body is put back after being read, so handlers still read it whole.
+//
+// # Usage
+//
+//	import "github.com/alexferl/zerohttp/middleware/requestdump"
+//
+//	// Dump every request
+//	app.Use(requestdump.New(logger))
+//
+//	// Dump webhook requests only, with more of their body
+//	app.Use(requestdump.New(logger, requestdump.Config{
+//	    IncludedPaths: []string{"/webhooks/"},
+//	    MaxBodySize:   64 << 10,
+//	}))
+//
+// Dumps are logged with the "Request dump" message and a "dump" field:
+//
+//	POST /webhooks/stripe HTTP/1.1
+//	Host: api.example.com
+//	Authorization: ***
+//	Content-Type: application/json
+//
+//	{"type":"invoice.paid",...}
+//
+// # Log Level
+//
+// With a logger that reports its level, such as log.DefaultLogger, requests
+// are only dumped while the level is Debug. At any other level the middleware
+// passes requests through untouched, so it can stay registered in production:
+//
+//	logger := log.NewDefaultLogger()
+//	logger.SetLevel(log.InfoLevel) // No dumps
+//
+// # Redaction
+//
+// The values of RedactHeaders, by default those of
+// requestlogger.DefaultRedactHeaders, are dumped as "***", so credentials
+// such as bearer tokens and session cookies never reach the logs.
+package requestdump
@@ -0,0 +1,89 @@
+package requestdump
+
+import (
+	"net/http"
+	"net/http/httputil"
+
+	zconfig "github.com/alexferl/zerohttp/internal/config"
+	"github.com/alexferl/zerohttp/internal/mwutil"
+	"github.com/alexferl/zerohttp/log"
+	"github.com/alexferl/zerohttp/middleware/requestid"
+	"github.com/alexferl/zerohttp/middleware/requestlogger"
+)
+
+// leveledLogger is implemented by loggers that report their level, such as
+// log.DefaultLogger.
+type leveledLogger interface {
+	GetLevel() log.LogLevel
+}
+
+// New creates a request dump middleware with the provided configuration.
+// It logs each request, as sent on the wire, at Debug level with a "dump"
+// field. With a logger that reports its level, such as log.DefaultLogger,
+// requests are only dumped while the level is Debug, so the middleware costs
+// nothing in production.
+func New(logger log.Logger, cfg ...Config) func(http.Handler) http.Handler {
+	c := DefaultConfig
+	if len(cfg) > 0 {
+		zconfig.Merge(&c, cfg[0])
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if l, ok := logger.(leveledLogger); ok && l.GetLevel() > log.DebugLevel {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(c.IncludedPaths) > 0 && !pathIncluded(r.URL.Path, c.IncludedPaths) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			fields := []log.Field{log.F("dump", dumpRequest(r, c))}
+			if requestID := requestid.FromRequest(r); requestID != "" {
+				fields = append(fields, log.F("request_id", requestID))
+			}
+			logger.Debug("Request dump", fields...)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// pathIncluded reports whether path matches one of the included paths.
+func pathIncluded(path string, included []string) bool {
+	for _, p := range included {
+		if mwutil.PathMatches(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// dumpRequest returns the request line, headers and body of r, with the
+// headers of c.RedactHeaders redacted and the body truncated to
+// c.MaxBodySize. The body read is put back so the handler reads it whole.
+func dumpRequest(r *http.Request, c Config) string {
+	// Dump a shallow copy, so redacting doesn't change the request headers
+	dump := *r
+	dump.Header = requestlogger.RedactHeaders(r.Header, c.RedactHeaders)
+
+	head, err := httputil.DumpRequest(&dump, false)
+	if err != nil {
+		return err.Error()
+	}
+
+	if c.MaxBodySize < 0 || r.Body == nil || r.Body == http.NoBody {
+		return string(head)
+	}
+
+	body, err := mwutil.PeekBody(r, int64(c.MaxBodySize)+1)
+	if err != nil {
+		return string(head)
+	}
+	if len(body) > c.MaxBodySize {
+		return string(head) + string(body[:c.MaxBodySize]) + "..."
+	}
+	return string(head) + string(body)
+}
@@ -0,0 +1,149 @@
+package requestdump
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/log"
+	"github.com/alexferl/zerohttp/middleware/requestid"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+type logEntry struct {
+	message string
+	fields  []log.Field
+}
+
+type dumpMockLogger struct {
+	log.NoopLogger
+	debugLogs []logEntry
+}
+
+func (m *dumpMockLogger) Debug(msg string, fields ...log.Field) {
+	m.debugLogs = append(m.debugLogs, logEntry{msg, fields})
+}
+
+func (m *dumpMockLogger) WithFields(...log.Field) log.Logger     { return m }
+func (m *dumpMockLogger) WithContext(context.Context) log.Logger { return m }
+
+// dump returns the dump logged by the i-th entry.
+func (m *dumpMockLogger) dump(t *testing.T, i int) string {
+	t.Helper()
+	return fieldValue(t, m.debugLogs[i], "dump")
+}
+
+func fieldValue(t *testing.T, entry logEntry, key string) string {
+	t.Helper()
+	for _, f := range entry.fields {
+		if f.Key == key {
+			return f.Value.(string)
+		}
+	}
+	t.Fatalf("field %q not found", key)
+	return ""
+}
+
+// echoHandler writes back the request body it reads.
+var echoHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	_, _ = w.Write(body)
+})
+
+func TestRequestDump(t *testing.T) {
+	logger := &dumpMockLogger{}
+	req := zhtest.NewRequest(http.MethodPost, "/users?active=true").
+		WithHeader(httpx.HeaderContentType, httpx.MIMEApplicationJSON).
+		WithHeader(httpx.HeaderAuthorization, "Bearer secret-token").
+		WithBody(strings.NewReader(`{"name":"alice"}`)).
+		Build()
+
+	w := zhtest.TestMiddlewareWithHandler(New(logger), echoHandler, req)
+
+	// The handler still reads the whole body
+	zhtest.AssertWith(t, w).Status(http.StatusOK).Body(`{"name":"alice"}`)
+
+	zhtest.AssertEqual(t, 1, len(logger.debugLogs))
+	zhtest.AssertEqual(t, "Request dump", logger.debugLogs[0].message)
+	dump := logger.dump(t, 0)
+	zhtest.AssertTrue(t, strings.HasPrefix(dump, "POST /users?active=true HTTP/1.1\r\n"))
+	zhtest.AssertTrue(t, strings.Contains(dump, "Content-Type: application/json\r\n"))
+	zhtest.AssertTrue(t, strings.Contains(dump, "Authorization: ***\r\n"))
+	zhtest.AssertFalse(t, strings.Contains(dump, "secret-token"))
+	zhtest.AssertTrue(t, strings.HasSuffix(dump, "\r\n\r\n"+`{"name":"alice"}`))
+
+	// The request headers are not modified
+	zhtest.AssertEqual(t, "Bearer secret-token", req.Header.Get(httpx.HeaderAuthorization))
+}
+
+func TestRequestDump_MaxBodySize(t *testing.T) {
+	body := strings.Repeat("a", 100)
+
+	t.Run("truncated", func(t *testing.T) {
+		logger := &dumpMockLogger{}
+		req := zhtest.NewRequest(http.MethodPost, "/").WithBody(strings.NewReader(body)).Build()
+		w := zhtest.TestMiddlewareWithHandler(New(logger, Config{MaxBodySize: 10}), echoHandler, req)
+
+		zhtest.AssertWith(t, w).Body(body)
+		zhtest.AssertTrue(t, strings.HasSuffix(logger.dump(t, 0), "\r\n\r\n"+strings.Repeat("a", 10)+"..."))
+	})
+
+	t.Run("body left out", func(t *testing.T) {
+		logger := &dumpMockLogger{}
+		req := zhtest.NewRequest(http.MethodPost, "/").WithBody(strings.NewReader(body)).Build()
+		w := zhtest.TestMiddlewareWithHandler(New(logger, Config{MaxBodySize: -1}), echoHandler, req)
+
+		zhtest.AssertWith(t, w).Body(body)
+		zhtest.AssertFalse(t, strings.Contains(logger.dump(t, 0), "aaa"))
+	})
+}
+
+func TestRequestDump_RedactHeaders(t *testing.T) {
+	logger := &dumpMockLogger{}
+	req := zhtest.NewRequest(http.MethodGet, "/").
+		WithHeader("X-API-Key", "key-123").
+		WithHeader(httpx.HeaderAuthorization, "Bearer token").
+		Build()
+	zhtest.TestMiddlewareWithHandler(New(logger, Config{RedactHeaders: []string{"x-api-key"}}), echoHandler, req)
+
+	dump := logger.dump(t, 0)
+	zhtest.AssertTrue(t, strings.Contains(dump, "X-Api-Key: ***\r\n"))
+	zhtest.AssertTrue(t, strings.Contains(dump, "Authorization: Bearer token\r\n"))
+}
+
+func TestRequestDump_IncludedPaths(t *testing.T) {
+	logger := &dumpMockLogger{}
+	mw := New(logger, Config{IncludedPaths: []string{"/webhooks/", "/debug"}})
+
+	for _, p := range []string{"/webhooks/stripe", "/debug", "/users", "/debug/other"} {
+		zhtest.TestMiddlewareWithHandler(mw, echoHandler, zhtest.NewRequest(http.MethodGet, p).Build())
+	}
+
+	zhtest.AssertEqual(t, 2, len(logger.debugLogs))
+	zhtest.AssertTrue(t, strings.HasPrefix(logger.dump(t, 0), "GET /webhooks/stripe "))
+	zhtest.AssertTrue(t, strings.HasPrefix(logger.dump(t, 1), "GET /debug "))
+}
+
+func TestRequestDump_LogLevel(t *testing.T) {
+	logger := log.NewDefaultLogger()
+	logger.SetLevel(log.InfoLevel)
+
+	req := zhtest.NewRequest(http.MethodPost, "/").WithBody(strings.NewReader("payload")).Build()
+	body := req.Body
+	w := zhtest.TestMiddlewareWithHandler(New(logger), echoHandler, req)
+
+	// Above Debug level, the request is passed through untouched
+	zhtest.AssertWith(t, w).Body("payload")
+	zhtest.AssertEqual(t, body, req.Body)
+}
+
+func TestRequestDump_RequestID(t *testing.T) {
+	logger := &dumpMockLogger{}
+	handler := requestid.New()(New(logger)(echoHandler))
+	zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/").WithHeader(httpx.HeaderXRequestId, "req-1").Build())
+
+	zhtest.AssertEqual(t, "req-1", fieldValue(t, logger.debugLogs[0], "request_id"))
+}
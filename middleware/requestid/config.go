@@ -2,7 +2,10 @@ package requestid
 
 import (
 	"crypto/rand"
+	"encoding/binary"
 	"encoding/hex"
+	"sync"
+	"time"
 )
 
 // contextKey is the context key type for request ID.
@@ -19,12 +22,19 @@ type Config struct {
 
 	// Generator is a custom function to generate request IDs.
 	// The default generator uses crypto/rand (CSPRNG) for 128 bits of entropy.
+	// Use GenerateUUIDv7 for time-ordered IDs in the standard UUID format.
 	// Default: GenerateRequestID
 	Generator func() string
 
 	// ContextKey is the key to store the request ID in context.
 	// Default: package-provided ContextKey
 	ContextKey any
+
+	// FromTraceparent reuses the trace ID of a W3C traceparent header as the
+	// request ID when the request has no Header, so logs correlate with
+	// traces across services. Invalid traceparent headers are ignored.
+	// Default: false
+	FromTraceparent bool
 }
 
 // DefaultConfig contains the default configuration for request ID generation.
@@ -41,3 +51,55 @@ func GenerateRequestID() string {
 	_, _ = rand.Read(bytes)
 	return hex.EncodeToString(bytes)
 }
+
+// uuidV7State is the last timestamp and counter used by GenerateUUIDv7.
+var uuidV7State struct {
+	mu      sync.Mutex
+	lastMs  int64
+	counter uint16
+}
+
+// GenerateUUIDv7 creates a UUID version 7 (RFC 9562), such as
+// "01920c5e-8f3a-7b21-9c4d-5e6f7a8b9c0d", for request IDs that sort by
+// creation time, e.g. when they double as database keys. The first 48 bits
+// are the Unix time in milliseconds and a 12-bit counter orders IDs created
+// within the same millisecond, so IDs are strictly increasing within the
+// process, even across goroutines. The remaining 62 bits are random.
+func GenerateUUIDv7() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+
+	uuidV7State.mu.Lock()
+	ms := time.Now().UnixMilli()
+	if ms > uuidV7State.lastMs {
+		// Start the counter at a random value in its lower half,
+		// leaving room to increment it
+		uuidV7State.lastMs = ms
+		uuidV7State.counter = binary.BigEndian.Uint16(b[6:8]) & 0x7ff
+	} else if uuidV7State.counter++; uuidV7State.counter > 0xfff {
+		// The counter overflowed, or the clock went backwards:
+		// borrow the next millisecond to stay monotonic
+		uuidV7State.lastMs++
+		uuidV7State.counter = 0
+	}
+	ms, counter := uuidV7State.lastMs, uuidV7State.counter
+	uuidV7State.mu.Unlock()
+
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(ms))
+	copy(b[:6], ts[2:])
+	binary.BigEndian.PutUint16(b[6:8], 0x7000|counter) // Version 7
+	b[8] = b[8]&0x3f | 0x80                            // RFC 9562 variant
+
+	var dst [36]byte
+	hex.Encode(dst[0:8], b[0:4])
+	dst[8] = '-'
+	hex.Encode(dst[9:13], b[4:6])
+	dst[13] = '-'
+	hex.Encode(dst[14:18], b[6:8])
+	dst[18] = '-'
+	hex.Encode(dst[19:23], b[8:10])
+	dst[23] = '-'
+	hex.Encode(dst[24:], b[10:])
+	return string(dst[:])
+}
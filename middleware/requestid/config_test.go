@@ -2,6 +2,8 @@ package requestid
 
 import (
 	"regexp"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +15,7 @@ func TestRequestIDConfig_DefaultValues(t *testing.T) {
 	zhtest.AssertEqual(t, "X-Request-Id", cfg.Header)
 	zhtest.AssertNotNil(t, cfg.Generator)
 	zhtest.AssertEqual(t, ContextKey, cfg.ContextKey)
+	zhtest.AssertFalse(t, cfg.FromTraceparent)
 
 	// Test context key type
 	_, ok := cfg.ContextKey.(contextKey)
@@ -79,6 +82,64 @@ func TestGenerateRequestID(t *testing.T) {
 	})
 }
 
+func TestGenerateUUIDv7(t *testing.T) {
+	t.Run("format validation", func(t *testing.T) {
+		uuidPattern := regexp.MustCompile("^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$")
+		for range 10 {
+			id := GenerateUUIDv7()
+			zhtest.AssertTrue(t, uuidPattern.MatchString(id))
+		}
+	})
+
+	t.Run("timestamp", func(t *testing.T) {
+		before := time.Now().UnixMilli()
+		id := GenerateUUIDv7()
+
+		ms, err := strconv.ParseInt(strings.ReplaceAll(id[:13], "-", ""), 16, 64)
+		zhtest.AssertNoError(t, err)
+		zhtest.AssertTrue(t, ms >= before)
+		zhtest.AssertTrue(t, ms <= time.Now().UnixMilli()+1)
+	})
+
+	t.Run("monotonic", func(t *testing.T) {
+		prev := GenerateUUIDv7()
+		for range 10000 {
+			id := GenerateUUIDv7()
+			zhtest.AssertTrue(t, id > prev)
+			prev = id
+		}
+	})
+
+	t.Run("concurrent generation", func(t *testing.T) {
+		const numGoroutines = 10
+		const numIterations = 100
+		ids := make(chan string, numGoroutines*numIterations)
+
+		for range numGoroutines {
+			go func() {
+				prev := ""
+				for range numIterations {
+					id := GenerateUUIDv7()
+					if id <= prev {
+						ids <- "out of order"
+						continue
+					}
+					prev = id
+					ids <- id
+				}
+			}()
+		}
+
+		uniqueIDs := make(map[string]bool)
+		for range numGoroutines * numIterations {
+			id := <-ids
+			zhtest.AssertFalse(t, uniqueIDs[id])
+			uniqueIDs[id] = true
+		}
+		zhtest.AssertEqual(t, numGoroutines*numIterations, len(uniqueIDs))
+	})
+}
+
 func TestRequestIDConfig_StructAssignment(t *testing.T) {
 	t.Run("header assignment", func(t *testing.T) {
 		cfg := Config{
@@ -7,20 +7,60 @@
 //
 //	import "github.com/alexferl/zerohttp/middleware/requestid"
 //
-//	// Use defaults (generates UUID)
+//	// Use defaults (generates a random 128-bit hex ID)
 //	app.Use(requestid.New())
 //
 //	// Custom configuration
 //	app.Use(requestid.New(requestid.Config{
-//	    HeaderName:     "X-Request-ID",
-//	    Generator:      func() string { return myCustomID() },
-//	    AllowFromHeader: true, // Accept client-provided IDs
+//	    Header:    "X-Correlation-Id",
+//	    Generator: func() string { return myCustomID() },
+//	}))
+//
+// An ID sent by the client in the header is reused.
+//
+// # Trace Context
+//
+// Behind a service mesh or gateway that propagates W3C trace context, set
+// FromTraceparent to use the trace ID of the traceparent header as the request
+// ID when the request ID header is absent:
+//
+//	app.Use(requestid.New(requestid.Config{
+//	    FromTraceparent: true,
+//	}))
+//
+//	// traceparent: 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+//	// X-Request-Id: 4bf92f3577b34da6a3ce929d0e0e4736
+//
+// # Time-Ordered IDs
+//
+// Use GenerateUUIDv7 for RFC 9562 UUID v7 IDs, which sort by creation time
+// and index well as database keys:
+//
+//	app.Use(requestid.New(requestid.Config{
+//	    Generator: requestid.GenerateUUIDv7,
 //	}))
 //
 // # Accessing the Request ID
 //
 // Retrieve the ID in handlers:
 //
-//	id := requestid.Get(r)
+//	id := requestid.FromRequest(r)
 //	log.Printf("Request ID: %s", id)
+//
+// Or from a context, such as in code called by the handler:
+//
+//	id := requestid.Get(ctx)
+//
+// Both take the context key as an optional argument when a custom
+// Config.ContextKey is used:
+//
+//	id := requestid.FromRequest(r, myKey)
+//
+// # Ordering
+//
+// Register the middleware before any middleware that reads the request ID.
+// The default middlewares run it first, so the recover middleware and the
+// request logger see the same ID as the handler and the response header.
+// The ID is always stored under the package ContextKey as well, so Get and
+// FromRequest without a key work even with a custom ContextKey.
 package requestid
@@ -3,11 +3,17 @@ package requestid
 import (
 	"context"
 	"net/http"
+	"strings"
 
+	"github.com/alexferl/zerohttp/httpx"
 	zconfig "github.com/alexferl/zerohttp/internal/config"
 )
 
-// New creates a request ID middleware with the provided configuration
+// New creates a request ID middleware with the provided configuration.
+// The request ID is stored in the request context under Config.ContextKey
+// and, when a custom key is used, under the package ContextKey too, so
+// middleware running after it, such as the request logger, can always read
+// it with Get or FromRequest.
 func New(cfg ...Config) func(http.Handler) http.Handler {
 	c := DefaultConfig
 	if len(cfg) > 0 {
@@ -18,6 +24,13 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			requestID := r.Header.Get(c.Header)
 
+			if requestID == "" && c.FromTraceparent {
+				requestID = traceIDFromTraceparent(r.Header.Get(httpx.HeaderTraceparent))
+				if requestID != "" {
+					r.Header.Set(c.Header, requestID)
+				}
+			}
+
 			if requestID == "" {
 				requestID = c.Generator()
 				r.Header.Set(c.Header, requestID)
@@ -26,6 +39,9 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 			w.Header().Set(c.Header, requestID)
 
 			ctx := context.WithValue(r.Context(), c.ContextKey, requestID)
+			if c.ContextKey != ContextKey {
+				ctx = context.WithValue(ctx, ContextKey, requestID)
+			}
 			r = r.WithContext(ctx)
 
 			next.ServeHTTP(w, r)
@@ -33,6 +49,40 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 	}
 }
 
+// traceIDFromTraceparent returns the trace ID of a W3C traceparent header,
+// formatted as version-traceid-parentid-flags, or "" if it isn't valid.
+// Headers of future versions may have more fields after the flags.
+func traceIDFromTraceparent(v string) string {
+	if len(v) < 55 || (len(v) > 55 && v[55] != '-') {
+		return ""
+	}
+	if v[2] != '-' || v[35] != '-' || v[52] != '-' {
+		return ""
+	}
+
+	version, traceID, parentID, flags := v[:2], v[3:35], v[36:52], v[53:55]
+	if !isLowerHex(version) || version == "ff" || (version == "00" && len(v) != 55) {
+		return ""
+	}
+	if !isLowerHex(traceID) || !isLowerHex(parentID) || !isLowerHex(flags) {
+		return ""
+	}
+	if strings.Trim(traceID, "0") == "" || strings.Trim(parentID, "0") == "" {
+		return ""
+	}
+	return traceID
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
 // Get retrieves the request ID from context using the specified key
 // If no key is provided, uses the default key
 func Get(ctx context.Context, key ...any) string {
@@ -48,3 +98,14 @@ func Get(ctx context.Context, key ...any) string {
 	}
 	return ""
 }
+
+// FromRequest retrieves the request ID of r from its context using the
+// specified key, like Get. If the context has no request ID, such as in
+// middleware running before the request ID middleware, it falls back to the
+// default request ID header, which the middleware sets on the request.
+func FromRequest(r *http.Request, key ...any) string {
+	if requestID := Get(r.Context(), key...); requestID != "" {
+		return requestID
+	}
+	return r.Header.Get(httpx.HeaderXRequestId)
+}
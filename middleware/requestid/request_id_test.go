@@ -78,8 +78,8 @@ func TestRequestID_CustomContextKey(t *testing.T) {
 	zhtest.AssertWith(t, w).Status(http.StatusOK)
 	customRequestID := Get(handler.context, customKey)
 	zhtest.AssertNotEmpty(t, customRequestID)
-	zhtest.AssertEmpty(t, Get(handler.context))
-	zhtest.AssertEmpty(t, handler.requestID)
+	// The ID is also stored under the package key for downstream middleware
+	zhtest.AssertEqual(t, customRequestID, handler.requestID)
 }
 
 func TestRequestID_EmptyConfigValues(t *testing.T) {
@@ -127,6 +127,36 @@ func TestGetRequestID_EdgeCases(t *testing.T) {
 	})
 }
 
+func TestFromRequest(t *testing.T) {
+	t.Run("from context", func(t *testing.T) {
+		handler := &testHandler{}
+		req := zhtest.NewRequest(http.MethodGet, "/").Build()
+		zhtest.TestMiddlewareWithHandler(New(), handler, req)
+
+		zhtest.AssertNotEmpty(t, handler.requestID)
+		zhtest.AssertEqual(t, handler.requestID, FromRequest(handler.request))
+	})
+
+	t.Run("from context with custom key", func(t *testing.T) {
+		type traceKey struct{}
+		handler := &testHandler{}
+		req := zhtest.NewRequest(http.MethodGet, "/").Build()
+		zhtest.TestMiddlewareWithHandler(New(Config{Header: "X-Trace-Id", ContextKey: traceKey{}}), handler, req)
+
+		zhtest.AssertEqual(t, handler.request.Header.Get("X-Trace-Id"), FromRequest(handler.request, traceKey{}))
+	})
+
+	t.Run("falls back to header outside the middleware", func(t *testing.T) {
+		req := zhtest.NewRequest(http.MethodGet, "/").WithHeader(httpx.HeaderXRequestId, "abc").Build()
+		zhtest.AssertEqual(t, "abc", FromRequest(req))
+	})
+
+	t.Run("no request ID", func(t *testing.T) {
+		req := zhtest.NewRequest(http.MethodGet, "/").Build()
+		zhtest.AssertEmpty(t, FromRequest(req))
+	})
+}
+
 func TestDefaultRequestIDConfig(t *testing.T) {
 	cfg := DefaultConfig
 	zhtest.AssertEqual(t, httpx.HeaderXRequestId, cfg.Header)
@@ -177,6 +207,74 @@ func TestRequestID_CaseInsensitiveHeader(t *testing.T) {
 	zhtest.AssertEqual(t, existingID, handler.requestID)
 }
 
+func TestRequestID_FromTraceparent(t *testing.T) {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	t.Run("uses trace ID", func(t *testing.T) {
+		handler := &testHandler{}
+		req := zhtest.NewRequest(http.MethodGet, "/").WithHeader(httpx.HeaderTraceparent, traceparent).Build()
+		w := zhtest.TestMiddlewareWithHandler(New(Config{FromTraceparent: true}), handler, req)
+
+		zhtest.AssertWith(t, w).Status(http.StatusOK).Header(httpx.HeaderXRequestId, traceID)
+		zhtest.AssertEqual(t, traceID, handler.requestID)
+		zhtest.AssertEqual(t, traceID, handler.request.Header.Get(httpx.HeaderXRequestId))
+	})
+
+	t.Run("request ID header takes precedence", func(t *testing.T) {
+		handler := &testHandler{}
+		req := zhtest.NewRequest(http.MethodGet, "/").
+			WithHeader(httpx.HeaderXRequestId, "existing-id").
+			WithHeader(httpx.HeaderTraceparent, traceparent).
+			Build()
+		w := zhtest.TestMiddlewareWithHandler(New(Config{FromTraceparent: true}), handler, req)
+
+		zhtest.AssertWith(t, w).Header(httpx.HeaderXRequestId, "existing-id")
+		zhtest.AssertEqual(t, "existing-id", handler.requestID)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		handler := &testHandler{}
+		req := zhtest.NewRequest(http.MethodGet, "/").WithHeader(httpx.HeaderTraceparent, traceparent).Build()
+		zhtest.TestMiddlewareWithHandler(New(), handler, req)
+
+		zhtest.AssertNotEmpty(t, handler.requestID)
+		zhtest.AssertNotEqual(t, traceID, handler.requestID)
+	})
+
+	t.Run("invalid headers generate an ID", func(t *testing.T) {
+		invalid := []string{
+			"",
+			"garbage",
+			"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+			"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+			"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+			"00_4bf92f3577b34da6a3ce929d0e0e4736_00f067aa0ba902b7_01",
+			"00-4bf92f3577b34da6a3ce929d0e0e473g-00f067aa0ba902b7-01",
+		}
+		for _, v := range invalid {
+			handler := &testHandler{}
+			req := zhtest.NewRequest(http.MethodGet, "/").WithHeader(httpx.HeaderTraceparent, v).Build()
+			zhtest.TestMiddlewareWithHandler(New(Config{FromTraceparent: true}), handler, req)
+
+			zhtest.AssertEqual(t, 32, len(handler.requestID))
+			zhtest.AssertNotEqual(t, traceID, handler.requestID)
+		}
+	})
+
+	t.Run("future version with extra fields", func(t *testing.T) {
+		handler := &testHandler{}
+		req := zhtest.NewRequest(http.MethodGet, "/").
+			WithHeader(httpx.HeaderTraceparent, "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-abcd").
+			Build()
+		zhtest.TestMiddlewareWithHandler(New(Config{FromTraceparent: true}), handler, req)
+
+		zhtest.AssertEqual(t, traceID, handler.requestID)
+	})
+}
+
 // testHandler is a reusable test handler that captures request information
 type testHandler struct {
 	requestID string
@@ -2,8 +2,10 @@ package requestlogger
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/alexferl/zerohttp/config"
+	"github.com/alexferl/zerohttp/httpx"
 	"github.com/alexferl/zerohttp/log"
 )
 
@@ -19,6 +21,7 @@ const (
 	FieldReferer       LogField = "referer"
 	FieldUserAgent     LogField = "user_agent"
 	FieldStatus        LogField = "status"
+	FieldBytesWritten  LogField = "bytes_written"
 	FieldDurationNS    LogField = "duration_ns"
 	FieldDurationHuman LogField = "duration_human"
 	FieldRemoteAddr    LogField = "remote_addr"
@@ -26,6 +29,39 @@ const (
 	FieldRequestID     LogField = "request_id"
 	FieldRequestBody   LogField = "request_body"
 	FieldResponseBody  LogField = "response_body"
+
+	// FieldRequestHeaders logs the request headers, and FieldResponseHeaders
+	// the response headers, with the values of RedactHeaders replaced by
+	// "***". Multiple values of a header are joined with ", ".
+	FieldRequestHeaders  LogField = "request_headers"
+	FieldResponseHeaders LogField = "response_headers"
+
+	// FieldProtocolVariant logs how the request was transported: "HTTP/1.1",
+	// "HTTP/2" over TLS, "h2c" (HTTP/2 over cleartext) or "HTTP/3". Behind a
+	// TLS-terminating proxy, HTTP/2 requests from the proxy are logged as h2c.
+	FieldProtocolVariant LogField = "protocol_variant"
+
+	// FieldGRPCWeb logs whether the request is a gRPC-Web call, based on its
+	// Content-Type (application/grpc-web or application/grpc-web-text, with
+	// or without a +proto or +json suffix).
+	FieldGRPCWeb LogField = "grpc_web"
+)
+
+// Format is the format of request log entries.
+type Format string
+
+const (
+	// FormatStructured logs each request as key/value fields.
+	FormatStructured Format = "structured"
+
+	// FormatCommon logs each request as a single line in the Apache Common
+	// Log Format: host - - [time] "method uri proto" status bytes
+	FormatCommon Format = "common"
+
+	// FormatCombined logs each request as a single line in the Apache
+	// Combined Log Format, which adds the referer and user agent to
+	// FormatCommon: host - - [time] "method uri proto" status bytes "referer" "ua"
+	FormatCombined Format = "combined"
 )
 
 // Config allows customization of request logging.
@@ -40,8 +76,28 @@ type Config struct {
 	// Default: true
 	LogErrors bool
 
+	// SlowThreshold logs requests that take longer than this at Warn level,
+	// with a "slow": true field, even when they succeed. 5xx responses are
+	// still logged at Error level. Zero disables slow request detection.
+	// Default: 0
+	SlowThreshold time.Duration
+
+	// SampleRate logs only 1 in SampleRate successful requests, those with
+	// a status below 400, to cut the volume of routine logs on busy services.
+	// Client and server errors and slow requests are always logged.
+	// Zero or 1 logs every request.
+	// Default: 0
+	SampleRate int
+
+	// Format selects how requests are logged. With FormatCommon and
+	// FormatCombined, the log line is the message of the entry and no fields
+	// are logged, so Fields, FieldExtractors and CustomFields are ignored.
+	// Default: FormatStructured
+	Format Format
+
 	// Fields to include in logs.
-	// Default: all fields
+	// FieldProtocolVariant, FieldGRPCWeb and the body and header fields are opt-in.
+	// Default: all other fields
 	Fields []LogField
 
 	// ExcludedPaths contains paths to skip logging (e.g., health checks).
@@ -59,11 +115,14 @@ type Config struct {
 
 	// LogRequestBody enables logging of request bodies.
 	// This is opt-in due to performance and security considerations.
+	// Only text bodies such as JSON, XML, form data and text/* are logged;
+	// binary content types are skipped.
 	// Default: false
 	LogRequestBody bool
 
 	// LogResponseBody enables logging of response bodies.
 	// This is opt-in due to performance and security considerations.
+	// Binary content types are skipped, as for LogRequestBody.
 	// Default: false
 	LogResponseBody bool
 
@@ -78,10 +137,26 @@ type Config struct {
 	// Default: common sensitive field names
 	SensitiveFields []string
 
+	// RedactHeaders contains header names (case-insensitive) whose values are
+	// replaced with "***" when headers are logged, so credentials such as
+	// bearer tokens and session cookies never reach the logs.
+	// Default: DefaultRedactHeaders
+	RedactHeaders []string
+
 	// CustomFields allows adding arbitrary fields to request logs.
 	// Called once per request after the handler completes.
 	// Return nil or empty slice if no custom fields needed.
 	CustomFields func(r *http.Request) []log.Field
+
+	// FieldExtractors adds a field per entry, named by its key, with the value
+	// returned by the extractor. Extractors are called after the handler
+	// completes with the response status and request duration. They get the
+	// request the logger was called with: context values set by middleware
+	// that runs before the logger are there, but not those set by later
+	// middleware or the handler, which only exist on the requests they derive.
+	// Fields are added in key order, after the built-in Fields.
+	// Default: nil
+	FieldExtractors map[string]func(r *http.Request, status int, d time.Duration) any
 }
 
 // DefaultSensitiveFields contains common sensitive field names that should be masked.
@@ -113,10 +188,19 @@ var DefaultSensitiveFields = []string{
 	"dob",
 }
 
+// DefaultRedactHeaders contains the headers whose values are redacted by default.
+var DefaultRedactHeaders = []string{
+	httpx.HeaderAuthorization,
+	httpx.HeaderProxyAuthorization,
+	httpx.HeaderCookie,
+	httpx.HeaderSetCookie,
+}
+
 // DefaultConfig contains the default values for request logging configuration.
 var DefaultConfig = Config{
 	Enabled:   config.Bool(true),
 	LogErrors: true,
+	Format:    FormatStructured,
 	Fields: []LogField{
 		FieldMethod,
 		FieldURI,
@@ -126,6 +210,7 @@ var DefaultConfig = Config{
 		FieldReferer,
 		FieldUserAgent,
 		FieldStatus,
+		FieldBytesWritten,
 		FieldDurationNS,
 		FieldDurationHuman,
 		FieldRemoteAddr,
@@ -136,4 +221,5 @@ var DefaultConfig = Config{
 	IncludedPaths:   []string{},
 	MaxBodySize:     1024, // 1KB default
 	SensitiveFields: DefaultSensitiveFields,
+	RedactHeaders:   DefaultRedactHeaders,
 }
@@ -12,16 +12,17 @@ import (
 func TestRequestLoggerConfig_DefaultValues(t *testing.T) {
 	cfg := DefaultConfig
 	zhtest.AssertTrue(t, cfg.LogErrors)
-	zhtest.AssertEqual(t, 13, len(cfg.Fields))
+	zhtest.AssertEqual(t, 14, len(cfg.Fields))
 	zhtest.AssertEqual(t, 0, len(cfg.ExcludedPaths))
 
 	// Test default field values
 	expectedFields := []LogField{
 		FieldMethod, FieldURI, FieldPath, FieldHost, FieldProtocol,
-		FieldReferer, FieldUserAgent, FieldStatus, FieldDurationNS,
+		FieldReferer, FieldUserAgent, FieldStatus, FieldBytesWritten, FieldDurationNS,
 		FieldDurationHuman, FieldRemoteAddr, FieldClientIP, FieldRequestID,
 	}
 	zhtest.AssertEqual(t, expectedFields, cfg.Fields)
+	zhtest.AssertEqual(t, []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}, cfg.RedactHeaders)
 }
 
 func TestRequestLoggerConfig_FieldConstants(t *testing.T) {
@@ -37,11 +38,16 @@ func TestRequestLoggerConfig_FieldConstants(t *testing.T) {
 		{FieldReferer, "referer"},
 		{FieldUserAgent, "user_agent"},
 		{FieldStatus, "status"},
+		{FieldBytesWritten, "bytes_written"},
 		{FieldDurationNS, "duration_ns"},
 		{FieldDurationHuman, "duration_human"},
 		{FieldRemoteAddr, "remote_addr"},
 		{FieldClientIP, "client_ip"},
 		{FieldRequestID, "request_id"},
+		{FieldProtocolVariant, "protocol_variant"},
+		{FieldGRPCWeb, "grpc_web"},
+		{FieldRequestHeaders, "request_headers"},
+		{FieldResponseHeaders, "response_headers"},
 	}
 
 	for _, tt := range tests {
@@ -114,7 +120,7 @@ func TestRequestLoggerConfig_FieldScenarios(t *testing.T) {
 	t.Run("single field variations", func(t *testing.T) {
 		allFields := []LogField{
 			FieldMethod, FieldURI, FieldPath, FieldHost, FieldProtocol,
-			FieldReferer, FieldUserAgent, FieldStatus, FieldDurationNS,
+			FieldReferer, FieldUserAgent, FieldStatus, FieldBytesWritten, FieldDurationNS,
 			FieldDurationHuman, FieldRemoteAddr, FieldClientIP, FieldRequestID,
 		}
 
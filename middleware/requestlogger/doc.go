@@ -23,7 +23,129 @@
 //   - method
 //   - path
 //   - status_code
+//   - bytes_written (response body size)
 //   - duration
 //   - client_ip
 //   - user_agent
+//
+// # Access Log Formats
+//
+// For log pipelines that expect Apache access logs, set Format to
+// FormatCommon or FormatCombined. Each request is logged as a single line,
+// used as the message of the entry, instead of key/value fields:
+//
+//	app.Use(requestlogger.New(logger, requestlogger.Config{
+//	    Format: requestlogger.FormatCombined,
+//	}))
+//
+//	// 203.0.113.1 - - [10/Oct/2026:13:55:36 +0000] "GET /users?page=2 HTTP/1.1" 200 2326 "-" "curl/8.0"
+//
+// The entry is logged at the same level as in the structured format.
+//
+// # Custom Fields
+//
+// FieldExtractors adds app-specific fields to the same entry as the built-in
+// ones. Each extractor gets the request, response status and duration. The
+// request is the one the logger was called with, so read values from headers
+// or from context values set by middleware added before the logger; context
+// values set further down the chain aren't visible:
+//
+//	app.Use(requestlogger.New(logger, requestlogger.Config{
+//	    FieldExtractors: map[string]func(*http.Request, int, time.Duration) any{
+//	        "tenant_id": func(r *http.Request, _ int, _ time.Duration) any {
+//	            return r.Header.Get("X-Tenant-Id")
+//	        },
+//	    },
+//	}))
+//
+// # Slow Requests
+//
+// Set SlowThreshold to log requests that take longer than the threshold at
+// Warn level with a "slow": true field, regardless of their status code:
+//
+//	app.Use(requestlogger.New(logger, requestlogger.Config{
+//	    SlowThreshold: 2 * time.Second,
+//	}))
+//
+// # Sampling
+//
+// On busy services, set SampleRate to log only 1 in N successful requests.
+// Client and server errors, and requests slower than SlowThreshold, are
+// always logged:
+//
+//	app.Use(requestlogger.New(logger, requestlogger.Config{
+//	    LogErrors:  true,
+//	    SampleRate: 100, // 1% of 2xx and 3xx responses
+//	}))
+//
+// # Client Disconnects
+//
+// The server cancels the request context when the client closes the
+// connection, so handlers that pass r.Context() to database queries and
+// upstream calls stop early. Requests whose context was canceled this way
+// are logged with a "client_disconnected": true field, with the status the
+// handler wrote, if any. Deadlines and cancellations with a cause, such as
+// those of the timeout middleware or a server shutdown, aren't logged as
+// disconnects:
+//
+//	app.GET("/report", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+//	    rows, err := db.QueryContext(r.Context(), query) // Aborted on disconnect
+//	    ...
+//	}))
+//
+// The [github.com/alexferl/zerohttp/middleware/disconnect] middleware gives
+// the cancellation [disconnect.ErrClientDisconnected] as its cause, for
+// handlers to check.
+//
+// # Request and Response Bodies
+//
+// LogRequestBody and LogResponseBody add the request_body and response_body
+// fields (which must also be listed in Fields), truncated to MaxBodySize.
+// Values of SensitiveFields in JSON and form-encoded bodies are replaced
+// with "[REDACTED]" before truncation. Other bodies that mention one of
+// SensitiveFields, such as JSON cut off at MaxBodySize, are logged as
+// "[REDACTED]" as a whole. Binary content types such as images or
+// application/octet-stream are not logged. The handler still reads the whole request body, and streaming
+// responses are flushed as usual:
+//
+//	fields := append(slices.Clone(requestlogger.DefaultConfig.Fields),
+//	    requestlogger.FieldRequestBody,
+//	    requestlogger.FieldResponseBody,
+//	)
+//	app.Use(requestlogger.New(logger, requestlogger.Config{
+//	    Fields:          fields,
+//	    LogRequestBody:  true,
+//	    LogResponseBody: true,
+//	    MaxBodySize:     4096,
+//	    SensitiveFields: append(slices.Clone(requestlogger.DefaultSensitiveFields), "signature"),
+//	}))
+//
+// # Headers
+//
+// FieldRequestHeaders and FieldResponseHeaders log the request and response
+// headers. The values of RedactHeaders, by default Authorization,
+// Proxy-Authorization, Cookie and Set-Cookie, are logged as "***":
+//
+//	fields := append(slices.Clone(requestlogger.DefaultConfig.Fields),
+//	    requestlogger.FieldRequestHeaders,
+//	    requestlogger.FieldResponseHeaders,
+//	)
+//	app.Use(requestlogger.New(logger, requestlogger.Config{
+//	    Fields:        fields,
+//	    RedactHeaders: append(slices.Clone(requestlogger.DefaultRedactHeaders), "X-API-Key"),
+//	}))
+//
+// RedactHeaders is also exported as a function, to redact headers logged
+// elsewhere the same way.
+//
+// # Protocol Variant
+//
+// For servers mixing HTTP/1.1, HTTP/2, h2c and gRPC-Web clients, opt in to
+// FieldProtocolVariant and FieldGRPCWeb to debug protocol negotiation:
+//
+//	fields := append(slices.Clone(requestlogger.DefaultConfig.Fields),
+//	    requestlogger.FieldProtocolVariant,
+//	    requestlogger.FieldGRPCWeb,
+//	)
+//	app.Use(requestlogger.New(logger, requestlogger.Config{Fields: fields}))
 package requestlogger
@@ -3,9 +3,14 @@ package requestlogger
 import (
 	"bytes"
 	"encoding/json"
-	"io"
+	"maps"
+	"net"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/alexferl/zerohttp/httpx"
@@ -13,6 +18,8 @@ import (
 	"github.com/alexferl/zerohttp/internal/mwutil"
 	"github.com/alexferl/zerohttp/internal/rwutil"
 	"github.com/alexferl/zerohttp/log"
+	"github.com/alexferl/zerohttp/middleware/disconnect"
+	"github.com/alexferl/zerohttp/middleware/requestid"
 )
 
 // New creates a request logging middleware with the provided configuration
@@ -31,6 +38,9 @@ func New(logger log.Logger, cfg ...Config) func(http.Handler) http.Handler {
 		fieldMap[field] = true
 	}
 
+	// sampled counts the successful requests considered for sampling
+	var sampled atomic.Uint64
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			for _, excludedPath := range c.ExcludedPaths {
@@ -61,16 +71,29 @@ func New(logger log.Logger, cfg ...Config) func(http.Handler) http.Handler {
 			}
 
 			duration := time.Since(start)
+			status := wrapped.StatusCode()
+
+			// Sampling only drops routine requests, errors and slow requests are always logged
+			if c.SampleRate > 1 && status < http.StatusBadRequest && (c.SlowThreshold <= 0 || duration <= c.SlowThreshold) {
+				if (sampled.Add(1)-1)%uint64(c.SampleRate) != 0 {
+					return
+				}
+			}
 
+			// Bodies are masked before they're truncated, since masking
+			// changes their length
 			var responseBody string
-			if c.LogResponseBody && bodyLoggingAllowed {
-				responseBody = maskSensitiveData(wrapped.bodyString(), c.SensitiveFields)
+			if c.LogResponseBody && bodyLoggingAllowed && wrapped.body != nil {
+				responseBody = maskSensitiveData(wrapped.body.String(), wrapped.Header().Get(httpx.HeaderContentType), c.SensitiveFields)
+				responseBody = truncateBody(responseBody, c.MaxBodySize, wrapped.truncated)
 			}
 			if c.LogRequestBody && bodyLoggingAllowed && requestBody != "" {
-				requestBody = maskSensitiveData(requestBody, c.SensitiveFields)
+				truncated := len(requestBody) > c.MaxBodySize
+				requestBody = maskSensitiveData(requestBody, r.Header.Get(httpx.HeaderContentType), c.SensitiveFields)
+				requestBody = truncateBody(requestBody, c.MaxBodySize, truncated)
 			}
 
-			Log(logger, c, fieldMap, r, wrapped.StatusCode(), duration, requestBody, responseBody)
+			logRequest(logger, c, fieldMap, r, status, wrapped.Header(), wrapped.BytesWritten(), duration, requestBody, responseBody)
 		})
 	}
 }
@@ -78,6 +101,20 @@ func New(logger log.Logger, cfg ...Config) func(http.Handler) http.Handler {
 // Log logs an HTTP request with consistent formatting.
 // If fieldMap is nil, it will be computed from cfg.Fields.
 func Log(logger log.Logger, cfg Config, fieldMap map[LogField]bool, r *http.Request, statusCode int, duration time.Duration, requestBody, responseBody string) {
+	logRequest(logger, cfg, fieldMap, r, statusCode, nil, -1, duration, requestBody, responseBody)
+}
+
+// logRequest logs an HTTP request whose response had the header respHeader,
+// if known, and a body size bytes long. A negative size means the size is
+// unknown.
+func logRequest(logger log.Logger, cfg Config, fieldMap map[LogField]bool, r *http.Request, statusCode int, respHeader http.Header, size int64, duration time.Duration, requestBody, responseBody string) {
+	slow := cfg.SlowThreshold > 0 && duration > cfg.SlowThreshold
+
+	if cfg.Format == FormatCommon || cfg.Format == FormatCombined {
+		logAtLevel(logger, cfg, statusCode, slow, formatAccessLog(cfg.Format, r, statusCode, size, time.Now().Add(-duration)))
+		return
+	}
+
 	if fieldMap == nil {
 		fieldMap = make(map[LogField]bool)
 		for _, field := range cfg.Fields {
@@ -106,6 +143,12 @@ func Log(logger log.Logger, cfg Config, fieldMap map[LogField]bool, r *http.Requ
 	if fieldMap[FieldProtocol] {
 		logFields = append(logFields, log.F("protocol", r.Proto))
 	}
+	if fieldMap[FieldProtocolVariant] {
+		logFields = append(logFields, log.F("protocol_variant", protocolVariant(r)))
+	}
+	if fieldMap[FieldGRPCWeb] {
+		logFields = append(logFields, log.F("grpc_web", isGRPCWeb(r)))
+	}
 	if fieldMap[FieldReferer] {
 		logFields = append(logFields, log.F("referer", r.Referer()))
 	}
@@ -115,6 +158,9 @@ func Log(logger log.Logger, cfg Config, fieldMap map[LogField]bool, r *http.Requ
 	if fieldMap[FieldStatus] {
 		logFields = append(logFields, log.F("status", statusCode))
 	}
+	if fieldMap[FieldBytesWritten] && size >= 0 {
+		logFields = append(logFields, log.F("bytes_written", size))
+	}
 	if fieldMap[FieldDurationNS] {
 		logFields = append(logFields, log.F("duration_ns", duration.Nanoseconds()))
 	}
@@ -125,10 +171,16 @@ func Log(logger log.Logger, cfg Config, fieldMap map[LogField]bool, r *http.Requ
 		logFields = append(logFields, log.F("remote_addr", r.RemoteAddr))
 	}
 	if fieldMap[FieldRequestID] {
-		if requestID := r.Header.Get(httpx.HeaderXRequestId); requestID != "" {
+		if requestID := requestid.FromRequest(r); requestID != "" {
 			logFields = append(logFields, log.F("request_id", requestID))
 		}
 	}
+	if fieldMap[FieldRequestHeaders] {
+		logFields = append(logFields, log.F("request_headers", redactHeaders(r.Header, cfg.RedactHeaders)))
+	}
+	if fieldMap[FieldResponseHeaders] && respHeader != nil {
+		logFields = append(logFields, log.F("response_headers", redactHeaders(respHeader, cfg.RedactHeaders)))
+	}
 	if fieldMap[FieldRequestBody] && cfg.LogRequestBody && requestBody != "" {
 		logFields = append(logFields, log.F("request_body", requestBody))
 	}
@@ -136,25 +188,140 @@ func Log(logger log.Logger, cfg Config, fieldMap map[LogField]bool, r *http.Requ
 		logFields = append(logFields, log.F("response_body", responseBody))
 	}
 
+	for _, name := range slices.Sorted(maps.Keys(cfg.FieldExtractors)) {
+		logFields = append(logFields, log.F(name, cfg.FieldExtractors[name](r, statusCode, duration)))
+	}
+
 	// Add custom fields from user-defined callback
 	if cfg.CustomFields != nil {
 		customFields := cfg.CustomFields(r)
 		logFields = append(logFields, customFields...)
 	}
 
-	msg := "Request completed"
+	if slow {
+		logFields = append(logFields, log.F("slow", true))
+	}
 
-	if cfg.LogErrors {
-		if statusCode >= http.StatusInternalServerError {
-			logger.Error(msg, logFields...)
-		} else if statusCode >= http.StatusBadRequest {
-			logger.Warn(msg, logFields...)
-		} else {
-			logger.Info(msg, logFields...)
+	// Cancellations by upstream middleware, with a cause or a deadline,
+	// aren't disconnects
+	if disconnect.Disconnected(r.Context()) {
+		logFields = append(logFields, log.F("client_disconnected", true))
+	}
+
+	logAtLevel(logger, cfg, statusCode, slow, "Request completed", logFields...)
+}
+
+// RedactedValue replaces the values of redacted headers.
+const RedactedValue = "***"
+
+// RedactHeaders returns a copy of h with the values of the headers named in
+// redact (case-insensitive) replaced by [RedactedValue], for logging or
+// dumping headers without leaking credentials.
+func RedactHeaders(h http.Header, redact []string) http.Header {
+	out := h.Clone()
+	for name, values := range out {
+		if slices.ContainsFunc(redact, func(r string) bool { return strings.EqualFold(r, name) }) {
+			out[name] = slices.Repeat([]string{RedactedValue}, len(values))
+		}
+	}
+	return out
+}
+
+// redactHeaders returns h as a map of header names to their comma-joined
+// values, with the values of the headers named in redact replaced.
+func redactHeaders(h http.Header, redact []string) map[string]string {
+	out := make(map[string]string, len(h))
+	for name, values := range RedactHeaders(h, redact) {
+		out[name] = strings.Join(values, ", ")
+	}
+	return out
+}
+
+// logAtLevel logs errors at Error level, client errors and slow requests at
+// Warn level and everything else at Info level.
+func logAtLevel(logger log.Logger, cfg Config, statusCode int, slow bool, msg string, fields ...log.Field) {
+	switch {
+	case cfg.LogErrors && statusCode >= http.StatusInternalServerError:
+		logger.Error(msg, fields...)
+	case cfg.LogErrors && statusCode >= http.StatusBadRequest, slow:
+		logger.Warn(msg, fields...)
+	default:
+		logger.Info(msg, fields...)
+	}
+}
+
+// formatAccessLog formats a request as a line in the Apache Common or
+// Combined Log Format, such as:
+//
+//	203.0.113.1 - - [10/Oct/2000:13:55:36 -0700] "GET /index.html HTTP/1.1" 200 2326 "-" "curl/8.0"
+func formatAccessLog(format Format, r *http.Request, statusCode int, size int64, start time.Time) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if host == "" {
+		host = "-"
+	}
+
+	uri := r.RequestURI
+	if uri == "" {
+		uri = r.URL.RequestURI()
+	}
+
+	bytesField := "-"
+	if size > 0 {
+		bytesField = strconv.FormatInt(size, 10)
+	}
+
+	var b strings.Builder
+	b.WriteString(host)
+	b.WriteString(" - - [")
+	b.WriteString(start.Format("02/Jan/2006:15:04:05 -0700"))
+	b.WriteString("] ")
+	b.WriteString(quoteLogField(r.Method + " " + uri + " " + r.Proto))
+	b.WriteByte(' ')
+	b.WriteString(strconv.Itoa(statusCode))
+	b.WriteByte(' ')
+	b.WriteString(bytesField)
+	if format == FormatCombined {
+		b.WriteByte(' ')
+		b.WriteString(quoteLogField(r.Referer()))
+		b.WriteByte(' ')
+		b.WriteString(quoteLogField(r.UserAgent()))
+	}
+	return b.String()
+}
+
+// quoteLogField quotes s for an access log line, escaping quotes and control
+// characters so clients can't forge log lines. Empty values are logged as "-".
+func quoteLogField(s string) string {
+	if s == "" {
+		return `"-"`
+	}
+	return strconv.Quote(s)
+}
+
+// protocolVariant returns the protocol the request was transported with,
+// telling HTTP/2 over TLS apart from h2c.
+func protocolVariant(r *http.Request) string {
+	switch r.ProtoMajor {
+	case 2:
+		if r.TLS == nil {
+			return "h2c"
 		}
-	} else {
-		logger.Info(msg, logFields...)
+		return "HTTP/2"
+	case 3:
+		return "HTTP/3"
 	}
+	return r.Proto
+}
+
+// isGRPCWeb reports whether the request has a gRPC-Web content type.
+func isGRPCWeb(r *http.Request) bool {
+	contentType, _, _ := strings.Cut(r.Header.Get(httpx.HeaderContentType), ";")
+	contentType, _, _ = strings.Cut(strings.TrimSpace(contentType), "+")
+	contentType = strings.ToLower(contentType)
+	return contentType == httpx.MIMEApplicationGRPCWeb || contentType == httpx.MIMEApplicationGRPCWebText
 }
 
 // bodyCapturingResponseWriter wraps ResponseWriter to capture response body for logging.
@@ -164,6 +331,7 @@ type bodyCapturingResponseWriter struct {
 	maxSize   int
 	sizeLimit bool
 	truncated bool
+	checked   bool
 }
 
 // newBodyCapturingResponseWriter creates a new response writer that captures body.
@@ -178,6 +346,19 @@ func newBodyCapturingResponseWriter(w http.ResponseWriter, maxSize int) *bodyCap
 
 // Write captures the response body and forwards to the underlying ResponseWriter.
 func (rw *bodyCapturingResponseWriter) Write(data []byte) (int, error) {
+	// Binary responses are not captured. Without a Content-Type, the type is
+	// sniffed from the first write, as net/http does.
+	if rw.body != nil && !rw.checked {
+		rw.checked = true
+		contentType := rw.Header().Get(httpx.HeaderContentType)
+		if contentType == "" {
+			contentType = http.DetectContentType(data)
+		}
+		if !isTextContentType(contentType) {
+			rw.body = nil
+		}
+	}
+
 	// Capture body up to max size (only if body buffer is initialized)
 	if rw.body != nil {
 		if !rw.sizeLimit || rw.body.Len() < rw.maxSize {
@@ -195,18 +376,6 @@ func (rw *bodyCapturingResponseWriter) Write(data []byte) (int, error) {
 	return rw.ResponseWriter.Write(data)
 }
 
-// body returns the captured body as a string.
-func (rw *bodyCapturingResponseWriter) bodyString() string {
-	if rw.body == nil {
-		return ""
-	}
-	body := rw.body.String()
-	if rw.truncated {
-		return body + "..."
-	}
-	return body
-}
-
 // Flush implements http.Flusher to support streaming responses like SSE.
 // It passes the flush through to the underlying ResponseWriter if it supports Flusher.
 func (rw *bodyCapturingResponseWriter) Flush() {
@@ -215,7 +384,10 @@ func (rw *bodyCapturingResponseWriter) Flush() {
 	}
 }
 
-// captureRequestBody reads and restores the request body.
+// captureRequestBody reads up to maxSize+1 bytes of the request body, one
+// more than is logged so that truncation can be detected, and puts them back
+// in front of the unread remainder, so the handler still reads the whole
+// body and large or streamed bodies aren't buffered in memory.
 func captureRequestBody(r *http.Request, maxSize int) string {
 	if r.Body == nil || r.Body == http.NoBody {
 		return ""
@@ -226,35 +398,81 @@ func captureRequestBody(r *http.Request, maxSize int) string {
 		return ""
 	}
 
-	body, err := io.ReadAll(io.LimitReader(r.Body, int64(maxSize)+1))
-
-	// Always restore the body, even on error, so downstream handlers are unaffected
-	r.Body = io.NopCloser(bytes.NewReader(body))
+	// Requests without a Content-Type are logged, binary ones are not
+	if contentType := r.Header.Get(httpx.HeaderContentType); contentType != "" && !isTextContentType(contentType) {
+		return ""
+	}
 
+	body, err := mwutil.PeekBody(r, int64(maxSize)+1)
 	if err != nil {
 		return ""
 	}
+	return string(body)
+}
 
-	if len(body) > maxSize {
-		return string(body[:maxSize]) + "..."
+// truncateBody cuts body to maxSize bytes, if positive, and marks it with
+// "..." if it was cut or had already been truncated when captured.
+func truncateBody(body string, maxSize int, truncated bool) string {
+	if maxSize > 0 && len(body) > maxSize {
+		body, truncated = body[:maxSize], true
 	}
-	return string(body)
+	if truncated {
+		return body + "..."
+	}
+	return body
 }
 
-// maskSensitiveData masks sensitive fields in JSON data.
-func maskSensitiveData(data string, sensitiveFields []string) string {
+// isTextContentType reports whether a body of the given content type is
+// text that can be logged, such as JSON, XML, form data or text/*.
+func isTextContentType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+	if strings.HasPrefix(mediaType, "text/") ||
+		strings.HasSuffix(mediaType, "+json") ||
+		strings.HasSuffix(mediaType, "+xml") {
+		return true
+	}
+
+	switch mediaType {
+	case httpx.MIMEApplicationJSON,
+		httpx.MIMEApplicationXML,
+		httpx.MIMEApplicationFormURLEncoded,
+		httpx.MIMEApplicationJavaScript,
+		"application/x-ndjson",
+		"application/graphql":
+		return true
+	}
+	return false
+}
+
+// redactedBody replaces a body whose sensitive values can't be masked.
+const redactedBody = "[REDACTED]"
+
+// maskSensitiveData masks sensitive fields in JSON and form-encoded data of
+// the given content type. Data that can't be parsed, such as a truncated
+// JSON body, is replaced with "[REDACTED]" as a whole if it contains the
+// name of a sensitive field, rather than logged as is.
+func maskSensitiveData(data, contentType string, sensitiveFields []string) string {
 	if data == "" || len(sensitiveFields) == 0 {
 		return data
 	}
 
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	if strings.EqualFold(strings.TrimSpace(mediaType), httpx.MIMEApplicationFormURLEncoded) {
+		if masked, ok := maskForm(data, sensitiveFields); ok {
+			return masked
+		}
+		return maskUnparsed(data, sensitiveFields)
+	}
+
 	// Try to parse as JSON object
 	var jsonObj map[string]any
 	if err := json.Unmarshal([]byte(data), &jsonObj); err != nil {
 		// Try to parse as JSON array
 		var jsonArr []map[string]any
 		if err := json.Unmarshal([]byte(data), &jsonArr); err != nil {
-			// Not valid JSON, return as-is
-			return data
+			return maskUnparsed(data, sensitiveFields)
 		}
 		// Process array of objects
 		for i, obj := range jsonArr {
@@ -262,7 +480,7 @@ func maskSensitiveData(data string, sensitiveFields []string) string {
 		}
 		result, err := json.Marshal(jsonArr)
 		if err != nil {
-			return data
+			return maskUnparsed(data, sensitiveFields)
 		}
 		return string(result)
 	}
@@ -271,11 +489,41 @@ func maskSensitiveData(data string, sensitiveFields []string) string {
 	maskedObj := maskObject(jsonObj, sensitiveFields)
 	result, err := json.Marshal(maskedObj)
 	if err != nil {
-		return data
+		return maskUnparsed(data, sensitiveFields)
 	}
 	return string(result)
 }
 
+// maskForm masks the values of sensitive keys in form-encoded data, keeping
+// the order and encoding of the other pairs. Keys are decoded as by
+// url.ParseQuery. It reports false if a key can't be decoded.
+func maskForm(data string, sensitiveFields []string) (string, bool) {
+	pairs := strings.Split(data, "&")
+	for i, pair := range pairs {
+		rawKey, _, _ := strings.Cut(pair, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			return "", false
+		}
+		if isSensitiveField(key, sensitiveFields) {
+			pairs[i] = rawKey + "=" + redactedBody
+		}
+	}
+	return strings.Join(pairs, "&"), true
+}
+
+// maskUnparsed returns data unless it mentions a sensitive field, whose
+// value can't be located without parsing it.
+func maskUnparsed(data string, sensitiveFields []string) string {
+	lower := strings.ToLower(data)
+	for _, field := range sensitiveFields {
+		if strings.Contains(lower, strings.ToLower(field)) {
+			return redactedBody
+		}
+	}
+	return data
+}
+
 // maskObject masks sensitive fields in a single JSON object.
 func maskObject(obj map[string]any, sensitiveFields []string) map[string]any {
 	if obj == nil {
@@ -333,7 +333,7 @@ func BenchmarkRequestLogger_MaskSensitiveData(b *testing.B) {
 			b.ResetTimer()
 
 			for b.Loop() {
-				maskSensitiveData(tc.data, sensitiveFields)
+				maskSensitiveData(tc.data, httpx.MIMEApplicationJSON, sensitiveFields)
 			}
 		})
 	}
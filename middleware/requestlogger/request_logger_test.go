@@ -2,6 +2,9 @@ package requestlogger
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -110,6 +113,143 @@ func TestRequestLogger_LogLevels(t *testing.T) {
 	})
 }
 
+func TestRequestLogger_SlowThreshold(t *testing.T) {
+	cfg := Config{
+		LogErrors:     true,
+		Fields:        []LogField{FieldStatus},
+		SlowThreshold: 500 * time.Millisecond,
+	}
+	req := zhtest.NewRequest(http.MethodGet, "/reports").Build()
+
+	t.Run("fast request", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		Log(logger, cfg, nil, req, http.StatusOK, 100*time.Millisecond, "", "")
+
+		zhtest.AssertEqual(t, 1, len(logger.infoLogs))
+		_, found := findFieldValue(logger.infoLogs[0].fields, "slow")
+		zhtest.AssertFalse(t, found)
+	})
+
+	t.Run("slow request", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		Log(logger, cfg, nil, req, http.StatusOK, time.Second, "", "")
+
+		zhtest.AssertEqual(t, 0, len(logger.infoLogs))
+		zhtest.AssertEqual(t, 1, len(logger.warnLogs))
+		value, found := findFieldValue(logger.warnLogs[0].fields, "slow")
+		zhtest.AssertTrue(t, found)
+		zhtest.AssertEqual(t, true, value)
+	})
+
+	t.Run("slow server error stays at error", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		Log(logger, cfg, nil, req, http.StatusBadGateway, time.Second, "", "")
+
+		zhtest.AssertEqual(t, 1, len(logger.errorLogs))
+		_, found := findFieldValue(logger.errorLogs[0].fields, "slow")
+		zhtest.AssertTrue(t, found)
+	})
+
+	t.Run("slow request with LogErrors disabled", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		cfg := cfg
+		cfg.LogErrors = false
+		Log(logger, cfg, nil, req, http.StatusOK, time.Second, "", "")
+
+		zhtest.AssertEqual(t, 1, len(logger.warnLogs))
+	})
+}
+
+func TestRequestLogger_ClientDisconnected(t *testing.T) {
+	t.Run("connected client", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		req := zhtest.NewRequest(http.MethodGet, "/report").Build()
+		zhtest.Serve(New(logger)(&statusTestHandler{statusCode: http.StatusOK}), req)
+
+		zhtest.AssertEqual(t, 1, len(logger.infoLogs))
+		_, found := findFieldValue(logger.infoLogs[0].fields, "client_disconnected")
+		zhtest.AssertFalse(t, found)
+	})
+
+	t.Run("disconnected client", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		ctx, cancel := context.WithCancel(context.Background())
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cancel() // Simulates the server canceling the context on disconnect
+			<-r.Context().Done()
+		})
+
+		req := zhtest.NewRequest(http.MethodGet, "/report").Build().WithContext(ctx)
+		zhtest.Serve(New(logger)(handler), req)
+
+		zhtest.AssertEqual(t, 1, len(logger.infoLogs))
+		value, found := findFieldValue(logger.infoLogs[0].fields, "client_disconnected")
+		zhtest.AssertTrue(t, found)
+		zhtest.AssertEqual(t, true, value)
+	})
+
+	t.Run("canceled upstream", func(t *testing.T) {
+		for name, cancel := range map[string]func(context.Context) (context.Context, func()){
+			"with cause": func(ctx context.Context) (context.Context, func()) {
+				ctx, cancel := context.WithCancelCause(ctx)
+				return ctx, func() { cancel(errors.New("shutting down")) }
+			},
+			"deadline": func(ctx context.Context) (context.Context, func()) {
+				return context.WithTimeout(ctx, 0)
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				logger := &requestLoggerMockLogger{}
+				ctx, stop := cancel(context.Background())
+				stop()
+
+				req := zhtest.NewRequest(http.MethodGet, "/report").Build().WithContext(ctx)
+				zhtest.Serve(New(logger)(&statusTestHandler{statusCode: http.StatusOK}), req)
+
+				zhtest.AssertEqual(t, 1, len(logger.infoLogs))
+				_, found := findFieldValue(logger.infoLogs[0].fields, "client_disconnected")
+				zhtest.AssertFalse(t, found)
+			})
+		}
+	})
+
+	t.Run("over a real connection", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		started := make(chan struct{})
+		canceled := make(chan struct{})
+		server := httptest.NewServer(New(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-r.Context().Done()
+			close(canceled)
+		})))
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		zhtest.AssertNoError(t, err)
+		go func() {
+			resp, err := http.DefaultClient.Do(req)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		}()
+
+		<-started
+		cancel()
+
+		select {
+		case <-canceled:
+		case <-time.After(2 * time.Second):
+			zhtest.AssertFail(t, "request context was not canceled on disconnect")
+		}
+		server.Close() // Waits for the handler and the middleware to return
+
+		zhtest.AssertEqual(t, 1, len(logger.infoLogs))
+		_, found := findFieldValue(logger.infoLogs[0].fields, "client_disconnected")
+		zhtest.AssertTrue(t, found)
+	})
+}
+
 func TestRequestLogger_FieldsAndDurations(t *testing.T) {
 	logger := &requestLoggerMockLogger{}
 	delay := 10 * time.Millisecond
@@ -214,6 +354,80 @@ func TestRequestLogger_CustomFields(t *testing.T) {
 	}
 }
 
+func TestRequestLogger_ProtocolVariant(t *testing.T) {
+	tests := []struct {
+		name        string
+		protoMajor  int
+		proto       string
+		tls         bool
+		contentType string
+		wantVariant string
+		wantGRPCWeb bool
+	}{
+		{name: "HTTP/1.1", protoMajor: 1, proto: "HTTP/1.1", wantVariant: "HTTP/1.1"},
+		{name: "HTTP/2 over TLS", protoMajor: 2, proto: "HTTP/2.0", tls: true, wantVariant: "HTTP/2"},
+		{name: "h2c", protoMajor: 2, proto: "HTTP/2.0", wantVariant: "h2c"},
+		{name: "HTTP/3", protoMajor: 3, proto: "HTTP/3.0", tls: true, wantVariant: "HTTP/3"},
+		{
+			name: "gRPC-Web", protoMajor: 2, proto: "HTTP/2.0", tls: true,
+			contentType: "application/grpc-web+proto", wantVariant: "HTTP/2", wantGRPCWeb: true,
+		},
+		{
+			name: "gRPC-Web text", protoMajor: 1, proto: "HTTP/1.1",
+			contentType: "application/grpc-web-text", wantVariant: "HTTP/1.1", wantGRPCWeb: true,
+		},
+		{
+			name: "native gRPC is not gRPC-Web", protoMajor: 2, proto: "HTTP/2.0",
+			contentType: "application/grpc", wantVariant: "h2c",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &requestLoggerMockLogger{}
+			handler := &statusTestHandler{statusCode: http.StatusOK}
+			middleware := New(logger, Config{
+				Fields: []LogField{FieldProtocolVariant, FieldGRPCWeb},
+			})(handler)
+
+			req := zhtest.NewRequest(http.MethodPost, "/rpc").Build()
+			req.ProtoMajor = tt.protoMajor
+			req.Proto = tt.proto
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+			if tt.contentType != "" {
+				req.Header.Set(httpx.HeaderContentType, tt.contentType)
+			}
+			zhtest.Serve(middleware, req)
+
+			zhtest.AssertEqual(t, 1, len(logger.infoLogs))
+			entry := logger.infoLogs[0]
+
+			variant, found := findFieldValue(entry.fields, "protocol_variant")
+			zhtest.AssertTrue(t, found)
+			zhtest.AssertEqual(t, tt.wantVariant, variant)
+
+			grpcWeb, found := findFieldValue(entry.fields, "grpc_web")
+			zhtest.AssertTrue(t, found)
+			zhtest.AssertEqual(t, tt.wantGRPCWeb, grpcWeb)
+		})
+	}
+
+	t.Run("not logged by default", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		middleware := New(logger)(&statusTestHandler{statusCode: http.StatusOK})
+
+		zhtest.Serve(middleware, zhtest.NewRequest(http.MethodGet, "/").Build())
+
+		zhtest.AssertEqual(t, 1, len(logger.infoLogs))
+		_, found := findFieldValue(logger.infoLogs[0].fields, "protocol_variant")
+		zhtest.AssertFalse(t, found)
+		_, found = findFieldValue(logger.infoLogs[0].fields, "grpc_web")
+		zhtest.AssertFalse(t, found)
+	})
+}
+
 func TestRequestLogger_ExcludedPaths(t *testing.T) {
 	logger := &requestLoggerMockLogger{}
 	handler := &statusTestHandler{statusCode: http.StatusOK}
@@ -230,6 +444,141 @@ func TestRequestLogger_ExcludedPaths(t *testing.T) {
 	zhtest.AssertEqual(t, 1, len(logger.infoLogs))
 }
 
+func TestRequestLogger_SampleRate(t *testing.T) {
+	t.Run("logs 1 in N successful requests", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		middleware := New(logger, Config{SampleRate: 10})(&statusTestHandler{statusCode: http.StatusOK})
+
+		for range 25 {
+			zhtest.Serve(middleware, zhtest.NewRequest(http.MethodGet, "/api").Build())
+		}
+
+		// Requests 1, 11 and 21
+		zhtest.AssertEqual(t, 3, len(logger.infoLogs))
+	})
+
+	t.Run("errors are always logged", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		status := http.StatusOK
+		handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(status)
+		})
+		middleware := New(logger, Config{LogErrors: true, SampleRate: 100})(handler)
+
+		for _, status = range []int{http.StatusOK, http.StatusNotFound, http.StatusOK, http.StatusInternalServerError, http.StatusFound} {
+			zhtest.Serve(middleware, zhtest.NewRequest(http.MethodGet, "/api").Build())
+		}
+
+		zhtest.AssertEqual(t, 1, len(logger.infoLogs))
+		zhtest.AssertEqual(t, 1, len(logger.warnLogs))
+		zhtest.AssertEqual(t, 1, len(logger.errorLogs))
+	})
+
+	t.Run("slow requests are always logged", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			time.Sleep(5 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+		})
+		middleware := New(logger, Config{SampleRate: 100, SlowThreshold: time.Millisecond})(handler)
+
+		for range 3 {
+			zhtest.Serve(middleware, zhtest.NewRequest(http.MethodGet, "/api").Build())
+		}
+
+		zhtest.AssertEqual(t, 3, len(logger.warnLogs))
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		middleware := New(logger)(&statusTestHandler{statusCode: http.StatusOK})
+
+		for range 5 {
+			zhtest.Serve(middleware, zhtest.NewRequest(http.MethodGet, "/api").Build())
+		}
+
+		zhtest.AssertEqual(t, 5, len(logger.infoLogs))
+	})
+}
+
+func TestRequestLogger_HeaderFields(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(httpx.HeaderContentType, httpx.MIMETextPlain)
+		w.Header().Add(httpx.HeaderSetCookie, "session=abc")
+		w.Header().Add(httpx.HeaderSetCookie, "theme=dark")
+		w.WriteHeader(http.StatusOK)
+	})
+	req := zhtest.NewRequest(http.MethodGet, "/api").
+		WithHeader(httpx.HeaderAuthorization, "Bearer secret-token").
+		WithHeader(httpx.HeaderCookie, "session=abc").
+		WithHeader(httpx.HeaderAccept, httpx.MIMEApplicationJSON).
+		Build()
+
+	t.Run("default redaction", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		middleware := New(logger, Config{Fields: []LogField{FieldRequestHeaders, FieldResponseHeaders}})(handler)
+		zhtest.Serve(middleware, req)
+
+		zhtest.AssertEqual(t, 1, len(logger.infoLogs))
+		value, found := findFieldValue(logger.infoLogs[0].fields, "request_headers")
+		zhtest.AssertTrue(t, found)
+		headers := value.(map[string]string)
+		zhtest.AssertEqual(t, RedactedValue, headers[httpx.HeaderAuthorization])
+		zhtest.AssertEqual(t, RedactedValue, headers[httpx.HeaderCookie])
+		zhtest.AssertEqual(t, httpx.MIMEApplicationJSON, headers[httpx.HeaderAccept])
+
+		value, found = findFieldValue(logger.infoLogs[0].fields, "response_headers")
+		zhtest.AssertTrue(t, found)
+		headers = value.(map[string]string)
+		zhtest.AssertEqual(t, RedactedValue+", "+RedactedValue, headers[httpx.HeaderSetCookie])
+		zhtest.AssertEqual(t, httpx.MIMETextPlain, headers[httpx.HeaderContentType])
+	})
+
+	t.Run("custom redaction", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		middleware := New(logger, Config{
+			Fields:        []LogField{FieldRequestHeaders},
+			RedactHeaders: []string{"accept"},
+		})(handler)
+		zhtest.Serve(middleware, req)
+
+		value, _ := findFieldValue(logger.infoLogs[0].fields, "request_headers")
+		headers := value.(map[string]string)
+		zhtest.AssertEqual(t, RedactedValue, headers[httpx.HeaderAccept])
+		zhtest.AssertEqual(t, "Bearer secret-token", headers[httpx.HeaderAuthorization])
+	})
+
+	t.Run("not logged by default", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		zhtest.Serve(New(logger)(handler), req)
+
+		_, found := findFieldValue(logger.infoLogs[0].fields, "request_headers")
+		zhtest.AssertFalse(t, found)
+		_, found = findFieldValue(logger.infoLogs[0].fields, "response_headers")
+		zhtest.AssertFalse(t, found)
+	})
+
+	t.Run("request headers are not modified", func(t *testing.T) {
+		zhtest.AssertEqual(t, "Bearer secret-token", req.Header.Get(httpx.HeaderAuthorization))
+	})
+}
+
+func TestRedactHeaders(t *testing.T) {
+	h := http.Header{
+		"Authorization": {"Bearer secret-token"},
+		"x-api-key":     {"key-1", "key-2"},
+		"Accept":        {"*/*"},
+	}
+
+	redacted := RedactHeaders(h, []string{"authorization", "X-API-Key"})
+	zhtest.AssertEqual(t, []string{RedactedValue}, redacted["Authorization"])
+	zhtest.AssertEqual(t, []string{RedactedValue, RedactedValue}, redacted["x-api-key"])
+	zhtest.AssertEqual(t, []string{"*/*"}, redacted["Accept"])
+
+	// The original header is unchanged
+	zhtest.AssertEqual(t, "Bearer secret-token", h.Get("Authorization"))
+}
+
 func TestRequestLogger_NilFields(t *testing.T) {
 	logger := &requestLoggerMockLogger{}
 	handler := &statusTestHandler{statusCode: http.StatusOK}
@@ -265,12 +614,12 @@ func TestRequestLogger_MultipleOptions(t *testing.T) {
 func TestDefaultRequestLoggerConfig(t *testing.T) {
 	cfg := DefaultConfig
 	zhtest.AssertTrue(t, cfg.LogErrors)
-	zhtest.AssertEqual(t, 13, len(cfg.Fields))
+	zhtest.AssertEqual(t, 14, len(cfg.Fields))
 	zhtest.AssertEqual(t, 0, len(cfg.ExcludedPaths))
 
 	expectedFields := []LogField{
 		FieldMethod, FieldURI, FieldPath, FieldHost, FieldProtocol,
-		FieldReferer, FieldUserAgent, FieldStatus, FieldDurationNS,
+		FieldReferer, FieldUserAgent, FieldStatus, FieldBytesWritten, FieldDurationNS,
 		FieldDurationHuman, FieldRemoteAddr, FieldClientIP, FieldRequestID,
 	}
 	zhtest.AssertEqual(t, expectedFields, cfg.Fields)
@@ -460,6 +809,105 @@ func TestRequestLogger_MaxBodySize(t *testing.T) {
 	})
 }
 
+func TestRequestLogger_TruncatedRequestBodyReadInFull(t *testing.T) {
+	logger := &requestLoggerMockLogger{}
+	var received string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+	})
+	middleware := New(logger, Config{
+		Fields:         []LogField{FieldRequestBody},
+		LogRequestBody: true,
+		MaxBodySize:    10,
+	})(handler)
+
+	req := zhtest.NewRequest(http.MethodPost, "/test").
+		WithBytes([]byte(`this is a long request body`)).
+		Build()
+	zhtest.Serve(middleware, req)
+
+	zhtest.AssertEqual(t, "this is a long request body", received)
+	value, found := findFieldValue(logger.infoLogs[0].fields, "request_body")
+	zhtest.AssertTrue(t, found)
+	zhtest.AssertEqual(t, "this is a ...", value)
+}
+
+func TestRequestLogger_BinaryBodiesSkipped(t *testing.T) {
+	binary := []byte{0x89, 'P', 'N', 'G', 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00}
+
+	t.Run("request", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		var received []byte
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received, _ = io.ReadAll(r.Body)
+		})
+		middleware := New(logger, Config{
+			Fields:         []LogField{FieldRequestBody},
+			LogRequestBody: true,
+		})(handler)
+
+		req := zhtest.NewRequest(http.MethodPost, "/upload").
+			WithBytes(binary).
+			WithHeader(httpx.HeaderContentType, "application/octet-stream").
+			Build()
+		zhtest.Serve(middleware, req)
+
+		zhtest.AssertEqual(t, binary, received)
+		_, found := findFieldValue(logger.infoLogs[0].fields, "request_body")
+		zhtest.AssertFalse(t, found)
+	})
+
+	t.Run("response with content type", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(httpx.HeaderContentType, "image/png")
+			_, _ = w.Write([]byte("not really a png"))
+		})
+		middleware := New(logger, Config{
+			Fields:          []LogField{FieldResponseBody},
+			LogResponseBody: true,
+		})(handler)
+
+		recorder := zhtest.Serve(middleware, zhtest.NewRequest(http.MethodGet, "/image").Build())
+
+		zhtest.AssertEqual(t, "not really a png", recorder.Body.String())
+		_, found := findFieldValue(logger.infoLogs[0].fields, "response_body")
+		zhtest.AssertFalse(t, found)
+	})
+
+	t.Run("sniffed response", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(binary)
+		})
+		middleware := New(logger, Config{
+			Fields:          []LogField{FieldResponseBody},
+			LogResponseBody: true,
+		})(handler)
+
+		zhtest.Serve(middleware, zhtest.NewRequest(http.MethodGet, "/image").Build())
+
+		_, found := findFieldValue(logger.infoLogs[0].fields, "response_body")
+		zhtest.AssertFalse(t, found)
+	})
+
+	t.Run("structured text types are logged", func(t *testing.T) {
+		for _, contentType := range []string{
+			"application/json; charset=utf-8",
+			"application/problem+json",
+			"application/x-www-form-urlencoded",
+			"text/plain",
+			"application/atom+xml",
+		} {
+			zhtest.AssertTrue(t, isTextContentType(contentType))
+		}
+		for _, contentType := range []string{"application/octet-stream", "image/png", "multipart/form-data; boundary=x"} {
+			zhtest.AssertFalse(t, isTextContentType(contentType))
+		}
+	})
+}
+
 func TestRequestLogger_SensitiveFieldMasking(t *testing.T) {
 	t.Run("masks password field", func(t *testing.T) {
 		logger := &requestLoggerMockLogger{}
@@ -526,6 +974,75 @@ func TestRequestLogger_SensitiveFieldMasking(t *testing.T) {
 		zhtest.AssertContains(t, body, "John")
 	})
 
+	t.Run("form-encoded fields masked before truncation", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		handler := &statusTestHandler{statusCode: http.StatusOK}
+		middleware := New(logger, Config{
+			Fields:          []LogField{FieldRequestBody},
+			LogRequestBody:  true,
+			MaxBodySize:     20,
+			SensitiveFields: []string{"password"},
+		})(handler)
+
+		req := zhtest.NewRequest(http.MethodPost, "/login").
+			WithBytes([]byte(`user=bob&password=hunter2&remember=1`)).
+			WithHeader(httpx.HeaderContentType, httpx.MIMEApplicationFormURLEncoded).
+			Build()
+		zhtest.Serve(middleware, req)
+
+		value, found := findFieldValue(logger.infoLogs[0].fields, "request_body")
+		zhtest.AssertTrue(t, found)
+		zhtest.AssertEqual(t, "user=bob&password=[R...", value)
+
+		zhtest.AssertEqual(t, "a=1&Password=[REDACTED]&b=%20",
+			maskSensitiveData("a=1&Password=hunter2&b=%20", httpx.MIMEApplicationFormURLEncoded, []string{"password"}))
+	})
+
+	t.Run("truncated JSON isn't logged raw", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		middleware := New(logger, Config{
+			Fields:          []LogField{FieldRequestBody, FieldResponseBody},
+			LogRequestBody:  true,
+			LogResponseBody: true,
+			MaxBodySize:     20,
+			SensitiveFields: []string{"password"},
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"password":"hunter2","name":"bob"}`))
+		}))
+
+		req := zhtest.NewRequest(http.MethodPost, "/login").
+			WithBytes([]byte(`{"password":"hunter2","name":"bob"}`)).
+			WithHeader(httpx.HeaderContentType, httpx.MIMEApplicationJSON).
+			Build()
+		zhtest.Serve(middleware, req)
+
+		for _, field := range []string{"request_body", "response_body"} {
+			value, found := findFieldValue(logger.infoLogs[0].fields, field)
+			zhtest.AssertTrue(t, found)
+			zhtest.AssertEqual(t, "[REDACTED]...", value)
+		}
+	})
+
+	t.Run("complete JSON masked before truncation", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		handler := &statusTestHandler{statusCode: http.StatusOK}
+		middleware := New(logger, Config{
+			Fields:          []LogField{FieldRequestBody},
+			LogRequestBody:  true,
+			MaxBodySize:     24,
+			SensitiveFields: []string{"password"},
+		})(handler)
+
+		req := zhtest.NewRequest(http.MethodPost, "/login").
+			WithBytes([]byte(`{"password":"hunter2"}`)).
+			Build()
+		zhtest.Serve(middleware, req)
+
+		value, found := findFieldValue(logger.infoLogs[0].fields, "request_body")
+		zhtest.AssertTrue(t, found)
+		zhtest.AssertEqual(t, `{"password":"[REDACTED]"...`, value)
+	})
+
 	t.Run("nested object masking", func(t *testing.T) {
 		logger := &requestLoggerMockLogger{}
 		handler := &statusTestHandler{statusCode: http.StatusOK}
@@ -1027,3 +1544,102 @@ func TestRequestLogger_CustomFieldsCallback(t *testing.T) {
 		zhtest.AssertFalse(t, found)
 	})
 }
+
+func TestRequestLogger_FieldExtractors(t *testing.T) {
+	logger := &requestLoggerMockLogger{}
+	handler := &statusTestHandler{statusCode: http.StatusCreated}
+	middleware := New(logger, Config{
+		Fields: []LogField{FieldMethod},
+		FieldExtractors: map[string]func(r *http.Request, status int, d time.Duration) any{
+			"tenant_id": func(r *http.Request, status int, d time.Duration) any {
+				return r.Header.Get("X-Tenant-Id")
+			},
+			"created": func(r *http.Request, status int, d time.Duration) any {
+				return status == http.StatusCreated
+			},
+		},
+	})(handler)
+
+	req := zhtest.NewRequest(http.MethodPost, "/orders").WithHeader("X-Tenant-Id", "acme").Build()
+	zhtest.Serve(middleware, req)
+
+	zhtest.AssertEqual(t, 1, len(logger.infoLogs))
+	fields := logger.infoLogs[0].fields
+	zhtest.AssertEqual(t, 3, len(fields))
+	zhtest.AssertEqual(t, "method", fields[0].Key)
+	zhtest.AssertEqual(t, "created", fields[1].Key)
+	zhtest.AssertEqual(t, true, fields[1].Value)
+	zhtest.AssertEqual(t, "tenant_id", fields[2].Key)
+	zhtest.AssertEqual(t, "acme", fields[2].Value)
+}
+
+func TestRequestLogger_AccessLogFormats(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("hello"))
+	})
+
+	tests := []struct {
+		name     string
+		format   Format
+		expected string
+	}{
+		{"common", FormatCommon, `203.0.113.1 - - [` + "%s" + `] "POST /orders?id=1 HTTP/1.1" 201 5`},
+		{"combined", FormatCombined, `203.0.113.1 - - [` + "%s" + `] "POST /orders?id=1 HTTP/1.1" 201 5 "https://example.com/" "agent \"quoted\""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &requestLoggerMockLogger{}
+			middleware := New(logger, Config{Format: tt.format})(handler)
+
+			req := zhtest.NewRequest(http.MethodPost, "/orders?id=1").
+				WithHeader(httpx.HeaderReferer, "https://example.com/").
+				WithHeader(httpx.HeaderUserAgent, `agent "quoted"`).
+				Build()
+			req.RemoteAddr = "203.0.113.1:1234"
+			zhtest.Serve(middleware, req)
+
+			zhtest.AssertEqual(t, 1, len(logger.infoLogs))
+			entry := logger.infoLogs[0]
+			zhtest.AssertEqual(t, 0, len(entry.fields))
+
+			start, _, ok := strings.Cut(entry.message, "[")
+			zhtest.AssertTrue(t, ok)
+			timestamp, _, _ := strings.Cut(strings.TrimPrefix(entry.message, start+"["), "]")
+			_, err := time.Parse("02/Jan/2006:15:04:05 -0700", timestamp)
+			zhtest.AssertNoError(t, err)
+			zhtest.AssertEqual(t, fmt.Sprintf(tt.expected, timestamp), entry.message)
+		})
+	}
+
+	t.Run("empty responses and levels", func(t *testing.T) {
+		logger := &requestLoggerMockLogger{}
+		middleware := New(logger, Config{Format: FormatCombined, LogErrors: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+
+		zhtest.Serve(middleware, zhtest.NewRequest(http.MethodGet, "/").Build())
+
+		zhtest.AssertEqual(t, 1, len(logger.errorLogs))
+		zhtest.AssertTrue(t, strings.HasSuffix(logger.errorLogs[0].message, `"GET / HTTP/1.1" 500 - "-" "-"`))
+	})
+}
+
+func TestRequestLogger_BytesWrittenField(t *testing.T) {
+	logger := &requestLoggerMockLogger{}
+	middleware := New(logger)(&statusTestHandler{statusCode: http.StatusOK})
+
+	zhtest.Serve(middleware, zhtest.NewRequest(http.MethodGet, "/").Build())
+
+	zhtest.AssertEqual(t, 1, len(logger.infoLogs))
+	value, found := findFieldValue(logger.infoLogs[0].fields, "bytes_written")
+	zhtest.AssertTrue(t, found)
+	zhtest.AssertEqual(t, int64(len("test response")), value)
+
+	// The size is unknown when Log is called directly
+	logger = &requestLoggerMockLogger{}
+	Log(logger, DefaultConfig, nil, zhtest.NewRequest(http.MethodGet, "/").Build(), http.StatusOK, time.Millisecond, "", "")
+	_, found = findFieldValue(logger.infoLogs[0].fields, "bytes_written")
+	zhtest.AssertFalse(t, found)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/alexferl/zerohttp/config"
 	"github.com/alexferl/zerohttp/httpx"
 	"github.com/alexferl/zerohttp/internal/mwutil"
+	"github.com/alexferl/zerohttp/internal/rwutil"
 	"github.com/alexferl/zerohttp/metrics"
 )
 
@@ -34,7 +35,9 @@ type proxyResponseRecorder struct {
 }
 
 func (rec *proxyResponseRecorder) WriteHeader(code int) {
-	rec.statusCode = code
+	if !rwutil.IsInformational(code) {
+		rec.statusCode = code
+	}
 	rec.ResponseWriter.WriteHeader(code)
 }
 
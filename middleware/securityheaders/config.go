@@ -93,7 +93,15 @@ type Config struct {
 	// Default: "same-origin"
 	CrossOriginResourcePolicy string
 
+	// CrossOriginResourcePolicyExcludedPaths contains paths that don't get the
+	// `Cross-Origin-Resource-Policy` header, such as public assets or images
+	// embedded by other sites, while the other headers still apply.
+	// Supports exact matches, prefixes (ending with /), and wildcards (ending with *).
+	// Default: []
+	CrossOriginResourcePolicyExcludedPaths []string
+
 	// PermissionsPolicy sets the `Permissions-Policy` header.
+	// Use NewPermissionsPolicy or DefaultPermissionsPolicy to build the value.
 	// Default: permission policy features (accelerometer, camera, geolocation, etc.)
 	PermissionsPolicy string
 
@@ -117,6 +125,34 @@ type Config struct {
 	// Default: "DENY"
 	XFrameOptions string
 
+	// XPermittedCrossDomainPolicies sets the `X-Permitted-Cross-Domain-Policies`
+	// header, which controls cross-domain policy files used by Adobe clients
+	// such as Acrobat, e.g. "none".
+	// Default: "" (not set)
+	XPermittedCrossDomainPolicies string
+
+	// XDNSPrefetchControl sets the `X-DNS-Prefetch-Control` header, "on" or
+	// "off", which controls whether browsers prefetch DNS for links on the page.
+	// Default: "" (not set)
+	XDNSPrefetchControl string
+
+	// ExtraHeaders are static headers set on every response along with the
+	// security headers, for headers not covered by the fields above. They
+	// are set last, so they override headers of the same name.
+	// Default: nil
+	ExtraHeaders map[string]string
+
+	// DocumentContentTypes restricts the document-only headers
+	// (Content-Security-Policy, Content-Security-Policy-Report-Only,
+	// Permissions-Policy and X-Frame-Options) to responses with one of these
+	// media types, e.g. []string{"text/html"}. Entries may use a wildcard
+	// subtype such as "text/*". Since the content type is only known once the
+	// handler writes, these headers are removed from non-matching responses
+	// right before the headers are sent. Responses without a Content-Type keep
+	// the headers.
+	// Default: [] (headers apply to all responses)
+	DocumentContentTypes []string
+
 	// ExcludedPaths contains paths to skip security headers.
 	// Supports exact matches, prefixes (ending with /), and wildcards (ending with *).
 	// Cannot be used with IncludedPaths - setting both will panic.
@@ -134,15 +170,17 @@ type Config struct {
 
 // DefaultConfig contains the default values for security headers configuration.
 var DefaultConfig = Config{
-	ContentSecurityPolicy:     "default-src 'none'; script-src 'self'; connect-src 'self'; img-src 'self'; style-src 'self'; frame-ancestors 'self'; form-action 'self';",
-	CrossOriginEmbedderPolicy: "require-corp",
-	CrossOriginOpenerPolicy:   "same-origin",
-	CrossOriginResourcePolicy: "same-origin",
-	PermissionsPolicy:         strings.Join(permissionPolicyFeatures, ", "),
-	ReferrerPolicy:            "no-referrer",
-	StrictTransportSecurity:   DefaultStrictTransportSecurity,
-	XContentTypeOptions:       "nosniff",
-	XFrameOptions:             "DENY",
-	ExcludedPaths:             []string{},
-	IncludedPaths:             []string{},
+	ContentSecurityPolicy:                  "default-src 'none'; script-src 'self'; connect-src 'self'; img-src 'self'; style-src 'self'; frame-ancestors 'self'; form-action 'self';",
+	CrossOriginEmbedderPolicy:              "require-corp",
+	CrossOriginOpenerPolicy:                "same-origin",
+	CrossOriginResourcePolicy:              "same-origin",
+	PermissionsPolicy:                      strings.Join(permissionPolicyFeatures, ", "),
+	ReferrerPolicy:                         "no-referrer",
+	StrictTransportSecurity:                DefaultStrictTransportSecurity,
+	XContentTypeOptions:                    "nosniff",
+	XFrameOptions:                          "DENY",
+	DocumentContentTypes:                   []string{},
+	ExcludedPaths:                          []string{},
+	IncludedPaths:                          []string{},
+	CrossOriginResourcePolicyExcludedPaths: []string{},
 }
@@ -21,6 +21,7 @@ func TestSecurityHeadersConfig_DefaultValues(t *testing.T) {
 	zhtest.AssertEqual(t, "nosniff", cfg.XContentTypeOptions)
 	zhtest.AssertEqual(t, "DENY", cfg.XFrameOptions)
 	zhtest.AssertEqual(t, 0, len(cfg.ExcludedPaths))
+	zhtest.AssertEqual(t, 0, len(cfg.DocumentContentTypes))
 	zhtest.AssertEqual(t, 0, len(cfg.IncludedPaths))
 
 	// Test default HSTS values
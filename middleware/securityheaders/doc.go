@@ -25,13 +25,69 @@
 //
 // # CSP Nonces
 //
-// Enable nonce generation for inline scripts:
+// Enable nonce generation to allow inline scripts and styles without
+// 'unsafe-inline'. A fresh nonce replaces every "{{nonce}}" in the policy
+// on each request:
 //
 //	app.Use(securityheaders.New(securityheaders.Config{
-//	    ContentSecurityPolicy:             "script-src 'nonce-{{nonce}}'",
+//	    ContentSecurityPolicy:             "script-src 'nonce-{{nonce}}'; style-src 'self' 'nonce-{{nonce}}'",
 //	    ContentSecurityPolicyNonceEnabled: true,
 //	}))
 //
-//	// Access nonce in handler:
-//	nonce := r.Context().Value(securityheaders.CSPNonceContextKey)
+// Handlers pass the nonce to their templates with GetCSPNonce, so the
+// nonce attributes match the header:
+//
+//	nonce := securityheaders.GetCSPNonce(r)
+//	// <script nonce="{{.Nonce}}">...</script>
+//
+// Nonces only apply to <script> and <style> elements, not to inline style
+// attributes or event handlers, which must move to those elements.
+//
+// # Permissions Policy
+//
+// Build the Permissions-Policy value instead of writing the syntax by hand.
+// [DefaultPermissionsPolicy] starts from the default deny-all policy:
+//
+//	app.Use(securityheaders.New(securityheaders.Config{
+//	    PermissionsPolicy: securityheaders.DefaultPermissionsPolicy().
+//	        Allow("geolocation", "self", "https://maps.example.com").
+//	        Allow("fullscreen").
+//	        Build(),
+//	}))
+//
+// # Document Content Types
+//
+// Content-Security-Policy, Permissions-Policy and X-Frame-Options only apply
+// to documents. Set DocumentContentTypes to leave them off other responses,
+// such as JSON from an API:
+//
+//	app.Use(securityheaders.New(securityheaders.Config{
+//	    DocumentContentTypes: []string{"text/html", "application/xhtml+xml"},
+//	}))
+//
+// # Additional Headers
+//
+// X-Permitted-Cross-Domain-Policies and X-DNS-Prefetch-Control are set when
+// configured, and ExtraHeaders sets any other static header, overriding the
+// built-in ones of the same name. CrossOriginResourcePolicyExcludedPaths
+// leaves Cross-Origin-Resource-Policy off assets that other sites embed:
+//
+//	app.Use(securityheaders.New(securityheaders.Config{
+//	    XPermittedCrossDomainPolicies:          "none",
+//	    XDNSPrefetchControl:                    "off",
+//	    CrossOriginResourcePolicyExcludedPaths: []string{"/public/"},
+//	    ExtraHeaders: map[string]string{
+//	        "Origin-Agent-Cluster": "?1",
+//	    },
+//	}))
+//
+// # Per-Response Exemptions
+//
+// Headers are set before the handler runs, so a handler can drop one for a
+// single response with [Disable]:
+//
+//	app.GET("/widget", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+//	    securityheaders.Disable(w, r, httpx.HeaderXFrameOptions) // Allow embedding
+//	    return zh.R.HTML(w, http.StatusOK, widgetHTML)
+//	}))
 package securityheaders
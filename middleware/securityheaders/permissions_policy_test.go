@@ -0,0 +1,51 @@
+package securityheaders
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestPermissionsPolicyBuilder(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *PermissionsPolicyBuilder
+		expected string
+	}{
+		{"empty", NewPermissionsPolicy(), ""},
+		{"deny", NewPermissionsPolicy().Deny("camera"), "camera=()"},
+		{"allow self by default", NewPermissionsPolicy().Allow("geolocation"), "geolocation=(self)"},
+		{"allow keywords", NewPermissionsPolicy().Allow("autoplay", "self", "src"), "autoplay=(self src)"},
+		{"allow origins quoted", NewPermissionsPolicy().Allow("geolocation", "self", "https://maps.example.com"), `geolocation=(self "https://maps.example.com")`},
+		{"allow all", NewPermissionsPolicy().Allow("fullscreen", "self", "*"), "fullscreen=*"},
+		{"multiple features in order", NewPermissionsPolicy().Allow("geolocation", "self").Deny("camera").Deny("microphone"), "geolocation=(self), camera=(), microphone=()"},
+		{"replace keeps position", NewPermissionsPolicy().Deny("camera").Deny("usb").Allow("camera"), "camera=(self), usb=()"},
+		{"remove", NewPermissionsPolicy().Deny("camera").Deny("usb").Remove("camera").Remove("missing"), "usb=()"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zhtest.AssertEqual(t, tt.expected, tt.builder.Build())
+		})
+	}
+}
+
+func TestDefaultPermissionsPolicy(t *testing.T) {
+	zhtest.AssertEqual(t, DefaultConfig.PermissionsPolicy, DefaultPermissionsPolicy().Build())
+
+	policy := DefaultPermissionsPolicy().Allow("geolocation").Build()
+	zhtest.AssertContains(t, policy, "geolocation=(self)")
+	zhtest.AssertContains(t, policy, "camera=()")
+	zhtest.AssertNotContains(t, policy, "geolocation=()")
+}
+
+func TestSecurityHeaders_PermissionsPolicyBuilder(t *testing.T) {
+	policy := NewPermissionsPolicy().Allow("geolocation", "self").Deny("camera").Build()
+	handler := New(Config{PermissionsPolicy: policy})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/").Build())
+	zhtest.AssertWith(t, w).Header(httpx.HeaderPermissionsPolicy, "geolocation=(self), camera=()")
+}
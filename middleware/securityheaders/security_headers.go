@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/alexferl/zerohttp/httpx"
 	zconfig "github.com/alexferl/zerohttp/internal/config"
 	"github.com/alexferl/zerohttp/internal/mwutil"
+	"github.com/alexferl/zerohttp/internal/rwutil"
 )
 
 // New creates a security headers middleware with the provided configuration
@@ -23,6 +25,11 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 
 	mwutil.ValidatePathConfig(c.ExcludedPaths, c.IncludedPaths, "SecurityHeaders")
 
+	documentTypes := make([]string, 0, len(c.DocumentContentTypes))
+	for _, ct := range c.DocumentContentTypes {
+		documentTypes = append(documentTypes, strings.ToLower(strings.TrimSpace(ct)))
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if !mwutil.ShouldProcessMiddleware(r.URL.Path, c.IncludedPaths, c.ExcludedPaths) {
@@ -59,7 +66,7 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 			if c.CrossOriginOpenerPolicy != "" {
 				w.Header().Set(httpx.HeaderCrossOriginOpenerPolicy, c.CrossOriginOpenerPolicy)
 			}
-			if c.CrossOriginResourcePolicy != "" {
+			if c.CrossOriginResourcePolicy != "" && !matchesAnyPath(r.URL.Path, c.CrossOriginResourcePolicyExcludedPaths) {
 				w.Header().Set(httpx.HeaderCrossOriginResourcePolicy, c.CrossOriginResourcePolicy)
 			}
 
@@ -95,11 +102,156 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 				w.Header().Set(httpx.HeaderXFrameOptions, c.XFrameOptions)
 			}
 
+			if c.XPermittedCrossDomainPolicies != "" {
+				w.Header().Set(httpx.HeaderXPermittedCrossDomainPolicies, c.XPermittedCrossDomainPolicies)
+			}
+
+			if c.XDNSPrefetchControl != "" {
+				w.Header().Set(httpx.HeaderXDNSPrefetchControl, c.XDNSPrefetchControl)
+			}
+
+			for name, value := range c.ExtraHeaders {
+				w.Header().Set(name, value)
+			}
+
+			// Headers disabled by the handler are removed when the response is
+			// written rather than from the handler's writer, which may be a
+			// wrapper with its own header map, such as the timeout middleware's
+			disabled := &disabledHeaders{}
+			r = r.WithContext(context.WithValue(r.Context(), disabledHeadersKey{}, disabled))
+			w = &securityHeaderWriter{ResponseWriter: w, contentTypes: documentTypes, disabled: disabled}
+
 			next.ServeHTTP(w, r)
 		})
 	}
 }
 
+// disabledHeadersKey is the context key for the headers disabled for a request.
+type disabledHeadersKey struct{}
+
+// disabledHeaders holds the names of the headers disabled with Disable. The
+// handler may run in another goroutine than the one writing the response,
+// such as with the timeout middleware.
+type disabledHeaders struct {
+	mu    sync.Mutex
+	names []string
+}
+
+// Disable removes the named security headers from the response to r.
+// Use it in a handler to opt a single response out of a header, for
+// example to drop X-Frame-Options on an embeddable widget. It must be
+// called before the response is written. The headers are also removed when
+// the middleware sends the response, so writers with their own header map
+// between the middleware and the handler don't bring them back.
+//
+//	securityheaders.Disable(w, r, httpx.HeaderXFrameOptions)
+func Disable(w http.ResponseWriter, r *http.Request, headers ...string) {
+	h := w.Header()
+	for _, name := range headers {
+		h.Del(name)
+	}
+
+	if d, ok := r.Context().Value(disabledHeadersKey{}).(*disabledHeaders); ok {
+		d.mu.Lock()
+		d.names = append(d.names, headers...)
+		d.mu.Unlock()
+	}
+}
+
+// documentHeaders are only meaningful for documents rendered by a browser.
+var documentHeaders = []string{
+	httpx.HeaderContentSecurityPolicy,
+	httpx.HeaderContentSecurityPolicyReportOnly,
+	httpx.HeaderPermissionsPolicy,
+	httpx.HeaderXFrameOptions,
+}
+
+// securityHeaderWriter removes the headers disabled with Disable, and the
+// document-only headers if the response content type doesn't match, before
+// the response headers are sent.
+type securityHeaderWriter struct {
+	http.ResponseWriter
+	contentTypes []string
+	disabled     *disabledHeaders
+	wroteHeader  bool
+}
+
+func (dw *securityHeaderWriter) WriteHeader(code int) {
+	// The content type is only known with the final response
+	if !rwutil.IsInformational(code) {
+		dw.finalize()
+	}
+	dw.ResponseWriter.WriteHeader(code)
+}
+
+func (dw *securityHeaderWriter) Write(p []byte) (int, error) {
+	dw.finalize()
+	return dw.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher.
+func (dw *securityHeaderWriter) Flush() {
+	dw.finalize()
+	if f, ok := dw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (dw *securityHeaderWriter) Unwrap() http.ResponseWriter {
+	return dw.ResponseWriter
+}
+
+func (dw *securityHeaderWriter) finalize() {
+	if dw.wroteHeader {
+		return
+	}
+	dw.wroteHeader = true
+
+	h := dw.ResponseWriter.Header()
+
+	dw.disabled.mu.Lock()
+	for _, name := range dw.disabled.names {
+		h.Del(name)
+	}
+	dw.disabled.mu.Unlock()
+
+	contentType := h.Get(httpx.HeaderContentType)
+	if len(dw.contentTypes) == 0 || contentType == "" || matchesContentType(contentType, dw.contentTypes) {
+		return
+	}
+	for _, name := range documentHeaders {
+		h.Del(name)
+	}
+}
+
+// matchesContentType reports whether the media type of contentType matches
+// one of types, which may use a wildcard subtype ("text/*").
+func matchesContentType(contentType string, types []string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	mainType, _, _ := strings.Cut(mediaType, "/")
+
+	for _, t := range types {
+		if t == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(t, "/*"); ok && prefix == mainType {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAnyPath reports whether path matches one of the patterns.
+func matchesAnyPath(path string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if mwutil.PathMatches(path, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
 // isHTTPS checks if the request is over HTTPS
 func isHTTPS(r *http.Request) bool {
 	return r.TLS != nil ||
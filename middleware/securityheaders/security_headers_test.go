@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/middleware/timeout"
 	"github.com/alexferl/zerohttp/zhtest"
 )
 
@@ -415,3 +416,152 @@ func TestSecurityHeaders_BothExcludedAndIncludedPathsPanics(t *testing.T) {
 		})
 	}, "cannot set both ExcludedPaths and IncludedPaths")
 }
+
+func TestSecurityHeaders_Disable(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/widget", func(w http.ResponseWriter, r *http.Request) {
+		Disable(w, r, httpx.HeaderXFrameOptions, "content-security-policy")
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := New()(mux)
+
+	w := zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/widget").Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusOK).
+		HeaderNotExists(httpx.HeaderXFrameOptions).
+		HeaderNotExists(httpx.HeaderContentSecurityPolicy).
+		Header(httpx.HeaderXContentTypeOptions, "nosniff")
+
+	// Other responses keep the headers
+	w = zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/page").Build())
+	zhtest.AssertWith(t, w).
+		Header(httpx.HeaderXFrameOptions, "DENY").
+		HeaderExists(httpx.HeaderContentSecurityPolicy)
+
+	t.Run("through a writer with its own headers", func(t *testing.T) {
+		handler := New()(timeout.New()(mux))
+
+		w := zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/widget").Build())
+		zhtest.AssertWith(t, w).
+			Status(http.StatusOK).
+			HeaderNotExists(httpx.HeaderXFrameOptions).
+			HeaderNotExists(httpx.HeaderContentSecurityPolicy).
+			Header(httpx.HeaderXContentTypeOptions, "nosniff")
+
+		w = zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/page").Build())
+		zhtest.AssertWith(t, w).Header(httpx.HeaderXFrameOptions, "DENY")
+	})
+}
+
+func TestSecurityHeaders_DocumentContentTypes(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(httpx.HeaderContentType, "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(httpx.HeaderContentType, "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/css", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(httpx.HeaderContentType, "TEXT/CSS")
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/untyped", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(httpx.HeaderContentType, "text/event-stream")
+		w.(http.Flusher).Flush()
+	})
+
+	handler := New(Config{
+		DocumentContentTypes:            []string{"text/html", "text/*"},
+		ContentSecurityPolicyReportOnly: true,
+	})(mux)
+
+	tests := []struct {
+		path         string
+		wantDocument bool
+	}{
+		{"/api", false},
+		{"/page", true},
+		{"/css", true},
+		{"/untyped", true},
+		{"/stream", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, tt.path).Build())
+
+			a := zhtest.AssertWith(t, w).
+				Header(httpx.HeaderXContentTypeOptions, "nosniff").
+				Header(httpx.HeaderReferrerPolicy, "no-referrer")
+			if tt.wantDocument {
+				a.HeaderExists(httpx.HeaderContentSecurityPolicyReportOnly).
+					HeaderExists(httpx.HeaderPermissionsPolicy).
+					Header(httpx.HeaderXFrameOptions, "DENY")
+			} else {
+				a.HeaderNotExists(httpx.HeaderContentSecurityPolicyReportOnly).
+					HeaderNotExists(httpx.HeaderPermissionsPolicy).
+					HeaderNotExists(httpx.HeaderXFrameOptions)
+			}
+		})
+	}
+}
+
+func TestSecurityHeaders_DocumentContentTypesJSONOnly(t *testing.T) {
+	handler := New(Config{DocumentContentTypes: []string{"text/html"}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(httpx.HeaderContentType, "application/problem+json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+
+	w := zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/").Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusBadRequest).
+		HeaderNotExists(httpx.HeaderContentSecurityPolicy)
+}
+
+func TestSecurityHeaders_CrossOriginResourcePolicyExcludedPaths(t *testing.T) {
+	mw := New(Config{CrossOriginResourcePolicyExcludedPaths: []string{"/public/"}})
+
+	w := zhtest.TestMiddleware(mw, zhtest.NewRequest(http.MethodGet, "/public/logo.png").Build())
+	zhtest.AssertWith(t, w).
+		HeaderNotExists(httpx.HeaderCrossOriginResourcePolicy).
+		Header(httpx.HeaderXContentTypeOptions, "nosniff")
+
+	w = zhtest.TestMiddleware(mw, zhtest.NewRequest(http.MethodGet, "/api/users").Build())
+	zhtest.AssertWith(t, w).Header(httpx.HeaderCrossOriginResourcePolicy, "same-origin")
+}
+
+func TestSecurityHeaders_AdditionalHeaders(t *testing.T) {
+	w := zhtest.TestMiddleware(New(), zhtest.NewRequest(http.MethodGet, "/").Build())
+	zhtest.AssertWith(t, w).
+		HeaderNotExists(httpx.HeaderXPermittedCrossDomainPolicies).
+		HeaderNotExists(httpx.HeaderXDNSPrefetchControl)
+
+	w = zhtest.TestMiddleware(New(Config{
+		XPermittedCrossDomainPolicies: "none",
+		XDNSPrefetchControl:           "off",
+	}), zhtest.NewRequest(http.MethodGet, "/").Build())
+	zhtest.AssertWith(t, w).
+		Header(httpx.HeaderXPermittedCrossDomainPolicies, "none").
+		Header(httpx.HeaderXDNSPrefetchControl, "off")
+}
+
+func TestSecurityHeaders_ExtraHeaders(t *testing.T) {
+	w := zhtest.TestMiddleware(New(Config{
+		ExtraHeaders: map[string]string{
+			"Origin-Agent-Cluster": "?1",
+			"X-Frame-Options":      "SAMEORIGIN",
+		},
+	}), zhtest.NewRequest(http.MethodGet, "/").Build())
+
+	zhtest.AssertWith(t, w).
+		Header("Origin-Agent-Cluster", "?1").
+		Header(httpx.HeaderXFrameOptions, "SAMEORIGIN")
+}
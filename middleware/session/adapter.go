@@ -0,0 +1,101 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	zconfig "github.com/alexferl/zerohttp/internal/config"
+	"github.com/alexferl/zerohttp/storage"
+)
+
+// Codec handles serialization and deserialization of session values.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONCodec is the default codec using encoding/json.
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (JSONCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// StorageAdapterConfig configures the StorageAdapter.
+type StorageAdapterConfig struct {
+	// KeyPrefix is the prefix for session keys.
+	// Default: "session:"
+	KeyPrefix string
+
+	// Codec is the serialization codec for session values.
+	// Default: JSONCodec
+	Codec Codec
+}
+
+// DefaultStorageAdapterConfig is the default configuration for StorageAdapter.
+var DefaultStorageAdapterConfig = StorageAdapterConfig{
+	KeyPrefix: "session:",
+	Codec:     JSONCodec{},
+}
+
+// StorageAdapter wraps a storage.Storage to implement the session.Store interface.
+type StorageAdapter struct {
+	store     storage.Storage
+	keyPrefix string
+	codec     Codec
+}
+
+// NewStorageAdapter creates a session.Store from a storage.Storage.
+func NewStorageAdapter(s storage.Storage, cfg ...StorageAdapterConfig) Store {
+	c := DefaultStorageAdapterConfig
+	if len(cfg) > 0 {
+		zconfig.Merge(&c, cfg[0])
+	}
+	return &StorageAdapter{
+		store:     s,
+		keyPrefix: c.KeyPrefix,
+		codec:     c.Codec,
+	}
+}
+
+func (a *StorageAdapter) prefixKey(token string) string {
+	return a.keyPrefix + token
+}
+
+// Load retrieves the session values for the given token.
+func (a *StorageAdapter) Load(ctx context.Context, token string) (map[string]string, bool, error) {
+	data, found, err := a.store.Get(ctx, a.prefixKey(token))
+	if !found || err != nil {
+		return nil, false, err
+	}
+
+	var values map[string]string
+	if err := a.codec.Unmarshal(data, &values); err != nil {
+		return nil, false, err
+	}
+
+	return values, true, nil
+}
+
+// Save stores the session values with the given TTL and returns the token unchanged.
+func (a *StorageAdapter) Save(ctx context.Context, token string, values map[string]string, ttl time.Duration) (string, error) {
+	data, err := a.codec.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+
+	if err := a.store.Set(ctx, a.prefixKey(token), data, ttl); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Delete removes the session for the given token.
+func (a *StorageAdapter) Delete(ctx context.Context, token string) error {
+	return a.store.Delete(ctx, a.prefixKey(token))
+}
+
+// Close releases resources associated with the underlying storage.
+func (a *StorageAdapter) Close() error {
+	return a.store.Close()
+}
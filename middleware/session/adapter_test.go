@@ -0,0 +1,87 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+// mockStorage is a test implementation of storage.Storage
+type mockStorage struct {
+	data    map[string][]byte
+	ttlVals map[string]time.Duration
+}
+
+func newMockStorage() *mockStorage {
+	return &mockStorage{
+		data:    make(map[string][]byte),
+		ttlVals: make(map[string]time.Duration),
+	}
+}
+
+func (m *mockStorage) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	val, ok := m.data[key]
+	return val, ok, nil
+}
+
+func (m *mockStorage) Set(ctx context.Context, key string, val []byte, ttl time.Duration) error {
+	m.data[key] = val
+	m.ttlVals[key] = ttl
+	return nil
+}
+
+func (m *mockStorage) Delete(ctx context.Context, key string) error {
+	delete(m.data, key)
+	delete(m.ttlVals, key)
+	return nil
+}
+
+func (m *mockStorage) Close() error {
+	return nil
+}
+
+func TestSessionStorageAdapter(t *testing.T) {
+	ctx := context.Background()
+	s := newMockStorage()
+	adapter := NewStorageAdapter(s)
+
+	token, err := adapter.Save(ctx, "abc", map[string]string{"k": "v"}, time.Minute)
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertEqual(t, "abc", token)
+	zhtest.AssertEqual(t, time.Minute, s.ttlVals["session:abc"])
+
+	values, found, err := adapter.Load(ctx, "abc")
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertTrue(t, found)
+	zhtest.AssertEqual(t, "v", values["k"])
+
+	zhtest.AssertNoError(t, adapter.Delete(ctx, "abc"))
+	_, found, err = adapter.Load(ctx, "abc")
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertFalse(t, found)
+
+	zhtest.AssertNoError(t, adapter.(*StorageAdapter).Close())
+}
+
+func TestSessionStorageAdapter_CustomPrefix(t *testing.T) {
+	s := newMockStorage()
+	adapter := NewStorageAdapter(s, StorageAdapterConfig{KeyPrefix: "sess:"})
+
+	_, err := adapter.Save(context.Background(), "abc", map[string]string{}, time.Minute)
+	zhtest.AssertNoError(t, err)
+
+	_, ok := s.data["sess:abc"]
+	zhtest.AssertTrue(t, ok)
+}
+
+func TestSessionStorageAdapter_InvalidData(t *testing.T) {
+	s := newMockStorage()
+	s.data["session:abc"] = []byte("not json")
+	adapter := NewStorageAdapter(s)
+
+	_, found, err := adapter.Load(context.Background(), "abc")
+	zhtest.AssertError(t, err)
+	zhtest.AssertFalse(t, found)
+}
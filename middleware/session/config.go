@@ -0,0 +1,88 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/alexferl/zerohttp/config"
+)
+
+// Store is the interface for session storage backends.
+type Store interface {
+	// Load retrieves the session values for the given token.
+	// Returns the values, true if found, and any error.
+	// If not found or expired, returns nil, false and nil error.
+	Load(ctx context.Context, token string) (map[string]string, bool, error)
+
+	// Save stores the session values with the given TTL and returns the
+	// token to send to the client in the session cookie. Server-side stores
+	// return the token unchanged; client-side stores may return an encoded
+	// form of the values.
+	Save(ctx context.Context, token string, values map[string]string, ttl time.Duration) (string, error)
+
+	// Delete removes the session for the given token.
+	// Returns an error if the operation fails.
+	Delete(ctx context.Context, token string) error
+}
+
+// Config configures the session middleware.
+type Config struct {
+	// Store is the session store implementation.
+	// If nil, an in-memory store is used.
+	// Default: nil
+	Store Store
+
+	// CookieName is the name of the session cookie.
+	// Default: "session_id"
+	CookieName string
+
+	// CookieDomain sets the domain for the session cookie.
+	// Default: "" (current domain only)
+	CookieDomain string
+
+	// CookiePath sets the path for the session cookie.
+	// Default: "/"
+	CookiePath string
+
+	// CookieSecure sets the Secure flag on the cookie.
+	// Use a pointer to distinguish between "not set" and "explicitly set to false".
+	// Default: true
+	CookieSecure *bool
+
+	// CookieSameSite sets the SameSite attribute.
+	// Default: http.SameSiteLaxMode
+	CookieSameSite http.SameSite
+
+	// Lifetime is how long a session lives after it was last saved.
+	// It is used both as the store TTL and the cookie Max-Age.
+	// Default: 24h
+	Lifetime time.Duration
+
+	// ExcludedPaths contains paths that skip session loading.
+	// Supports exact matches, prefixes (ending with /), and wildcards (ending with *).
+	// Cannot be used with IncludedPaths - setting both will panic.
+	// Default: []
+	ExcludedPaths []string
+
+	// IncludedPaths contains paths where sessions are explicitly loaded.
+	// If set, sessions will only be loaded for paths matching these patterns.
+	// Supports exact matches, prefixes (ending with /), and wildcards (ending with *).
+	// If empty, sessions are loaded for all paths (subject to ExcludedPaths).
+	// Cannot be used with ExcludedPaths - setting both will panic.
+	// Default: []
+	IncludedPaths []string
+}
+
+// DefaultConfig contains the default values for session configuration.
+var DefaultConfig = Config{
+	Store:          nil,
+	CookieName:     "session_id",
+	CookieDomain:   "",
+	CookiePath:     "/",
+	CookieSecure:   config.Bool(true),
+	CookieSameSite: http.SameSiteLaxMode,
+	Lifetime:       24 * time.Hour,
+	ExcludedPaths:  []string{},
+	IncludedPaths:  []string{},
+}
@@ -0,0 +1,33 @@
+package session
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestSessionConfig_DefaultValues(t *testing.T) {
+	cfg := DefaultConfig
+
+	zhtest.AssertNil(t, cfg.Store)
+	zhtest.AssertEqual(t, "session_id", cfg.CookieName)
+	zhtest.AssertEmpty(t, cfg.CookieDomain)
+	zhtest.AssertEqual(t, "/", cfg.CookiePath)
+	zhtest.AssertNotNil(t, cfg.CookieSecure)
+	zhtest.AssertTrue(t, *cfg.CookieSecure)
+	zhtest.AssertEqual(t, http.SameSiteLaxMode, cfg.CookieSameSite)
+	zhtest.AssertEqual(t, 24*time.Hour, cfg.Lifetime)
+	zhtest.AssertEqual(t, 0, len(cfg.ExcludedPaths))
+	zhtest.AssertEqual(t, 0, len(cfg.IncludedPaths))
+}
+
+func TestSessionConfig_PathValidation(t *testing.T) {
+	zhtest.AssertPanic(t, func() {
+		New(Config{
+			ExcludedPaths: []string{"/a"},
+			IncludedPaths: []string{"/b"},
+		})
+	})
+}
@@ -0,0 +1,48 @@
+// Package session provides cookie-based session middleware.
+//
+// The session is loaded from the session cookie before the handler runs and
+// saved right before the response headers are written. Handlers read and
+// modify it through the package functions.
+//
+// # Usage
+//
+//	import "github.com/alexferl/zerohttp/middleware/session"
+//
+//	// In-memory store (default)
+//	app.Use(session.New())
+//
+//	app.POST("/login", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+//	    // ... authenticate user
+//	    session.RenewID(r) // Prevent session fixation
+//	    session.Set(r, "user_id", user.ID)
+//	    return zh.R.NoContent(w)
+//	}))
+//
+//	app.GET("/me", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+//	    userID := session.Get(r, "user_id")
+//	    // ...
+//	}))
+//
+//	app.POST("/logout", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+//	    session.Destroy(r)
+//	    return zh.R.NoContent(w)
+//	}))
+//
+// # Cookie Store
+//
+// [CookieStore] keeps the session in the cookie itself, encrypted with AES-GCM,
+// so no server-side state is needed:
+//
+//	app.Use(session.New(session.Config{
+//	    Store: session.NewCookieStore([]byte(os.Getenv("SESSION_KEY"))), // 32 bytes
+//	}))
+//
+// # Custom Store
+//
+// Implement the [Store] interface, or use [NewStorageAdapter] to wrap a
+// [storage.Storage] implementation such as Redis:
+//
+//	app.Use(session.New(session.Config{
+//	    Store: session.NewStorageAdapter(myRedisStorage),
+//	}))
+package session
@@ -0,0 +1,291 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"net/http"
+	"sync"
+
+	"github.com/alexferl/zerohttp/config"
+	zconfig "github.com/alexferl/zerohttp/internal/config"
+	"github.com/alexferl/zerohttp/internal/mwutil"
+	"github.com/alexferl/zerohttp/internal/rwutil"
+	"github.com/alexferl/zerohttp/log"
+	"github.com/alexferl/zerohttp/metrics"
+)
+
+// contextKey is the key type for the session in context.
+type contextKey struct{}
+
+// ContextKey is the context key for the session.
+var ContextKey = contextKey{}
+
+// tokenLength is the length of generated session tokens in bytes.
+const tokenLength = 32
+
+// session holds the values of a single session for the duration of a request.
+type session struct {
+	mu        sync.Mutex
+	token     string
+	values    map[string]string
+	modified  bool
+	renew     bool
+	destroyed bool
+}
+
+// New creates a session middleware with the provided configuration.
+// The session is loaded from the cookie before calling the next handler and
+// saved before the response headers are written.
+func New(cfg ...Config) func(http.Handler) http.Handler {
+	c := DefaultConfig
+	if len(cfg) > 0 {
+		zconfig.Merge(&c, cfg[0])
+	}
+
+	store := c.Store
+	if store == nil {
+		store = NewMemoryStore()
+	}
+
+	mwutil.ValidatePathConfig(c.ExcludedPaths, c.IncludedPaths, "Session")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !mwutil.ShouldProcessMiddleware(r.URL.Path, c.IncludedPaths, c.ExcludedPaths) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			reg := metrics.SafeRegistry(metrics.GetRegistry(r.Context()))
+
+			s := &session{values: make(map[string]string)}
+			if cookie, err := r.Cookie(c.CookieName); err == nil && cookie.Value != "" {
+				values, found, err := store.Load(r.Context(), cookie.Value)
+				if err != nil {
+					log.GetGlobalLogger().Error("Session load failed", log.E(err))
+					reg.Counter("session_errors_total", "operation").WithLabelValues("load").Inc()
+				}
+				if found {
+					s.token = cookie.Value
+					if values != nil {
+						s.values = values
+					}
+				}
+			}
+
+			sw := &sessionWriter{
+				ResponseWriter: w,
+				req:            r,
+				session:        s,
+				store:          store,
+				config:         c,
+				reg:            reg,
+			}
+
+			ctx := context.WithValue(r.Context(), ContextKey, s)
+			next.ServeHTTP(sw, r.WithContext(ctx))
+
+			sw.commit()
+		})
+	}
+}
+
+// Get returns the session value for key.
+// Returns an empty string if the key is not set or no session is present.
+func Get(r *http.Request, key string) string {
+	s := fromRequest(r)
+	if s == nil {
+		return ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.values[key]
+}
+
+// Set stores a session value. It is a no-op if no session is present.
+func Set(r *http.Request, key, value string) {
+	s := fromRequest(r)
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+	s.modified = true
+}
+
+// Delete removes a session value. It is a no-op if no session is present.
+func Delete(r *http.Request, key string) {
+	s := fromRequest(r)
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.values[key]; ok {
+		delete(s.values, key)
+		s.modified = true
+	}
+}
+
+// Destroy removes all session values, deletes the session from the store
+// and expires the session cookie.
+func Destroy(r *http.Request) {
+	s := fromRequest(r)
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.values)
+	s.destroyed = true
+}
+
+// RenewID issues a new session token while keeping the session values.
+// Call it after a privilege change such as login to prevent session fixation.
+func RenewID(r *http.Request) {
+	s := fromRequest(r)
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.renew = true
+	s.destroyed = false
+}
+
+func fromRequest(r *http.Request) *session {
+	s, _ := r.Context().Value(ContextKey).(*session)
+	return s
+}
+
+// sessionWriter saves the session right before the response headers are written.
+type sessionWriter struct {
+	http.ResponseWriter
+	req       *http.Request
+	session   *session
+	store     Store
+	config    Config
+	reg       metrics.Registry
+	committed bool
+}
+
+func (sw *sessionWriter) WriteHeader(code int) {
+	// Browsers ignore cookies on interim responses such as 103 Early Hints,
+	// so the session is saved with the final response
+	if !rwutil.IsInformational(code) {
+		sw.commit()
+	}
+	sw.ResponseWriter.WriteHeader(code)
+}
+
+func (sw *sessionWriter) Write(p []byte) (int, error) {
+	sw.commit()
+	return sw.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher.
+func (sw *sessionWriter) Flush() {
+	sw.commit()
+	if f, ok := sw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (sw *sessionWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
+
+// commit saves or deletes the session and sets the cookie. Only the first
+// call has an effect since headers can't be changed once written.
+func (sw *sessionWriter) commit() {
+	if sw.committed {
+		return
+	}
+	sw.committed = true
+
+	s := sw.session
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx := sw.req.Context()
+
+	if s.destroyed {
+		if s.token != "" {
+			if err := sw.store.Delete(ctx, s.token); err != nil {
+				sw.logError("delete", err)
+			}
+			sw.setCookie("", -1)
+		}
+		return
+	}
+
+	if s.renew && s.token != "" {
+		if err := sw.store.Delete(ctx, s.token); err != nil {
+			sw.logError("delete", err)
+		}
+		s.token = ""
+	}
+
+	if !s.modified && !s.renew {
+		return
+	}
+
+	// A new session with nothing in it isn't worth a cookie
+	if s.token == "" && len(s.values) == 0 {
+		return
+	}
+
+	if s.token == "" {
+		token, err := generateToken()
+		if err != nil {
+			sw.logError("save", err)
+			return
+		}
+		s.token = token
+	}
+
+	token, err := sw.store.Save(ctx, s.token, maps.Clone(s.values), sw.config.Lifetime)
+	if err != nil {
+		sw.logError("save", err)
+		return
+	}
+	s.token = token
+
+	sw.setCookie(token, int(sw.config.Lifetime.Seconds()))
+}
+
+func (sw *sessionWriter) setCookie(value string, maxAge int) {
+	http.SetCookie(sw.ResponseWriter, &http.Cookie{
+		Name:     sw.config.CookieName,
+		Value:    value,
+		MaxAge:   maxAge,
+		Domain:   sw.config.CookieDomain,
+		Path:     sw.config.CookiePath,
+		Secure:   config.BoolOrDefault(sw.config.CookieSecure, true),
+		HttpOnly: true,
+		SameSite: sw.config.CookieSameSite,
+	})
+}
+
+func (sw *sessionWriter) logError(operation string, err error) {
+	log.GetGlobalLogger().Error("Session "+operation+" failed", log.E(err))
+	sw.reg.Counter("session_errors_total", "operation").WithLabelValues(operation).Inc()
+}
+
+// generateToken creates a random session token.
+func generateToken() (string, error) {
+	b := make([]byte, tokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate session token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
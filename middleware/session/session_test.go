@@ -0,0 +1,249 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"github.com/alexferl/zerohttp/config"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+// sessionCookie returns the session cookie set on the response, if any.
+func sessionCookie(w *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, c := range w.Result().Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func newSessionHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/set", func(w http.ResponseWriter, r *http.Request) {
+		Set(r, "user", r.URL.Query().Get("user"))
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/get", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(Get(r, "user")))
+	})
+	mux.HandleFunc("/delete", func(w http.ResponseWriter, r *http.Request) {
+		Delete(r, "user")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/destroy", func(w http.ResponseWriter, r *http.Request) {
+		Destroy(r)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/renew", func(w http.ResponseWriter, r *http.Request) {
+		RenewID(r)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
+
+func TestSession_Lifecycle(t *testing.T) {
+	stores := map[string]Store{
+		"memory": NewMemoryStore(),
+		"cookie": NewCookieStore([]byte("0123456789abcdef0123456789abcdef")),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			handler := New(Config{Store: store})(newSessionHandler())
+
+			// No session yet
+			w := zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/get").Build())
+			zhtest.AssertWith(t, w).Status(http.StatusOK).Body("").CookieNotExists("session_id")
+
+			// Set creates a session
+			w = zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/set?user=alice").Build())
+			cookie := sessionCookie(w, "session_id")
+			zhtest.AssertNotNil(t, cookie)
+			zhtest.AssertTrue(t, cookie.HttpOnly)
+			zhtest.AssertTrue(t, cookie.Secure)
+			zhtest.AssertEqual(t, http.SameSiteLaxMode, cookie.SameSite)
+			zhtest.AssertEqual(t, 86400, cookie.MaxAge)
+
+			// Get reads it back without rewriting the cookie
+			w = zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/get").WithCookie(cookie).Build())
+			zhtest.AssertWith(t, w).Body("alice").CookieNotExists("session_id")
+
+			// Delete removes the value
+			w = zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/delete").WithCookie(cookie).Build())
+			zhtest.AssertWith(t, w).Status(http.StatusNoContent)
+			cookie = sessionCookie(w, "session_id")
+			zhtest.AssertNotNil(t, cookie)
+
+			w = zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/get").WithCookie(cookie).Build())
+			zhtest.AssertWith(t, w).Body("")
+		})
+	}
+}
+
+func TestSession_Destroy(t *testing.T) {
+	store := NewMemoryStore()
+	handler := New(Config{Store: store})(newSessionHandler())
+
+	w := zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/set?user=alice").Build())
+	cookie := sessionCookie(w, "session_id")
+
+	w = zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/destroy").WithCookie(cookie).Build())
+	expired := sessionCookie(w, "session_id")
+	zhtest.AssertNotNil(t, expired)
+	zhtest.AssertEqual(t, -1, expired.MaxAge)
+	zhtest.AssertEmpty(t, expired.Value)
+
+	// Old cookie no longer resolves to a session
+	_, found, err := store.Load(context.Background(), cookie.Value)
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertFalse(t, found)
+}
+
+func TestSession_RenewID(t *testing.T) {
+	store := NewMemoryStore()
+	handler := New(Config{Store: store})(newSessionHandler())
+
+	w := zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/set?user=alice").Build())
+	oldCookie := sessionCookie(w, "session_id")
+
+	w = zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/renew").WithCookie(oldCookie).Build())
+	newCookie := sessionCookie(w, "session_id")
+	zhtest.AssertNotNil(t, newCookie)
+	zhtest.AssertNotEqual(t, oldCookie.Value, newCookie.Value)
+
+	// Values carry over to the new ID
+	w = zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/get").WithCookie(newCookie).Build())
+	zhtest.AssertWith(t, w).Body("alice")
+
+	// Old ID is gone
+	w = zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/get").WithCookie(oldCookie).Build())
+	zhtest.AssertWith(t, w).Body("")
+}
+
+func TestSession_UnknownCookie(t *testing.T) {
+	handler := New()(newSessionHandler())
+
+	req := zhtest.NewRequest(http.MethodGet, "/set?user=bob").
+		WithCookie(&http.Cookie{Name: "session_id", Value: "attacker-chosen"}).
+		Build()
+	w := zhtest.Serve(handler, req)
+
+	// An unknown token is never adopted
+	cookie := sessionCookie(w, "session_id")
+	zhtest.AssertNotNil(t, cookie)
+	zhtest.AssertNotEqual(t, "attacker-chosen", cookie.Value)
+}
+
+func TestSession_CookieSetBeforeHeaders(t *testing.T) {
+	handler := New()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Set(r, "k", "v")
+		w.WriteHeader(http.StatusCreated)
+		// Changes after the headers are written can't be saved
+		Set(r, "late", "v")
+	}))
+
+	w := zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusCreated).CookieExists("session_id")
+}
+
+func TestSession_EarlyHints(t *testing.T) {
+	store := NewMemoryStore()
+	handler := New(Config{Store: store})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", "</app.css>; rel=preload; as=style")
+		w.WriteHeader(http.StatusEarlyHints)
+		// The session isn't saved with the interim response
+		Set(r, "user", "alice")
+		_, _ = w.Write([]byte(Get(r, "user")))
+	}))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	var hints []http.Header
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			hints = append(hints, http.Header(header))
+			return nil
+		},
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), http.MethodGet, server.URL, nil)
+	zhtest.AssertNoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	zhtest.AssertNoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	zhtest.AssertEqual(t, 1, len(hints))
+	zhtest.AssertEmpty(t, hints[0].Get("Set-Cookie"))
+	zhtest.AssertEqual(t, http.StatusOK, resp.StatusCode)
+
+	var cookie *http.Cookie
+	for _, c := range resp.Cookies() {
+		if c.Name == "session_id" {
+			cookie = c
+		}
+	}
+	zhtest.AssertNotNil(t, cookie)
+
+	// The value set after the early hints was saved
+	values, found, err := store.Load(context.Background(), cookie.Value)
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertTrue(t, found)
+	zhtest.AssertEqual(t, "alice", values["user"])
+}
+
+func TestSession_CustomCookie(t *testing.T) {
+	handler := New(Config{
+		CookieName:     "sid",
+		CookieDomain:   "example.com",
+		CookiePath:     "/app",
+		CookieSecure:   config.Bool(false),
+		CookieSameSite: http.SameSiteStrictMode,
+		Lifetime:       time.Hour,
+	})(newSessionHandler())
+
+	w := zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/set?user=alice").Build())
+	cookie := sessionCookie(w, "sid")
+	zhtest.AssertNotNil(t, cookie)
+	zhtest.AssertEqual(t, "example.com", cookie.Domain)
+	zhtest.AssertEqual(t, "/app", cookie.Path)
+	zhtest.AssertFalse(t, cookie.Secure)
+	zhtest.AssertEqual(t, http.SameSiteStrictMode, cookie.SameSite)
+	zhtest.AssertEqual(t, 3600, cookie.MaxAge)
+}
+
+func TestSession_ExcludedPaths(t *testing.T) {
+	handler := New(Config{ExcludedPaths: []string{"/set"}})(newSessionHandler())
+
+	w := zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/set?user=alice").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusOK).CookieNotExists("session_id")
+}
+
+func TestSession_NoMiddleware(t *testing.T) {
+	req := zhtest.NewRequest(http.MethodGet, "/").Build()
+
+	zhtest.AssertEmpty(t, Get(req, "k"))
+	Set(req, "k", "v")
+	Delete(req, "k")
+	Destroy(req)
+	RenewID(req)
+}
+
+type failingStore struct{ MemoryStore }
+
+func (f *failingStore) Save(context.Context, string, map[string]string, time.Duration) (string, error) {
+	return "", errors.New("store unavailable")
+}
+
+func TestSession_SaveError(t *testing.T) {
+	handler := New(Config{Store: &failingStore{}})(newSessionHandler())
+
+	w := zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/set?user=alice").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusOK).Body("ok").CookieNotExists("session_id")
+}
@@ -0,0 +1,204 @@
+package session
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+)
+
+// ErrCookieTooLarge is returned by CookieStore when the encoded session
+// exceeds the maximum cookie size.
+var ErrCookieTooLarge = errors.New("session: encoded session exceeds cookie size limit")
+
+// maxCookieSize is the largest cookie value browsers reliably accept.
+const maxCookieSize = 4096
+
+// sweepInterval is how often MemoryStore removes expired sessions.
+const sweepInterval = time.Minute
+
+// MemoryStore is a thread-safe in-memory session store.
+// Sessions are lost on restart and are not shared between instances.
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*sessionEntry
+	lastSweep time.Time
+}
+
+// sessionEntry represents stored session values with expiry.
+type sessionEntry struct {
+	values map[string]string
+	expiry time.Time
+}
+
+// NewMemoryStore creates a new in-memory session store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries:   make(map[string]*sessionEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+// Load retrieves the session values for the given token.
+// The context is accepted for interface compatibility but not used by the in-memory store.
+func (s *MemoryStore) Load(_ context.Context, token string) (map[string]string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[token]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if time.Now().After(entry.expiry) {
+		delete(s.entries, token)
+		return nil, false, nil
+	}
+
+	return maps.Clone(entry.values), true, nil
+}
+
+// Save stores the session values with the given TTL and returns the token unchanged.
+// The context is accepted for interface compatibility but not used by the in-memory store.
+func (s *MemoryStore) Save(_ context.Context, token string, values map[string]string, ttl time.Duration) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.removeExpired(now)
+		s.lastSweep = now
+	}
+
+	s.entries[token] = &sessionEntry{
+		values: maps.Clone(values),
+		expiry: now.Add(ttl),
+	}
+	return token, nil
+}
+
+// Delete removes the session for the given token.
+// The context is accepted for interface compatibility but not used by the in-memory store.
+func (s *MemoryStore) Delete(_ context.Context, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, token)
+	return nil
+}
+
+// removeExpired removes all expired entries.
+// Must be called with the lock held.
+func (s *MemoryStore) removeExpired(now time.Time) {
+	for token, entry := range s.entries {
+		if now.After(entry.expiry) {
+			delete(s.entries, token)
+		}
+	}
+}
+
+// CookieStore keeps the session values in the cookie itself, encrypted and
+// authenticated with AES-GCM. No server-side state is kept, so sessions
+// survive restarts and work across instances sharing the same key.
+// Since nothing is stored server-side, Delete can't revoke a copied cookie
+// before it expires.
+type CookieStore struct {
+	aead cipher.AEAD
+}
+
+// cookiePayload is the encrypted content of a session cookie.
+type cookiePayload struct {
+	Values  map[string]string `json:"v"`
+	Expires int64             `json:"e"`
+}
+
+// NewCookieStore creates a cookie-backed session store.
+// The key must be 16, 24 or 32 bytes long to select AES-128, AES-192 or
+// AES-256. All servers sharing sessions must use the same key.
+// Panics if the key has an invalid length.
+func NewCookieStore(key []byte) *CookieStore {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		panic(fmt.Sprintf("zerohttp: session cookie store key must be 16, 24 or 32 bytes, got %d", len(key)))
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(fmt.Sprintf("zerohttp: session cookie store: %v", err))
+	}
+
+	return &CookieStore{aead: aead}
+}
+
+// Load decrypts the session values from the token.
+// Tampered, undecodable or expired tokens are reported as not found.
+func (s *CookieStore) Load(_ context.Context, token string) (map[string]string, bool, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	nonceSize := s.aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, false, nil
+	}
+
+	plaintext, err := s.aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	var payload cookiePayload
+	if err := json.Unmarshal(plaintext, &payload); err != nil {
+		return nil, false, nil
+	}
+
+	if time.Now().Unix() > payload.Expires {
+		return nil, false, nil
+	}
+
+	return payload.Values, true, nil
+}
+
+// Save encrypts the session values and returns them as the new token.
+// The given token is ignored. Returns ErrCookieTooLarge if the result
+// doesn't fit in a cookie.
+func (s *CookieStore) Save(_ context.Context, _ string, values map[string]string, ttl time.Duration) (string, error) {
+	plaintext, err := json.Marshal(cookiePayload{
+		Values:  values,
+		Expires: time.Now().Add(ttl).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate session nonce: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(s.aead.Seal(nonce, nonce, plaintext, nil))
+	if len(token) > maxCookieSize {
+		return "", ErrCookieTooLarge
+	}
+	return token, nil
+}
+
+// Delete is a no-op since the session only exists in the client's cookie.
+// The middleware expires the cookie when a session is destroyed.
+func (s *CookieStore) Delete(_ context.Context, _ string) error {
+	return nil
+}
+
+// Ensure interface compliance at compile time.
+var (
+	_ Store = (*MemoryStore)(nil)
+	_ Store = (*CookieStore)(nil)
+)
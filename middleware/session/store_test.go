@@ -0,0 +1,141 @@
+package session
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestSessionMemoryStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("save and load", func(t *testing.T) {
+		store := NewMemoryStore()
+
+		token, err := store.Save(ctx, "abc", map[string]string{"k": "v"}, time.Minute)
+		zhtest.AssertNoError(t, err)
+		zhtest.AssertEqual(t, "abc", token)
+
+		values, found, err := store.Load(ctx, "abc")
+		zhtest.AssertNoError(t, err)
+		zhtest.AssertTrue(t, found)
+		zhtest.AssertEqual(t, "v", values["k"])
+	})
+
+	t.Run("load returns a copy", func(t *testing.T) {
+		store := NewMemoryStore()
+		_, _ = store.Save(ctx, "abc", map[string]string{"k": "v"}, time.Minute)
+
+		values, _, _ := store.Load(ctx, "abc")
+		values["k"] = "changed"
+
+		values, _, _ = store.Load(ctx, "abc")
+		zhtest.AssertEqual(t, "v", values["k"])
+	})
+
+	t.Run("expired session not returned", func(t *testing.T) {
+		store := NewMemoryStore()
+		_, _ = store.Save(ctx, "abc", map[string]string{"k": "v"}, time.Millisecond)
+		time.Sleep(2 * time.Millisecond)
+
+		_, found, err := store.Load(ctx, "abc")
+		zhtest.AssertNoError(t, err)
+		zhtest.AssertFalse(t, found)
+	})
+
+	t.Run("sweep removes expired sessions", func(t *testing.T) {
+		store := NewMemoryStore()
+		_, _ = store.Save(ctx, "old", map[string]string{}, time.Millisecond)
+		time.Sleep(2 * time.Millisecond)
+
+		store.lastSweep = time.Now().Add(-sweepInterval)
+		_, _ = store.Save(ctx, "new", map[string]string{}, time.Minute)
+
+		zhtest.AssertEqual(t, 1, len(store.entries))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		store := NewMemoryStore()
+		_, _ = store.Save(ctx, "abc", map[string]string{"k": "v"}, time.Minute)
+
+		zhtest.AssertNoError(t, store.Delete(ctx, "abc"))
+
+		_, found, _ := store.Load(ctx, "abc")
+		zhtest.AssertFalse(t, found)
+	})
+}
+
+func TestSessionCookieStore(t *testing.T) {
+	ctx := context.Background()
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	t.Run("round trip", func(t *testing.T) {
+		store := NewCookieStore(key)
+
+		token, err := store.Save(ctx, "ignored", map[string]string{"k": "secret"}, time.Minute)
+		zhtest.AssertNoError(t, err)
+		zhtest.AssertFalse(t, strings.Contains(token, "secret"))
+
+		values, found, err := store.Load(ctx, token)
+		zhtest.AssertNoError(t, err)
+		zhtest.AssertTrue(t, found)
+		zhtest.AssertEqual(t, "secret", values["k"])
+	})
+
+	t.Run("tampered token rejected", func(t *testing.T) {
+		store := NewCookieStore(key)
+		token, _ := store.Save(ctx, "", map[string]string{"role": "user"}, time.Minute)
+
+		b := []byte(token)
+		i := len(b) / 2
+		if b[i] == 'A' {
+			b[i] = 'B'
+		} else {
+			b[i] = 'A'
+		}
+
+		_, found, err := store.Load(ctx, string(b))
+		zhtest.AssertNoError(t, err)
+		zhtest.AssertFalse(t, found)
+	})
+
+	t.Run("wrong key rejected", func(t *testing.T) {
+		token, _ := NewCookieStore(key).Save(ctx, "", map[string]string{"k": "v"}, time.Minute)
+
+		other := NewCookieStore([]byte("fedcba9876543210fedcba9876543210"))
+		_, found, _ := other.Load(ctx, token)
+		zhtest.AssertFalse(t, found)
+	})
+
+	t.Run("garbage rejected", func(t *testing.T) {
+		store := NewCookieStore(key)
+		for _, token := range []string{"", "!!!", "YWJj"} {
+			_, found, err := store.Load(ctx, token)
+			zhtest.AssertNoError(t, err)
+			zhtest.AssertFalse(t, found)
+		}
+	})
+
+	t.Run("expired token rejected", func(t *testing.T) {
+		store := NewCookieStore(key)
+		token, _ := store.Save(ctx, "", map[string]string{"k": "v"}, -time.Second)
+
+		_, found, _ := store.Load(ctx, token)
+		zhtest.AssertFalse(t, found)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		store := NewCookieStore(key)
+		_, err := store.Save(ctx, "", map[string]string{"k": strings.Repeat("x", maxCookieSize)}, time.Minute)
+		zhtest.AssertErrorIs(t, err, ErrCookieTooLarge)
+	})
+
+	t.Run("invalid key length panics", func(t *testing.T) {
+		zhtest.AssertPanic(t, func() {
+			NewCookieStore([]byte("short"))
+		})
+	})
+}
@@ -3,6 +3,8 @@ package timeout
 import (
 	"net/http"
 	"time"
+
+	"github.com/alexferl/zerohttp/httpx"
 )
 
 // Config allows customization of request timeout behavior
@@ -15,10 +17,18 @@ type Config struct {
 	// Default: 504 (Gateway Timeout)
 	StatusCode int
 
-	// Message to write on timeout.
+	// Message is the detail of the problem detail written on timeout.
 	// Default: "" (empty)
 	Message string
 
+	// RequestIDHeader is the header name for the request ID. The request ID
+	// is read from the request context, or else from this request header,
+	// and is set as a response header and a request_id member of the
+	// timeout response.
+	// This should match the header configured in RequestIDConfig.
+	// Default: "X-Request-Id"
+	RequestIDHeader string
+
 	// ExcludedPaths contains paths that skip timeout enforcement.
 	// Supports exact matches, prefixes (ending with /), and wildcards (ending with *).
 	// Cannot be used with IncludedPaths - setting both will panic.
@@ -36,9 +46,10 @@ type Config struct {
 
 // DefaultConfig contains the default values for timeout configuration.
 var DefaultConfig = Config{
-	Duration:      30 * time.Second,
-	StatusCode:    http.StatusGatewayTimeout,
-	Message:       "",
-	ExcludedPaths: []string{},
-	IncludedPaths: []string{},
+	Duration:        30 * time.Second,
+	StatusCode:      http.StatusGatewayTimeout,
+	Message:         "",
+	RequestIDHeader: httpx.HeaderXRequestId,
+	ExcludedPaths:   []string{},
+	IncludedPaths:   []string{},
 }
@@ -13,6 +13,7 @@ func TestTimeoutConfig_DefaultValues(t *testing.T) {
 	zhtest.AssertEqual(t, 30*time.Second, cfg.Duration)
 	zhtest.AssertEqual(t, http.StatusGatewayTimeout, cfg.StatusCode)
 	zhtest.AssertEqual(t, "", cfg.Message)
+	zhtest.AssertEqual(t, "X-Request-Id", cfg.RequestIDHeader)
 	zhtest.AssertEqual(t, 0, len(cfg.ExcludedPaths))
 	zhtest.AssertEqual(t, 0, len(cfg.IncludedPaths))
 
@@ -17,14 +17,41 @@
 //
 // # Per-Route Timeouts
 //
-//	// Different timeouts for different routes
-//	api := app.Group(func(api zh.Router) {
+//	// Timeout a single route with 503 Service Unavailable
+//	app.GET("/report", reportHandler, timeout.New(timeout.Config{
+//	    Duration:   2 * time.Second,
+//	    StatusCode: http.StatusServiceUnavailable,
+//	}))
+//
+//	// Different timeouts for different groups of routes
+//	app.Group(func(api zh.Router) {
 //	    api.Use(timeout.New(timeout.Config{Duration: 5 * time.Second}))
 //	    api.GET("/fast", fastHandler)
 //	})
 //
-//	upload := app.Group(func(upload zh.Router) {
+//	app.Group(func(upload zh.Router) {
 //	    upload.Use(timeout.New(timeout.Config{Duration: 5 * time.Minute}))
 //	    upload.POST("/files", uploadHandler)
 //	})
+//
+// # Timeout Response
+//
+// The timeout response is a problem detail with StatusCode and Message as
+// its detail. The request ID, from the request ID middleware or the
+// RequestIDHeader request header, is set as a response header and a
+// request_id member, so clients can report the failure:
+//
+//	{"detail":"Report took too long","request_id":"d6cbc3b0...","status":504,"title":"Gateway Timeout"}
+//
+// Each timeout is logged at Warn level with the global logger, with the
+// method, path, timeout and request ID.
+//
+// # Handler Behavior
+//
+// The request context passed to the handler carries the deadline and is
+// canceled when it expires. Handlers should watch r.Context().Done() and
+// return early. Once the timeout response has been written, further writes
+// from the handler fail with [ErrTimeoutWrite], so the client never receives
+// two responses. If the handler already flushed part of its response, the
+// timeout response is skipped since the status line has been sent.
 package timeout
@@ -4,13 +4,17 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"maps"
 	"net/http"
 	"sync"
 
 	zconfig "github.com/alexferl/zerohttp/internal/config"
 	"github.com/alexferl/zerohttp/internal/mwutil"
 	"github.com/alexferl/zerohttp/internal/problem"
+	"github.com/alexferl/zerohttp/internal/rwutil"
+	"github.com/alexferl/zerohttp/log"
 	"github.com/alexferl/zerohttp/metrics"
+	"github.com/alexferl/zerohttp/middleware/requestid"
 )
 
 // ErrTimeoutWrite is returned when the timeout middleware fails to write response data.
@@ -18,7 +22,8 @@ var ErrTimeoutWrite = errors.New("zerohttp: timeout middleware write failed")
 
 // New creates a timeout middleware with the provided configuration that enforces request timeouts by canceling the context
 // after a specified duration. When the timeout is exceeded, it returns an HTTP 504
-// Gateway Timeout response to the client.
+// Gateway Timeout problem detail to the client, carrying the request ID if the
+// request has one, and logs the timeout at Warn level.
 //
 // Important: Your handler must monitor the ctx.Done() channel to detect when the
 // context deadline has been reached. If you don't check this channel and return
@@ -44,6 +49,7 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 
 			done := make(chan struct{})
 			panicChan := make(chan any, 1)
+			var finishedInTime bool // Set before done is closed
 
 			tw := &timeoutWriter{
 				w:   w,
@@ -58,6 +64,7 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 					}
 				}()
 				next.ServeHTTP(tw, r.WithContext(ctx))
+				finishedInTime = ctx.Err() == nil
 				close(done)
 			}()
 
@@ -67,28 +74,52 @@ func New(cfg ...Config) func(http.Handler) http.Handler {
 			case <-done:
 				tw.mu.Lock()
 				defer tw.mu.Unlock()
-
-				dst := w.Header()
-				for k, v := range tw.h {
-					dst[k] = v
-				}
-
-				if !tw.wroteHeader {
-					tw.code = http.StatusOK
-				}
-				w.WriteHeader(tw.code)
-				_, _ = w.Write(tw.wbuf.Bytes()) // Best effort write
+				tw.writeResponseLocked()
 			case <-ctx.Done():
 				tw.mu.Lock()
 				defer tw.mu.Unlock()
 
+				// The handler may have finished right at the deadline, with both
+				// channels ready. Its response wins over the timeout response if
+				// it returned in time or already wrote one.
+				select {
+				case <-done:
+					if finishedInTime || tw.wroteHeader {
+						tw.writeResponseLocked()
+						return
+					}
+				default:
+				}
+
 				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 					metrics.SafeRegistry(metrics.GetRegistry(r.Context())).Counter("timeout_requests_total").Inc()
 
-					detail := problem.NewDetail(c.StatusCode, c.Message)
-					_ = detail.RenderAuto(w, r) // Best effort - client may have disconnected
-					tw.err = ErrTimeoutWrite
+					reqID := requestid.Get(r.Context())
+					if reqID == "" {
+						reqID = r.Header.Get(c.RequestIDHeader)
+					}
+
+					fields := []log.Field{
+						log.F("method", r.Method),
+						log.F("path", r.URL.Path),
+						log.F("timeout", c.Duration.String()),
+					}
+					if reqID != "" {
+						fields = append(fields, log.F("request_id", reqID))
+					}
+					log.GetGlobalLogger().Warn("Request timed out", fields...)
+
+					// Headers already sent by a flush can't be replaced
+					if !tw.flushed {
+						detail := problem.NewDetail(c.StatusCode, c.Message)
+						if reqID != "" {
+							w.Header().Set(c.RequestIDHeader, reqID)
+							detail.Set("request_id", reqID)
+						}
+						_ = detail.RenderAuto(w, r) // Best effort - client may have disconnected
+					}
 				}
+				tw.err = ErrTimeoutWrite
 			}
 		})
 	}
@@ -103,9 +134,36 @@ type timeoutWriter struct {
 	mu          sync.Mutex
 	err         error
 	wroteHeader bool
+	flushed     bool
 	code        int
 }
 
+// writeResponseLocked copies the buffered handler response to the underlying writer.
+// Headers and status are only written if they weren't already sent by Flush.
+func (tw *timeoutWriter) writeResponseLocked() {
+	if !tw.wroteHeader {
+		tw.code = http.StatusOK
+	}
+	tw.writeHeaderToUnderlyingLocked()
+	_, _ = tw.w.Write(tw.wbuf.Bytes()) // Best effort write
+	tw.wbuf.Reset()
+}
+
+// writeHeaderToUnderlyingLocked sends the handler's headers and status code
+// to the underlying writer once.
+func (tw *timeoutWriter) writeHeaderToUnderlyingLocked() {
+	if tw.flushed {
+		return
+	}
+	tw.flushed = true
+
+	dst := tw.w.Header()
+	for k, v := range tw.h {
+		dst[k] = v
+	}
+	tw.w.WriteHeader(tw.code)
+}
+
 func (tw *timeoutWriter) Header() http.Header {
 	return tw.h
 }
@@ -136,6 +194,15 @@ func (tw *timeoutWriter) writeHeaderLocked(code int) {
 		return
 	}
 
+	// Interim responses are sent right away, ahead of the buffered response
+	if rwutil.IsInformational(code) {
+		if !tw.flushed {
+			maps.Copy(tw.w.Header(), tw.h)
+			tw.w.WriteHeader(code)
+		}
+		return
+	}
+
 	if tw.wroteHeader {
 		return
 	}
@@ -157,7 +224,8 @@ func (tw *timeoutWriter) Flush() {
 		tw.writeHeaderLocked(http.StatusOK)
 	}
 
-	// Flush buffered content to underlying writer
+	// Flush headers and buffered content to underlying writer
+	tw.writeHeaderToUnderlyingLocked()
 	if tw.wbuf.Len() > 0 {
 		_, _ = tw.w.Write(tw.wbuf.Bytes())
 		tw.wbuf.Reset()
@@ -4,12 +4,16 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/alexferl/zerohttp/config"
 	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/log"
 	"github.com/alexferl/zerohttp/metrics"
+	"github.com/alexferl/zerohttp/middleware/requestid"
 	"github.com/alexferl/zerohttp/zhtest"
 )
 
@@ -376,3 +380,193 @@ func TestTimeout_BothExcludedAndIncludedPathsPanics(t *testing.T) {
 		})
 	})
 }
+
+// countingRecorder counts WriteHeader calls to detect double writes.
+type countingRecorder struct {
+	*httptest.ResponseRecorder
+	mu           sync.Mutex
+	headerWrites int
+}
+
+func (c *countingRecorder) WriteHeader(code int) {
+	c.mu.Lock()
+	c.headerWrites++
+	c.mu.Unlock()
+	c.ResponseRecorder.WriteHeader(code)
+}
+
+func (c *countingRecorder) Flush() {
+	c.ResponseRecorder.Flush()
+}
+
+func TestTimeout_ContextDeadline(t *testing.T) {
+	var deadline time.Time
+	var hasDeadline bool
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, hasDeadline = r.Context().Deadline()
+		w.WriteHeader(http.StatusOK)
+	})
+
+	start := time.Now()
+	middleware := New(Config{Duration: 2 * time.Second})(handler)
+	req := zhtest.NewRequest(http.MethodGet, "/").Build()
+	zhtest.AssertWith(t, zhtest.Serve(middleware, req)).Status(http.StatusOK)
+
+	zhtest.AssertTrue(t, hasDeadline)
+	zhtest.AssertTrue(t, deadline.After(start))
+	zhtest.AssertFalse(t, deadline.After(time.Now().Add(2*time.Second)))
+}
+
+func TestTimeout_HandlerFinishesAtDeadline(t *testing.T) {
+	tests := []struct {
+		name string
+		code int
+		body string
+	}{
+		{"written response", http.StatusCreated, "done"},
+		{"implicit 200", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 50 {
+				handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					deadline, _ := r.Context().Deadline()
+					time.Sleep(time.Until(deadline))
+					if tt.body != "" {
+						w.WriteHeader(tt.code)
+						_, _ = w.Write([]byte(tt.body))
+					}
+				})
+				middleware := New(Config{Duration: 5 * time.Millisecond})(handler)
+
+				rec := &countingRecorder{ResponseRecorder: httptest.NewRecorder()}
+				req := zhtest.NewRequest(http.MethodGet, "/").Build()
+				middleware.ServeHTTP(rec, req)
+
+				zhtest.AssertEqual(t, 1, rec.headerWrites)
+				switch rec.Code {
+				case tt.code:
+					zhtest.AssertEqual(t, tt.body, rec.Body.String())
+				case http.StatusGatewayTimeout:
+					zhtest.AssertWith(t, rec.ResponseRecorder).IsProblemDetail().BodyNotContains("done")
+				default:
+					zhtest.AssertFailf(t, "unexpected status %d", rec.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestTimeout_FlushedResponseNotOverwritten(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Stream", "yes")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	middleware := New(Config{Duration: 20 * time.Millisecond})(handler)
+
+	rec := &countingRecorder{ResponseRecorder: httptest.NewRecorder()}
+	req := zhtest.NewRequest(http.MethodGet, "/").Build()
+	middleware.ServeHTTP(rec, req)
+
+	zhtest.AssertEqual(t, 1, rec.headerWrites)
+	zhtest.AssertWith(t, rec.ResponseRecorder).
+		Status(http.StatusAccepted).
+		Header("X-Stream", "yes").
+		Body("partial")
+}
+
+func TestTimeout_WriteAfterTimeout(t *testing.T) {
+	writeErr := make(chan error, 1)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		time.Sleep(10 * time.Millisecond)
+		_, err := w.Write([]byte("late"))
+		writeErr <- err
+	})
+	middleware := New(Config{Duration: 10 * time.Millisecond})(handler)
+
+	req := zhtest.NewRequest(http.MethodGet, "/").Build()
+	w := zhtest.Serve(middleware, req)
+
+	zhtest.AssertWith(t, w).Status(http.StatusGatewayTimeout).IsProblemDetail().BodyNotContains("late")
+	zhtest.AssertErrorIs(t, <-writeErr, ErrTimeoutWrite)
+}
+
+// timeoutWarnLogger records the fields of Warn entries.
+type timeoutWarnLogger struct {
+	log.NoopLogger
+	mu       sync.Mutex
+	messages []string
+	fields   map[string]any
+}
+
+func (l *timeoutWarnLogger) Warn(msg string, fields ...log.Field) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, msg)
+	l.fields = make(map[string]any)
+	for _, f := range fields {
+		l.fields[f.Key] = f.Value
+	}
+}
+
+func TestTimeout_RequestIDAndLogging(t *testing.T) {
+	logger := &timeoutWarnLogger{}
+	original := log.GetGlobalLogger()
+	log.SetGlobalLogger(logger)
+	defer log.SetGlobalLogger(original)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	mw := requestid.New(requestid.Config{
+		Generator: func() string { return "req-123" },
+	})(New(Config{Duration: 10 * time.Millisecond, Message: "Report took too long"})(handler))
+
+	w := zhtest.Serve(mw, zhtest.NewRequest(http.MethodGet, "/report").Build())
+
+	zhtest.AssertWith(t, w).
+		Status(http.StatusGatewayTimeout).
+		Header(httpx.HeaderContentType, httpx.MIMEApplicationProblemJSON).
+		Header(httpx.HeaderXRequestId, "req-123").
+		BodyContains(`"request_id":"req-123"`).
+		BodyContains(`"detail":"Report took too long"`)
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	zhtest.AssertEqual(t, []string{"Request timed out"}, logger.messages)
+	zhtest.AssertEqual(t, "req-123", logger.fields["request_id"])
+	zhtest.AssertEqual(t, "/report", logger.fields["path"])
+	zhtest.AssertEqual(t, "10ms", logger.fields["timeout"])
+}
+
+func TestTimeout_RequestIDFromHeader(t *testing.T) {
+	original := log.GetGlobalLogger()
+	log.SetGlobalLogger(&log.NoopLogger{})
+	defer log.SetGlobalLogger(original)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	mw := New(Config{Duration: 10 * time.Millisecond, RequestIDHeader: "X-Correlation-Id"})(handler)
+
+	t.Run("with request ID", func(t *testing.T) {
+		req := zhtest.NewRequest(http.MethodGet, "/").WithHeader("X-Correlation-Id", "abc").Build()
+		zhtest.AssertWith(t, zhtest.Serve(mw, req)).
+			Status(http.StatusGatewayTimeout).
+			Header("X-Correlation-Id", "abc").
+			BodyContains(`"request_id":"abc"`)
+	})
+
+	t.Run("without request ID", func(t *testing.T) {
+		w := zhtest.Serve(mw, zhtest.NewRequest(http.MethodGet, "/").Build())
+		zhtest.AssertWith(t, w).
+			Status(http.StatusGatewayTimeout).
+			HeaderNotExists("X-Correlation-Id")
+		zhtest.AssertFalse(t, strings.Contains(w.Body.String(), "request_id"))
+	})
+}
@@ -7,17 +7,33 @@
 //
 //	import "github.com/alexferl/zerohttp/middleware/value"
 //
-//	// Inject static values
-//	app.Use(value.New(value.Config{
-//	    Values: map[string]any{
-//	        "version":    "1.0.0",
-//	        "request_id": uuid.New(),
-//	    },
-//	}))
+//	// Inject static values for a route
+//	app.GET("/user", userHandler,
+//	    value.With("version", "1.0.0"),
+//	    value.With("role", "admin"),
+//	)
 //
 // # Accessing Values
 //
-// Retrieve values in handlers:
+// Retrieve typed values in handlers:
 //
-//	version := r.Context().Value("version")
+//	version, ok := value.Get[string](r, "version")
+//
+// # Typed Keys
+//
+// String keys are convenient in an application, but two packages using the
+// same string collide. Libraries and middleware built on zerohttp should
+// create their own keys with NewKey instead: keys are compared by identity,
+// so they never collide, and their values are typed:
+//
+//	var tenantKey = value.NewKey[string]("tenant")
+//
+//	func tenantMiddleware(next http.Handler) http.Handler {
+//	    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//	        ctx := tenantKey.Set(r.Context(), r.Header.Get("X-Tenant"))
+//	        next.ServeHTTP(w, r.WithContext(ctx))
+//	    })
+//	}
+//
+//	tenant, ok := tenantKey.Get(r)
 package value
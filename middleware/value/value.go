@@ -32,3 +32,37 @@ func Get[T any](r *http.Request, key any) (T, bool) {
 
 	return zero, false
 }
+
+// Key is a typed context key. Keys are compared by identity rather than by
+// name, so keys created by different packages never collide, even with the
+// same name, and the type of their values is checked at compile time.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey creates a context key for values of type T. The name is only used
+// to describe the key, e.g. when printing a context.
+//
+// Example:
+//
+//	var tenantKey = value.NewKey[string]("tenant")
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+// Set returns a copy of ctx with the key set to v.
+func (k *Key[T]) Set(ctx context.Context, v T) context.Context {
+	return context.WithValue(ctx, k, v)
+}
+
+// Get retrieves the value of the key from the request context.
+// Returns the value and true if set, zero value and false otherwise.
+func (k *Key[T]) Get(r *http.Request) (T, bool) {
+	v, ok := r.Context().Value(k).(T)
+	return v, ok
+}
+
+// String returns the name of the key.
+func (k *Key[T]) String() string {
+	return "value.Key(" + k.name + ")"
+}
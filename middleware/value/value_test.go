@@ -87,3 +87,37 @@ func TestGetContextValue_NilValue(t *testing.T) {
 
 	zhtest.Serve(handler, req)
 }
+
+func TestKey(t *testing.T) {
+	userKey := NewKey[int]("user")
+	otherUserKey := NewKey[int]("user")
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(userKey.Set(r.Context(), 42))
+
+		got, ok := userKey.Get(r)
+		zhtest.AssertTrue(t, ok)
+		zhtest.AssertEqual(t, 42, got)
+
+		// Keys with the same name don't collide
+		got, ok = otherUserKey.Get(r)
+		zhtest.AssertFalse(t, ok)
+		zhtest.AssertEqual(t, 0, got)
+
+		// Nor with string keys
+		_, ok = Get[int](r, "user")
+		zhtest.AssertFalse(t, ok)
+	})
+
+	zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/").Build())
+}
+
+func TestKey_NotSet(t *testing.T) {
+	key := NewKey[string]("tenant")
+	req := zhtest.NewRequest(http.MethodGet, "/").Build()
+
+	got, ok := key.Get(req)
+	zhtest.AssertFalse(t, ok)
+	zhtest.AssertEqual(t, "", got)
+	zhtest.AssertEqual(t, "value.Key(tenant)", key.String())
+}
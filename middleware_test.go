@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/alexferl/zerohttp/httpx"
 	"github.com/alexferl/zerohttp/log"
+	"github.com/alexferl/zerohttp/middleware/requestid"
 	"github.com/alexferl/zerohttp/zhtest"
 )
 
@@ -70,3 +72,105 @@ func TestDefaultMiddlewares_NilInputs(t *testing.T) {
 		zhtest.AssertNotEmpty(t, middlewares)
 	})
 }
+
+func TestDefaultMiddlewares_RequestIDPropagation(t *testing.T) {
+	type traceKey struct{}
+
+	cfg := DefaultConfig
+	cfg.RequestID = requestid.Config{Header: "X-Correlation-Id", ContextKey: traceKey{}}
+	logger := &mockServerLogger{}
+
+	var ctxID string
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctxID = requestid.Get(r.Context(), traceKey{})
+		zhtest.AssertEqual(t, ctxID, requestid.FromRequest(r))
+		w.WriteHeader(http.StatusOK)
+	})
+	middlewares := DefaultMiddlewares(cfg, logger)
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	w := zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/").Build())
+
+	headerID := w.Header().Get("X-Correlation-Id")
+	zhtest.AssertNotEmpty(t, headerID)
+	zhtest.AssertEqual(t, headerID, ctxID)
+
+	var logID any
+	for _, entry := range logger.logs {
+		for _, f := range entry.fields {
+			if f.Key == "request_id" {
+				logID = f.Value
+			}
+		}
+	}
+	zhtest.AssertEqual(t, any(headerID), logID)
+}
+
+func TestWithout(t *testing.T) {
+	var calls []string
+	router := NewRouter()
+	router.Use(
+		Named("first", testMiddleware("first", &calls)),
+		testMiddleware("unnamed", &calls),
+		Named("second", testMiddleware("second", &calls)),
+	)
+	router.GET("/all", testHandler("all"))
+	router.GET("/skip", testHandler("skip"), Without("first", "missing"), testMiddleware("route", &calls))
+
+	router.Group(func(api Router) {
+		api.Use(Named("group", testMiddleware("group", &calls)))
+		api.GET("/api/skip", testHandler("api"), Without("group", "second"))
+	})
+
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"/all", []string{"first", "unnamed", "second"}},
+		{"/skip", []string{"unnamed", "second", "route"}},
+		{"/api/skip", []string{"first", "unnamed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			calls = nil
+			w := zhtest.Serve(router, zhtest.NewRequest(http.MethodGet, tt.path).Build())
+
+			zhtest.AssertWith(t, w).Status(http.StatusOK)
+			zhtest.AssertEqual(t, tt.expected, calls)
+		})
+	}
+}
+
+func TestWithout_AppliesMiddlewareOnce(t *testing.T) {
+	var applied int
+	counting := func(next http.Handler) http.Handler {
+		applied++
+		return next
+	}
+
+	router := NewRouter()
+	router.Use(counting, Named("named", counting))
+	router.GET("/plain", testHandler("plain"))
+	router.GET("/skip", testHandler("skip"), Without("named"), counting)
+
+	// Once per route and middleware, none for the skipped one
+	zhtest.AssertEqual(t, 4, applied)
+}
+
+func TestWithout_DefaultMiddlewares(t *testing.T) {
+	server := New()
+	server.GET("/plain", testHandler("plain"))
+	server.GET("/bare", testHandler("bare"), Without("requestid", "securityheaders"))
+
+	w := zhtest.Serve(server, zhtest.NewRequest(http.MethodGet, "/plain").Build())
+	zhtest.AssertWith(t, w).HeaderExists(httpx.HeaderXRequestId).HeaderExists(httpx.HeaderXContentTypeOptions)
+
+	w = zhtest.Serve(server, zhtest.NewRequest(http.MethodGet, "/bare").Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusOK).
+		HeaderNotExists(httpx.HeaderXRequestId).
+		HeaderNotExists(httpx.HeaderXContentTypeOptions)
+}
@@ -0,0 +1,130 @@
+package zerohttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// openAPIVersion is the OpenAPI Specification version of exported documents.
+const openAPIVersion = "3.1.0"
+
+// errOpenAPIInfo is returned by ExportOpenAPI when the info is incomplete.
+var errOpenAPIInfo = errors.New("zerohttp: OpenAPI info requires a title and version")
+
+// OpenAPIInfo is the metadata of the API in an exported OpenAPI document.
+type OpenAPIInfo struct {
+	// Title is the title of the API. Required.
+	Title string `json:"title"`
+
+	// Version is the version of the API, not of the OpenAPI Specification. Required.
+	Version string `json:"version"`
+
+	// Description is a short description of the API.
+	Description string `json:"description,omitempty"`
+}
+
+// openAPIDocument is the root object of an OpenAPI document.
+type openAPIDocument struct {
+	OpenAPI string                                 `json:"openapi"`
+	Info    OpenAPIInfo                            `json:"info"`
+	Paths   map[string]map[string]openAPIOperation `json:"paths"`
+}
+
+// openAPIOperation describes a single method on a path.
+type openAPIOperation struct {
+	Parameters []openAPIParameter         `json:"parameters,omitempty"`
+	Responses  map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name     string            `json:"name"`
+	In       string            `json:"in"`
+	Required bool              `json:"required"`
+	Schema   map[string]string `json:"schema"`
+}
+
+type openAPIResponse struct {
+	Description string `json:"description"`
+}
+
+// ExportOpenAPI generates an OpenAPI 3.1 document, encoded as JSON, listing
+// the method and path of every route returned by [Router.Routes]. Path
+// parameters such as {id}, and wildcards such as {path...}, are declared as
+// required string parameters. Request and response schemas aren't known, so
+// each operation only has a default response: the document is a skeleton to
+// fill in, or to serve to tools that list endpoints. CONNECT routes have no
+// OpenAPI equivalent and are left out.
+//
+// Example:
+//
+//	spec, err := zh.ExportOpenAPI(app, zh.OpenAPIInfo{Title: "Users API", Version: "1.0.0"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	_ = os.WriteFile("openapi.json", spec, 0o644)
+func ExportOpenAPI(r Router, info OpenAPIInfo) ([]byte, error) {
+	if info.Title == "" || info.Version == "" {
+		return nil, errOpenAPIInfo
+	}
+
+	doc := openAPIDocument{
+		OpenAPI: openAPIVersion,
+		Info:    info,
+		Paths:   make(map[string]map[string]openAPIOperation),
+	}
+
+	for _, route := range r.Routes() {
+		if route.Method == http.MethodConnect {
+			continue
+		}
+
+		path, params := openAPIPath(route.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]openAPIOperation)
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = openAPIOperation{
+			Parameters: params,
+			Responses: map[string]openAPIResponse{
+				"default": {Description: "Default response"},
+			},
+		}
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// openAPIPath converts a ServeMux pattern to an OpenAPI path template and
+// returns its path parameters. Wildcards lose their "..." suffix and the
+// {$} end anchor is dropped, so "/files/{path...}" becomes "/files/{path}".
+func openAPIPath(pattern string) (string, []openAPIParameter) {
+	segments := strings.Split(pattern, "/")
+	var params []openAPIParameter
+
+	for i, segment := range segments {
+		name, ok := strings.CutPrefix(segment, "{")
+		if !ok {
+			continue
+		}
+		name, ok = strings.CutSuffix(name, "}")
+		if !ok {
+			continue
+		}
+		if name == "$" {
+			segments[i] = ""
+			continue
+		}
+
+		name = strings.TrimSuffix(name, "...")
+		segments[i] = "{" + name + "}"
+		params = append(params, openAPIParameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   map[string]string{"type": "string"},
+		})
+	}
+
+	return strings.Join(segments, "/"), params
+}
@@ -0,0 +1,83 @@
+package zerohttp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestExportOpenAPI(t *testing.T) {
+	router := NewRouter()
+	router.GET("/", testHandler("root"))
+	router.GET("/users", testHandler("list"))
+	router.POST("/users", testHandler("create"))
+	router.GET("/users/{id}", testHandler("get"))
+	router.GET("/files/{path...}", testHandler("file"))
+	router.GET("/posts/{$}", testHandler("posts"))
+	router.CONNECT("/tunnel", testHandler("tunnel"))
+
+	spec, err := ExportOpenAPI(router, OpenAPIInfo{Title: "Users API", Version: "1.0.0"})
+	zhtest.AssertNoError(t, err)
+
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Info    struct {
+			Title   string `json:"title"`
+			Version string `json:"version"`
+		} `json:"info"`
+		Paths map[string]map[string]struct {
+			Parameters []struct {
+				Name     string            `json:"name"`
+				In       string            `json:"in"`
+				Required bool              `json:"required"`
+				Schema   map[string]string `json:"schema"`
+			} `json:"parameters"`
+			Responses map[string]struct {
+				Description string `json:"description"`
+			} `json:"responses"`
+		} `json:"paths"`
+	}
+	zhtest.AssertNoError(t, json.Unmarshal(spec, &doc))
+
+	zhtest.AssertEqual(t, "3.1.0", doc.OpenAPI)
+	zhtest.AssertEqual(t, "Users API", doc.Info.Title)
+	zhtest.AssertEqual(t, "1.0.0", doc.Info.Version)
+
+	zhtest.AssertEqual(t, 5, len(doc.Paths))
+	zhtest.AssertEqual(t, 2, len(doc.Paths["/users"]))
+	zhtest.AssertEqual(t, 0, len(doc.Paths["/users"]["post"].Parameters))
+	zhtest.AssertEqual(t, "Default response", doc.Paths["/users"]["get"].Responses["default"].Description)
+	zhtest.AssertNotNil(t, doc.Paths["/"]["get"].Responses)
+	zhtest.AssertNotNil(t, doc.Paths["/posts/"]["get"].Responses)
+
+	params := doc.Paths["/users/{id}"]["get"].Parameters
+	zhtest.AssertEqual(t, 1, len(params))
+	zhtest.AssertEqual(t, "id", params[0].Name)
+	zhtest.AssertEqual(t, "path", params[0].In)
+	zhtest.AssertTrue(t, params[0].Required)
+	zhtest.AssertEqual(t, "string", params[0].Schema["type"])
+
+	params = doc.Paths["/files/{path}"]["get"].Parameters
+	zhtest.AssertEqual(t, 1, len(params))
+	zhtest.AssertEqual(t, "path", params[0].Name)
+
+	_, found := doc.Paths["/tunnel"]
+	zhtest.AssertFalse(t, found)
+}
+
+func TestExportOpenAPI_RequiresInfo(t *testing.T) {
+	router := NewRouter()
+	router.GET("/users", testHandler("list"))
+
+	for _, info := range []OpenAPIInfo{
+		{Version: "1.0.0"},
+		{Title: "Users API"},
+	} {
+		_, err := ExportOpenAPI(router, info)
+		zhtest.AssertErrorIs(t, err, errOpenAPIInfo)
+	}
+
+	_, err := ExportOpenAPI(router, OpenAPIInfo{Title: "Users API", Version: "1.0.0"})
+	zhtest.AssertNoError(t, err)
+}
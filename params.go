@@ -6,12 +6,15 @@
 //	    // String value
 //	    id := zh.Param(r, "id")
 //
-//	    // Typed extraction
+//	    // Typed extraction, a returned error responds with 400
 //	    userID, err := zh.ParamAs[int](r, "id")
 //	    if err != nil {
-//	        return zh.NewProblemDetail(http.StatusBadRequest, "Invalid ID")
+//	        return err
 //	    }
 //
+//	    // UUIDs
+//	    orderID, err := zh.ParamUUID(r, "order_id")
+//
 //	    // With default
 //	    category := zh.ParamOrDefault(r, "category", "all")
 //
@@ -22,11 +25,51 @@
 package zerohttp
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
+// ErrParamNotFound is the error of a [ParamError] for a missing parameter.
+var ErrParamNotFound = errors.New("not found")
+
+// ParamError is returned when a path parameter is missing or can't be
+// converted to the requested type. Handlers returning it respond with a
+// 400 Bad Request problem detail naming the parameter.
+type ParamError struct {
+	// Name is the name of the parameter.
+	Name string
+
+	// Value is the raw value of the parameter, empty if it is missing.
+	Value string
+
+	// Type is the type the value was converted to, e.g. "int".
+	Type string
+
+	// Err is the conversion error, or ErrParamNotFound.
+	Err error
+}
+
+func (e *ParamError) Error() string {
+	if errors.Is(e.Err, ErrParamNotFound) {
+		return fmt.Sprintf("parameter %q not found", e.Name)
+	}
+	return fmt.Sprintf("parameter %q: invalid %s: %v", e.Name, e.Type, e.Err)
+}
+
+func (e *ParamError) Unwrap() error { return e.Err }
+
+// problem returns the 400 problem detail for the error.
+func (e *ParamError) problem() *ProblemDetail {
+	detail := fmt.Sprintf("Path parameter %q is missing", e.Name)
+	if !errors.Is(e.Err, ErrParamNotFound) {
+		detail = fmt.Sprintf("Path parameter %q must be a valid %s", e.Name, e.Type)
+	}
+	return NewProblemDetail(http.StatusBadRequest, detail).Set("parameter", e.Name)
+}
+
 // Params is the default params extractor instance used by the package
 var Params = &defaultParamsExtractor{}
 
@@ -79,7 +122,7 @@ func ParamAs[T ParamType](r *http.Request, name string) (T, error) {
 	var zero T
 	val := Params.Param(r, name)
 	if val == "" {
-		return zero, fmt.Errorf("parameter %q not found", name)
+		return zero, &ParamError{Name: name, Err: ErrParamNotFound}
 	}
 
 	switch any(zero).(type) {
@@ -88,83 +131,83 @@ func ParamAs[T ParamType](r *http.Request, name string) (T, error) {
 	case int:
 		n, err := strconv.Atoi(val)
 		if err != nil {
-			return zero, fmt.Errorf("parameter %q: invalid int: %w", name, err)
+			return zero, &ParamError{Name: name, Value: val, Type: "int", Err: err}
 		}
 		return any(n).(T), nil
 	case int8:
 		n, err := strconv.ParseInt(val, 10, 8)
 		if err != nil {
-			return zero, fmt.Errorf("parameter %q: invalid int8: %w", name, err)
+			return zero, &ParamError{Name: name, Value: val, Type: "int8", Err: err}
 		}
 		return any(int8(n)).(T), nil
 	case int16:
 		n, err := strconv.ParseInt(val, 10, 16)
 		if err != nil {
-			return zero, fmt.Errorf("parameter %q: invalid int16: %w", name, err)
+			return zero, &ParamError{Name: name, Value: val, Type: "int16", Err: err}
 		}
 		return any(int16(n)).(T), nil
 	case int32:
 		n, err := strconv.ParseInt(val, 10, 32)
 		if err != nil {
-			return zero, fmt.Errorf("parameter %q: invalid int32: %w", name, err)
+			return zero, &ParamError{Name: name, Value: val, Type: "int32", Err: err}
 		}
 		return any(int32(n)).(T), nil
 	case int64:
 		n, err := strconv.ParseInt(val, 10, 64)
 		if err != nil {
-			return zero, fmt.Errorf("parameter %q: invalid int64: %w", name, err)
+			return zero, &ParamError{Name: name, Value: val, Type: "int64", Err: err}
 		}
 		return any(n).(T), nil
 	case uint:
 		n, err := strconv.ParseUint(val, 10, 0)
 		if err != nil {
-			return zero, fmt.Errorf("parameter %q: invalid uint: %w", name, err)
+			return zero, &ParamError{Name: name, Value: val, Type: "uint", Err: err}
 		}
 		return any(uint(n)).(T), nil
 	case uint8:
 		n, err := strconv.ParseUint(val, 10, 8)
 		if err != nil {
-			return zero, fmt.Errorf("parameter %q: invalid uint8: %w", name, err)
+			return zero, &ParamError{Name: name, Value: val, Type: "uint8", Err: err}
 		}
 		return any(uint8(n)).(T), nil
 	case uint16:
 		n, err := strconv.ParseUint(val, 10, 16)
 		if err != nil {
-			return zero, fmt.Errorf("parameter %q: invalid uint16: %w", name, err)
+			return zero, &ParamError{Name: name, Value: val, Type: "uint16", Err: err}
 		}
 		return any(uint16(n)).(T), nil
 	case uint32:
 		n, err := strconv.ParseUint(val, 10, 32)
 		if err != nil {
-			return zero, fmt.Errorf("parameter %q: invalid uint32: %w", name, err)
+			return zero, &ParamError{Name: name, Value: val, Type: "uint32", Err: err}
 		}
 		return any(uint32(n)).(T), nil
 	case uint64:
 		n, err := strconv.ParseUint(val, 10, 64)
 		if err != nil {
-			return zero, fmt.Errorf("parameter %q: invalid uint64: %w", name, err)
+			return zero, &ParamError{Name: name, Value: val, Type: "uint64", Err: err}
 		}
 		return any(n).(T), nil
 	case float32:
 		n, err := strconv.ParseFloat(val, 32)
 		if err != nil {
-			return zero, fmt.Errorf("parameter %q: invalid float32: %w", name, err)
+			return zero, &ParamError{Name: name, Value: val, Type: "float32", Err: err}
 		}
 		return any(float32(n)).(T), nil
 	case float64:
 		n, err := strconv.ParseFloat(val, 64)
 		if err != nil {
-			return zero, fmt.Errorf("parameter %q: invalid float64: %w", name, err)
+			return zero, &ParamError{Name: name, Value: val, Type: "float64", Err: err}
 		}
 		return any(n).(T), nil
 	case bool:
 		n, err := strconv.ParseBool(val)
 		if err != nil {
-			return zero, fmt.Errorf("parameter %q: invalid bool: %w", name, err)
+			return zero, &ParamError{Name: name, Value: val, Type: "bool", Err: err}
 		}
 		return any(n).(T), nil
 	default:
-		return zero, fmt.Errorf("parameter %q: unsupported type", name)
+		return zero, &ParamError{Name: name, Value: val, Type: fmt.Sprintf("%T", zero), Err: errors.ErrUnsupported}
 	}
 }
 
@@ -178,6 +221,44 @@ func ParamAsOrDefault[T ParamType](r *http.Request, name string, defaultVal T) T
 	return val
 }
 
+// ParamUUID extracts a path parameter that must be a UUID in the canonical
+// 8-4-4-4-12 hex format, such as "f47ac10b-58cc-4372-a567-0e02b2c3d479".
+// The UUID is returned in lowercase. Returns a [ParamError] if the
+// parameter is missing or isn't a UUID.
+func ParamUUID(r *http.Request, name string) (string, error) {
+	val := Params.Param(r, name)
+	if val == "" {
+		return "", &ParamError{Name: name, Err: ErrParamNotFound}
+	}
+	if !isUUID(val) {
+		return "", &ParamError{Name: name, Value: val, Type: "UUID", Err: errInvalidUUID}
+	}
+	return strings.ToLower(val), nil
+}
+
+var errInvalidUUID = errors.New("expected format xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx")
+
+// isUUID reports whether s is a UUID in the canonical 8-4-4-4-12 hex format.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return false
+			}
+		default:
+			c := s[i]
+			if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // Param is a convenience function that calls Params.Param
 func Param(r *http.Request, name string) string {
 	return Params.Param(r, name)
@@ -1,10 +1,14 @@
 package zerohttp
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
+	"github.com/alexferl/zerohttp/httpx"
 	"github.com/alexferl/zerohttp/zhtest"
 )
 
@@ -642,3 +646,76 @@ func TestDefaultParamsExtractor(t *testing.T) {
 		zhtest.AssertEqual(t, got, "default")
 	})
 }
+
+func TestParamUUID(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"valid", "/orders/f47ac10b-58cc-4372-a567-0e02b2c3d479", "f47ac10b-58cc-4372-a567-0e02b2c3d479", false},
+		{"uppercase is lowered", "/orders/F47AC10B-58CC-4372-A567-0E02B2C3D479", "f47ac10b-58cc-4372-a567-0e02b2c3d479", false},
+		{"missing dashes", "/orders/f47ac10b58cc4372a5670e02b2c3d479", "", true},
+		{"invalid hex", "/orders/g47ac10b-58cc-4372-a567-0e02b2c3d479", "", true},
+		{"too short", "/orders/123", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			var got string
+			var gotErr error
+			mux.HandleFunc("/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+				got, gotErr = ParamUUID(r, "id")
+			})
+			mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			zhtest.AssertEqual(t, tt.want, got)
+			if tt.wantErr {
+				var paramErr *ParamError
+				zhtest.AssertTrue(t, errors.As(gotErr, &paramErr))
+				zhtest.AssertEqual(t, "UUID", paramErr.Type)
+			} else {
+				zhtest.AssertNoError(t, gotErr)
+			}
+		})
+	}
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := ParamUUID(httptest.NewRequest(http.MethodGet, "/", nil), "id")
+		zhtest.AssertErrorIs(t, err, ErrParamNotFound)
+	})
+}
+
+func TestParamError_Response(t *testing.T) {
+	router := NewRouter()
+	router.GET("/users/{id}", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		id, err := ParamAs[int](r, "id")
+		if err != nil {
+			return fmt.Errorf("loading user: %w", err)
+		}
+		return R.Text(w, http.StatusOK, strconv.Itoa(id))
+	}))
+	router.GET("/items", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		_, err := ParamAs[int](r, "id")
+		return err
+	}))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/abc", nil))
+	zhtest.AssertWith(t, w).
+		Status(http.StatusBadRequest).
+		Header(httpx.HeaderContentType, httpx.MIMEApplicationProblemJSON).
+		BodyContains(`"detail":"Path parameter \"id\" must be a valid int"`).
+		BodyContains(`"parameter":"id"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", nil))
+	zhtest.AssertWith(t, w).
+		Status(http.StatusBadRequest).
+		BodyContains(`"detail":"Path parameter \"id\" is missing"`)
+
+	_, err := ParamAs[int64](httptest.NewRequest(http.MethodGet, "/", nil), "id")
+	zhtest.AssertEqual(t, http.StatusBadRequest, ProblemFromError(err).Status)
+}
@@ -1,7 +1,17 @@
 package zerohttp
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/alexferl/zerohttp/httpx"
 	"github.com/alexferl/zerohttp/internal/problem"
+	"github.com/alexferl/zerohttp/middleware/requestid"
 )
 
 // ProblemDetail is an alias to problem.Detail.
@@ -32,3 +42,216 @@ func NewProblemDetail(statusCode int, detail string) *ProblemDetail {
 func NewValidationProblemDetail[T any](detail string, errors []T) *ProblemDetail {
 	return problem.NewValidationDetail(detail, errors)
 }
+
+// problemRequestIDKey is the extension member that carries the request ID.
+const problemRequestIDKey = "request_id"
+
+// withRequestID returns p with id as its "request_id" extension member, so
+// clients can quote it when reporting the error. p is copied rather than
+// modified since it may be shared, such as a package-level sentinel error.
+// It returns p as is if id is empty or p already has the member.
+func withRequestID(p *ProblemDetail, id string) *ProblemDetail {
+	if id == "" {
+		return p
+	}
+	if _, ok := p.Extensions[problemRequestIDKey]; ok {
+		return p
+	}
+
+	c := *p
+	c.Extensions = maps.Clone(p.Extensions)
+	if c.Extensions == nil {
+		c.Extensions = make(map[string]any, 1)
+	}
+	c.Extensions[problemRequestIDKey] = id
+	return &c
+}
+
+// problemRequestID returns the request ID of a response: the one stored in
+// the context of r by the request ID middleware if r is not nil, or else the
+// X-Request-Id response header the middleware sets.
+func problemRequestID(w http.ResponseWriter, r *http.Request) string {
+	if r != nil {
+		if id := requestid.Get(r.Context()); id != "" {
+			return id
+		}
+	}
+	return w.Header().Get(httpx.HeaderXRequestId)
+}
+
+// errorMapping maps errors matching target to a problem detail. Mappings
+// with bind set only match errors wrapped in a binding error, such as those
+// of [Binder.JSON].
+type errorMapping struct {
+	target error
+	status int
+	detail string
+	bind   bool
+}
+
+// defaultErrorMappings are the standard errors recognized by ProblemFromError.
+var defaultErrorMappings = []errorMapping{
+	{sql.ErrNoRows, http.StatusNotFound, "Resource not found", false},
+	{context.DeadlineExceeded, http.StatusGatewayTimeout, "The request timed out", false},
+	{io.EOF, http.StatusBadRequest, "Request body is empty", true},
+	{io.ErrUnexpectedEOF, http.StatusBadRequest, "Request body is incomplete", true},
+}
+
+// ErrorMapping maps errors matching Target, as reported by errors.Is, to a
+// problem detail with the given Status and Title. If Title is empty, the
+// status text is used. Mappings are set with Config.ErrorMappings.
+//
+// Example:
+//
+//	app := zh.New(zh.Config{
+//	    ErrorMappings: []zh.ErrorMapping{
+//	        {Target: store.ErrConflict, Status: http.StatusConflict, Title: "Version Conflict"},
+//	    },
+//	})
+type ErrorMapping struct {
+	// Target is the error to match, usually a sentinel error
+	Target error
+
+	// Status is the HTTP status code of the problem detail
+	Status int
+
+	// Title is the title of the problem detail
+	Title string
+}
+
+// ProblemFromError converts err into a problem detail. Errors that are
+// already a [ProblemDetail] are returned as is, and errors matching a
+// default mapping get its status. Wrapped errors are unwrapped. All other
+// errors become a 500 Internal Server Error. When the problem detail is
+// returned from a handler or rendered with [Renderer.ProblemDetailAuto],
+// Config.ErrorMappings of the server take precedence over the defaults.
+//
+// The default mappings are:
+//   - [ParamError]: 400 Bad Request
+//   - sql.ErrNoRows: 404 Not Found
+//   - context.DeadlineExceeded: 504 Gateway Timeout
+//   - io.EOF and io.ErrUnexpectedEOF from decoding a body, wrapped in a
+//     binding error by [Binder.JSON] and [BindAndValidate]: 400 Bad Request
+//
+// The error message isn't included in the problem detail, since it may
+// contain internal details. With Config.DebugErrors, a 500-level problem
+// detail returned from a handler or rendered with [Renderer.ProblemDetailAuto]
+// gets the error message and the stack trace captured by [WithStack], if any.
+func ProblemFromError(err error) *ProblemDetail {
+	var pd *ProblemDetail
+	if errors.As(err, &pd) {
+		return pd
+	}
+	var paramErr *ParamError
+	if errors.As(err, &paramErr) {
+		return paramErr.problem()
+	}
+	if pd := lookupErrorMapping(err, nil); pd != nil {
+		return problem.WithCause(pd, err)
+	}
+	return problem.WithCause(NewProblemDetail(http.StatusInternalServerError, "An unexpected error occurred"), err)
+}
+
+// WithStack returns err annotated with the stack trace of its caller. With
+// Config.DebugErrors, the trace is added to the 500-level problem detail
+// rendered for err, so it shows where the error happened rather than the
+// router. It returns nil if err is nil.
+//
+//	if err := db.QueryRowContext(ctx, query).Scan(&user); err != nil {
+//	    return zh.WithStack(err)
+//	}
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &stackError{err: err, stack: debug.Stack()}
+}
+
+// stackError is an error annotated with a stack trace by WithStack.
+type stackError struct {
+	err   error
+	stack []byte
+}
+
+func (e *stackError) Error() string {
+	return e.err.Error()
+}
+
+func (e *stackError) Unwrap() error {
+	return e.err
+}
+
+// routerConfigKey is the context key for the Config of the router serving a
+// request, set when it has error settings such as Config.DebugErrors.
+type routerConfigKey struct{}
+
+// requestConfig returns the Config of the router serving r, or nil if it has
+// no error settings.
+func requestConfig(r *http.Request) *Config {
+	c, _ := r.Context().Value(routerConfigKey{}).(*Config)
+	return c
+}
+
+// withDebugInfo adds the error message, and the stack trace captured by
+// WithStack if any, to a 500-level problem detail when debug errors are
+// enabled for r.
+func withDebugInfo(r *http.Request, pd *ProblemDetail, err error) *ProblemDetail {
+	if err == nil || pd.Status < http.StatusInternalServerError {
+		return pd
+	}
+	if c := requestConfig(r); c == nil || !c.DebugErrors {
+		return pd
+	}
+	pd.Set("error", err.Error())
+	var se *stackError
+	if errors.As(err, &se) {
+		pd.Set("stack", string(se.stack))
+	}
+	return pd
+}
+
+// lookupErrorMapping returns the problem detail for the first of mappings or
+// of the default mappings matching err, or nil if none does.
+func lookupErrorMapping(err error, mappings []ErrorMapping) *ProblemDetail {
+	for _, m := range mappings {
+		if errors.Is(err, m.Target) {
+			pd := NewProblemDetail(m.Status, "")
+			if m.Title != "" {
+				pd.Title = m.Title
+			}
+			return pd
+		}
+	}
+	for _, m := range defaultErrorMappings {
+		if !errors.Is(err, m.target) || (m.bind && !IsBindError(err)) {
+			continue
+		}
+		return NewProblemDetail(m.status, m.detail)
+	}
+	return nil
+}
+
+// requestErrorMappings returns the Config.ErrorMappings of the router
+// serving r.
+func requestErrorMappings(r *http.Request) []ErrorMapping {
+	if c := requestConfig(r); c != nil {
+		return c.ErrorMappings
+	}
+	return nil
+}
+
+// problemForRequest returns pd as rendered for r. Problem details from
+// ProblemFromError, which can't see the router, get the error mappings and
+// debug details of the router serving r.
+func problemForRequest(r *http.Request, pd *ProblemDetail) *ProblemDetail {
+	cause := problem.Cause(pd)
+	if cause == nil {
+		return pd
+	}
+	if mappings := requestErrorMappings(r); len(mappings) > 0 {
+		if mapped := lookupErrorMapping(cause, mappings); mapped != nil {
+			pd = problem.WithCause(mapped, cause)
+		}
+	}
+	return withDebugInfo(r, pd, cause)
+}
@@ -1,10 +1,17 @@
 package zerohttp
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/alexferl/zerohttp/internal/problem"
+	"github.com/alexferl/zerohttp/validator"
 	"github.com/alexferl/zerohttp/zhtest"
 )
 
@@ -88,3 +95,176 @@ func TestNewValidationProblemDetail(t *testing.T) {
 		zhtest.AssertEqual(t, http.StatusUnprocessableEntity, pd.Status)
 	})
 }
+
+func TestProblemFromError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		title  string
+	}{
+		{"no rows", sql.ErrNoRows, http.StatusNotFound, "Not Found"},
+		{"wrapped no rows", fmt.Errorf("get user: %w", sql.ErrNoRows), http.StatusNotFound, "Not Found"},
+		{"deadline", context.DeadlineExceeded, http.StatusGatewayTimeout, "Gateway Timeout"},
+		{"unexpected EOF", &validator.BindError{Err: io.ErrUnexpectedEOF}, http.StatusBadRequest, "Bad Request"},
+		{"unexpected EOF outside binding", io.ErrUnexpectedEOF, http.StatusInternalServerError, "Internal Server Error"},
+		{"problem detail", NewProblemDetail(http.StatusForbidden, "nope"), http.StatusForbidden, "Forbidden"},
+		{"unknown", errors.New("secret internals"), http.StatusInternalServerError, "Internal Server Error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pd := ProblemFromError(tt.err)
+			zhtest.AssertEqual(t, tt.status, pd.Status)
+			zhtest.AssertEqual(t, tt.title, pd.Title)
+			zhtest.AssertFalse(t, strings.Contains(pd.Detail, "secret"))
+		})
+	}
+}
+
+func TestErrorMappings(t *testing.T) {
+	errConflict := errors.New("conflict")
+	app := New(Config{
+		ErrorMappings: []ErrorMapping{
+			{Target: errConflict, Status: http.StatusConflict, Title: "Version Conflict"},
+			{Target: sql.ErrNoRows, Status: http.StatusGone},
+		},
+	})
+	conflict := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return fmt.Errorf("save: %w", errConflict)
+	})
+	app.GET("/conflict", conflict)
+	app.GET("/gone", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return sql.ErrNoRows
+	}))
+	app.GET("/problem", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return ProblemFromError(errConflict)
+	}))
+	app.GET("/auto", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return R.ProblemDetailAuto(w, r, ProblemFromError(errConflict))
+	}))
+
+	w := zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/conflict").Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusConflict).
+		BodyContains(`"title":"Version Conflict"`)
+
+	// Mappings take precedence over the defaults
+	w = zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/gone").Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusGone).
+		BodyContains(`"title":"Gone"`)
+
+	// ProblemFromError can't see the server, its problem details are mapped
+	// when rendered
+	zhtest.AssertEqual(t, http.StatusInternalServerError, ProblemFromError(errConflict).Status)
+	w = zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/problem").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusConflict)
+	w = zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/auto").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusConflict)
+
+	// Mappings belong to their server
+	other := New()
+	other.GET("/conflict", conflict)
+	w = zhtest.Serve(other, zhtest.NewRequest(http.MethodGet, "/conflict").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusInternalServerError)
+}
+
+func TestHandlerFunc_SentinelErrors(t *testing.T) {
+	router := NewRouter()
+	router.GET("/users/{id}", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return fmt.Errorf("get user: %w", sql.ErrNoRows)
+	}))
+
+	w := zhtest.Serve(router, zhtest.NewRequest(http.MethodGet, "/users/1").Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusNotFound).
+		BodyContains(`"detail":"Resource not found"`)
+}
+
+func TestHandlerFunc_EOF(t *testing.T) {
+	router := NewRouter()
+	router.POST("/users", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var user struct{ Name string }
+		return B.JSON(r.Body, &user)
+	}))
+	router.GET("/upstream", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return fmt.Errorf("read upstream: %w", io.EOF)
+	}))
+
+	// An empty body is the client's fault
+	w := zhtest.Serve(router, zhtest.NewRequest(http.MethodPost, "/users").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusBadRequest)
+	zhtest.AssertTrue(t, errors.Is(B.JSON(strings.NewReader(""), &struct{}{}), io.EOF))
+
+	// An EOF from anywhere else is the server's
+	w = zhtest.Serve(router, zhtest.NewRequest(http.MethodGet, "/upstream").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusInternalServerError)
+}
+
+func TestDebugErrors(t *testing.T) {
+	app := New(Config{DebugErrors: true})
+	zhtest.AssertTrue(t, mergeConfig(Config{DebugErrors: true}).Recover.DebugErrors)
+
+	handler := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("connection refused")
+	})
+	app.GET("/error", handler)
+	app.GET("/stack", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return WithStack(errors.New("connection refused"))
+	}))
+	app.GET("/problem", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return ProblemFromError(errors.New("connection refused"))
+	}))
+	app.GET("/auto", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return R.ProblemDetailAuto(w, r, ProblemFromError(WithStack(errors.New("connection refused"))))
+	}))
+	app.GET("/missing", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return sql.ErrNoRows
+	}))
+
+	// Without WithStack the router has no meaningful stack trace to show
+	w := zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/error").Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusInternalServerError).
+		BodyContains(`"error":"connection refused"`)
+	zhtest.AssertFalse(t, strings.Contains(w.Body.String(), "stack"))
+
+	// The stack trace is the one captured where the error happened
+	w = zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/stack").Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusInternalServerError).
+		BodyContains(`"error":"connection refused"`).
+		BodyContains(`"stack":"goroutine`).
+		BodyContains("TestDebugErrors")
+
+	w = zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/problem").Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusInternalServerError).
+		BodyContains(`"error":"connection refused"`)
+
+	w = zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/auto").Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusInternalServerError).
+		BodyContains(`"error":"connection refused"`).
+		BodyContains(`"stack":"goroutine`)
+
+	// Client errors never carry debug details
+	w = zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/missing").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusNotFound)
+	zhtest.AssertFalse(t, strings.Contains(w.Body.String(), "stack"))
+
+	// Debug details are only added when rendered for a request
+	zhtest.AssertNil(t, ProblemFromError(context.DeadlineExceeded).Extensions["error"])
+	zhtest.AssertNil(t, WithStack(nil))
+
+	// Disabled by default, and per server
+	other := New()
+	other.GET("/error", handler)
+	w = zhtest.Serve(other, zhtest.NewRequest(http.MethodGet, "/error").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusInternalServerError)
+	zhtest.AssertFalse(t, strings.Contains(w.Body.String(), "connection refused"))
+
+	w = zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/error").Build())
+	zhtest.AssertWith(t, w).BodyContains(`"error":"connection refused"`)
+}
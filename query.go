@@ -14,6 +14,10 @@
 //	// With default
 //	limit := zh.QueryParamAsOrDefault(r, "limit", 20)
 //
+//	// Repeated parameters, e.g. ?tag=go&tag=web
+//	tags := zh.QueryParams(r, "tag")
+//	ids, err := zh.QueryParamsAs[int](r, "id")
+//
 //	// Struct binding
 //	var req struct {
 //	    Search string `query:"search"`
@@ -70,12 +74,17 @@ func (q *defaultQueryExtractor) QueryParamOrDefault(r *http.Request, name, defau
 // Supported types: string, int, int8, int16, int32, int64,
 // uint, uint8, uint16, uint32, uint64, float32, float64, bool
 func QueryParamAs[T ParamType](r *http.Request, name string) (T, error) {
-	var zero T
 	val := Query.QueryParam(r, name)
 	if val == "" {
+		var zero T
 		return zero, nil
 	}
+	return convertQueryParam[T](name, val)
+}
 
+// convertQueryParam converts the value val of the query parameter name to type T.
+func convertQueryParam[T ParamType](name, val string) (T, error) {
+	var zero T
 	switch any(zero).(type) {
 	case string:
 		return any(val).(T), nil
@@ -165,19 +174,45 @@ func QueryParamAs[T ParamType](r *http.Request, name string) (T, error) {
 // QueryParamAsOrDefault extracts and converts a query parameter to type T,
 // returning a default value if the parameter is missing or conversion fails.
 func QueryParamAsOrDefault[T ParamType](r *http.Request, name string, defaultVal T) T {
-	val, err := QueryParamAs[T](r, name)
+	raw := Query.QueryParam(r, name)
+	if raw == "" {
+		return defaultVal
+	}
+	val, err := convertQueryParam[T](name, raw)
 	if err != nil {
 		return defaultVal
 	}
-	// Check for zero value (missing param) and return default
-	var zero T
-	if any(val) == any(zero) {
-		// Check if param actually exists
-		if Query.QueryParam(r, name) == "" {
-			return defaultVal
+	return val
+}
+
+// QueryParams returns all values of a repeated query parameter, such as
+// ["go", "web"] for "?tag=go&tag=web", in the order they appear.
+// Returns nil if the parameter is not found.
+func QueryParams(r *http.Request, name string) []string {
+	return r.URL.Query()[name]
+}
+
+// QueryParamsAs extracts and converts all values of a repeated query
+// parameter to type T. Returns an error if any value fails to convert.
+// Empty values are skipped. Returns nil if the parameter is not found.
+func QueryParamsAs[T ParamType](r *http.Request, name string) ([]T, error) {
+	vals := QueryParams(r, name)
+	if len(vals) == 0 {
+		return nil, nil
+	}
+
+	out := make([]T, 0, len(vals))
+	for _, val := range vals {
+		if val == "" {
+			continue
+		}
+		v, err := convertQueryParam[T](name, val)
+		if err != nil {
+			return nil, err
 		}
+		out = append(out, v)
 	}
-	return val
+	return out, nil
 }
 
 // QueryParam is a convenience function that calls Query.QueryParam
@@ -482,3 +482,21 @@ func TestQueryParam_MultipleValues(t *testing.T) {
 	allTags := req.URL.Query()["tag"]
 	zhtest.AssertEqual(t, len(allTags), 3)
 }
+
+func TestQueryParams(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?tag=go&tag=web&tag=api&id=1&id=&id=3&bad=1&bad=x", nil)
+
+	zhtest.AssertEqual(t, []string{"go", "web", "api"}, QueryParams(req, "tag"))
+	zhtest.AssertNil(t, QueryParams(req, "missing"))
+
+	ids, err := QueryParamsAs[int](req, "id")
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertEqual(t, []int{1, 3}, ids)
+
+	_, err = QueryParamsAs[int](req, "bad")
+	zhtest.AssertError(t, err)
+
+	missing, err := QueryParamsAs[int](req, "missing")
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertNil(t, missing)
+}
@@ -1,7 +1,9 @@
 package zerohttp
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"io"
@@ -9,9 +11,15 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/internal/problem"
+	"github.com/alexferl/zerohttp/internal/rwutil"
+	"github.com/alexferl/zerohttp/middleware/etag"
 )
 
 // M is a convenience type for map[string]any, useful for quick JSON responses.
@@ -42,41 +50,100 @@ var Render Renderer = &defaultRenderer{}
 //	return zh.R.JSON(w, http.StatusOK, data)
 var R = Render
 
+// ErrEarlyHintsNoLinks is returned by [Renderer.EarlyHints] when called without links.
+var ErrEarlyHintsNoLinks = errors.New("zerohttp: early hints require at least one link")
+
+// ErrEarlyHintsUnsupported is returned by [Renderer.EarlyHints] when the
+// interim response can't reach the client, because the request is HTTP/1.0
+// or because the response is buffered by middleware.
+var ErrEarlyHintsUnsupported = errors.New("zerohttp: early hints are not supported by the response")
+
 // Renderer handles response rendering for various content types
 type Renderer interface {
 	// JSON writes a JSON response with the given status code and data
 	JSON(w http.ResponseWriter, statusCode int, data any) error
 
+	// JSONModified writes a JSON response with a Last-Modified header, or a
+	// 304 Not Modified response if the client's copy is still current
+	JSONModified(w http.ResponseWriter, r *http.Request, statusCode int, lastModified time.Time, data any) error
+
+	// JSONWithETag writes a JSON response with an ETag header, or a
+	// 304 Not Modified response if the client's copy is still current
+	JSONWithETag(w http.ResponseWriter, r *http.Request, statusCode int, etag string, data any) error
+
 	// Text writes a plain text response with the given status code and data
 	Text(w http.ResponseWriter, statusCode int, data string) error
 
 	// HTML writes an HTML response with the given status code and data
 	HTML(w http.ResponseWriter, statusCode int, data string) error
 
-	// Template renders an HTML template with proper Content-Type header
+	// Template renders an HTML template with proper Content-Type header.
+	// Nothing is written if the template fails to render
 	Template(w http.ResponseWriter, code int, tmpl *template.Template, name string, data any) error
 
 	// Blob writes a binary response with the given status code, content type, and data
 	Blob(w http.ResponseWriter, statusCode int, contentType string, data []byte) error
 
+	// BlobDownload writes a binary response with the given content type and
+	// data as a file download with the given filename
+	BlobDownload(w http.ResponseWriter, filename, contentType string, data []byte) error
+
 	// Stream writes a streaming response with the given status code and content type,
 	// copying data from the provided reader to the response writer
 	Stream(w http.ResponseWriter, statusCode int, contentType string, reader io.Reader) error
 
 	// File serves a file as the response, automatically setting appropriate headers
+	// and answering Range requests with 206 Partial Content
 	File(w http.ResponseWriter, r *http.Request, filename string) error
 
 	// NoContent writes a 204 No Content response with no body
 	NoContent(w http.ResponseWriter) error
 
+	// EarlyHints writes a 103 Early Hints interim response with the given
+	// Link header values, before the final response
+	EarlyHints(w http.ResponseWriter, r *http.Request, links []string) error
+
 	// NotModified writes a 304 Not Modified response for conditional requests
 	NotModified(w http.ResponseWriter) error
 
 	// Redirect performs an HTTP redirect with the specified status code and location
 	Redirect(w http.ResponseWriter, r *http.Request, url string, code int) error
 
-	// ProblemDetail writes an RFC 9457 Problem Details response
+	// ProblemDetail writes an RFC 9457 Problem Details response. The request
+	// ID set in the X-Request-Id response header, such as by the request ID
+	// middleware, is added as the "request_id" extension member.
 	ProblemDetail(w http.ResponseWriter, problem *ProblemDetail) error
+
+	// ProblemDetailAuto writes an RFC 9457 Problem Details response as
+	// application/problem+xml if the client prefers XML, or as JSON otherwise.
+	// The request ID is added like for ProblemDetail, also read from the
+	// request context. If an error handler is set for the status with
+	// [Router.ErrorHandler], it writes the response instead.
+	ProblemDetailAuto(w http.ResponseWriter, r *http.Request, problem *ProblemDetail) error
+
+	// PreconditionFailed writes a 412 Precondition Failed problem detail for
+	// requests whose If-Match precondition doesn't hold
+	PreconditionFailed(w http.ResponseWriter) error
+
+	// Unavailable writes a 503 Service Unavailable problem detail with a
+	// Retry-After header telling the client when to retry
+	Unavailable(w http.ResponseWriter, retryAfter time.Duration, detail string) error
+
+	// TooManyRequests writes a 429 Too Many Requests problem detail with a
+	// Retry-After header telling the client when to retry
+	TooManyRequests(w http.ResponseWriter, retryAfter time.Duration, detail string) error
+
+	// CacheFor sets Cache-Control to allow caching the response for the given duration.
+	// Must be called before the response is written.
+	CacheFor(w http.ResponseWriter, d time.Duration)
+
+	// Private marks the response as specific to the user so that only the client caches it.
+	// Must be called before the response is written.
+	Private(w http.ResponseWriter)
+
+	// NoStore sets Cache-Control to forbid storing the response in any cache.
+	// Must be called before the response is written.
+	NoStore(w http.ResponseWriter)
 }
 
 // Ensure defaultRenderer implements Renderer
@@ -92,6 +159,63 @@ func (r *defaultRenderer) JSON(w http.ResponseWriter, statusCode int, data any)
 	return json.NewEncoder(w).Encode(data)
 }
 
+// JSONModified writes a JSON response like JSON, with lastModified as its
+// Last-Modified header. For GET and HEAD requests with an If-Modified-Since
+// header, it writes a 304 Not Modified response with no body instead if the
+// resource hasn't changed since. If-Modified-Since is ignored when the
+// request has an If-None-Match header, as required by RFC 9110.
+// A zero lastModified writes a plain JSON response.
+//
+// Example:
+//
+//	return zh.R.JSONModified(w, r, http.StatusOK, report.UpdatedAt, report)
+func (r *defaultRenderer) JSONModified(w http.ResponseWriter, req *http.Request, statusCode int, lastModified time.Time, data any) error {
+	if lastModified.IsZero() {
+		return r.JSON(w, statusCode, data)
+	}
+
+	lastModified = lastModified.UTC().Truncate(time.Second)
+	w.Header().Set(httpx.HeaderLastModified, lastModified.Format(http.TimeFormat))
+
+	if isSafeConditionalMethod(req) && req.Header.Get(httpx.HeaderIfNoneMatch) == "" {
+		if since, err := http.ParseTime(req.Header.Get(httpx.HeaderIfModifiedSince)); err == nil && !lastModified.After(since) {
+			return r.NotModified(w)
+		}
+	}
+	return r.JSON(w, statusCode, data)
+}
+
+// JSONWithETag writes a JSON response like JSON, with etag as its ETag
+// header. The etag must be quoted, such as `"v42"` or `W/"v42"`. For GET and
+// HEAD requests with an If-None-Match header matching etag, with the weak
+// comparison, it writes a 304 Not Modified response with no body instead.
+// Unlike the etag middleware, the response body isn't buffered or hashed,
+// so the ETag should come from a version or hash the handler already has.
+//
+// Example:
+//
+//	return zh.R.JSONWithETag(w, r, http.StatusOK, `"`+strconv.Itoa(user.Version)+`"`, user)
+func (r *defaultRenderer) JSONWithETag(w http.ResponseWriter, req *http.Request, statusCode int, etagValue string, data any) error {
+	if etagValue == "" {
+		return r.JSON(w, statusCode, data)
+	}
+
+	w.Header().Set(httpx.HeaderETag, etagValue)
+
+	if isSafeConditionalMethod(req) {
+		if ifNoneMatch := req.Header.Get(httpx.HeaderIfNoneMatch); ifNoneMatch != "" && etag.Matches(ifNoneMatch, etagValue) {
+			return r.NotModified(w)
+		}
+	}
+	return r.JSON(w, statusCode, data)
+}
+
+// isSafeConditionalMethod reports whether a matching If-None-Match or
+// If-Modified-Since precondition answers req with 304 Not Modified.
+func isSafeConditionalMethod(req *http.Request) bool {
+	return req.Method == http.MethodGet || req.Method == http.MethodHead
+}
+
 // Text writes a plain text response with the given status code and data
 func (r *defaultRenderer) Text(w http.ResponseWriter, statusCode int, data string) error {
 	w.Header().Set(httpx.HeaderContentType, httpx.MIMETextPlainCharset)
@@ -108,21 +232,45 @@ func (r *defaultRenderer) HTML(w http.ResponseWriter, statusCode int, data strin
 	return err
 }
 
-// Template writes an HTML response with the given status code, rendered from the specified template and data
+// Template writes an HTML response with the given status code, rendered from the specified template and data.
+// The template is rendered into a buffer first, so if it fails partway
+// through, the error is returned before anything is written and the error
+// handler can still send a proper error response.
 func (r *defaultRenderer) Template(w http.ResponseWriter, code int, tmpl *template.Template, name string, data any) error {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return err
+	}
+
 	w.Header().Set(httpx.HeaderContentType, httpx.MIMETextHTMLCharset)
 	w.WriteHeader(code)
-	return tmpl.ExecuteTemplate(w, name, data)
+	_, err := buf.WriteTo(w)
+	return err
 }
 
-// Blob writes a blob response with the given status code, content type, and data
+// Blob writes a blob response with the given status code, content type, and data.
+// It also sets the Content-Length header, for data generated in memory such
+// as PDFs or images that isn't a file on disk.
 func (r *defaultRenderer) Blob(w http.ResponseWriter, statusCode int, contentType string, data []byte) error {
 	w.Header().Set(httpx.HeaderContentType, contentType)
+	w.Header().Set(httpx.HeaderContentLength, strconv.Itoa(len(data)))
 	w.WriteHeader(statusCode)
 	_, err := w.Write(data)
 	return err
 }
 
+// BlobDownload writes a 200 OK blob response like Blob, with a
+// Content-Disposition header that makes browsers save it as filename
+// instead of displaying it. Non-ASCII filenames are encoded as per RFC 2231.
+func (r *defaultRenderer) BlobDownload(w http.ResponseWriter, filename, contentType string, data []byte) error {
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+	if disposition == "" {
+		disposition = "attachment"
+	}
+	w.Header().Set(httpx.HeaderContentDisposition, disposition)
+	return r.Blob(w, http.StatusOK, contentType, data)
+}
+
 // Stream writes a streaming response with the given status code and content type,
 // copying data from the provided reader to the response writer
 func (r *defaultRenderer) Stream(w http.ResponseWriter, statusCode int, contentType string, reader io.Reader) error {
@@ -135,6 +283,13 @@ func (r *defaultRenderer) Stream(w http.ResponseWriter, statusCode int, contentT
 // File sends the contents of a file as the response.
 // It automatically sets the Content-Type header based on the file extension
 // and handles file opening/closing. Also sets ETag and Content-Length headers.
+//
+// Range requests are supported for resumable downloads and media seeking:
+// responses advertise "Accept-Ranges: bytes", a single range is answered
+// with 206 Partial Content and a Content-Range header, multiple ranges with
+// a multipart/byteranges body, and unsatisfiable ranges with 416. If-Range
+// is checked against the ETag, so a download resumed after the file changed
+// gets the whole file.
 func (r *defaultRenderer) File(w http.ResponseWriter, req *http.Request, filename string) (err error) {
 	file, err := os.Open(filename)
 	if err != nil {
@@ -155,12 +310,8 @@ func (r *defaultRenderer) File(w http.ResponseWriter, req *http.Request, filenam
 		return err
 	}
 
-	contentType := mime.TypeByExtension(filepath.Ext(filename))
-	// Use charset-aware constants for known types
-	if contentType == "application/json" {
-		contentType = httpx.MIMEApplicationJSONCharset
-	}
-	if contentType == "" {
+	contentType := httpx.DetectContentType(filename)
+	if contentType == httpx.MIMEApplicationOctetStream {
 		buffer := make([]byte, 512)
 		n, err := file.Read(buffer)
 		if err != nil && err != io.EOF {
@@ -188,6 +339,45 @@ func (r *defaultRenderer) NoContent(w http.ResponseWriter) error {
 	return nil
 }
 
+// EarlyHints writes a 103 Early Hints interim response with links as Link
+// headers, so that browsers can start preloading assets while the handler
+// prepares the final response, which it then writes as usual. Each link is a
+// Link header value, such as "</app.css>; rel=preload; as=style". The links
+// remain set on the final response, as recommended by RFC 8297.
+//
+// It must be called before the final response is written. Returns
+// [ErrEarlyHintsNoLinks] if links is empty, and [ErrEarlyHintsUnsupported]
+// if the client can't receive interim responses, since r is HTTP/1.0, or
+// if middleware buffers the response, such as cache, etag and idempotency,
+// in both cases without writing anything. Either way, the handler can go on
+// with the final response.
+//
+// Example:
+//
+//	_ = zh.R.EarlyHints(w, r, []string{
+//	    "</static/app.css>; rel=preload; as=style",
+//	    "</static/app.js>; rel=preload; as=script",
+//	})
+//	page, err := renderDashboard(r.Context()) // Slow
+//	if err != nil {
+//	    return err
+//	}
+//	return zh.R.HTML(w, http.StatusOK, page)
+func (r *defaultRenderer) EarlyHints(w http.ResponseWriter, req *http.Request, links []string) error {
+	if len(links) == 0 {
+		return ErrEarlyHintsNoLinks
+	}
+	if !req.ProtoAtLeast(1, 1) || rwutil.DropsInterim(w) {
+		return ErrEarlyHintsUnsupported
+	}
+
+	for _, link := range links {
+		w.Header().Add(httpx.HeaderLink, link)
+	}
+	w.WriteHeader(http.StatusEarlyHints)
+	return nil
+}
+
 // NotModified writes a 304 Not Modified response for conditional requests
 func (r *defaultRenderer) NotModified(w http.ResponseWriter) error {
 	w.WriteHeader(http.StatusNotModified)
@@ -202,7 +392,110 @@ func (r *defaultRenderer) Redirect(w http.ResponseWriter, req *http.Request, url
 
 // ProblemDetail writes an RFC 9457 Problem Details response
 func (r *defaultRenderer) ProblemDetail(w http.ResponseWriter, problem *ProblemDetail) error {
+	problem = withRequestID(problem, problemRequestID(w, nil))
 	w.Header().Set(httpx.HeaderContentType, httpx.MIMEApplicationProblemJSON)
 	w.WriteHeader(problem.Status)
 	return json.NewEncoder(w).Encode(problem)
 }
+
+// ProblemDetailAuto writes an RFC 9457 Problem Details response, negotiating
+// between application/problem+xml and application/problem+json based on the
+// Accept header. JSON is used unless the client prefers XML.
+func (r *defaultRenderer) ProblemDetailAuto(w http.ResponseWriter, req *http.Request, p *ProblemDetail) error {
+	if problem.ServeError(w, req, p.Status) {
+		return nil
+	}
+	p = problemForRequest(req, p)
+	p = withRequestID(p, problemRequestID(w, req))
+	if problem.AcceptsXML(req) {
+		return p.RenderXML(w)
+	}
+	return r.ProblemDetail(w, p)
+}
+
+// PreconditionFailed writes a 412 Precondition Failed problem detail, telling
+// the client that the resource changed since it fetched its ETag
+func (r *defaultRenderer) PreconditionFailed(w http.ResponseWriter) error {
+	problem := NewProblemDetail(http.StatusPreconditionFailed, "The resource has been modified since it was retrieved")
+	return r.ProblemDetail(w, problem)
+}
+
+// Unavailable writes a 503 Service Unavailable problem detail, for example
+// while a dependency is down. retryAfter is sent as the Retry-After header in
+// seconds, rounded up; it is omitted when retryAfter is not positive.
+//
+// Example:
+//
+//	if !db.Healthy() {
+//	    return zh.R.Unavailable(w, 30*time.Second, "Database is unavailable")
+//	}
+func (r *defaultRenderer) Unavailable(w http.ResponseWriter, retryAfter time.Duration, detail string) error {
+	return r.retryLater(w, http.StatusServiceUnavailable, retryAfter, detail)
+}
+
+// TooManyRequests writes a 429 Too Many Requests problem detail, for example
+// when a per-user quota is exhausted. retryAfter is sent as the Retry-After
+// header in seconds, rounded up; it is omitted when retryAfter is not positive.
+//
+// Example:
+//
+//	if quota.Exceeded() {
+//	    return zh.R.TooManyRequests(w, quota.ResetIn(), "Export quota exceeded")
+//	}
+func (r *defaultRenderer) TooManyRequests(w http.ResponseWriter, retryAfter time.Duration, detail string) error {
+	return r.retryLater(w, http.StatusTooManyRequests, retryAfter, detail)
+}
+
+// retryLater writes a problem detail with the given status and a Retry-After header.
+func (r *defaultRenderer) retryLater(w http.ResponseWriter, statusCode int, retryAfter time.Duration, detail string) error {
+	if retryAfter > 0 {
+		seconds := int64((retryAfter + time.Second - 1) / time.Second)
+		w.Header().Set(httpx.HeaderRetryAfter, strconv.FormatInt(seconds, 10))
+	}
+	return r.ProblemDetail(w, NewProblemDetail(statusCode, detail))
+}
+
+// CacheFor sets Cache-Control to "public, max-age=<seconds>", or to "private,
+// max-age=<seconds>" if Private was called first. A duration under one second
+// sets "no-cache" so that caches must revalidate the response on every use.
+// It replaces any Cache-Control directives already set.
+func (r *defaultRenderer) CacheFor(w http.ResponseWriter, d time.Duration) {
+	if d < time.Second {
+		w.Header().Set(httpx.HeaderCacheControl, httpx.CacheControlNoCache)
+		return
+	}
+
+	visibility := httpx.CacheControlPublic
+	if slices.Contains(cacheDirectives(w.Header()), httpx.CacheControlPrivate) {
+		visibility = httpx.CacheControlPrivate
+	}
+	maxAge := httpx.CacheControlMaxAge + "=" + strconv.FormatInt(int64(d/time.Second), 10)
+	w.Header().Set(httpx.HeaderCacheControl, visibility+", "+maxAge)
+}
+
+// Private adds the "private" directive to Cache-Control, replacing "public",
+// so that shared caches such as proxies and the cache middleware don't store
+// the response. It composes with CacheFor in either order.
+func (r *defaultRenderer) Private(w http.ResponseWriter) {
+	directives := slices.DeleteFunc(cacheDirectives(w.Header()), func(d string) bool {
+		return d == httpx.CacheControlPublic || d == httpx.CacheControlPrivate
+	})
+	directives = slices.Insert(directives, 0, httpx.CacheControlPrivate)
+	w.Header().Set(httpx.HeaderCacheControl, strings.Join(directives, ", "))
+}
+
+// NoStore sets Cache-Control to "no-store", replacing any other directives.
+func (r *defaultRenderer) NoStore(w http.ResponseWriter) {
+	w.Header().Set(httpx.HeaderCacheControl, httpx.CacheControlNoStore)
+}
+
+// cacheDirectives returns the trimmed, non-empty Cache-Control directives of h.
+func cacheDirectives(h http.Header) []string {
+	var directives []string
+	for _, d := range strings.Split(h.Get(httpx.HeaderCacheControl), ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			directives = append(directives, d)
+		}
+	}
+	return directives
+}
@@ -1,16 +1,28 @@
 package zerohttp
 
 import (
+	"context"
 	"errors"
 	"html/template"
+	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/middleware/compress"
+	"github.com/alexferl/zerohttp/middleware/etag"
+	"github.com/alexferl/zerohttp/middleware/requestid"
+	"github.com/alexferl/zerohttp/middleware/timeout"
 	"github.com/alexferl/zerohttp/zhtest"
 )
 
@@ -51,6 +63,94 @@ func TestRenderer_JSON_Error(t *testing.T) {
 	zhtest.AssertError(t, err)
 }
 
+func TestRenderer_JSONModified(t *testing.T) {
+	lastModified := time.Date(2026, 3, 1, 12, 0, 0, 500, time.UTC)
+	header := lastModified.Format(http.TimeFormat)
+
+	tests := []struct {
+		name       string
+		method     string
+		headers    map[string]string
+		wantStatus int
+	}{
+		{"no precondition", http.MethodGet, nil, http.StatusOK},
+		{"not modified", http.MethodGet, map[string]string{httpx.HeaderIfModifiedSince: header}, http.StatusNotModified},
+		{"head not modified", http.MethodHead, map[string]string{httpx.HeaderIfModifiedSince: header}, http.StatusNotModified},
+		{"modified", http.MethodGet, map[string]string{
+			httpx.HeaderIfModifiedSince: lastModified.Add(-time.Hour).Format(http.TimeFormat),
+		}, http.StatusOK},
+		{"invalid date", http.MethodGet, map[string]string{httpx.HeaderIfModifiedSince: "yesterday"}, http.StatusOK},
+		{"unsafe method", http.MethodPost, map[string]string{httpx.HeaderIfModifiedSince: header}, http.StatusOK},
+		{"if-none-match takes precedence", http.MethodGet, map[string]string{
+			httpx.HeaderIfModifiedSince: header,
+			httpx.HeaderIfNoneMatch:     `"other"`,
+		}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := zhtest.NewRequest(tt.method, "/report").WithHeaders(tt.headers).Build()
+			w := httptest.NewRecorder()
+
+			zhtest.AssertNoError(t, R.JSONModified(w, req, http.StatusOK, lastModified, M{"id": 1}))
+			zhtest.AssertWith(t, w).
+				Status(tt.wantStatus).
+				Header(httpx.HeaderLastModified, header)
+			if tt.wantStatus == http.StatusNotModified {
+				zhtest.AssertWith(t, w).BodyEmpty()
+			} else {
+				zhtest.AssertWith(t, w).JSONEq(`{"id":1}`)
+			}
+		})
+	}
+
+	t.Run("zero time", func(t *testing.T) {
+		req := zhtest.NewRequest(http.MethodGet, "/report").WithHeader(httpx.HeaderIfModifiedSince, header).Build()
+		w := httptest.NewRecorder()
+
+		zhtest.AssertNoError(t, R.JSONModified(w, req, http.StatusOK, time.Time{}, M{"id": 1}))
+		zhtest.AssertWith(t, w).
+			Status(http.StatusOK).
+			HeaderNotExists(httpx.HeaderLastModified)
+	})
+}
+
+func TestRenderer_JSONWithETag(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		etag        string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{"no precondition", http.MethodGet, `"v1"`, "", http.StatusOK},
+		{"match", http.MethodGet, `"v1"`, `"v1"`, http.StatusNotModified},
+		{"match in list", http.MethodGet, `"v1"`, `"v0", "v1"`, http.StatusNotModified},
+		{"weak match", http.MethodGet, `"v1"`, `W/"v1"`, http.StatusNotModified},
+		{"wildcard", http.MethodHead, `"v1"`, "*", http.StatusNotModified},
+		{"mismatch", http.MethodGet, `"v2"`, `"v1"`, http.StatusOK},
+		{"unsafe method", http.MethodPut, `"v1"`, `"v1"`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := zhtest.NewRequest(tt.method, "/users/1").Build()
+			if tt.ifNoneMatch != "" {
+				req.Header.Set(httpx.HeaderIfNoneMatch, tt.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+
+			zhtest.AssertNoError(t, R.JSONWithETag(w, req, http.StatusOK, tt.etag, M{"id": 1}))
+			zhtest.AssertWith(t, w).
+				Status(tt.wantStatus).
+				Header(httpx.HeaderETag, tt.etag)
+			if tt.wantStatus == http.StatusNotModified {
+				zhtest.AssertWith(t, w).BodyEmpty()
+			}
+		})
+	}
+}
+
 func TestRenderer_Text(t *testing.T) {
 	tests := []struct {
 		name string
@@ -107,6 +207,30 @@ func TestRenderer_Template(t *testing.T) {
 		zhtest.AssertError(t, err)
 	})
 
+	t.Run("writes nothing if the template fails mid-render", func(t *testing.T) {
+		failing := template.Must(template.New("page.html").Funcs(template.FuncMap{
+			"fail": func() (string, error) { return "", errors.New("render failed") },
+		}).Parse(`<html><body><h1>{{.Title}}</h1>{{fail}}</body></html>`))
+
+		w := httptest.NewRecorder()
+		err := R.Template(w, http.StatusOK, failing, "page.html", map[string]string{"Title": "Partial"})
+		zhtest.AssertErrorContains(t, err, "render failed")
+		zhtest.AssertFalse(t, w.Flushed)
+		zhtest.AssertWith(t, w).
+			BodyEmpty().
+			HeaderNotExists(httpx.HeaderContentType)
+
+		// The error handler can still send a 500
+		router := NewRouter()
+		router.GET("/", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+			return R.Template(w, http.StatusOK, failing, "page.html", map[string]string{"Title": "Partial"})
+		}))
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		zhtest.AssertWith(t, w).Status(http.StatusInternalServerError)
+		zhtest.AssertFalse(t, strings.Contains(w.Body.String(), "Partial"))
+	})
+
 	t.Run("sets correct status code", func(t *testing.T) {
 		w := httptest.NewRecorder()
 
@@ -131,9 +255,38 @@ func TestRenderer_Blob(t *testing.T) {
 	zhtest.AssertNoError(t, R.Blob(w, http.StatusOK, "image/png", data))
 	zhtest.AssertWith(t, w).
 		Header(httpx.HeaderContentType, "image/png").
+		Header(httpx.HeaderContentLength, "4").
+		HeaderNotExists(httpx.HeaderContentDisposition).
 		Body(string(data))
 }
 
+func TestRenderer_BlobDownload(t *testing.T) {
+	tests := []struct {
+		name        string
+		filename    string
+		disposition string
+	}{
+		{"ascii", "report.pdf", "attachment; filename=report.pdf"},
+		{"quoted", "my report.pdf", `attachment; filename="my report.pdf"`},
+		{"non-ascii", "résumé.pdf", "attachment; filename*=utf-8''r%C3%A9sum%C3%A9.pdf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := []byte("%PDF-1.7")
+			w := httptest.NewRecorder()
+
+			zhtest.AssertNoError(t, R.BlobDownload(w, tt.filename, "application/pdf", data))
+			zhtest.AssertWith(t, w).
+				Status(http.StatusOK).
+				Header(httpx.HeaderContentType, "application/pdf").
+				Header(httpx.HeaderContentLength, strconv.Itoa(len(data))).
+				Header(httpx.HeaderContentDisposition, tt.disposition).
+				Body(string(data))
+		})
+	}
+}
+
 func TestRenderer_Stream(t *testing.T) {
 	data := "streaming content"
 	w := httptest.NewRecorder()
@@ -209,7 +362,96 @@ func TestRenderer_File_Range(t *testing.T) {
 	zhtest.AssertNoError(t, R.File(w, r, filePath))
 	zhtest.AssertWith(t, w).
 		Status(http.StatusPartialContent).
+		Header(httpx.HeaderAcceptRanges, "bytes").
+		Header(httpx.HeaderContentRange, "bytes 5-10/16").
+		Header(httpx.HeaderContentLength, "6").
 		Body("56789A")
+
+	t.Run("full response advertises ranges", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/test", nil)
+
+		zhtest.AssertNoError(t, R.File(w, r, filePath))
+		zhtest.AssertWith(t, w).
+			Status(http.StatusOK).
+			Header(httpx.HeaderAcceptRanges, "bytes").
+			Header(httpx.HeaderContentLength, "16").
+			Body(content)
+	})
+
+	t.Run("suffix range", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/test", nil)
+		r.Header.Set(httpx.HeaderRange, "bytes=-4")
+
+		zhtest.AssertNoError(t, R.File(w, r, filePath))
+		zhtest.AssertWith(t, w).
+			Status(http.StatusPartialContent).
+			Header(httpx.HeaderContentRange, "bytes 12-15/16").
+			Body("CDEF")
+	})
+
+	t.Run("multiple ranges", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/test", nil)
+		r.Header.Set(httpx.HeaderRange, "bytes=0-1,10-11")
+
+		zhtest.AssertNoError(t, R.File(w, r, filePath))
+		zhtest.AssertWith(t, w).Status(http.StatusPartialContent)
+
+		mediaType, params, err := mime.ParseMediaType(w.Header().Get(httpx.HeaderContentType))
+		zhtest.AssertNoError(t, err)
+		zhtest.AssertEqual(t, "multipart/byteranges", mediaType)
+		zhtest.AssertEqual(t, strconv.Itoa(w.Body.Len()), w.Header().Get(httpx.HeaderContentLength))
+
+		mr := multipart.NewReader(w.Body, params["boundary"])
+		for _, want := range []struct{ contentRange, body string }{
+			{"bytes 0-1/16", "01"},
+			{"bytes 10-11/16", "AB"},
+		} {
+			part, err := mr.NextPart()
+			zhtest.AssertNoError(t, err)
+			zhtest.AssertEqual(t, want.contentRange, part.Header.Get(httpx.HeaderContentRange))
+			zhtest.AssertEqual(t, httpx.MIMETextPlainCharset, part.Header.Get(httpx.HeaderContentType))
+			body, err := io.ReadAll(part)
+			zhtest.AssertNoError(t, err)
+			zhtest.AssertEqual(t, want.body, string(body))
+		}
+		_, err = mr.NextPart()
+		zhtest.AssertErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("unsatisfiable range", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/test", nil)
+		r.Header.Set(httpx.HeaderRange, "bytes=100-200")
+
+		zhtest.AssertNoError(t, R.File(w, r, filePath))
+		zhtest.AssertWith(t, w).
+			Status(http.StatusRequestedRangeNotSatisfiable).
+			Header(httpx.HeaderContentRange, "bytes */16")
+	})
+
+	t.Run("If-Range resumes only unchanged files", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		zhtest.AssertNoError(t, R.File(w, httptest.NewRequest(http.MethodGet, "/test", nil), filePath))
+		etag := w.Header().Get(httpx.HeaderETag)
+
+		w = httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/test", nil)
+		r.Header.Set(httpx.HeaderRange, "bytes=8-")
+		r.Header.Set(httpx.HeaderIfRange, etag)
+		zhtest.AssertNoError(t, R.File(w, r, filePath))
+		zhtest.AssertWith(t, w).Status(http.StatusPartialContent).Body("89ABCDEF")
+
+		// A stale validator gets the whole file
+		w = httptest.NewRecorder()
+		r = httptest.NewRequest(http.MethodGet, "/test", nil)
+		r.Header.Set(httpx.HeaderRange, "bytes=8-")
+		r.Header.Set(httpx.HeaderIfRange, `"stale"`)
+		zhtest.AssertNoError(t, R.File(w, r, filePath))
+		zhtest.AssertWith(t, w).Status(http.StatusOK).Body(content)
+	})
 }
 
 func TestRenderer_NoContent(t *testing.T) {
@@ -222,6 +464,75 @@ func TestRenderer_NoContent(t *testing.T) {
 		HeaderNotExists(httpx.HeaderContentType)
 }
 
+func TestRenderer_EarlyHints(t *testing.T) {
+	links := []string{
+		"</static/app.css>; rel=preload; as=style",
+		"</static/app.js>; rel=preload; as=script",
+	}
+
+	app := New()
+	app.Use(compress.New(), timeout.New())
+	app.GET("/dashboard", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		if err := R.EarlyHints(w, r, links); err != nil {
+			return err
+		}
+		return R.HTML(w, http.StatusOK, "<h1>Dashboard</h1>")
+	}))
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	var hints []http.Header
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			if code == http.StatusEarlyHints {
+				hints = append(hints, http.Header(header))
+			}
+			return nil
+		},
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), http.MethodGet, server.URL+"/dashboard", nil)
+	zhtest.AssertNoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	zhtest.AssertNoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	zhtest.AssertNoError(t, err)
+
+	zhtest.AssertEqual(t, 1, len(hints))
+	zhtest.AssertEqual(t, links, hints[0].Values(httpx.HeaderLink))
+	zhtest.AssertEqual(t, http.StatusOK, resp.StatusCode)
+	zhtest.AssertEqual(t, "<h1>Dashboard</h1>", string(body))
+	zhtest.AssertEqual(t, links, resp.Header.Values(httpx.HeaderLink))
+
+	t.Run("no links", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := zhtest.NewRequest(http.MethodGet, "/").Build()
+		zhtest.AssertErrorIs(t, R.EarlyHints(w, req, nil), ErrEarlyHintsNoLinks)
+		zhtest.AssertWith(t, w).HeaderNotExists(httpx.HeaderLink)
+	})
+
+	t.Run("HTTP/1.0", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := zhtest.NewRequest(http.MethodGet, "/").Build()
+		req.Proto, req.ProtoMajor, req.ProtoMinor = "HTTP/1.0", 1, 0
+		zhtest.AssertErrorIs(t, R.EarlyHints(w, req, links), ErrEarlyHintsUnsupported)
+		zhtest.AssertWith(t, w).HeaderNotExists(httpx.HeaderLink)
+	})
+
+	t.Run("buffered response", func(t *testing.T) {
+		var hintsErr error
+		handler := etag.New()(compress.New()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hintsErr = R.EarlyHints(w, r, links)
+			_ = R.Text(w, http.StatusOK, "ok")
+		})))
+		w := zhtest.Serve(handler, zhtest.NewRequest(http.MethodGet, "/").Build())
+
+		zhtest.AssertErrorIs(t, hintsErr, ErrEarlyHintsUnsupported)
+		zhtest.AssertWith(t, w).Status(http.StatusOK).HeaderNotExists(httpx.HeaderLink)
+	})
+}
+
 func TestRenderer_NotModified(t *testing.T) {
 	w := httptest.NewRecorder()
 
@@ -232,6 +543,126 @@ func TestRenderer_NotModified(t *testing.T) {
 		HeaderNotExists(httpx.HeaderContentType)
 }
 
+func TestRenderer_RetryLater(t *testing.T) {
+	tests := []struct {
+		name       string
+		render     func(w http.ResponseWriter) error
+		wantStatus int
+		wantRetry  string
+	}{
+		{"unavailable", func(w http.ResponseWriter) error {
+			return R.Unavailable(w, 30*time.Second, "Database is unavailable")
+		}, http.StatusServiceUnavailable, "30"},
+		{"unavailable rounds up", func(w http.ResponseWriter) error {
+			return R.Unavailable(w, 1500*time.Millisecond, "Database is unavailable")
+		}, http.StatusServiceUnavailable, "2"},
+		{"too many requests", func(w http.ResponseWriter) error {
+			return R.TooManyRequests(w, time.Minute, "Database is unavailable")
+		}, http.StatusTooManyRequests, "60"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			zhtest.AssertNoError(t, tt.render(w))
+
+			zhtest.AssertWith(t, w).
+				Status(tt.wantStatus).
+				Header(httpx.HeaderRetryAfter, tt.wantRetry).
+				Header(httpx.HeaderContentType, httpx.MIMEApplicationProblemJSON).
+				BodyContains(`"status":` + strconv.Itoa(tt.wantStatus)).
+				BodyContains(`"detail":"Database is unavailable"`)
+		})
+	}
+
+	t.Run("no retry after", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		zhtest.AssertNoError(t, R.TooManyRequests(w, 0, ""))
+
+		zhtest.AssertWith(t, w).
+			Status(http.StatusTooManyRequests).
+			HeaderNotExists(httpx.HeaderRetryAfter)
+	})
+}
+
+func TestRenderer_PreconditionFailed(t *testing.T) {
+	w := httptest.NewRecorder()
+	zhtest.AssertNoError(t, R.PreconditionFailed(w))
+
+	zhtest.AssertWith(t, w).
+		Status(http.StatusPreconditionFailed).
+		Header(httpx.HeaderContentType, httpx.MIMEApplicationProblemJSON).
+		BodyContains(`"status":412`)
+}
+
+func TestRenderer_ProblemDetailAuto(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"json by default", "", httpx.MIMEApplicationProblemJSON, `"detail":"User not found"`},
+		{"json for wildcard", "*/*", httpx.MIMEApplicationProblemJSON, `"detail":"User not found"`},
+		{"xml when preferred", "application/problem+xml", httpx.MIMEApplicationProblemXML, "<detail>User not found</detail>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := zhtest.NewRequest(http.MethodGet, "/").Build()
+			if tt.accept != "" {
+				req.Header.Set(httpx.HeaderAccept, tt.accept)
+			}
+			w := httptest.NewRecorder()
+			zhtest.AssertNoError(t, R.ProblemDetailAuto(w, req, NewProblemDetail(http.StatusNotFound, "User not found")))
+
+			zhtest.AssertWith(t, w).
+				Status(http.StatusNotFound).
+				Header(httpx.HeaderContentType, tt.contentType).
+				BodyContains(tt.body)
+		})
+	}
+}
+
+func TestRenderer_CacheDirectives(t *testing.T) {
+	tests := []struct {
+		name  string
+		apply func(w http.ResponseWriter)
+		want  string
+	}{
+		{"cache for", func(w http.ResponseWriter) { R.CacheFor(w, 5*time.Minute) }, "public, max-age=300"},
+		{"cache for truncates to seconds", func(w http.ResponseWriter) { R.CacheFor(w, 1500*time.Millisecond) }, "public, max-age=1"},
+		{"cache for under a second", func(w http.ResponseWriter) { R.CacheFor(w, 0) }, "no-cache"},
+		{"private", func(w http.ResponseWriter) { R.Private(w) }, "private"},
+		{"private then cache for", func(w http.ResponseWriter) {
+			R.Private(w)
+			R.CacheFor(w, time.Hour)
+		}, "private, max-age=3600"},
+		{"cache for then private", func(w http.ResponseWriter) {
+			R.CacheFor(w, time.Hour)
+			R.Private(w)
+		}, "private, max-age=3600"},
+		{"private is idempotent", func(w http.ResponseWriter) {
+			R.Private(w)
+			R.Private(w)
+		}, "private"},
+		{"no store", func(w http.ResponseWriter) { R.NoStore(w) }, "no-store"},
+		{"no store replaces", func(w http.ResponseWriter) {
+			R.CacheFor(w, time.Hour)
+			R.NoStore(w)
+		}, "no-store"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.apply(w)
+			zhtest.AssertNoError(t, R.NoContent(w))
+			zhtest.AssertWith(t, w).Header(httpx.HeaderCacheControl, tt.want)
+		})
+	}
+}
+
 func TestRenderer_Redirect(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -322,4 +753,57 @@ func TestRenderer_ProblemDetail(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("request ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		w.Header().Set(httpx.HeaderXRequestId, "req-123")
+
+		problem := NewProblemDetail(http.StatusNotFound, "Resource not found")
+
+		zhtest.AssertNoError(t, R.ProblemDetail(w, problem))
+		zhtest.AssertWith(t, w).
+			Status(http.StatusNotFound).
+			JSONPathEqual("request_id", "req-123")
+
+		// The problem detail itself isn't modified, since it may be shared
+		_, ok := problem.Extensions["request_id"]
+		zhtest.AssertFalse(t, ok)
+	})
+
+	t.Run("no request ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		zhtest.AssertNoError(t, R.ProblemDetail(w, NewProblemDetail(http.StatusNotFound, "Resource not found")))
+		zhtest.AssertWith(t, w).JSONPathNotExists("request_id")
+	})
+
+	t.Run("request ID set by the caller", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		w.Header().Set(httpx.HeaderXRequestId, "req-123")
+
+		problem := NewProblemDetail(http.StatusNotFound, "Resource not found").Set("request_id", "custom")
+
+		zhtest.AssertNoError(t, R.ProblemDetail(w, problem))
+		zhtest.AssertWith(t, w).JSONPathEqual("request_id", "custom")
+	})
+
+	t.Run("request ID from context", func(t *testing.T) {
+		app := NewRouter(requestid.New(requestid.Config{Header: "X-Correlation-Id"}))
+		app.GET("/", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+			return R.ProblemDetailAuto(w, r, NewProblemDetail(http.StatusConflict, "Version conflict"))
+		}))
+
+		w := zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/").WithHeader("X-Correlation-Id", "corr-1").Build())
+		zhtest.AssertWith(t, w).
+			Status(http.StatusConflict).
+			JSONPathEqual("request_id", "corr-1")
+
+		w = zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/").
+			WithHeader("X-Correlation-Id", "corr-2").
+			WithHeader(httpx.HeaderAccept, httpx.MIMEApplicationProblemXML).
+			Build())
+		zhtest.AssertWith(t, w).
+			Status(http.StatusConflict).
+			BodyContains("<request_id>corr-2</request_id>")
+	})
 }
@@ -1,21 +1,27 @@
 package zerohttp
 
 import (
+	"context"
 	"embed"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"net/http"
 	"os"
 	"path"
+	"path/filepath"
+	"runtime/debug"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/alexferl/zerohttp/httpx"
 	"github.com/alexferl/zerohttp/internal/problem"
+	"github.com/alexferl/zerohttp/internal/rwutil"
 	"github.com/alexferl/zerohttp/log"
 	"github.com/alexferl/zerohttp/middleware/requestlogger"
 	"github.com/alexferl/zerohttp/validator"
@@ -50,10 +56,15 @@ func init() {
 // HTTP handler is expected.
 //
 // Errors are automatically converted to appropriate HTTP responses:
+//   - ProblemDetail errors are rendered as is
+//   - Errors matched by one of Config.ErrorMappers return the mapper's
+//     problem detail
 //   - Validation errors return 422 Unprocessable Entity with field details
 //   - Binding errors return 400 Bad Request
 //   - Request too large returns 413 Payload Too Large
-//   - ProblemDetail errors return their specified status code
+//   - Invalid path parameters ([ParamError]) return 400 Bad Request
+//   - Sentinel errors such as sql.ErrNoRows return the status mapped by
+//     [ProblemFromError] or Config.ErrorMappings
 //   - All other errors return 500 Internal Server Error
 //
 // Example:
@@ -88,16 +99,34 @@ func (h HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	if err := h(w, r); err != nil {
 		// Handle all errors directly - no panic propagation
-		handleHandlerError(w, err)
+		handleHandlerError(w, r, err)
 	}
 }
 
 // handleHandlerError handles all handler errors.
-// Returns appropriate HTTP responses for different error types.
-func handleHandlerError(w http.ResponseWriter, err error) {
+// Returns appropriate HTTP responses for different error types, or serves
+// the error handler set for the status with [Router.ErrorHandler].
+func handleHandlerError(w http.ResponseWriter, r *http.Request, err error) {
+	// Problem details returned as errors, such as from ProblemFromError,
+	// which keeps the error it was created from for the router's settings
+	var pd *ProblemDetail
+	if errors.As(err, &pd) {
+		renderProblemError(w, r, err, problemForRequest(r, pd))
+		return
+	}
+
+	// Problem details from a registered mapper
+	if pd = mapError(r, err); pd != nil {
+		renderProblemError(w, r, err, pd)
+		return
+	}
+
 	// Check for validation errors (422)
 	var verr validator.ValidationErrorer
 	if errors.As(err, &verr) {
+		if problem.ServeError(w, r, http.StatusUnprocessableEntity) {
+			return
+		}
 		w.Header().Set(httpx.HeaderContentType, httpx.MIMEApplicationProblemJSON)
 		w.WriteHeader(http.StatusUnprocessableEntity)
 		response := map[string]any{
@@ -112,8 +141,31 @@ func handleHandlerError(w http.ResponseWriter, err error) {
 		return
 	}
 
+	// Check for request body too large errors (413), before binding
+	// errors since binding a body over the limit fails with one
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		if problem.ServeError(w, r, http.StatusRequestEntityTooLarge) {
+			return
+		}
+		w.Header().Set(httpx.HeaderContentType, httpx.MIMEApplicationProblemJSON)
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		response := map[string]any{
+			"title":  "Payload Too Large",
+			"status": http.StatusRequestEntityTooLarge,
+			"detail": "Request body exceeds maximum allowed size",
+		}
+		if encErr := json.NewEncoder(w).Encode(response); encErr != nil {
+			log.GetGlobalLogger().Error("Failed to encode payload too large error response", log.E(encErr))
+		}
+		return
+	}
+
 	// Check for binding errors (400)
 	if IsBindError(err) {
+		if problem.ServeError(w, r, http.StatusBadRequest) {
+			return
+		}
 		w.Header().Set(httpx.HeaderContentType, httpx.MIMEApplicationProblemJSON)
 		w.WriteHeader(http.StatusBadRequest)
 		response := map[string]any{
@@ -127,35 +179,40 @@ func handleHandlerError(w http.ResponseWriter, err error) {
 		return
 	}
 
-	// Check for request body too large errors (413)
-	var maxBytesErr *http.MaxBytesError
-	if errors.As(err, &maxBytesErr) {
-		w.Header().Set(httpx.HeaderContentType, httpx.MIMEApplicationProblemJSON)
-		w.WriteHeader(http.StatusRequestEntityTooLarge)
-		response := map[string]any{
-			"title":  "Payload Too Large",
-			"status": http.StatusRequestEntityTooLarge,
-			"detail": "Request body exceeds maximum allowed size",
-		}
-		if encErr := json.NewEncoder(w).Encode(response); encErr != nil {
-			log.GetGlobalLogger().Error("Failed to encode payload too large error response", log.E(encErr))
-		}
+	// Invalid path parameters (400)
+	var paramErr *ParamError
+	if errors.As(err, &paramErr) {
+		renderProblemError(w, r, err, paramErr.problem())
+		return
+	}
+
+	// Standard and registered sentinel errors, such as sql.ErrNoRows
+	if pd := lookupErrorMapping(err, requestErrorMappings(r)); pd != nil {
+		renderProblemError(w, r, err, withDebugInfo(r, pd, err))
 		return
 	}
 
 	// For all other errors, return 500 Internal Server Error
-	// Log the actual error for debugging
-	log.GetGlobalLogger().Error("Handler error", log.E(err))
+	pd = NewProblemDetail(http.StatusInternalServerError, "An unexpected error occurred")
+	renderProblemError(w, r, err, withDebugInfo(r, pd, err))
+}
 
-	w.Header().Set(httpx.HeaderContentType, httpx.MIMEApplicationProblemJSON)
-	w.WriteHeader(http.StatusInternalServerError)
-	response := map[string]any{
-		"title":  "Internal Server Error",
-		"status": http.StatusInternalServerError,
-		"detail": "An unexpected error occurred",
+// renderProblemError renders the problem detail for a handler error,
+// negotiating between JSON and XML like [Renderer.ProblemDetailAuto].
+// Server errors are logged since the response doesn't describe the cause.
+func renderProblemError(w http.ResponseWriter, r *http.Request, err error, pd *ProblemDetail) {
+	if pd.Status >= http.StatusInternalServerError {
+		log.GetGlobalLogger().Error("Handler error", log.E(err))
+	}
+	if problem.ServeError(w, r, pd.Status) {
+		return
 	}
-	if encErr := json.NewEncoder(w).Encode(response); encErr != nil {
-		log.GetGlobalLogger().Error("Failed to encode internal server error response", log.E(encErr))
+	render := pd.Render
+	if problem.AcceptsXML(r) {
+		render = pd.RenderXML
+	}
+	if renderErr := render(w); renderErr != nil {
+		log.GetGlobalLogger().Error("Failed to encode problem detail response", log.E(renderErr))
 	}
 }
 
@@ -177,6 +234,10 @@ func (h *headResponseWriter) Write(p []byte) (int, error) {
 }
 
 func (h *headResponseWriter) WriteHeader(code int) {
+	if rwutil.IsInformational(code) {
+		h.ResponseWriter.WriteHeader(code)
+		return
+	}
 	h.code = code
 	// Don't write headers yet - we'll do it in Close()
 }
@@ -248,18 +309,44 @@ type Router interface {
 	// Middleware is applied to all routes registered after this call.
 	Use(mw ...MiddlewareFunc)
 
+	// Pre adds middleware that runs before routing, on every request.
+	// It can rewrite the request path to change which route matches.
+	// Must be called before the router serves its first request.
+	Pre(mw ...MiddlewareFunc)
+
 	// Group creates a new router scope that inherits the current middleware chain.
 	// This allows for organizing routes and applying middleware to specific groups.
 	Group(fn func(Router))
 
+	// Route creates a group whose routes are registered under prefix.
+	// NotFound and MethodNotAllowed handlers set in the group apply to
+	// requests under the prefix.
+	Route(prefix string, fn func(Router))
+
+	// Mount serves every request under prefix with h, with the prefix
+	// stripped from the request path.
+	Mount(prefix string, h http.Handler)
+
+	// MountRouter registers the routes of sub under prefix, so that 404 and
+	// 405 responses account for them.
+	MountRouter(prefix string, sub Router)
+
 	// NotFound sets a custom handler for 404 Not Found responses.
 	// If not set, a default handler that returns a problem detail response is used.
+	// In a group created with Route, it only applies to requests under the group's prefix.
 	NotFound(h http.Handler)
 
 	// MethodNotAllowed sets a custom handler for 405 Method Not Allowed responses.
 	// If not set, a default handler that returns a problem detail response is used.
+	// In a group created with Route, it only applies to requests under the group's prefix.
 	MethodNotAllowed(h http.Handler)
 
+	// ErrorHandler sets a custom handler for error responses with the given
+	// 4xx or 5xx status code, such as a custom 403 or 429 page. It replaces
+	// the problem details rendered for the status by handlers and middleware.
+	// In a group created with Route, it only applies to requests under the group's prefix.
+	ErrorHandler(status int, h http.Handler)
+
 	// Files serves static files from embedded FS at the specified prefix.
 	// The prefix is stripped from URLs before looking up files in the embedFS.
 	Files(prefix string, embedFS embed.FS, dir string)
@@ -268,21 +355,46 @@ type Router interface {
 	// The prefix is stripped from URLs before looking up files in the directory.
 	FilesDir(prefix, dir string)
 
+	// FilesDirSecure serves files from a directory at the specified prefix, like
+	// FilesDir, but answers requests resolving outside the directory, through
+	// ".." segments or symlinks, with 403 Forbidden. Directories are not listed.
+	// Use it for directories holding untrusted files, such as uploads.
+	FilesDirSecure(prefix, dir string)
+
 	// Static serves a static web application from embedded FS with configurable fallback behavior.
 	// If fallback is true, falls back to index.html for non-existent files (SPA behavior).
 	// If fallback is false, uses the custom NotFound handler for missing files.
-	// Requests matching apiPrefix patterns return 404 regardless.
+	// Requests matching apiPrefix patterns return 404 regardless, and paths of
+	// routes registered without GET, before or after, return 405.
+	// Pre-compressed "<file>.br" and "<file>.gz" siblings are served to
+	// clients that accept brotli or gzip.
 	Static(embedFS embed.FS, distDir string, fallback bool, apiPrefix ...string)
 
 	// StaticDir serves a static web application from a directory with configurable fallback behavior.
 	// If fallback is true, falls back to index.html for non-existent files (SPA behavior).
 	// If fallback is false, uses the custom NotFound handler for missing files.
-	// Requests matching apiPrefix patterns return 404 regardless.
+	// Requests matching apiPrefix patterns return 404 regardless, and paths of
+	// routes registered without GET, before or after, return 405.
+	// Pre-compressed "<file>.br" and "<file>.gz" siblings are served to
+	// clients that accept brotli or gzip.
 	StaticDir(dir string, fallback bool, apiPrefix ...string)
 
+	// StaticFS serves a static web application from any fs.FS, such as a MemFS
+	// holding assets generated at runtime, with configurable fallback behavior.
+	// If fallback is true, falls back to index.html for non-existent files (SPA behavior).
+	// If fallback is false, uses the custom NotFound handler for missing files.
+	// Requests matching apiPrefix patterns return 404 regardless, and paths of
+	// routes registered without GET, before or after, return 405.
+	// Pre-compressed "<file>.br" and "<file>.gz" siblings are served to
+	// clients that accept brotli or gzip.
+	StaticFS(filesystem fs.FS, fallback bool, apiPrefix ...string)
+
 	// ServeMux returns the underlying http.ServeMux for advanced usage or integration.
 	ServeMux() *http.ServeMux
 
+	// Routes returns the registered routes, sorted by path and method.
+	Routes() []RouteInfo
+
 	// ServeHTTP implements the http.Handler interface, making the router compatible
 	// with Go's standard HTTP server and middleware ecosystem.
 	ServeHTTP(w http.ResponseWriter, req *http.Request)
@@ -312,6 +424,15 @@ type Router interface {
 // Ensure defaultRouter implements Router
 var _ Router = (*defaultRouter)(nil)
 
+// RouteInfo describes a registered route.
+type RouteInfo struct {
+	// Method is the HTTP method of the route, such as "GET".
+	Method string
+
+	// Path is the path pattern the route was registered with, such as "/users/{id}".
+	Path string
+}
+
 // defaultRouter is the concrete implementation of the Router interface.
 // It wraps Go's standard http.ServeMux and adds method-specific routing,
 // middleware support, and proper HTTP status code handling.
@@ -322,17 +443,27 @@ type defaultRouter struct {
 	// chain contains the middleware functions that will be applied to all routes
 	chain []MiddlewareFunc
 
-	// handlerMu protects notFoundHandler and methodNotAllowedHandler.
-	// These handlers can be changed at runtime via NotFound() and MethodNotAllowed().
-	handlerMu sync.RWMutex
+	// pre contains the middleware functions that run before routing.
+	// Uses pointer so groups share the same chain.
+	pre *[]MiddlewareFunc
+
+	// handler is the mux wrapped with the pre-routing middleware, built on first use
+	handler http.Handler
+
+	// statusHandlers holds the handlers set with ErrorHandler, NotFound and
+	// MethodNotAllowed, by status code. They can be changed at runtime.
+	// Shared by groups, like scopes.
+	statusHandlers map[int]http.Handler
 
-	// notFoundHandler is called when no route matches the request path
-	notFoundHandler http.Handler
+	// prefix is prepended to the paths of routes registered on a group created with Route
+	prefix string
 
-	// methodNotAllowedHandler is called when a path exists but the HTTP method is not allowed
-	methodNotAllowedHandler http.Handler
+	// scopes holds the error handlers set in groups created with Route.
+	// Uses pointer so groups share the same scopes.
+	scopes *[]*errorHandlerScope
 
-	// routesMu protects registeredRoutes. Uses pointer so groups share the same mutex.
+	// routesMu protects registeredRoutes, statusHandlers and scopes. Uses
+	// pointer so groups share the same mutex.
 	routesMu *sync.RWMutex
 
 	// registeredRoutes tracks which HTTP methods are registered for each path
@@ -367,14 +498,15 @@ func NewRouter(mw ...MiddlewareFunc) Router {
 	log.SetGlobalLogger(logger)
 
 	r := &defaultRouter{
-		mux:                     &http.ServeMux{},
-		chain:                   mw,
-		notFoundHandler:         defaultNotFoundHandler,
-		methodNotAllowedHandler: defaultMethodNotAllowedHandler,
-		routesMu:                &sync.RWMutex{},
-		registeredRoutes:        make(map[string]map[string]bool),
-		logger:                  logger,
-		config:                  cfg,
+		mux:              &http.ServeMux{},
+		chain:            mw,
+		pre:              &[]MiddlewareFunc{},
+		scopes:           &[]*errorHandlerScope{},
+		statusHandlers:   make(map[int]http.Handler),
+		routesMu:         &sync.RWMutex{},
+		registeredRoutes: make(map[string]map[string]bool),
+		logger:           logger,
+		config:           cfg,
 	}
 	return r
 }
@@ -390,6 +522,20 @@ func (r *defaultRouter) Use(mw ...MiddlewareFunc) {
 	r.chain = append(r.chain, mw...)
 }
 
+// Pre adds middleware that runs before the request is routed, on every
+// request, including requests that don't match any route. Unlike middleware
+// added with Use, it can rewrite r.URL.Path to change which route matches,
+// and it runs before http.ServeMux cleans or redirects the path.
+// Pre-routing middleware is router-wide, even when called on a group, and
+// must be added before the router serves its first request.
+//
+// Example:
+//
+//	router.Pre(cleanpath.New())
+func (r *defaultRouter) Pre(mw ...MiddlewareFunc) {
+	*r.pre = append(*r.pre, mw...)
+}
+
 // Group creates a new router scope that inherits the current middleware chain.
 // This allows for organizing related routes and applying middleware to specific groups.
 // Changes to middleware within the group do not affect the parent router.
@@ -402,22 +548,153 @@ func (r *defaultRouter) Use(mw ...MiddlewareFunc) {
 //	    api.POST("/users", createUserHandler)
 //	})
 func (r *defaultRouter) Group(fn func(Router)) {
-	r.handlerMu.RLock()
-	notFoundHandler := r.notFoundHandler
-	methodNotAllowedHandler := r.methodNotAllowedHandler
-	r.handlerMu.RUnlock()
+	fn(r.newGroup())
+}
+
+// newGroup creates a router sharing the mux, routes and handlers of r, with
+// a copy of its middleware chain.
+func (r *defaultRouter) newGroup() *defaultRouter {
+	return &defaultRouter{
+		mux:              r.mux,
+		chain:            slices.Clone(r.chain), // Clone to avoid affecting parent
+		pre:              r.pre,                 // Pre-routing middleware is router-wide
+		prefix:           r.prefix,
+		scopes:           r.scopes,
+		statusHandlers:   r.statusHandlers,   // Share map with parent
+		routesMu:         r.routesMu,         // Share mutex with parent
+		registeredRoutes: r.registeredRoutes, // Share map with parent
+		logger:           r.logger,
+		config:           r.config,
+	}
+}
+
+// errorHandlerScope holds the error handlers set in a group created with
+// Route, by status code. A status without handler falls back to the handler
+// of the enclosing scope.
+type errorHandlerScope struct {
+	prefix   string
+	handlers map[int]http.Handler
+}
+
+// Route creates a group, like Group, whose routes are registered under
+// prefix. Groups created with Route can be nested, and their prefixes add up.
+//
+// NotFound and MethodNotAllowed handlers set in the group apply to requests
+// under the prefix that match no route, or not with the requested method,
+// instead of the router's handlers, and so do handlers set with
+// ErrorHandler. The handlers of the group with the longest matching prefix
+// are used.
+//
+// Static, StaticDir and StaticFS always serve from the root, even in a group
+// created with Route. Panics if prefix doesn't start with "/" or is the root.
+//
+// Example:
+//
+//	router.Route("/api", func(api Router) {
+//	    api.NotFound(jsonNotFoundHandler) // Only for paths under /api
+//	    api.GET("/users", getUsersHandler) // GET /api/users
+//	})
+func (r *defaultRouter) Route(prefix string, fn func(Router)) {
+	group := r.newGroup()
+	group.prefix += validatePrefix(prefix)
+	fn(group)
+}
+
+// mountMethods are the methods Mount registers a handler for. Patterns with
+// a method are used rather than a method-less one so that mounts don't
+// conflict with the GET /{path...} route of Static, StaticDir and StaticFS.
+var mountMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+	http.MethodConnect,
+	http.MethodTrace,
+}
 
-	groupRouter := &defaultRouter{
-		mux:                     r.mux,
-		chain:                   slices.Clone(r.chain), // Clone to avoid affecting parent
-		notFoundHandler:         notFoundHandler,
-		methodNotAllowedHandler: methodNotAllowedHandler,
-		routesMu:                r.routesMu,         // Share mutex with parent
-		registeredRoutes:        r.registeredRoutes, // Share map with parent
-		logger:                  r.logger,
-		config:                  r.config,
+// Mount serves every request under prefix with h, such as an independently
+// built sub-application or a third-party http.Handler. The prefix is
+// stripped from the request path, so a handler mounted at "/admin" sees
+// "/admin/users" as "/users", and "/admin" itself as "/". The router's
+// middleware chain is applied around h.
+//
+// Requests with methods other than the standard ones are not routed to h.
+// Panics if prefix doesn't start with "/" or is the root.
+//
+// Example:
+//
+//	router.Mount("/debug", debugMux)
+func (r *defaultRouter) Mount(prefix string, h http.Handler) {
+	prefix = r.prefix + validatePrefix(prefix)
+	handler := r.wrap(stripMountPrefix(prefix, h), nil)
+
+	for _, method := range mountMethods {
+		r.mux.Handle(method+" "+prefix, handler)
+		r.mux.Handle(method+" "+prefix+"/", handler)
+	}
+}
+
+// MountRouter registers the routes of sub, a router built independently
+// with NewRouter, under prefix. Unlike Mount, the routes are added to this
+// router's routes, so requests under prefix that match no route, or not
+// with the requested method, get this router's 404 and 405 responses.
+//
+// Requests are served by sub's ServeMux with prefix stripped from the path,
+// so the middleware added to sub with Use runs inside this router's chain.
+// Only routes registered on sub before MountRouter is called are mounted,
+// and sub's Pre middleware and NotFound and MethodNotAllowed handlers are
+// not used. Panics if prefix doesn't start with "/" or is the root, or if a
+// route conflicts with an existing one.
+//
+// Example:
+//
+//	admin := zh.NewRouter()
+//	admin.Use(adminAuth)
+//	admin.GET("/users", listUsersHandler) // Served at /admin/users
+//
+//	app.MountRouter("/admin", admin)
+func (r *defaultRouter) MountRouter(prefix string, sub Router) {
+	group := r.newGroup()
+	group.prefix += validatePrefix(prefix)
+
+	handler := stripMountPrefix(group.prefix, sub.ServeMux())
+	for _, route := range sub.Routes() {
+		group.handle(route.Method, route.Path, handler, nil)
+	}
+}
+
+// routePath returns the full path of a route registered on a group created
+// with Route. The root of the group is its prefix.
+func (r *defaultRouter) routePath(path string) string {
+	if path == "/" {
+		return r.prefix
 	}
-	fn(groupRouter)
+	return r.prefix + path
+}
+
+// validatePrefix returns prefix without its trailing slash, and panics
+// if it isn't a valid route or mount prefix.
+func validatePrefix(prefix string) string {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if !strings.HasPrefix(prefix, "/") {
+		panic(fmt.Sprintf("zerohttp: invalid prefix %q", prefix))
+	}
+	return prefix
+}
+
+// stripMountPrefix removes prefix from the request path before calling h,
+// like http.StripPrefix, but serves the prefix itself as "/".
+func stripMountPrefix(prefix string, h http.Handler) http.Handler {
+	return http.StripPrefix(prefix, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "" {
+			req.URL.Path = "/"
+			req.URL.RawPath = ""
+		}
+		h.ServeHTTP(w, req)
+	}))
 }
 
 // DELETE registers a handler for HTTP DELETE requests to the specified path.
@@ -478,9 +755,7 @@ func (r *defaultRouter) CONNECT(path string, h http.Handler, mw ...MiddlewareFun
 //	    http.Error(w, "Custom 404 message", http.StatusNotFound)
 //	}))
 func (r *defaultRouter) NotFound(h http.Handler) {
-	r.handlerMu.Lock()
-	defer r.handlerMu.Unlock()
-	r.notFoundHandler = h
+	r.setErrorHandler(http.StatusNotFound, h)
 }
 
 // MethodNotAllowed sets a custom handler for 405 Method Not Allowed responses.
@@ -494,9 +769,123 @@ func (r *defaultRouter) NotFound(h http.Handler) {
 //	    http.Error(w, fmt.Sprintf("Method not allowed. Allowed: %s", allow), http.StatusMethodNotAllowed)
 //	}))
 func (r *defaultRouter) MethodNotAllowed(h http.Handler) {
-	r.handlerMu.Lock()
-	defer r.handlerMu.Unlock()
-	r.methodNotAllowedHandler = h
+	r.setErrorHandler(http.StatusMethodNotAllowed, h)
+}
+
+// ErrorHandler sets a custom handler for error responses with the given
+// status code. Problem details rendered by HandlerFunc errors,
+// [Renderer.ProblemDetailAuto] and middleware for the status are replaced
+// by the handler's response, so an application can serve its own 403 or
+// 429 page. The handler writes the whole response, including the status
+// code, and headers set before it's called, such as Retry-After, are kept.
+// Problem details the handler renders itself are not passed back to it.
+//
+// ErrorHandler(http.StatusNotFound, h) is the same as NotFound(h), and
+// ErrorHandler(http.StatusMethodNotAllowed, h) as MethodNotAllowed(h), so
+// handlers for 404 and 405 also serve requests that match no route.
+// Panics if status is not a 4xx or 5xx status code.
+//
+// Example:
+//
+//	router.ErrorHandler(http.StatusForbidden, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//	    w.Header().Set(httpx.HeaderContentType, httpx.MIMETextHTMLCharset)
+//	    w.WriteHeader(http.StatusForbidden)
+//	    _, _ = w.Write(forbiddenPage)
+//	}))
+func (r *defaultRouter) ErrorHandler(status int, h http.Handler) {
+	if status < http.StatusBadRequest || status > 599 {
+		panic(fmt.Sprintf("zerohttp: invalid error status %d", status))
+	}
+	r.setErrorHandler(status, h)
+}
+
+// setErrorHandler sets the handler for status in the error handler scope of
+// the group's prefix, creating it if needed, or for the whole router.
+func (r *defaultRouter) setErrorHandler(status int, h http.Handler) {
+	r.routesMu.Lock()
+	defer r.routesMu.Unlock()
+
+	if r.prefix == "" {
+		r.statusHandlers[status] = h
+		return
+	}
+
+	for _, scope := range *r.scopes {
+		if scope.prefix == r.prefix {
+			scope.handlers[status] = h
+			return
+		}
+	}
+	scope := &errorHandlerScope{prefix: r.prefix, handlers: map[int]http.Handler{status: h}}
+	*r.scopes = append(*r.scopes, scope)
+}
+
+// errorHandler returns the handler set for status for path: that of the
+// scope with the longest prefix matching path, falling back to the router's
+// own handler. Returns nil if no handler is set for status.
+func (r *defaultRouter) errorHandler(path string, status int) http.Handler {
+	r.routesMu.RLock()
+	defer r.routesMu.RUnlock()
+
+	h := r.statusHandlers[status]
+	var hLen int
+	for _, scope := range *r.scopes {
+		if path != scope.prefix && !strings.HasPrefix(path, scope.prefix+"/") {
+			continue
+		}
+		if sh := scope.handlers[status]; sh != nil && len(scope.prefix) > hLen {
+			h, hLen = sh, len(scope.prefix)
+		}
+	}
+	return h
+}
+
+// errorHandlers returns the NotFound and MethodNotAllowed handlers for path,
+// falling back to the default handlers. They are called without the error
+// handlers of the request context, so that the problem details they render
+// don't loop back to them.
+func (r *defaultRouter) errorHandlers(path string) (notFound, methodNotAllowed http.Handler) {
+	notFound, methodNotAllowed = r.errorHandler(path, http.StatusNotFound), r.errorHandler(path, http.StatusMethodNotAllowed)
+	if notFound == nil {
+		notFound = defaultNotFoundHandler
+	}
+	if methodNotAllowed == nil {
+		methodNotAllowed = defaultMethodNotAllowedHandler
+	}
+	return withoutErrorHandlers(notFound), withoutErrorHandlers(methodNotAllowed)
+}
+
+// hasErrorHandlers reports whether any handler was set with ErrorHandler,
+// NotFound or MethodNotAllowed, in the router or in a group.
+func (r *defaultRouter) hasErrorHandlers() bool {
+	r.routesMu.RLock()
+	defer r.routesMu.RUnlock()
+	return len(r.statusHandlers) > 0 || len(*r.scopes) > 0
+}
+
+// withErrorHandlers makes the error handlers and the error settings of the
+// router, such as DebugErrors and ErrorMappers, available to the problem
+// details rendered while serving a request with h.
+func (r *defaultRouter) withErrorHandlers(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if r.config.DebugErrors || len(r.config.ErrorMappers) > 0 || len(r.config.ErrorMappings) > 0 {
+			req = req.WithContext(context.WithValue(req.Context(), routerConfigKey{}, &r.config))
+		}
+		if r.hasErrorHandlers() {
+			path := req.URL.Path
+			req = req.WithContext(problem.WithErrorHandlers(req.Context(), func(status int) http.Handler {
+				return r.errorHandler(path, status)
+			}))
+		}
+		h.ServeHTTP(w, req)
+	})
+}
+
+// withoutErrorHandlers calls h without the error handlers of the request context.
+func withoutErrorHandlers(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		h.ServeHTTP(w, problem.WithoutErrorHandlers(req))
+	})
 }
 
 // Files serves static files from embedded FS at the specified prefix.
@@ -506,7 +895,8 @@ func (r *defaultRouter) Files(prefix string, embedFS embed.FS, dir string) {
 		panic(fmt.Errorf("failed to create sub-filesystem: %w", err))
 	}
 
-	handler := http.StripPrefix(prefix, http.FileServer(http.FS(subFS)))
+	prefix = r.prefix + prefix
+	handler := http.StripPrefix(prefix, r.withCacheControl(http.FileServer(http.FS(subFS))))
 
 	// Ensure prefix ends with slash for subtree matching
 	if !strings.HasSuffix(prefix, "/") {
@@ -517,7 +907,8 @@ func (r *defaultRouter) Files(prefix string, embedFS embed.FS, dir string) {
 
 // FilesDir serves static files from a directory at the specified prefix.
 func (r *defaultRouter) FilesDir(prefix, dir string) {
-	handler := http.StripPrefix(prefix, http.FileServer(http.Dir(dir)))
+	prefix = r.prefix + prefix
+	handler := http.StripPrefix(prefix, r.withCacheControl(http.FileServer(http.Dir(dir))))
 
 	if !strings.HasSuffix(prefix, "/") {
 		prefix += "/"
@@ -525,6 +916,88 @@ func (r *defaultRouter) FilesDir(prefix, dir string) {
 	r.mux.Handle("GET "+prefix, r.wrap(handler, nil))
 }
 
+// FilesDirSecure serves files from a directory at the specified prefix,
+// for directories holding untrusted files, such as uploads. The requested
+// path is resolved, following symlinks, and must stay within dir: requests
+// with ".." segments, encoded or not, or resolving through a symlink to a
+// file outside dir are answered with 403 Forbidden. Missing files and
+// directories, which are not listed, are answered with 404 Not Found.
+// Files are opened with [os.OpenInRoot], so a symlink swapped in after
+// the check can't escape dir either.
+//
+// Panics if dir can't be resolved.
+//
+// Example:
+//
+//	router.FilesDirSecure("/downloads/", "./uploads")
+func (r *defaultRouter) FilesDirSecure(prefix, dir string) {
+	root, err := filepath.Abs(dir)
+	if err == nil {
+		root, err = filepath.EvalSymlinks(root)
+	}
+	if err != nil {
+		panic(fmt.Errorf("failed to resolve directory: %w", err))
+	}
+
+	prefix = r.prefix + prefix
+	handler := http.StripPrefix(prefix, r.withCacheControl(secureFileServer(root)))
+
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	r.mux.Handle("GET "+prefix, r.wrap(handler, nil))
+}
+
+// secureFileServer serves the files of root, an absolute path with its
+// symlinks resolved, rejecting requests for files outside of it.
+func secureFileServer(root string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// ServeMux redirects paths with ".." segments, but not those
+		// reaching the handler otherwise, e.g. with backslashes
+		if hasDotDotSegment(req.URL.Path) {
+			_ = NewProblemDetail(http.StatusForbidden, "Access to the file is forbidden").RenderAuto(w, req)
+			return
+		}
+
+		name := filepath.Join(root, filepath.FromSlash(path.Clean("/"+req.URL.Path)))
+		resolved, err := filepath.EvalSymlinks(name)
+		if err != nil {
+			_ = NewProblemDetail(http.StatusNotFound, "File not found").RenderAuto(w, req)
+			return
+		}
+		rel, err := filepath.Rel(root, resolved)
+		if err != nil || !filepath.IsLocal(rel) {
+			_ = NewProblemDetail(http.StatusForbidden, "Access to the file is forbidden").RenderAuto(w, req)
+			return
+		}
+
+		file, err := os.OpenInRoot(root, rel)
+		if err != nil {
+			_ = NewProblemDetail(http.StatusNotFound, "File not found").RenderAuto(w, req)
+			return
+		}
+		defer func() { _ = file.Close() }()
+
+		stat, err := file.Stat()
+		if err != nil || stat.IsDir() {
+			_ = NewProblemDetail(http.StatusNotFound, "File not found").RenderAuto(w, req)
+			return
+		}
+		http.ServeContent(w, req, stat.Name(), stat.ModTime(), file)
+	})
+}
+
+// hasDotDotSegment reports whether the slash or backslash separated path p
+// has a ".." segment.
+func hasDotDotSegment(p string) bool {
+	for seg := range strings.FieldsFuncSeq(p, func(c rune) bool { return c == '/' || c == '\\' }) {
+		if seg == ".." {
+			return true
+		}
+	}
+	return false
+}
+
 // checkAndMarkRoot atomically verifies that GET / is not yet claimed
 // and claims it for Static/StaticDir. Panics with the caller's name on conflict.
 func (r *defaultRouter) checkAndMarkRoot(caller string) {
@@ -570,6 +1043,16 @@ func (r *defaultRouter) StaticDir(dir string, fallback bool, apiPrefix ...string
 	r.mux.Handle("GET /{path...}", r.wrap(handler, nil))
 }
 
+// StaticFS serves a static web application from an fs.FS with fallback to index.html.
+func (r *defaultRouter) StaticFS(filesystem fs.FS, fallback bool, apiPrefix ...string) {
+	r.checkAndMarkRoot("StaticFS()")
+
+	handler := r.createStaticHandler(filesystem, fallback, apiPrefix)
+
+	r.mux.Handle("GET /{$}", r.wrap(handler, nil))
+	r.mux.Handle("GET /{path...}", r.wrap(handler, nil))
+}
+
 // statusCapture wraps http.ResponseWriter to capture the status code.
 // Used by static file handler to log actual response status instead of hardcoded 200.
 type statusCapture struct {
@@ -590,11 +1073,12 @@ func (s *statusCapture) Flush() {
 
 func (r *defaultRouter) createStaticHandler(filesystem fs.FS, fallback bool, apiPrefixes []string) http.Handler {
 	// Capture config values at handler creation time to avoid data races.
-	// notFoundHandler is protected by handlerMu and accessed with locking.
+	// Error handlers are protected by routesMu and accessed with locking.
 	requestIDHeader := r.config.RequestID.Header
 	requestIDGenerator := r.config.RequestID.Generator
 	requestLoggerConfig := r.config.RequestLogger
 	logger := r.logger
+	staticConfig := r.config.Static
 
 	fileServer := http.FileServer(http.FS(filesystem))
 
@@ -606,9 +1090,7 @@ func (r *defaultRouter) createStaticHandler(filesystem fs.FS, fallback bool, api
 		}
 		w.Header().Set(requestIDHeader, requestID)
 
-		r.handlerMu.RLock()
-		notFoundHandler := r.notFoundHandler
-		r.handlerMu.RUnlock()
+		notFoundHandler, _ := r.errorHandlers(req.URL.Path)
 
 		// Security: reject any request whose raw path contains ".." before
 		// it can be resolved away by cleaning. fs.FS also enforces this at
@@ -622,6 +1104,20 @@ func (r *defaultRouter) createStaticHandler(filesystem fs.FS, fallback bool, api
 
 		cleanPath := path.Clean(req.URL.Path)
 
+		// Defer to routes registered for the path with other methods, so a
+		// GET to a POST-only API route is a 405 rather than the SPA fallback.
+		// Files in the filesystem are still served, so a pattern such as
+		// "POST /{slug}" doesn't shadow them. Routes registered for GET are
+		// matched by the ServeMux before this handler, in any registration
+		// order.
+		if allowHeader := r.staticRouteConflict(cleanPath); allowHeader != "" && !isStaticFile(filesystem, cleanPath) {
+			w.Header().Set(httpx.HeaderAllow, allowHeader)
+			_, methodNotAllowedHandler := r.errorHandlers(cleanPath)
+			methodNotAllowedHandler.ServeHTTP(w, req)
+			requestlogger.Log(logger, requestLoggerConfig, nil, req, http.StatusMethodNotAllowed, time.Since(start), "", "")
+			return
+		}
+
 		// Skip API routes - return 404
 		for _, prefix := range apiPrefixes {
 			if strings.HasPrefix(cleanPath, prefix) {
@@ -637,29 +1133,317 @@ func (r *defaultRouter) createStaticHandler(filesystem fs.FS, fallback bool, api
 			stat, statErr := file.Stat()
 			_ = file.Close() // Close immediately - http.FileServer will open it again
 			if statErr == nil && !stat.IsDir() {
+				name := strings.TrimPrefix(cleanPath, "/")
 				rec := &statusCapture{ResponseWriter: w, status: http.StatusOK}
-				fileServer.ServeHTTP(rec, req)
+				cw := &cacheControlWriter{ResponseWriter: rec, value: staticConfig.cacheControl(name)}
+				serveStaticFile(cw, req, filesystem, name, stat, fileServer)
 				requestlogger.Log(logger, requestLoggerConfig, nil, req, rec.status, time.Since(start), "", "")
 				return
 			}
 		}
 
 		if fallback {
-			// Preserve original path for accurate logging and deferred middleware
-			originalPath := req.URL.Path
-			req.URL.Path = "/"
-			defer func() { req.URL.Path = originalPath }() // Safety net for panics upstream
-			rec := &statusCapture{ResponseWriter: w, status: http.StatusOK}
-			fileServer.ServeHTTP(rec, req)
-			req.URL.Path = originalPath // Restore NOW, before LogRequest reads req.URL.Path
-			requestlogger.Log(logger, requestLoggerConfig, nil, req, rec.status, time.Since(start), "", "")
-		} else {
-			notFoundHandler.ServeHTTP(w, req)
-			requestlogger.Log(logger, requestLoggerConfig, nil, req, http.StatusNotFound, time.Since(start), "", "")
+			status := staticConfig.FallbackStatus
+			if status <= 0 || cleanPath == "/" {
+				status = http.StatusOK
+			}
+			rec := &statusCapture{ResponseWriter: w, status: status}
+			if serveFallback(rec, req, filesystem, status) {
+				requestlogger.Log(logger, requestLoggerConfig, nil, req, rec.status, time.Since(start), "", "")
+				return
+			}
 		}
+
+		notFoundHandler.ServeHTTP(w, req)
+		requestlogger.Log(logger, requestLoggerConfig, nil, req, http.StatusNotFound, time.Since(start), "", "")
 	})
 }
 
+// staticRouteConflict returns the Allow header for a path served by the
+// static handler that matches registered routes without GET, or "" if the
+// static handler should serve it. The methods of every matching pattern are
+// combined, so the result doesn't depend on map iteration order.
+func (r *defaultRouter) staticRouteConflict(path string) string {
+	r.routesMu.RLock()
+	defer r.routesMu.RUnlock()
+
+	methods := make(map[string]bool)
+	for pattern, patternMethods := range r.registeredRoutes {
+		if pattern != path && !matchPattern(pattern, path) {
+			continue
+		}
+		for method := range patternMethods {
+			methods[method] = true
+		}
+	}
+	if len(methods) == 0 || methods[http.MethodGet] {
+		return ""
+	}
+	return allowedMethods(methods)
+}
+
+// isStaticFile reports whether filesystem has a regular file at the
+// cleaned request path.
+func isStaticFile(filesystem fs.FS, cleanPath string) bool {
+	f, err := filesystem.Open(strings.TrimPrefix(cleanPath, "/"))
+	if err != nil {
+		return false
+	}
+	defer func() { _ = f.Close() }()
+
+	stat, err := f.Stat()
+	return err == nil && !stat.IsDir()
+}
+
+// serveFallback serves index.html for a client-side route of a SPA, with
+// "Cache-Control: no-cache" since the same document is served for every
+// route. With a 200 status, conditional requests and pre-compressed copies
+// are handled like for other files. It reports false without writing a
+// response if there is no index.html to serve.
+func serveFallback(w http.ResponseWriter, req *http.Request, filesystem fs.FS, status int) bool {
+	f, err := filesystem.Open("index.html")
+	if err != nil {
+		return false
+	}
+	defer func() { _ = f.Close() }()
+
+	stat, err := f.Stat()
+	if err != nil || stat.IsDir() {
+		return false
+	}
+
+	w.Header().Set(httpx.HeaderCacheControl, "no-cache")
+
+	content, ok := f.(io.ReadSeeker)
+	if status == http.StatusOK && ok {
+		// Serve index.html directly rather than through the file server,
+		// which would serve a directory listing or redirect for its path
+		serveIndex := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			http.ServeContent(w, req, "index.html", stat.ModTime(), content)
+		})
+		serveStaticFile(w, req, filesystem, "index.html", stat, serveIndex)
+		return true
+	}
+
+	w.Header().Set(httpx.HeaderContentType, httpx.MIMETextHTMLCharset)
+	w.WriteHeader(status)
+	_, _ = io.Copy(w, f)
+	return true
+}
+
+// withCacheControl wraps a file server to set the Cache-Control header
+// configured by Config.Static on the files it serves.
+func (r *defaultRouter) withCacheControl(fileServer http.Handler) http.Handler {
+	staticConfig := r.config.Static
+	if staticConfig.MaxAge <= 0 && !staticConfig.Immutable {
+		return fileServer
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		cw := &cacheControlWriter{ResponseWriter: w, value: staticConfig.cacheControl(req.URL.Path)}
+		fileServer.ServeHTTP(cw, req)
+	})
+}
+
+// cacheControl returns the Cache-Control value for the static file name,
+// or an empty string if caching isn't configured.
+func (c StaticConfig) cacheControl(name string) string {
+	switch {
+	case c.MaxAge <= 0 && !c.Immutable:
+		return ""
+	case name == "" || strings.HasSuffix(name, "/") || path.Ext(name) == ".html" || path.Ext(name) == ".htm":
+		// Directories serve index.html or a listing
+		return "no-cache"
+	case c.Immutable && isFingerprinted(name):
+		return "public, max-age=31536000, immutable"
+	case c.MaxAge > 0:
+		return "public, max-age=" + strconv.FormatInt(int64(c.MaxAge/time.Second), 10)
+	default:
+		return ""
+	}
+}
+
+// isFingerprinted reports whether the base name of a file contains a content
+// hash added by a build tool, such as "app.3f2a9c1b.js" or
+// "index-BkL9x2aF.css". The hash must be its own segment, either a dot
+// separated part after the first or what follows a dash or underscore, and
+// must look like a hash: hex with letters and digits, or a mixed-case
+// base64url token with digits, of at least 8 characters. Names such as
+// "screenshot2024.png" or "report-2023.pdf" aren't fingerprinted.
+func isFingerprinted(name string) bool {
+	base := path.Base(name)
+	base = strings.TrimSuffix(base, path.Ext(base))
+
+	for i, part := range strings.Split(base, ".") {
+		if i > 0 && isHash(part) {
+			return true
+		}
+		for j := 1; j < len(part); j++ {
+			if (part[j-1] == '-' || part[j-1] == '_') && isHash(part[j:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isHash reports whether s looks like a content hash: at least 8 hex
+// characters with both letters and digits, or at least 8 base64url
+// characters with upper and lower case letters and digits.
+func isHash(s string) bool {
+	if len(s) < 8 {
+		return false
+	}
+	var digit, lower, upper, nonHex bool
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case '0' <= c && c <= '9':
+			digit = true
+		case 'a' <= c && c <= 'z':
+			lower = true
+			nonHex = nonHex || c > 'f'
+		case 'A' <= c && c <= 'Z':
+			upper = true
+			nonHex = nonHex || c > 'F'
+		case c == '-' || c == '_':
+			nonHex = true
+		default:
+			return false
+		}
+	}
+	if !digit {
+		return false
+	}
+	if !nonHex && lower != upper {
+		return true
+	}
+	return lower && upper
+}
+
+// cacheControlWriter sets the Cache-Control header on successful and
+// 304 Not Modified responses, so that errors aren't cached.
+type cacheControlWriter struct {
+	http.ResponseWriter
+	value string
+	wrote bool
+}
+
+func (w *cacheControlWriter) WriteHeader(code int) {
+	if !w.wrote && !rwutil.IsInformational(code) {
+		w.wrote = true
+		if w.value != "" && (code == http.StatusOK || code == http.StatusPartialContent || code == http.StatusNotModified) {
+			w.Header().Set(httpx.HeaderCacheControl, w.value)
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *cacheControlWriter) Write(p []byte) (int, error) {
+	if !w.wrote {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// etagger is implemented by fs.FileInfo values that provide an ETag,
+// such as those of MemFS files.
+type etagger interface {
+	ETag() string
+}
+
+// precompressedEncodings are the content encodings of pre-compressed files
+// served by serveStaticFile, in order of preference, with their extension.
+var precompressedEncodings = []struct {
+	encoding string
+	ext      string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// serveStaticFile serves the regular file name from filesystem. If a
+// pre-compressed "<name>.br" or "<name>.gz" file exists and the client
+// accepts its encoding, it is served instead with the matching
+// Content-Encoding and the Content-Type of name, preferring brotli. ETags
+// provided by the file info are set so that conditional requests are
+// answered with 304 Not Modified.
+func serveStaticFile(w http.ResponseWriter, req *http.Request, filesystem fs.FS, name string, stat fs.FileInfo, fileServer http.Handler) {
+	// Leave index.html requests to the file server, which redirects them to the directory
+	if !isPrecompressed(name) && !strings.HasSuffix(req.URL.Path, "/index.html") {
+		vary := false
+		for _, pc := range precompressedEncodings {
+			f, err := filesystem.Open(name + pc.ext)
+			if err != nil {
+				continue
+			}
+			defer func() { _ = f.Close() }()
+
+			fStat, statErr := f.Stat()
+			content, ok := f.(io.ReadSeeker)
+			if statErr != nil || fStat.IsDir() || !ok {
+				continue
+			}
+
+			if !vary {
+				w.Header().Add(httpx.HeaderVary, httpx.HeaderAcceptEncoding)
+				vary = true
+			}
+			if !acceptsEncoding(req, pc.encoding) {
+				continue
+			}
+
+			w.Header().Set(httpx.HeaderContentType, httpx.DetectContentType(name))
+			w.Header().Set(httpx.HeaderContentEncoding, pc.encoding)
+			if e, ok := fStat.(etagger); ok {
+				w.Header().Set(httpx.HeaderETag, e.ETag())
+			}
+			http.ServeContent(w, req, name, fStat.ModTime(), content)
+			return
+		}
+	}
+
+	if e, ok := stat.(etagger); ok {
+		w.Header().Set(httpx.HeaderETag, e.ETag())
+	}
+	fileServer.ServeHTTP(w, req)
+}
+
+// isPrecompressed reports whether name is a pre-compressed variant of a file.
+func isPrecompressed(name string) bool {
+	for _, pc := range precompressedEncodings {
+		if strings.HasSuffix(name, pc.ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsEncoding reports whether the request's Accept-Encoding allows
+// encoding, either by name or with "*". A listing by name takes precedence.
+func acceptsEncoding(req *http.Request, encoding string) bool {
+	wildcard := false
+	for _, v := range strings.Split(req.Header.Get(httpx.HeaderAcceptEncoding), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(v), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != encoding && name != "*" {
+			continue
+		}
+
+		// q=0 means explicitly not acceptable (RFC 7231 §5.3.1)
+		accepted := true
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(q, 64); err == nil && f == 0 {
+				accepted = false
+			}
+		}
+		if name == encoding {
+			return accepted
+		}
+		wildcard = accepted
+	}
+	return wildcard
+}
+
 // ServeMux returns the underlying http.ServeMux instance.
 // This can be useful for advanced integration scenarios or when you need
 // to access ServeMux-specific functionality.
@@ -667,14 +1451,81 @@ func (r *defaultRouter) ServeMux() *http.ServeMux {
 	return r.mux
 }
 
+// Routes returns the routes registered on the router and its groups, sorted
+// by path and then method. Static, StaticDir and StaticFS are listed as a
+// GET / route. Files, FilesDir and handlers registered directly on the
+// ServeMux are not listed.
+//
+// Example:
+//
+//	for _, route := range app.Routes() {
+//	    fmt.Println(route.Method, route.Path)
+//	}
+func (r *defaultRouter) Routes() []RouteInfo {
+	r.routesMu.RLock()
+	defer r.routesMu.RUnlock()
+
+	routes := make([]RouteInfo, 0, len(r.registeredRoutes))
+	for path, methods := range r.registeredRoutes {
+		for method := range methods {
+			routes = append(routes, RouteInfo{Method: method, Path: path})
+		}
+	}
+	slices.SortFunc(routes, func(a, b RouteInfo) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Method, b.Method)
+	})
+	return routes
+}
+
 // ServeHTTP implements the http.Handler interface, making the router compatible
 // with Go's standard HTTP server. This is the entry point for all HTTP requests.
 func (r *defaultRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	// Auto-finalize on first use - safe for concurrent access
 	r.finalizeOnce.Do(func() {
 		r.mux.Handle("/", r.wrap(r.catchAllHandler(), nil))
+
+		var handler http.Handler = r.mux
+		for _, m := range slices.Backward(*r.pre) {
+			handler = m(handler)
+		}
+		r.handler = handler
 	})
-	r.mux.ServeHTTP(w, req)
+
+	if !r.config.DisablePanicGuard {
+		defer r.guardPanic(w, req)
+	}
+	r.handler.ServeHTTP(w, req)
+}
+
+// guardPanic recovers from a panic that escaped the middleware chain, so a
+// single request can't take the server down when no recover middleware is
+// installed. Like the recover middleware, it lets http.ErrAbortHandler
+// through to abort the response.
+func (r *defaultRouter) guardPanic(w http.ResponseWriter, req *http.Request) {
+	rvr := recover()
+	if rvr == nil {
+		return
+	}
+	if rvr == http.ErrAbortHandler {
+		panic(rvr)
+	}
+
+	r.logger.Error("Recovered from panic in router",
+		log.P(rvr),
+		log.F("method", req.Method),
+		log.F("path", req.URL.Path),
+		log.F("stack", string(debug.Stack())),
+	)
+
+	if req.Header.Get(httpx.HeaderConnection) != httpx.ConnectionUpgrade {
+		pd := NewProblemDetail(http.StatusInternalServerError, "Internal server error")
+		if err := pd.RenderAuto(w, req); err != nil {
+			r.logger.Error("Failed to render panic response", log.E(err))
+		}
+	}
 }
 
 // Logger returns the logger instance used by the router for logging
@@ -722,16 +1573,32 @@ func (r *defaultRouter) wrap(fn http.Handler, mw []MiddlewareFunc) (out http.Han
 	// Reverse the order so first added middleware executes first
 	slices.Reverse(allMiddleware)
 
-	// Apply middleware from outermost to innermost
+	// Apply middleware from outermost to innermost. Route middleware may
+	// drop inherited middleware with Without, which links let it see.
+	var skip []string
 	for _, m := range allMiddleware {
-		out = m(out)
+		if len(mw) == 0 {
+			out = m(out)
+			continue
+		}
+		link := &middlewareLink{next: out, skip: &skip}
+		if h := m(link); h != link {
+			out = h
+		}
 	}
+
+	// Outermost, so that the errors of middleware are served by the error handlers too
+	out = r.withErrorHandlers(out)
 	return
 }
 
 // handle is the internal method that registers a handler for a specific HTTP method and path.
 // It tracks registered routes for proper 404/405 handling and registers the handler with ServeMux.
 func (r *defaultRouter) handle(method, path string, fn http.Handler, mw []MiddlewareFunc) {
+	if r.prefix != "" {
+		path = r.routePath(path)
+	}
+
 	// Track the route and method for 404/405 determination
 	r.routesMu.Lock()
 	if r.registeredRoutes[path] == nil {
@@ -745,6 +1612,10 @@ func (r *defaultRouter) handle(method, path string, fn http.Handler, mw []Middle
 	r.registeredRoutes[path][method] = true
 	r.routesMu.Unlock()
 
+	if h, ok := fn.(HandlerFunc); ok && r.config.EmptyResponseStatus > 0 {
+		fn = emptyResponseHandler(h, r.config.EmptyResponseStatus, r.logger)
+	}
+
 	// Special handling for root path to prevent catch-all behavior
 	// The {$} pattern ensures exact match for the root path
 	if path == "/" {
@@ -821,9 +1692,7 @@ func (r *defaultRouter) catchAllHandler() http.HandlerFunc {
 			if !methodAllowed {
 				w.Header().Set(httpx.HeaderAllow, allowHeader)
 
-				r.handlerMu.RLock()
-				methodNotAllowedHandler := r.methodNotAllowedHandler
-				r.handlerMu.RUnlock()
+				_, methodNotAllowedHandler := r.errorHandlers(req.URL.Path)
 				methodNotAllowedHandler.ServeHTTP(w, req)
 				return
 			}
@@ -838,9 +1707,7 @@ func (r *defaultRouter) catchAllHandler() http.HandlerFunc {
 			r.routesMu.RUnlock()
 		}
 
-		r.handlerMu.RLock()
-		notFoundHandler := r.notFoundHandler
-		r.handlerMu.RUnlock()
+		notFoundHandler, _ := r.errorHandlers(req.URL.Path)
 		notFoundHandler.ServeHTTP(w, req)
 	}
 }
@@ -922,10 +1789,25 @@ func matchPattern(pattern, path string) bool {
 }
 
 // defaultNotFoundHandler is the default handler for 404 Not Found responses.
-// It checks the Accept header and returns JSON problem detail by default.
+// It checks the Accept header and returns JSON problem detail by default,
+// XML for clients that prefer it, or a minimal HTML page for browsers.
+// Problem details include the request ID as the "request_id" member.
 var defaultNotFoundHandler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	requestID := problemRequestID(w, r)
+	if problem.AcceptsXML(r) {
+		_ = withRequestID(NewProblemDetail(http.StatusNotFound, "Requested resource was not found"), requestID).RenderXML(w)
+		return
+	}
+	if problem.AcceptsHTML(r) {
+		_ = NewProblemDetail(http.StatusNotFound, "Requested resource was not found").RenderHTML(w)
+		return
+	}
 	// Default to JSON; only use plain text if client explicitly requests it
 	if problem.AcceptsJSON(r) {
+		if requestID != "" {
+			_ = withRequestID(NewProblemDetail(http.StatusNotFound, "Requested resource was not found"), requestID).Render(w)
+			return
+		}
 		w.Header().Set(httpx.HeaderContentType, httpx.MIMEApplicationProblemJSON)
 		w.WriteHeader(http.StatusNotFound)
 		_, _ = w.Write(preEncodedNotFoundJSON)
@@ -938,11 +1820,26 @@ var defaultNotFoundHandler http.Handler = http.HandlerFunc(func(w http.ResponseW
 })
 
 // defaultMethodNotAllowedHandler is the default handler for 405 Method Not Allowed responses.
-// It checks the Accept header and returns JSON problem detail by default.
+// It checks the Accept header and returns JSON problem detail by default,
+// XML for clients that prefer it, or a minimal HTML page for browsers.
+// Problem details include the request ID as the "request_id" member.
 // The "Allow" header should be set by the caller to indicate which methods are allowed.
 var defaultMethodNotAllowedHandler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	requestID := problemRequestID(w, r)
+	if problem.AcceptsXML(r) {
+		_ = withRequestID(NewProblemDetail(http.StatusMethodNotAllowed, "HTTP method is not allowed"), requestID).RenderXML(w)
+		return
+	}
+	if problem.AcceptsHTML(r) {
+		_ = NewProblemDetail(http.StatusMethodNotAllowed, "HTTP method is not allowed").RenderHTML(w)
+		return
+	}
 	// Default to JSON; only use plain text if client explicitly requests it
 	if problem.AcceptsJSON(r) {
+		if requestID != "" {
+			_ = withRequestID(NewProblemDetail(http.StatusMethodNotAllowed, "HTTP method is not allowed"), requestID).Render(w)
+			return
+		}
 		w.Header().Set(httpx.HeaderContentType, httpx.MIMEApplicationProblemJSON)
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		_, _ = w.Write(preEncodedMethodNotAllowedJSON)
@@ -5,14 +5,23 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/alexferl/zerohttp/config"
 	"github.com/alexferl/zerohttp/httpx"
 	"github.com/alexferl/zerohttp/log"
+	"github.com/alexferl/zerohttp/middleware/basicauth"
+	"github.com/alexferl/zerohttp/middleware/cleanpath"
+	"github.com/alexferl/zerohttp/middleware/requestid"
 	"github.com/alexferl/zerohttp/middleware/requestlogger"
+	"github.com/alexferl/zerohttp/middleware/trailingslash"
 	"github.com/alexferl/zerohttp/validator"
 	"github.com/alexferl/zerohttp/zhtest"
 )
@@ -229,6 +238,26 @@ func TestHandlerFunc(t *testing.T) {
 			BodyContains("Payload Too Large").
 			BodyContains("413")
 	})
+
+	t.Run("binding a body over the limit returns 413", func(t *testing.T) {
+		router := NewRouter()
+		router.POST("/users", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+			r.Body = http.MaxBytesReader(w, r.Body, 10)
+			var user struct {
+				Name string `json:"name"`
+			}
+			return BindAndValidate(r, &user)
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"a name longer than the limit"}`))
+		req.Header.Set(httpx.HeaderContentType, httpx.MIMEApplicationJSON)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		zhtest.AssertWith(t, w).
+			Status(http.StatusRequestEntityTooLarge).
+			Header(httpx.HeaderContentType, httpx.MIMEApplicationProblemJSON)
+	})
 }
 
 func TestJSONEncodingErrorLogged(t *testing.T) {
@@ -275,7 +304,7 @@ func TestJSONEncodingErrorLogged(t *testing.T) {
 			}
 
 			// Call handleHandlerError directly
-			handleHandlerError(recorder, tc.handlerError)
+			handleHandlerError(recorder, httptest.NewRequest(http.MethodGet, "/", nil), tc.handlerError)
 
 			// Verify the status was written before the write failure
 			zhtest.AssertEqual(t, recorder.Code, tc.expectedStatus)
@@ -436,6 +465,53 @@ func TestRouter_Middleware(t *testing.T) {
 
 		zhtest.AssertEqual(t, order, []int{1, 2, 0, -2, -1})
 	})
+
+	t.Run("pre-routing middleware", func(t *testing.T) {
+		var calls []string
+		router := NewRouter()
+		router.Use(testMiddleware("use", &calls))
+		router.Group(func(api Router) {
+			api.Pre(testMiddleware("pre", &calls))
+		})
+		router.GET("/test", testHandler("response"))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+		zhtest.AssertWith(t, w).Status(http.StatusOK)
+		zhtest.AssertEqual(t, []string{"pre", "use"}, calls)
+
+		// Pre-routing middleware also runs for unmatched requests
+		calls = nil
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+		zhtest.AssertWith(t, w).Status(http.StatusNotFound)
+		zhtest.AssertEqual(t, "pre", calls[0])
+	})
+
+	t.Run("pre-routing path cleaning", func(t *testing.T) {
+		router := NewRouter()
+		router.Pre(cleanpath.New(cleanpath.Config{Lowercase: true}))
+		router.Use(trailingslash.New())
+		router.GET("/a/c", testHandler("get"))
+		router.POST("/a/c", testHandler("post"))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "//A//b/../c/", nil))
+		zhtest.AssertWith(t, w).
+			Status(http.StatusMovedPermanently).
+			Header(httpx.HeaderLocation, "/a/c/")
+
+		// trailingslash takes over from the clean path
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/a/c/", nil))
+		zhtest.AssertWith(t, w).
+			Status(http.StatusMovedPermanently).
+			Header(httpx.HeaderLocation, "/a/c")
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "//A//b/../c", nil))
+		zhtest.AssertWith(t, w).Status(http.StatusOK).Body("post")
+	})
 }
 
 func TestRouter_Groups(t *testing.T) {
@@ -493,6 +569,258 @@ func TestRouter_Groups(t *testing.T) {
 	})
 }
 
+func TestRouter_Route(t *testing.T) {
+	router := NewRouter()
+	router.NotFound(testHandler("html not found"))
+	router.GET("/about", testHandler("about"))
+	router.Route("/api", func(api Router) {
+		api.NotFound(testHandler("api not found"))
+		api.MethodNotAllowed(testHandler("api method not allowed"))
+		api.GET("/", testHandler("api root"))
+		api.GET("/users/{id}", testHandler("user"))
+
+		api.Route("/v2/", func(v2 Router) {
+			v2.NotFound(testHandler("v2 not found"))
+			v2.GET("/users", testHandler("v2 users"))
+		})
+	})
+
+	zhtest.AssertEqual(t, []RouteInfo{
+		{Method: http.MethodGet, Path: "/about"},
+		{Method: http.MethodGet, Path: "/api"},
+		{Method: http.MethodGet, Path: "/api/users/{id}"},
+		{Method: http.MethodGet, Path: "/api/v2/users"},
+	}, router.Routes())
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, "/api", "api root"},
+		{http.MethodGet, "/api/users/1", "user"},
+		{http.MethodGet, "/api/v2/users", "v2 users"},
+		{http.MethodGet, "/about", "about"},
+		{http.MethodGet, "/missing", "html not found"},
+		{http.MethodGet, "/apix", "html not found"},
+		{http.MethodGet, "/api/missing", "api not found"},
+		{http.MethodDelete, "/api/users/1", "api method not allowed"},
+		{http.MethodGet, "/api/v2/missing", "v2 not found"},
+		{http.MethodPost, "/api/v2/users", "api method not allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := zhtest.Serve(router, httptest.NewRequest(tt.method, tt.path, nil))
+			zhtest.AssertWith(t, w).Body(tt.body)
+		})
+	}
+
+	t.Run("invalid prefix", func(t *testing.T) {
+		zhtest.AssertPanic(t, func() {
+			NewRouter().Route("api", func(Router) {})
+		})
+	})
+}
+
+func TestRouter_ErrorHandler(t *testing.T) {
+	statusPage := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			status, _ := strconv.Atoi(name[:3])
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(name))
+		})
+	}
+
+	router := NewRouter()
+	router.ErrorHandler(http.StatusForbidden, statusPage("403 page"))
+	router.ErrorHandler(http.StatusBadRequest, statusPage("400 page"))
+	router.ErrorHandler(http.StatusUnauthorized, statusPage("401 page"))
+	router.ErrorHandler(http.StatusNotFound, statusPage("404 page"))
+	router.ErrorHandler(http.StatusTooManyRequests, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Problem details rendered by the error handler are not passed back to it
+		_ = NewProblemDetail(http.StatusTooManyRequests, "Nested").RenderAuto(w, r)
+	}))
+
+	router.GET("/forbidden", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return NewProblemDetail(http.StatusForbidden, "Not yours")
+	}))
+	router.GET("/conflict", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return NewProblemDetail(http.StatusConflict, "Already exists")
+	}))
+	router.GET("/auto", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		w.Header().Set(httpx.HeaderRetryAfter, "30")
+		return R.ProblemDetailAuto(w, r, NewProblemDetail(http.StatusTooManyRequests, "Slow down"))
+	}))
+	router.POST("/bind", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var v struct{ Name string }
+		return B.JSON(r.Body, &v)
+	}))
+	router.GET("/private", testHandler("private"), basicauth.New(basicauth.Config{
+		Credentials: map[string]string{"user": "pass"},
+	}))
+	router.Route("/admin", func(admin Router) {
+		admin.ErrorHandler(http.StatusForbidden, statusPage("403 admin page"))
+		admin.GET("/forbidden", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+			return NewProblemDetail(http.StatusForbidden, "Admins only")
+		}))
+	})
+
+	t.Run("handler error", func(t *testing.T) {
+		w := zhtest.Serve(router, httptest.NewRequest(http.MethodGet, "/forbidden", nil))
+		zhtest.AssertWith(t, w).Status(http.StatusForbidden).Body("403 page")
+	})
+
+	t.Run("status without handler", func(t *testing.T) {
+		w := zhtest.Serve(router, httptest.NewRequest(http.MethodGet, "/conflict", nil))
+		zhtest.AssertWith(t, w).
+			Status(http.StatusConflict).
+			IsProblemDetail().
+			JSONPathEqual("detail", "Already exists")
+	})
+
+	t.Run("rendered problem detail", func(t *testing.T) {
+		w := zhtest.Serve(router, httptest.NewRequest(http.MethodGet, "/auto", nil))
+		zhtest.AssertWith(t, w).
+			Status(http.StatusTooManyRequests).
+			Header(httpx.HeaderRetryAfter, "30").
+			JSONPathEqual("detail", "Nested")
+	})
+
+	t.Run("binding error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/bind", strings.NewReader("{"))
+		w := zhtest.Serve(router, req)
+		zhtest.AssertWith(t, w).Status(http.StatusBadRequest).Body("400 page")
+	})
+
+	t.Run("middleware error", func(t *testing.T) {
+		w := zhtest.Serve(router, httptest.NewRequest(http.MethodGet, "/private", nil))
+		zhtest.AssertWith(t, w).
+			Status(http.StatusUnauthorized).
+			Header(httpx.HeaderWWWAuthenticate, `Basic realm="Restricted"`).
+			Body("401 page")
+	})
+
+	t.Run("no route", func(t *testing.T) {
+		w := zhtest.Serve(router, httptest.NewRequest(http.MethodGet, "/missing", nil))
+		zhtest.AssertWith(t, w).Status(http.StatusNotFound).Body("404 page")
+	})
+
+	t.Run("group created with Route", func(t *testing.T) {
+		w := zhtest.Serve(router, httptest.NewRequest(http.MethodGet, "/admin/forbidden", nil))
+		zhtest.AssertWith(t, w).Status(http.StatusForbidden).Body("403 admin page")
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		zhtest.AssertPanic(t, func() {
+			NewRouter().ErrorHandler(http.StatusOK, statusPage("200 page"))
+		})
+	})
+}
+
+func TestRouter_Mount(t *testing.T) {
+	var calls []string
+	router := NewRouter(testMiddleware("global", &calls))
+	router.GET("/users", testHandler("users"))
+
+	sub := http.NewServeMux()
+	sub.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Method + " " + r.URL.Path))
+	})
+	router.Mount("/admin/", sub)
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, "/admin", "GET /"},
+		{http.MethodGet, "/admin/", "GET /"},
+		{http.MethodDelete, "/admin/users/1", "DELETE /users/1"},
+		{http.MethodGet, "/users", "users"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			calls = nil
+			w := zhtest.Serve(router, httptest.NewRequest(tt.method, tt.path, nil))
+
+			zhtest.AssertWith(t, w).
+				Status(http.StatusOK).
+				Body(tt.body)
+			zhtest.AssertEqual(t, []string{"global"}, calls)
+		})
+	}
+
+	t.Run("alongside static", func(t *testing.T) {
+		router := NewRouter()
+		router.StaticFS(NewMemFS(map[string][]byte{"index.html": []byte("index")}), true)
+		router.Mount("/admin", sub)
+
+		zhtest.AssertWith(t, zhtest.Serve(router, httptest.NewRequest(http.MethodGet, "/admin/x", nil))).
+			Status(http.StatusOK).
+			Body("GET /x")
+	})
+
+	t.Run("invalid prefix", func(t *testing.T) {
+		for _, prefix := range []string{"", "/", "admin"} {
+			zhtest.AssertPanic(t, func() {
+				NewRouter().Mount(prefix, sub)
+			})
+		}
+	})
+}
+
+func TestRouter_MountRouter(t *testing.T) {
+	var calls []string
+	admin := NewRouter(testMiddleware("admin", &calls))
+	admin.GET("/", testHandler("dashboard"))
+	admin.GET("/users/{id}", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		_, err := w.Write([]byte("user " + r.PathValue("id") + " at " + r.URL.Path))
+		return err
+	}))
+
+	router := NewRouter(testMiddleware("global", &calls))
+	router.MountRouter("/admin", admin)
+
+	t.Run("mounted routes", func(t *testing.T) {
+		calls = nil
+		zhtest.AssertWith(t, zhtest.Serve(router, httptest.NewRequest(http.MethodGet, "/admin/users/42", nil))).
+			Status(http.StatusOK).
+			Body("user 42 at /users/42")
+		zhtest.AssertEqual(t, []string{"global", "admin"}, calls)
+
+		zhtest.AssertWith(t, zhtest.Serve(router, httptest.NewRequest(http.MethodGet, "/admin", nil))).
+			Status(http.StatusOK).
+			Body("dashboard")
+	})
+
+	t.Run("routes are listed", func(t *testing.T) {
+		zhtest.AssertEqual(t, []RouteInfo{
+			{Method: http.MethodGet, Path: "/admin"},
+			{Method: http.MethodGet, Path: "/admin/users/{id}"},
+		}, router.Routes())
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		w := zhtest.Serve(router, httptest.NewRequest(http.MethodPost, "/admin/users/42", nil))
+		zhtest.AssertWith(t, w).
+			Status(http.StatusMethodNotAllowed).
+			Header(httpx.HeaderAllow, "GET, HEAD, OPTIONS")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		zhtest.AssertWith(t, zhtest.Serve(router, httptest.NewRequest(http.MethodGet, "/admin/settings", nil))).
+			Status(http.StatusNotFound)
+	})
+
+	t.Run("conflicting route", func(t *testing.T) {
+		router := NewRouter()
+		router.GET("/admin/users/{id}", testHandler("user"))
+		zhtest.AssertPanic(t, func() {
+			router.MountRouter("/admin", admin)
+		})
+	})
+}
+
 func TestRouter_ErrorHandlers(t *testing.T) {
 	t.Run("not found", func(t *testing.T) {
 		router := NewRouter()
@@ -960,6 +1288,26 @@ func TestUtilityFunctions(t *testing.T) {
 			BodyContains(`"status":404`)
 	})
 
+	t.Run("default handlers with XML accept", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set(httpx.HeaderAccept, httpx.MIMEApplicationProblemXML)
+		w := httptest.NewRecorder()
+		defaultNotFoundHandler.ServeHTTP(w, req)
+
+		zhtest.AssertWith(t, w).
+			Status(http.StatusNotFound).
+			Header(httpx.HeaderContentType, httpx.MIMEApplicationProblemXML).
+			BodyContains("<status>404</status>")
+
+		w = httptest.NewRecorder()
+		defaultMethodNotAllowedHandler.ServeHTTP(w, req)
+
+		zhtest.AssertWith(t, w).
+			Status(http.StatusMethodNotAllowed).
+			Header(httpx.HeaderContentType, httpx.MIMEApplicationProblemXML).
+			BodyContains("<status>405</status>")
+	})
+
 	t.Run("allowedMethods", func(t *testing.T) {
 		methods := map[string]bool{
 			http.MethodGet:  true,
@@ -1046,6 +1394,71 @@ func TestRouter_StaticFiles(t *testing.T) {
 	})
 }
 
+func TestRouter_FilesDirSecure(t *testing.T) {
+	base := t.TempDir()
+	dir := filepath.Join(base, "uploads")
+	zhtest.AssertNoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o755))
+	zhtest.AssertNoError(t, os.WriteFile(filepath.Join(dir, "report.txt"), []byte("report content"), 0o644))
+	zhtest.AssertNoError(t, os.WriteFile(filepath.Join(base, "secret.txt"), []byte("secret content"), 0o644))
+	zhtest.AssertNoError(t, os.Symlink(filepath.Join(dir, "report.txt"), filepath.Join(dir, "inside.txt")))
+	zhtest.AssertNoError(t, os.Symlink(filepath.Join(base, "secret.txt"), filepath.Join(dir, "outside.txt")))
+	zhtest.AssertNoError(t, os.Symlink(base, filepath.Join(dir, "parent")))
+
+	router := NewRouter()
+	router.FilesDirSecure("/files/", dir)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"file", "/files/report.txt", http.StatusOK},
+		{"symlink inside", "/files/inside.txt", http.StatusOK},
+		{"missing file", "/files/missing.txt", http.StatusNotFound},
+		{"directory", "/files/sub/", http.StatusNotFound},
+		{"symlink outside", "/files/outside.txt", http.StatusForbidden},
+		{"symlinked directory outside", "/files/parent/secret.txt", http.StatusForbidden},
+		{"encoded dot dot", "/files/%2e%2e/secret.txt", http.StatusForbidden},
+		{"backslash dot dot", "/files/..%5csecret.txt", http.StatusForbidden},
+		// ServeMux redirects it out of the prefix
+		{"dot dot", "/files/../secret.txt", http.StatusTemporaryRedirect},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := zhtest.Serve(router, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			zhtest.AssertWith(t, w).Status(tt.status).BodyNotContains("secret content")
+			if tt.status == http.StatusOK {
+				zhtest.AssertWith(t, w).Body("report content")
+			}
+		})
+	}
+
+	// Paths reaching the handler without being cleaned by ServeMux
+	t.Run("unclean paths", func(t *testing.T) {
+		root, err := filepath.EvalSymlinks(dir)
+		zhtest.AssertNoError(t, err)
+
+		handler := secureFileServer(root)
+		for _, p := range []string{"/../secret.txt", "/%2e%2e/secret.txt", "/sub/../../secret.txt"} {
+			w := zhtest.Serve(handler, httptest.NewRequest(http.MethodGet, p, nil))
+			zhtest.AssertWith(t, w).Status(http.StatusForbidden).BodyNotContains("secret content")
+		}
+	})
+
+	t.Run("range request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/files/report.txt", nil)
+		req.Header.Set(httpx.HeaderRange, "bytes=0-5")
+		w := zhtest.Serve(router, req)
+		zhtest.AssertWith(t, w).Status(http.StatusPartialContent).Body("report")
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		zhtest.AssertPanic(t, func() {
+			NewRouter().FilesDirSecure("/files/", filepath.Join(base, "missing"))
+		})
+	})
+}
+
 //go:embed testdata/static
 var testStaticFS embed.FS
 
@@ -1284,6 +1697,54 @@ func TestStatusCapture(t *testing.T) {
 	})
 }
 
+func TestRouter_PanicGuard(t *testing.T) {
+	panicking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	t.Run("recovers without recover middleware", func(t *testing.T) {
+		logger := &mockServerLogger{}
+		server := New(Config{DisableDefaultMiddlewares: true, Logger: logger})
+		server.GET("/panic", panicking)
+
+		var w *httptest.ResponseRecorder
+		zhtest.AssertNoPanic(t, func() {
+			w = zhtest.Serve(server, zhtest.NewRequest(http.MethodGet, "/panic").Build())
+		})
+
+		zhtest.AssertWith(t, w).Status(http.StatusInternalServerError).IsProblemDetail()
+
+		var logged bool
+		for _, entry := range logger.logs {
+			if entry.level == "error" && entry.message == "Recovered from panic in router" {
+				logged = true
+			}
+		}
+		zhtest.AssertTrue(t, logged)
+	})
+
+	t.Run("abort handler passes through", func(t *testing.T) {
+		router := NewRouter()
+		router.GET("/abort", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+
+		zhtest.AssertPanic(t, func() {
+			zhtest.Serve(router, zhtest.NewRequest(http.MethodGet, "/abort").Build())
+		})
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		router := NewRouter()
+		router.SetConfig(Config{DisablePanicGuard: true})
+		router.GET("/panic", panicking)
+
+		zhtest.AssertPanic(t, func() {
+			zhtest.Serve(router, zhtest.NewRequest(http.MethodGet, "/panic").Build())
+		})
+	})
+}
+
 func TestRouter_ServeMux(t *testing.T) {
 	router := NewRouter()
 
@@ -1303,6 +1764,27 @@ func TestRouter_ServeMux(t *testing.T) {
 		Body("direct handler")
 }
 
+func TestRouter_Routes(t *testing.T) {
+	router := NewRouter()
+	zhtest.AssertEqual(t, 0, len(router.Routes()))
+
+	router.POST("/users", testHandler("create"))
+	router.GET("/users", testHandler("list"))
+	router.Group(func(api Router) {
+		api.GET("/users/{id}", testHandler("get"))
+		api.DELETE("/users/{id}", testHandler("delete"))
+	})
+	router.GET("/", testHandler("root"))
+
+	zhtest.AssertEqual(t, []RouteInfo{
+		{Method: http.MethodGet, Path: "/"},
+		{Method: http.MethodGet, Path: "/users"},
+		{Method: http.MethodPost, Path: "/users"},
+		{Method: http.MethodDelete, Path: "/users/{id}"},
+		{Method: http.MethodGet, Path: "/users/{id}"},
+	}, router.Routes())
+}
+
 func TestRouter_CONNECT_WebTransport(t *testing.T) {
 	t.Run("CONNECT handler registration", func(t *testing.T) {
 		router := NewRouter()
@@ -1939,6 +2421,64 @@ func TestDefaultNotFoundHandler_AcceptJSON(t *testing.T) {
 
 // TestDefaultNotFoundHandler_AcceptProblemJSON verifies that the default NotFound handler
 // returns Problem Detail when Accept header contains application/problem+json.
+func TestDefaultHandlers_AcceptHTML(t *testing.T) {
+	router := NewRouter()
+	router.GET("/users", testHandler("users"))
+
+	browserAccept := "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+
+	w := zhtest.Serve(router, zhtest.NewRequest(http.MethodGet, "/missing").WithHeader(httpx.HeaderAccept, browserAccept).Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusNotFound).
+		Header(httpx.HeaderContentType, httpx.MIMETextHTMLCharset).
+		BodyContains("<h1>404 Not Found</h1>")
+
+	w = zhtest.Serve(router, zhtest.NewRequest(http.MethodPost, "/users").WithHeader(httpx.HeaderAccept, browserAccept).Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusMethodNotAllowed).
+		Header(httpx.HeaderContentType, httpx.MIMETextHTMLCharset).
+		BodyContains("<h1>405 Method Not Allowed</h1>")
+
+	// API clients accepting anything still get JSON
+	w = zhtest.Serve(router, zhtest.NewRequest(http.MethodGet, "/missing").WithHeader(httpx.HeaderAccept, "*/*").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusNotFound).IsProblemDetail()
+}
+
+func TestDefaultHandlers_RequestID(t *testing.T) {
+	router := NewRouter(requestid.New())
+	router.GET("/users", testHandler("users"))
+
+	w := zhtest.Serve(router, zhtest.NewRequest(http.MethodGet, "/missing").Build())
+	requestID := w.Header().Get(httpx.HeaderXRequestId)
+	zhtest.AssertNotEmpty(t, requestID)
+	zhtest.AssertWith(t, w).
+		Status(http.StatusNotFound).
+		IsProblemDetail().
+		JSONPathEqual("request_id", requestID)
+
+	w = zhtest.Serve(router, zhtest.NewRequest(http.MethodPost, "/users").WithHeader(httpx.HeaderXRequestId, "req-405").Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusMethodNotAllowed).
+		JSONPathEqual("request_id", "req-405")
+
+	w = zhtest.Serve(router, zhtest.NewRequest(http.MethodGet, "/missing").
+		WithHeader(httpx.HeaderXRequestId, "req-xml").
+		WithHeader(httpx.HeaderAccept, httpx.MIMEApplicationXML).
+		Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusNotFound).
+		BodyContains("<request_id>req-xml</request_id>")
+
+	// Without request IDs, the pre-encoded response is written
+	plain := NewRouter()
+	plain.SetConfig(Config{DisableDefaultMiddlewares: true})
+	w = zhtest.Serve(plain, zhtest.NewRequest(http.MethodGet, "/missing").Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusNotFound).
+		HeaderNotExists(httpx.HeaderXRequestId).
+		JSONPathNotExists("request_id")
+}
+
 func TestDefaultNotFoundHandler_AcceptProblemJSON(t *testing.T) {
 	router := NewRouter()
 
@@ -2051,3 +2591,324 @@ func TestDefaultMethodNotAllowedHandler_PlainText(t *testing.T) {
 		Header(httpx.HeaderContentType, httpx.MIMETextPlainCharset).
 		BodyContains("HTTP method is not allowed")
 }
+
+func TestRouter_StaticCacheControl(t *testing.T) {
+	m := NewMemFS(map[string][]byte{
+		"index.html":               []byte("<!DOCTYPE html><title>app</title>"),
+		"assets/index-BkL9x2aF.js": []byte("console.log('hashed')"),
+		"assets/logo.png":          {0x89, 'P', 'N', 'G'},
+	})
+
+	get := func(h http.Handler, target string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("StaticFS", func(t *testing.T) {
+		app := New(Config{
+			DisableDefaultMiddlewares: true,
+			Static:                    StaticConfig{MaxAge: time.Hour, Immutable: true},
+		})
+		app.StaticFS(m, true, "/api/")
+
+		zhtest.AssertWith(t, get(app, "/assets/index-BkL9x2aF.js")).
+			Status(http.StatusOK).
+			Header(httpx.HeaderCacheControl, "public, max-age=31536000, immutable")
+		zhtest.AssertWith(t, get(app, "/assets/logo.png")).
+			Status(http.StatusOK).
+			Header(httpx.HeaderCacheControl, "public, max-age=3600")
+		zhtest.AssertWith(t, get(app, "/")).
+			Status(http.StatusOK).
+			Header(httpx.HeaderCacheControl, "no-cache")
+		zhtest.AssertWith(t, get(app, "/dashboard")).
+			Status(http.StatusOK).
+			Header(httpx.HeaderCacheControl, "no-cache")
+		zhtest.AssertWith(t, get(app, "/api/users")).
+			Status(http.StatusNotFound).
+			HeaderNotExists(httpx.HeaderCacheControl)
+
+		// Conditional requests still work and keep the header
+		etag := get(app, "/assets/logo.png").Header().Get(httpx.HeaderETag)
+		zhtest.AssertWith(t, get(app, "/assets/logo.png", httpx.HeaderIfNoneMatch, etag)).
+			Status(http.StatusNotModified).
+			Header(httpx.HeaderCacheControl, "public, max-age=3600")
+	})
+
+	t.Run("Files", func(t *testing.T) {
+		app := New(Config{
+			DisableDefaultMiddlewares: true,
+			Static:                    StaticConfig{MaxAge: 24 * time.Hour},
+		})
+		app.Files("/static/", testFilesFS, "testdata/files")
+		app.FilesDir("/files/", "testdata/files")
+
+		zhtest.AssertWith(t, get(app, "/static/test.txt")).
+			Status(http.StatusOK).
+			Header(httpx.HeaderCacheControl, "public, max-age=86400")
+
+		w := get(app, "/files/test.txt")
+		zhtest.AssertWith(t, w).
+			Status(http.StatusOK).
+			Header(httpx.HeaderCacheControl, "public, max-age=86400")
+
+		zhtest.AssertWith(t, get(app, "/files/test.txt", httpx.HeaderIfModifiedSince, w.Header().Get(httpx.HeaderLastModified))).
+			Status(http.StatusNotModified).
+			Header(httpx.HeaderCacheControl, "public, max-age=86400")
+
+		// Errors aren't cached
+		zhtest.AssertWith(t, get(app, "/static/missing.txt")).
+			Status(http.StatusNotFound).
+			HeaderNotExists(httpx.HeaderCacheControl)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		app := New(Config{DisableDefaultMiddlewares: true})
+		app.StaticFS(m, true)
+		app.FilesDir("/files/", "testdata/files")
+
+		zhtest.AssertWith(t, get(app, "/assets/index-BkL9x2aF.js")).HeaderNotExists(httpx.HeaderCacheControl)
+		zhtest.AssertWith(t, get(app, "/files/test.txt")).HeaderNotExists(httpx.HeaderCacheControl)
+	})
+}
+
+func TestIsFingerprinted(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"assets/index-BkL9x2aF.js", true},
+		{"app.3f2a9c1b.js", true},
+		{"main.3f2a9c1b.min.css", true},
+		{"chunk_12ab34cd.js", true},
+		{"app.js", false},
+		{"bootstrap.min.css", false},
+		{"jquery-3.7.1.min.js", false},
+		{"index.html", false},
+		{"chunk-B_kL9x2a.js", true},
+		{"app.3F2A9C1B.js", true},
+		{"screenshot2024.png", false},
+		{"report2023.pdf", false},
+		{"favicon32.png", false},
+		{"report-2023.pdf", false},
+		{"photo-20240101.jpg", false},
+		{"logo-DarkMode.png", false},
+		{"3f2a9c1b.js", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zhtest.AssertEqual(t, tt.want, isFingerprinted(tt.name))
+		})
+	}
+}
+
+func TestRouter_StaticPrecompressed(t *testing.T) {
+	filesystem := fstest.MapFS{
+		"index.html":     {Data: []byte("<!DOCTYPE html>")},
+		"app.js":         {Data: []byte("console.log('plain')")},
+		"app.js.gz":      {Data: []byte("gzip bytes")},
+		"app.js.br":      {Data: []byte("brotli bytes")},
+		"style.css":      {Data: []byte("body{}")},
+		"style.css.gz":   {Data: []byte("gzip css")},
+		"only-br.svg":    {Data: []byte("<svg/>")},
+		"only-br.svg.br": {Data: []byte("brotli svg")},
+	}
+
+	router := NewRouter()
+	router.StaticFS(filesystem, false)
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		wantEncoding   string
+		wantBody       string
+		wantType       string
+	}{
+		{"prefers brotli", "/app.js", "gzip, deflate, br", "br", "brotli bytes", "javascript"},
+		{"gzip when brotli is not accepted", "/app.js", "gzip", "gzip", "gzip bytes", "javascript"},
+		{"gzip when brotli is refused", "/app.js", "br;q=0, gzip", "gzip", "gzip bytes", "javascript"},
+		{"wildcard", "/app.js", "*", "br", "brotli bytes", "javascript"},
+		{"named refusal wins over wildcard", "/app.js", "*, br;q=0", "gzip", "gzip bytes", "javascript"},
+		{"uncompressed without Accept-Encoding", "/app.js", "", "", "console.log('plain')", "javascript"},
+		{"gzip only variant", "/style.css", "br, gzip", "gzip", "gzip css", "text/css"},
+		{"brotli only variant not accepted", "/only-br.svg", "gzip", "", "<svg/>", "image/svg+xml"},
+		{"brotli only variant", "/only-br.svg", "br", "br", "brotli svg", "image/svg+xml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set(httpx.HeaderAcceptEncoding, tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			zhtest.AssertWith(t, w).
+				Status(http.StatusOK).
+				Body(tt.wantBody).
+				HeaderContains(httpx.HeaderContentType, tt.wantType).
+				Header(httpx.HeaderVary, httpx.HeaderAcceptEncoding)
+			zhtest.AssertEqual(t, tt.wantEncoding, w.Header().Get(httpx.HeaderContentEncoding))
+		})
+	}
+}
+
+func TestRouter_StaticDynamicRoutes(t *testing.T) {
+	index := []byte("<!DOCTYPE html><title>app</title>")
+	files := fstest.MapFS{
+		"index.html": {Data: index},
+		"app.js":     {Data: []byte("js")},
+	}
+
+	router := NewRouter()
+	router.StaticFS(files, true)
+
+	// Registered after the static catch-all, without an API prefix
+	router.GET("/api/users", testHandler("users"))
+	router.POST("/api/items", testHandler("created"))
+	router.PUT("/api/items/{id}", testHandler("updated"))
+
+	t.Run("GET route wins over the static handler", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+		zhtest.AssertWith(t, w).Status(http.StatusOK).Body("users")
+	})
+
+	t.Run("route without GET returns 405", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/items", nil))
+		zhtest.AssertWith(t, w).
+			Status(http.StatusMethodNotAllowed).
+			Header(httpx.HeaderAllow, "OPTIONS, POST").
+			BodyNotContains("<!DOCTYPE html>")
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/items/42", nil))
+		zhtest.AssertWith(t, w).Status(http.StatusMethodNotAllowed).Header(httpx.HeaderAllow, "OPTIONS, PUT")
+	})
+
+	t.Run("other methods of the route", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/items", nil))
+		zhtest.AssertWith(t, w).Status(http.StatusOK).Body("created")
+	})
+
+	t.Run("static files and fallback are still served", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/app.js", nil))
+		zhtest.AssertWith(t, w).Status(http.StatusOK).Body("js")
+
+		for _, p := range []string{"/", "/dashboard", "/api/other"} {
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, p, nil))
+			zhtest.AssertWith(t, w).Status(http.StatusOK).Body(string(index))
+		}
+	})
+
+	t.Run("parameterized route doesn't shadow files", func(t *testing.T) {
+		files := fstest.MapFS{
+			"index.html":  {Data: index},
+			"favicon.ico": {Data: []byte("icon")},
+		}
+
+		router := NewRouter()
+		router.POST("/{slug}", testHandler("created"))
+		router.DELETE("/{slug}", testHandler("deleted"))
+		router.StaticFS(files, true)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/favicon.ico", nil))
+		zhtest.AssertWith(t, w).Status(http.StatusOK).Body("icon")
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+		zhtest.AssertWith(t, w).Status(http.StatusMethodNotAllowed).Header(httpx.HeaderAllow, "DELETE, OPTIONS, POST")
+	})
+}
+
+func TestRouter_StaticFallback(t *testing.T) {
+	index := []byte("<!DOCTYPE html><title>app</title>")
+
+	t.Run("serves index.html for client routes", func(t *testing.T) {
+		mockLogger := &mockServerLogger{}
+		app := New(Config{DisableDefaultMiddlewares: true, Logger: mockLogger})
+		app.StaticFS(fstest.MapFS{"index.html": {Data: index}}, true)
+
+		req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+
+		zhtest.AssertWith(t, w).
+			Status(http.StatusOK).
+			Body(string(index)).
+			Header(httpx.HeaderCacheControl, "no-cache").
+			HeaderContains(httpx.HeaderContentType, "text/html")
+		zhtest.AssertEqual(t, "/users/42", req.URL.Path)
+
+		// The original path is logged
+		var logged bool
+		for _, entry := range mockLogger.logs {
+			for _, f := range entry.fields {
+				if f.Key == "path" && f.Value == "/users/42" {
+					logged = true
+				}
+			}
+		}
+		zhtest.AssertTrue(t, logged)
+	})
+
+	t.Run("configured status", func(t *testing.T) {
+		app := New(Config{
+			DisableDefaultMiddlewares: true,
+			Static:                    StaticConfig{FallbackStatus: http.StatusNotFound},
+		})
+		app.StaticFS(fstest.MapFS{"index.html": {Data: index}}, true)
+
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+		zhtest.AssertWith(t, w).
+			Status(http.StatusNotFound).
+			Body(string(index)).
+			Header(httpx.HeaderCacheControl, "no-cache").
+			Header(httpx.HeaderContentType, httpx.MIMETextHTMLCharset)
+
+		w = httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		zhtest.AssertWith(t, w).Status(http.StatusOK).Body(string(index))
+	})
+
+	t.Run("missing index.html returns not found", func(t *testing.T) {
+		router := NewRouter()
+		router.StaticFS(fstest.MapFS{"app.js": {Data: []byte("js")}}, true)
+
+		for _, p := range []string{"/", "/dashboard"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, p, nil))
+			zhtest.AssertWith(t, w).Status(http.StatusNotFound)
+			zhtest.AssertFalse(t, strings.Contains(w.Body.String(), "app.js"))
+		}
+	})
+
+	t.Run("conditional requests", func(t *testing.T) {
+		router := NewRouter()
+		router.StaticFS(NewMemFS(map[string][]byte{"index.html": index}), true)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/settings", nil))
+		etag := w.Header().Get(httpx.HeaderETag)
+		zhtest.AssertNotEqual(t, "", etag)
+
+		req := httptest.NewRequest(http.MethodGet, "/settings", nil)
+		req.Header.Set(httpx.HeaderIfNoneMatch, etag)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		zhtest.AssertWith(t, w).Status(http.StatusNotModified)
+	})
+}
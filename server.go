@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"os"
 	"sync"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	zconfig "github.com/alexferl/zerohttp/internal/config"
 	"github.com/alexferl/zerohttp/log"
 	"github.com/alexferl/zerohttp/metrics"
+	"github.com/alexferl/zerohttp/middleware/disconnect"
 	"github.com/alexferl/zerohttp/sse"
 )
 
@@ -32,6 +34,11 @@ const (
 	DefaultMaxHeaderBytes = 16 * 1024 // 16 KB -> actual limit is ~20 KB (Go adds +4096)
 )
 
+// ErrServerShutdown is the cause of the request contexts canceled by
+// [Server.Shutdown], so handlers can tell a shutdown apart from a client
+// that went away. It is the same error as [disconnect.ErrServerShutdown].
+var ErrServerShutdown = disconnect.ErrServerShutdown
+
 // DefaultHTTPServer returns a new http.Server with sensible defaults.
 // Use this as a base when you need to customize only specific server fields
 // while keeping the other defaults.
@@ -99,8 +106,8 @@ type Server struct {
 	// It is cancelled when Shutdown is called to signal request cancellation.
 	baseCtx context.Context
 
-	// cancelBaseCtx cancels the base context.
-	cancelBaseCtx context.CancelFunc
+	// cancelBaseCtx cancels the base context with ErrServerShutdown as its cause.
+	cancelBaseCtx context.CancelCauseFunc
 
 	// server is the HTTP server instance for handling plain HTTP traffic.
 	// If nil, HTTP server will not be started.
@@ -110,6 +117,19 @@ type Server struct {
 	// listener will be created using the server's configured address.
 	listener net.Listener
 
+	// unixSocket is the path of the Unix domain socket to listen on instead
+	// of the server's address when listener is nil.
+	unixSocket string
+
+	// unixSocketMode is the file mode of the Unix domain socket.
+	unixSocketMode os.FileMode
+
+	// proxyProtocol requires a PROXY protocol header on HTTP and HTTPS connections.
+	proxyProtocol bool
+
+	// reusePort sets SO_REUSEPORT on the HTTP and HTTPS TCP listeners.
+	reusePort bool
+
 	// tlsServer is the HTTPS server instance for handling encrypted traffic.
 	// If nil, HTTPS server will not be started.
 	tlsServer *http.Server
@@ -134,6 +154,13 @@ type Server struct {
 	// for recording HTTP requests, errors, and server lifecycle events.
 	logger log.Logger
 
+	// shutdownTimeout bounds the graceful shutdown performed by Run.
+	shutdownTimeout time.Duration
+
+	// drainDelay is how long Shutdown keeps serving after pre-shutdown hooks
+	// so load balancers can stop routing traffic to the server.
+	drainDelay time.Duration
+
 	// preStartupHooks execute sequentially before any startup hooks.
 	preStartupHooks []StartupHookConfig
 
@@ -198,6 +225,16 @@ type Server struct {
 	// If nil, WebTransport support will not be enabled.
 	// The server will be started automatically when ListenAndServeTLS or Start is called.
 	webTransportServer webtransport.Server
+
+	// activeRequests counts in-flight requests by route pattern so that
+	// Shutdown can report which routes are holding it up.
+	activeRequests *activeRequests
+
+	// stats holds the counters reported by Stats.
+	stats *serverStats
+
+	// startupGate serves the startup page until MarkStarted is called.
+	startupGate *startupGate
 }
 
 // New creates and configures a new Server instance with the provided
@@ -245,8 +282,13 @@ type Server struct {
 //	app := zh.New(Config{
 //	    Validator: myCustomValidator,
 //	})
+//
+// New panics if the config is invalid; see [Config.Validate].
 func New(cfg ...Config) *Server {
 	c := mergeConfig(cfg...)
+	if err := c.Validate(); err != nil {
+		panic(fmt.Errorf("zerohttp: invalid config: %w", err))
+	}
 	router := NewRouter()
 	logger := createLogger(c)
 
@@ -258,12 +300,16 @@ func New(cfg ...Config) *Server {
 	registry := createMetricsRegistry(c)
 	metricsServer := createMetricsServer(c, registry, logger)
 
-	baseCtx, cancelBaseCtx := context.WithCancel(context.Background())
+	baseCtx, cancelBaseCtx := context.WithCancelCause(context.Background())
 
 	s := &Server{
 		Router:             router,
 		server:             server,
 		listener:           c.Listener,
+		unixSocket:         c.UnixSocket,
+		unixSocketMode:     c.UnixSocketMode,
+		proxyProtocol:      c.ProxyProtocol,
+		reusePort:          c.ReusePort,
 		tlsServer:          tlsServer,
 		tlsListener:        c.TLS.Listener,
 		certFile:           c.TLS.CertFile,
@@ -279,6 +325,8 @@ func New(cfg ...Config) *Server {
 		metricsServerAddr:  config.StringOrDefault(c.Metrics.ServerAddr, ""),
 		validator:          c.Validator,
 		logger:             logger,
+		shutdownTimeout:    c.Lifecycle.ShutdownTimeout,
+		drainDelay:         c.Lifecycle.DrainDelay,
 		preStartupHooks:    c.Lifecycle.PreStartupHooks,
 		startupHooks:       c.Lifecycle.StartupHooks,
 		postStartupHooks:   c.Lifecycle.PostStartupHooks,
@@ -287,6 +335,9 @@ func New(cfg ...Config) *Server {
 		postShutdownHooks:  c.Lifecycle.PostShutdownHooks,
 		baseCtx:            baseCtx,
 		cancelBaseCtx:      cancelBaseCtx,
+		activeRequests:     newActiveRequests(),
+		stats:              &serverStats{},
+		startupGate:        newStartupGate(c.Startup),
 	}
 
 	setupMiddleware(s, c, registry)
@@ -318,20 +369,36 @@ func (s *Server) ListenAndServe() error {
 	}
 
 	var err error
-	if s.listener == nil {
+	if s.listener == nil && s.unixSocket != "" {
+		s.logger.Debug("Creating HTTP listener", log.F("unix_socket", s.unixSocket))
+		s.listener, err = listenUnix(s.unixSocket, s.unixSocketMode)
+		if err != nil {
+			s.mu.Unlock()
+			return err
+		}
+	} else if s.listener == nil {
 		s.logger.Debug("Creating HTTP listener", log.F("addr", s.server.Addr))
-		s.listener, err = net.Listen("tcp", s.server.Addr)
+		s.listener, err = s.listenTCP(s.server.Addr)
 		if err != nil {
 			s.mu.Unlock()
 			return err
 		}
 	}
 
+	ln := s.listener
+	if s.proxyProtocol {
+		ln = newProxyListener(ln, s.server.ReadHeaderTimeout, s.logger)
+	}
+
 	s.mu.Unlock()
 
-	s.logger.Info("Starting HTTP server", log.F("addr", fmtHTTPAddr(s.listener.Addr().String())))
+	if s.listener.Addr().Network() == "unix" {
+		s.logger.Info("Starting HTTP server", log.F("unix_socket", s.listener.Addr().String()))
+	} else {
+		s.logger.Info("Starting HTTP server", log.F("addr", fmtHTTPAddr(s.listener.Addr().String())))
+	}
 
-	return s.server.Serve(s.listener)
+	return s.server.Serve(ln)
 }
 
 // Start begins serving HTTP, HTTPS, and metrics traffic concurrently.
@@ -430,8 +497,15 @@ func (s *Server) Start() error {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			s.logger.Info("Starting HTTP server...", log.F("addr", fmtHTTPAddr(s.server.Addr)))
-			if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			var err error
+			if s.unixSocket != "" || s.proxyProtocol || s.reusePort {
+				// ListenAndServe creates the listener and logs its address
+				err = s.ListenAndServe()
+			} else {
+				s.logger.Info("Starting HTTP server...", log.F("addr", fmtHTTPAddr(s.server.Addr)))
+				err = s.server.ListenAndServe()
+			}
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
 				errCh <- fmt.Errorf("HTTP server error: %w", err)
 			}
 		}()
@@ -446,8 +520,7 @@ func (s *Server) Start() error {
 				log.F("addr", fmtHTTPSAddr(s.tlsServer.Addr)),
 				log.F("cert_file", s.certFile),
 				log.F("key_file", s.keyFile))
-			// Pass empty strings - certs are already loaded in TLSConfig.Certificates
-			if err := s.tlsServer.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			if err := s.serveTLS(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 				errCh <- fmt.Errorf("HTTPS server error: %w", err)
 			}
 		}()
@@ -550,6 +623,7 @@ func (s *Server) Start() error {
 
 // ListenerAddr returns the network address that the HTTP server is listening on.
 // If a listener is configured, it returns the listener's actual address.
+// If no listener is configured but a Unix socket is, it returns the socket path.
 // If no listener is configured but a server is configured, it returns the server's configured address.
 // If neither is configured, it returns an empty string.
 //
@@ -563,6 +637,10 @@ func (s *Server) ListenerAddr() string {
 		return s.listener.Addr().String()
 	}
 
+	if s.unixSocket != "" {
+		return s.unixSocket
+	}
+
 	if s.server != nil {
 		return s.server.Addr
 	}
@@ -620,17 +698,25 @@ func (s *Server) Validator() Validator {
 // encounters an error during shutdown, that error is returned.
 // Shutdown hooks are executed during the shutdown process:
 //   - Pre-shutdown hooks run sequentially before server shutdown begins
+//   - The server keeps serving for Lifecycle.DrainDelay, if set
 //   - Shutdown hooks run concurrently with server shutdown
 //   - Post-shutdown hooks run sequentially after all servers are shut down
 //
+// If the context has a deadline, the requests still active by route are
+// logged shortly before it expires to help find slow routes blocking shutdown.
+//
 // Returns the first error encountered during shutdown, or nil if successful.
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info("Shutting down server...")
 
-	// Cancel the base context to signal all requests to close
-	// This happens before pre-shutdown hooks so requests can start terminating
-	if s.cancelBaseCtx != nil {
-		s.cancelBaseCtx()
+	stopWatch := s.watchShutdownDeadline(ctx)
+	defer stopWatch()
+
+	// Cancel the base context with ErrServerShutdown to signal all requests to close
+	// This happens before pre-shutdown hooks so requests can start terminating,
+	// unless the server keeps serving during a drain delay
+	if s.cancelBaseCtx != nil && s.drainDelay <= 0 {
+		s.cancelBaseCtx(ErrServerShutdown)
 	}
 
 	// Execute pre-shutdown hooks sequentially
@@ -642,6 +728,13 @@ func (s *Server) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	if s.drainDelay > 0 {
+		s.drain(ctx)
+		if s.cancelBaseCtx != nil {
+			s.cancelBaseCtx(ErrServerShutdown)
+		}
+	}
+
 	// Start shutdown hooks concurrently and wait for them
 	hookWg, hookErrCh := s.startShutdownHooks(ctx)
 
@@ -826,6 +919,9 @@ func mergeConfig(cfg ...Config) Config {
 			c.Metrics.ServerAddr = userCfg.Metrics.ServerAddr
 		}
 	}
+	if c.DebugErrors {
+		c.Recover.DebugErrors = true
+	}
 	return c
 }
 
@@ -839,24 +935,63 @@ func createLogger(c Config) log.Logger {
 
 // createHTTPServer creates the HTTP server from config.
 func createHTTPServer(c Config, logger log.Logger) *http.Server {
-	if c.Server != nil {
-		if c.Server.ErrorLog == nil {
-			c.Server.ErrorLog = log.StdLogger(logger)
-		}
-		return c.Server
+	srv := c.Server
+	if srv == nil {
+		srv = DefaultHTTPServer()
+		srv.Addr = c.Addr
+		applyServerTimeouts(srv, c)
+	}
+	if srv.ErrorLog == nil {
+		srv.ErrorLog = log.StdLogger(logger)
+	}
+	if c.H2C {
+		enableH2C(srv)
 	}
-	srv := DefaultHTTPServer()
-	srv.Addr = c.Addr
-	srv.ErrorLog = log.StdLogger(logger)
 	return srv
 }
 
+// applyServerTimeouts applies the configured timeouts and header size limit
+// to a default server. Zero values keep the server defaults and negative
+// durations disable the timeout.
+func applyServerTimeouts(srv *http.Server, c Config) {
+	setTimeout := func(dst *time.Duration, d time.Duration) {
+		switch {
+		case d < 0:
+			*dst = 0
+		case d > 0:
+			*dst = d
+		}
+	}
+	setTimeout(&srv.ReadTimeout, c.ReadTimeout)
+	setTimeout(&srv.ReadHeaderTimeout, c.ReadHeaderTimeout)
+	setTimeout(&srv.WriteTimeout, c.WriteTimeout)
+	setTimeout(&srv.IdleTimeout, c.IdleTimeout)
+	if c.MaxHeaderBytes > 0 {
+		srv.MaxHeaderBytes = c.MaxHeaderBytes
+	}
+}
+
+// enableH2C adds unencrypted HTTP/2 to the protocols served by srv.
+func enableH2C(srv *http.Server) {
+	protocols := new(http.Protocols)
+	if srv.Protocols != nil {
+		*protocols = *srv.Protocols
+	} else {
+		// The server defaults when Protocols is unset
+		protocols.SetHTTP1(true)
+		protocols.SetHTTP2(true)
+	}
+	protocols.SetUnencryptedHTTP2(true)
+	srv.Protocols = protocols
+}
+
 // createTLSServer creates the TLS server from config if TLS is configured.
 func createTLSServer(c Config, logger log.Logger) *http.Server {
 	if c.TLS.Server != nil {
 		if c.TLS.Server.ErrorLog == nil {
 			c.TLS.Server.ErrorLog = log.StdLogger(logger)
 		}
+		applyTLSCertificates(c.TLS.Server, c)
 		return c.TLS.Server
 	}
 	if !needsTLSServer(c) {
@@ -864,14 +999,35 @@ func createTLSServer(c Config, logger log.Logger) *http.Server {
 	}
 	srv := DefaultTLSServer()
 	srv.Addr = c.TLS.Addr
+	applyServerTimeouts(srv, c)
+	applyTLSCertificates(srv, c)
 	srv.ErrorLog = log.StdLogger(logger)
 	return srv
 }
 
+// applyTLSCertificates sets the configured certificates on the TLS config of
+// srv. Start doesn't load CertFile and KeyFile once certificates are set.
+func applyTLSCertificates(srv *http.Server, c Config) {
+	if len(c.TLS.Certificates) == 0 && c.TLS.GetCertificate == nil {
+		return
+	}
+	if srv.TLSConfig == nil {
+		srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if len(c.TLS.Certificates) > 0 {
+		srv.TLSConfig.Certificates = c.TLS.Certificates
+	}
+	if c.TLS.GetCertificate != nil {
+		srv.TLSConfig.GetCertificate = c.TLS.GetCertificate
+	}
+}
+
 // needsTLSServer returns true if the config requires a TLS server to be created.
 func needsTLSServer(c Config) bool {
 	return c.TLS.CertFile != "" ||
 		c.TLS.KeyFile != "" ||
+		len(c.TLS.Certificates) > 0 ||
+		c.TLS.GetCertificate != nil ||
 		c.Extensions.AutocertManager != nil ||
 		c.TLS.Listener != nil ||
 		c.Extensions.HTTP3Server != nil
@@ -928,6 +1084,11 @@ func setupMiddleware(s *Server, c Config, registry metrics.Registry) {
 		middlewares = append(middlewares, metrics.NewMiddleware(registry, c.Metrics))
 	}
 
+	if !c.DisableDefaultMiddlewares {
+		middlewares = append(middlewares, s.activeRequests.middleware, s.stats.middleware)
+	}
+	middlewares = append(middlewares, c.PrependMiddlewares...)
+
 	if c.DisableDefaultMiddlewares {
 		middlewares = append(middlewares, c.DefaultMiddlewares...)
 	} else if c.DefaultMiddlewares == nil {
@@ -938,18 +1099,24 @@ func setupMiddleware(s *Server, c Config, registry metrics.Registry) {
 		middlewares = append(middlewares, c.DefaultMiddlewares...)
 	}
 
-	if len(middlewares) > 0 {
-		s.Use(middlewares...)
+	// Serve the startup page after the default middlewares so responses get
+	// request IDs, security headers and are logged
+	if c.Startup.Page != nil {
+		middlewares = append(middlewares, s.startupGate.middleware)
 	}
+
+	s.Use(middlewares...)
 }
 
-// setupServerHandlers sets the router and base context on server instances.
+// setupServerHandlers sets the router and base context on server instances
+// and counts their connections for Stats.
 func setupServerHandlers(s *Server, router Router) {
 	if s.server != nil {
 		s.server.Handler = router
 		s.server.BaseContext = func(net.Listener) context.Context {
 			return s.baseCtx
 		}
+		s.stats.trackConnState(s.server)
 	}
 
 	if s.tlsServer != nil {
@@ -957,6 +1124,7 @@ func setupServerHandlers(s *Server, router Router) {
 		s.tlsServer.BaseContext = func(net.Listener) context.Context {
 			return s.baseCtx
 		}
+		s.stats.trackConnState(s.tlsServer)
 	}
 }
 
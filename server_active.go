@@ -0,0 +1,93 @@
+// Package zerohttp provides tracking of active requests. See [Server.ActiveRequests].
+package zerohttp
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alexferl/zerohttp/log"
+)
+
+// activeRequests counts in-flight requests by route pattern. Counters are
+// created once per pattern and updated atomically, so that requests to
+// different routes don't contend on a lock.
+type activeRequests struct {
+	routes sync.Map // route pattern -> *atomic.Int64
+}
+
+func newActiveRequests() *activeRequests {
+	return &activeRequests{}
+}
+
+// middleware counts the request under its route pattern while it is served.
+// The pattern is set by the router before route middleware runs.
+func (a *activeRequests) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pattern := r.Pattern
+		if pattern == "" {
+			pattern = "unmatched"
+		}
+
+		v, ok := a.routes.Load(pattern)
+		if !ok {
+			v, _ = a.routes.LoadOrStore(pattern, new(atomic.Int64))
+		}
+		n := v.(*atomic.Int64)
+		n.Add(1)
+		defer n.Add(-1)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// snapshot returns the counts of the routes with active requests and their total.
+func (a *activeRequests) snapshot() (map[string]int, int) {
+	routes := make(map[string]int)
+	total := 0
+	a.routes.Range(func(k, v any) bool {
+		if n := int(v.(*atomic.Int64).Load()); n > 0 {
+			routes[k.(string)] = n
+			total += n
+		}
+		return true
+	})
+	return routes, total
+}
+
+// ActiveRequests returns the number of requests currently being served,
+// keyed by route pattern such as "GET /users/{id}". Routes without active
+// requests are omitted. Requests are counted by a default middleware, so
+// none are reported with [Config.DisableDefaultMiddlewares].
+//
+// This method is thread-safe and can be called concurrently.
+func (s *Server) ActiveRequests() map[string]int {
+	routes, _ := s.activeRequests.snapshot()
+	return routes
+}
+
+// watchShutdownDeadline logs the active requests by route once 90% of the
+// time left before the shutdown context's deadline has passed, so that the
+// routes holding up shutdown can be identified before they are cut off.
+// The returned function stops the watch.
+func (s *Server) watchShutdownDeadline(ctx context.Context) func() {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return func() {}
+	}
+
+	remaining := time.Until(deadline)
+	timer := time.AfterFunc(remaining-remaining/10, func() {
+		routes, total := s.activeRequests.snapshot()
+		if total == 0 {
+			return
+		}
+		s.logger.Warn("Shutdown deadline approaching with active requests",
+			log.F("active", total),
+			log.F("routes", routes),
+		)
+	})
+	return func() { timer.Stop() }
+}
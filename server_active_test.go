@@ -0,0 +1,118 @@
+package zerohttp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestServer_ActiveRequests(t *testing.T) {
+	server := New()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server.GET("/users/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+	}))
+
+	zhtest.AssertEqual(t, 0, len(server.ActiveRequests()))
+
+	done := make(chan struct{})
+	for _, id := range []string{"1", "2"} {
+		go func() {
+			server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/"+id, nil))
+			done <- struct{}{}
+		}()
+		<-started
+	}
+
+	zhtest.AssertEqual(t, map[string]int{"GET /users/{id}": 2}, server.ActiveRequests())
+
+	close(release)
+	<-done
+	<-done
+
+	zhtest.AssertEqual(t, 0, len(server.ActiveRequests()))
+}
+
+func TestServer_ActiveRequests_DisableDefaultMiddlewares(t *testing.T) {
+	server := New(Config{DisableDefaultMiddlewares: true})
+
+	server.GET("/users/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		zhtest.AssertEqual(t, 0, len(server.ActiveRequests()))
+	}))
+
+	server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+}
+
+func TestServer_Shutdown_ReportsActiveRequests(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	zhtest.AssertNoError(t, err)
+
+	mockLogger := &mockServerLogger{}
+	server := New(Config{Listener: listener, Logger: mockLogger})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	server.GET("/slow", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+
+	go func() { _ = server.ListenAndServe() }()
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String() + "/slow")
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		zhtest.AssertFail(t, "timeout waiting for request to start")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	err = server.Shutdown(ctx)
+	zhtest.AssertErrorIs(t, err, context.DeadlineExceeded)
+
+	var found bool
+	for _, entry := range mockLogger.entries() {
+		if entry.message != "Shutdown deadline approaching with active requests" {
+			continue
+		}
+		found = true
+		zhtest.AssertEqual(t, "warn", entry.level)
+		for _, f := range entry.fields {
+			switch f.Key {
+			case "active":
+				zhtest.AssertEqual(t, 1, f.Value)
+			case "routes":
+				zhtest.AssertEqual(t, map[string]int{"GET /slow": 1}, f.Value)
+			}
+		}
+	}
+	zhtest.AssertTrue(t, found)
+}
+
+func TestServer_Shutdown_NoReportWithoutActiveRequests(t *testing.T) {
+	mockLogger := &mockServerLogger{}
+	server := New(Config{Logger: mockLogger})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	zhtest.AssertNoError(t, server.Shutdown(ctx))
+
+	for _, entry := range mockLogger.entries() {
+		zhtest.AssertNotEqual(t, "warn", entry.level)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/alexferl/zerohttp/log"
 )
@@ -164,6 +165,9 @@ func (s *Server) runPostStartupHooks(ctx context.Context) error {
 // Hooks must respect context cancellation by checking ctx.Done().
 // If a hook blocks without respecting the context, shutdown will hang.
 //
+// To let load balancers drain the server after a hook marks it unhealthy,
+// set Config.Lifecycle.DrainDelay.
+//
 // Example:
 //
 //	app.RegisterPreShutdownHook("health", func(ctx context.Context) error {
@@ -212,6 +216,21 @@ func (s *Server) RegisterPostShutdownHook(name string, hook ShutdownHook) {
 	s.postShutdownHooks = append(s.postShutdownHooks, ShutdownHookConfig{Name: name, Hook: hook})
 }
 
+// drain waits for the drain delay, or until ctx is done, while the servers
+// keep serving requests.
+func (s *Server) drain(ctx context.Context) {
+	s.logger.Info("Draining connections before shutdown", log.F("delay", s.drainDelay.String()))
+
+	timer := time.NewTimer(s.drainDelay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		s.logger.Warn("Drain delay cut short by shutdown context", log.E(ctx.Err()))
+	}
+}
+
 // runPreShutdownHooks executes pre-shutdown hooks sequentially in registration order.
 func (s *Server) runPreShutdownHooks(ctx context.Context) error {
 	s.mu.RLock()
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/alexferl/zerohttp/middleware/disconnect"
 	"github.com/alexferl/zerohttp/zhtest"
 )
 
@@ -91,6 +92,79 @@ func TestServer_Shutdown_WithPreShutdownHooks(t *testing.T) {
 	zhtest.AssertEqual(t, "hook-2", order[1])
 }
 
+func TestServer_Shutdown_DrainDelay(t *testing.T) {
+	newServer := func() *Server {
+		listener, _ := net.Listen("tcp", "127.0.0.1:0")
+		server := New(Config{Lifecycle: LifecycleConfig{DrainDelay: 100 * time.Millisecond}})
+		server.listener = listener
+		server.server = &http.Server{Addr: listener.Addr().String()}
+		return server
+	}
+
+	t.Run("waits after pre-shutdown hooks", func(t *testing.T) {
+		server := newServer()
+
+		var hookRan time.Time
+		server.RegisterPreShutdownHook("deregister", func(ctx context.Context) error {
+			hookRan = time.Now()
+			// Requests are still served normally while draining
+			zhtest.AssertNoError(t, server.baseCtx.Err())
+			return nil
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		zhtest.AssertNoError(t, server.Shutdown(ctx))
+		zhtest.AssertTrue(t, time.Since(hookRan) >= 100*time.Millisecond)
+		zhtest.AssertErrorIs(t, context.Cause(server.baseCtx), ErrServerShutdown)
+	})
+
+	t.Run("cut short by the shutdown context", func(t *testing.T) {
+		server := newServer()
+		server.drainDelay = time.Minute
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_ = server.Shutdown(ctx)
+		zhtest.AssertTrue(t, time.Since(start) < time.Second)
+	})
+}
+
+func TestServer_Shutdown_CancelsRequestsWithCause(t *testing.T) {
+	server := New(Config{Addr: "127.0.0.1:0", DisableDefaultMiddlewares: true})
+
+	started := make(chan struct{})
+	cause := make(chan error, 1)
+	server.GET("/slow", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+		cause <- context.Cause(r.Context())
+	}), disconnect.New())
+
+	go func() { _ = server.ListenAndServe() }()
+
+	var addr string
+	for range 100 {
+		if addr = server.ListenerAddr(); addr != "127.0.0.1:0" {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	go func() { _, _ = http.Get("http://" + addr + "/slow") }()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_ = server.Shutdown(ctx)
+
+	// Shutting down is not a client disconnect
+	zhtest.AssertErrorIs(t, <-cause, ErrServerShutdown)
+}
+
 func TestServer_Shutdown_WithPostShutdownHooks(t *testing.T) {
 	listener, _ := net.Listen("tcp", "127.0.0.1:0")
 	server := New()
@@ -471,7 +545,7 @@ func TestServer_StartupHook_RespectsContextCancellation(t *testing.T) {
 	server.server = &http.Server{Addr: listener.Addr().String()}
 
 	// Cancel the base context before starting
-	server.cancelBaseCtx()
+	server.cancelBaseCtx(ErrServerShutdown)
 
 	err := server.Start()
 	zhtest.AssertError(t, err)
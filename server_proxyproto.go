@@ -0,0 +1,246 @@
+// Package zerohttp provides PROXY protocol listeners. See [Config.ProxyProtocol].
+package zerohttp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexferl/zerohttp/log"
+)
+
+// errNotProxyProtocol is returned when a connection on a PROXY protocol
+// listener doesn't start with a valid PROXY protocol header.
+var errNotProxyProtocol = errors.New("zerohttp: connection did not send a PROXY protocol header")
+
+// proxyV2Signature is the signature that starts a PROXY protocol v2 header.
+var proxyV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+const (
+	// proxyV1MaxLength is the maximum length of a v1 header, including the CRLF.
+	proxyV1MaxLength = 107
+
+	// proxyV2HeaderLength is the length of the fixed part of a v2 header.
+	proxyV2HeaderLength = 16
+)
+
+// proxyListener wraps a net.Listener to read the PROXY protocol header sent by
+// a load balancer at the start of each connection. The addresses from the
+// header are reported by the connection's RemoteAddr and LocalAddr, so
+// r.RemoteAddr is the address of the original client.
+type proxyListener struct {
+	net.Listener
+	timeout time.Duration
+	logger  log.Logger
+}
+
+// newProxyListener wraps ln to require a PROXY protocol header on every
+// connection. The header must be received within timeout.
+func newProxyListener(ln net.Listener, timeout time.Duration, logger log.Logger) net.Listener {
+	if timeout <= 0 {
+		timeout = DefaultReadHeaderTimeout
+	}
+	return &proxyListener{Listener: ln, timeout: timeout, logger: logger}
+}
+
+// Accept waits for the next connection. The header is read lazily on first
+// use of the connection so that a slow client can't block other connections
+// from being accepted.
+func (l *proxyListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &proxyConn{Conn: conn, listener: l}, nil
+}
+
+// proxyConn is a connection accepted by a proxyListener.
+type proxyConn struct {
+	net.Conn
+	listener *proxyListener
+	once     sync.Once
+	reader   *bufio.Reader
+	remote   net.Addr
+	local    net.Addr
+	err      error
+}
+
+// init reads the PROXY protocol header. Connections with a missing or
+// invalid header are closed, rather than served with a wrong client address.
+func (c *proxyConn) init() {
+	c.reader = bufio.NewReader(c.Conn)
+
+	_ = c.Conn.SetReadDeadline(time.Now().Add(c.listener.timeout))
+	c.remote, c.local, c.err = readProxyHeader(c.reader)
+	_ = c.Conn.SetReadDeadline(time.Time{})
+
+	if c.err != nil {
+		c.listener.logger.Warn("Rejected connection without a valid PROXY protocol header",
+			log.F("remote_addr", c.Conn.RemoteAddr().String()),
+			log.E(c.err),
+		)
+		_ = c.Conn.Close()
+	}
+}
+
+func (c *proxyConn) Read(b []byte) (int, error) {
+	c.once.Do(c.init)
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.reader.Read(b)
+}
+
+// RemoteAddr returns the client address from the PROXY protocol header, or
+// the address of the peer if the header doesn't carry one.
+func (c *proxyConn) RemoteAddr() net.Addr {
+	c.once.Do(c.init)
+	if c.remote != nil {
+		return c.remote
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// LocalAddr returns the destination address from the PROXY protocol header,
+// or the local address of the connection if the header doesn't carry one.
+func (c *proxyConn) LocalAddr() net.Addr {
+	c.once.Do(c.init)
+	if c.local != nil {
+		return c.local
+	}
+	return c.Conn.LocalAddr()
+}
+
+// readProxyHeader reads a v1 or v2 PROXY protocol header from r and returns
+// the source and destination addresses it carries. Both are nil for headers
+// that don't carry addresses, such as health checks from the load balancer.
+func readProxyHeader(r *bufio.Reader) (src, dst net.Addr, err error) {
+	first, err := r.Peek(1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch first[0] {
+	case 'P':
+		return readProxyV1(r)
+	case proxyV2Signature[0]:
+		return readProxyV2(r)
+	default:
+		return nil, nil, errNotProxyProtocol
+	}
+}
+
+// readProxyV1 reads a human-readable v1 header such as
+// "PROXY TCP4 192.0.2.1 198.51.100.1 56324 443\r\n".
+func readProxyV1(r *bufio.Reader) (src, dst net.Addr, err error) {
+	var line []byte
+	for len(line) < proxyV1MaxLength {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, nil, err
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+	}
+
+	header, ok := strings.CutSuffix(string(line), "\r\n")
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: v1 header is not terminated by CRLF", errNotProxyProtocol)
+	}
+
+	fields := strings.Split(header, " ")
+	if fields[0] != "PROXY" || len(fields) < 2 {
+		return nil, nil, errNotProxyProtocol
+	}
+
+	switch fields[1] {
+	case "UNKNOWN":
+		return nil, nil, nil
+	case "TCP4", "TCP6":
+		if len(fields) != 6 {
+			return nil, nil, fmt.Errorf("%w: malformed v1 header", errNotProxyProtocol)
+		}
+		src, err := parseProxyV1Addr(fields[2], fields[4], fields[1] == "TCP4")
+		if err != nil {
+			return nil, nil, err
+		}
+		dst, err := parseProxyV1Addr(fields[3], fields[5], fields[1] == "TCP4")
+		if err != nil {
+			return nil, nil, err
+		}
+		return src, dst, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: unsupported v1 protocol %q", errNotProxyProtocol, fields[1])
+	}
+}
+
+func parseProxyV1Addr(host, port string, v4 bool) (*net.TCPAddr, error) {
+	ip := net.ParseIP(host)
+	if ip == nil || (v4 && ip.To4() == nil) {
+		return nil, fmt.Errorf("%w: invalid address %q", errNotProxyProtocol, host)
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid port %q", errNotProxyProtocol, port)
+	}
+	return &net.TCPAddr{IP: ip, Port: int(p)}, nil
+}
+
+// readProxyV2 reads a binary v2 header.
+func readProxyV2(r *bufio.Reader) (src, dst net.Addr, err error) {
+	header := make([]byte, proxyV2HeaderLength)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, nil, err
+	}
+	if !bytes.Equal(header[:12], proxyV2Signature) {
+		return nil, nil, errNotProxyProtocol
+	}
+
+	version, command := header[12]>>4, header[12]&0x0f
+	if version != 2 || command > 1 {
+		return nil, nil, fmt.Errorf("%w: unsupported v2 version or command", errNotProxyProtocol)
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, nil, err
+	}
+
+	// LOCAL connections are opened by the proxy itself, e.g. for health checks
+	if command == 0 {
+		return nil, nil, nil
+	}
+
+	var ipLen int
+	switch header[13] >> 4 {
+	case 1: // AF_INET
+		ipLen = net.IPv4len
+	case 2: // AF_INET6
+		ipLen = net.IPv6len
+	default: // AF_UNSPEC and AF_UNIX carry no IP addresses
+		return nil, nil, nil
+	}
+
+	if len(payload) < 2*ipLen+4 {
+		return nil, nil, fmt.Errorf("%w: v2 address block too short", errNotProxyProtocol)
+	}
+	src = &net.TCPAddr{
+		IP:   net.IP(payload[:ipLen]),
+		Port: int(binary.BigEndian.Uint16(payload[2*ipLen:])),
+	}
+	dst = &net.TCPAddr{
+		IP:   net.IP(payload[ipLen : 2*ipLen]),
+		Port: int(binary.BigEndian.Uint16(payload[2*ipLen+2:])),
+	}
+	return src, dst, nil
+}
@@ -0,0 +1,120 @@
+package zerohttp
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func proxyV2Header(command byte, family byte, addrs []byte) []byte {
+	header := append([]byte{}, proxyV2Signature...)
+	header = append(header, 0x20|command, family)
+	header = binary.BigEndian.AppendUint16(header, uint16(len(addrs)))
+	return append(header, addrs...)
+}
+
+func TestReadProxyHeader(t *testing.T) {
+	v4Addrs := []byte{203, 0, 113, 7, 10, 0, 0, 1}
+	v4Addrs = binary.BigEndian.AppendUint16(v4Addrs, 56324)
+	v4Addrs = binary.BigEndian.AppendUint16(v4Addrs, 443)
+	withTLV := append(append([]byte{}, v4Addrs...), 0x04, 0x00, 0x01, 'x')
+
+	tests := []struct {
+		name    string
+		input   string
+		src     string
+		dst     string
+		wantErr bool
+	}{
+		{"v1 TCP4", "PROXY TCP4 203.0.113.7 10.0.0.1 56324 443\r\n", "203.0.113.7:56324", "10.0.0.1:443", false},
+		{"v1 TCP6", "PROXY TCP6 2001:db8::1 2001:db8::2 56324 443\r\n", "[2001:db8::1]:56324", "[2001:db8::2]:443", false},
+		{"v1 UNKNOWN", "PROXY UNKNOWN\r\n", "", "", false},
+		{"v2 TCP4", string(proxyV2Header(0x1, 0x11, v4Addrs)), "203.0.113.7:56324", "10.0.0.1:443", false},
+		{"v2 TCP4 with TLVs", string(proxyV2Header(0x1, 0x11, withTLV)), "203.0.113.7:56324", "10.0.0.1:443", false},
+		{"v2 LOCAL", string(proxyV2Header(0x0, 0x00, nil)), "", "", false},
+		{"plain HTTP", "GET / HTTP/1.1\r\n", "", "", true},
+		{"v1 wrong family", "PROXY TCP4 2001:db8::1 10.0.0.1 56324 443\r\n", "", "", true},
+		{"v1 bad port", "PROXY TCP4 203.0.113.7 10.0.0.1 99999 443\r\n", "", "", true},
+		{"v1 too long", "PROXY TCP4 " + strings.Repeat("1", 120) + "\r\n", "", "", true},
+		{"v2 short address block", string(proxyV2Header(0x1, 0x11, v4Addrs[:8])), "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(tt.input + "GET / HTTP/1.1\r\n"))
+			src, dst, err := readProxyHeader(r)
+			if tt.wantErr {
+				zhtest.AssertError(t, err)
+				return
+			}
+			zhtest.AssertNoError(t, err)
+
+			if tt.src == "" {
+				zhtest.AssertNil(t, src)
+				zhtest.AssertNil(t, dst)
+			} else {
+				zhtest.AssertEqual(t, tt.src, src.String())
+				zhtest.AssertEqual(t, tt.dst, dst.String())
+			}
+
+			// The request following the header is left to be read
+			rest, _ := r.ReadString('\n')
+			zhtest.AssertEqual(t, "GET / HTTP/1.1\r\n", rest)
+		})
+	}
+}
+
+func TestServer_ProxyProtocol(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	zhtest.AssertNoError(t, err)
+
+	server := New(Config{Listener: listener, ProxyProtocol: true, Logger: &mockServerLogger{}})
+	server.GET("/ip", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.RemoteAddr))
+	}))
+
+	go func() { _ = server.ListenAndServe() }()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+	}()
+
+	addr := listener.Addr().String()
+
+	t.Run("uses the client address from the header", func(t *testing.T) {
+		conn, err := net.Dial("tcp", addr)
+		zhtest.AssertNoError(t, err)
+		defer func() { _ = conn.Close() }()
+
+		_, err = io.WriteString(conn, "PROXY TCP4 203.0.113.7 10.0.0.1 56324 80\r\n"+
+			"GET /ip HTTP/1.1\r\nHost: example.com\r\nConnection: close\r\n\r\n")
+		zhtest.AssertNoError(t, err)
+
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		zhtest.AssertNoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		body, err := io.ReadAll(resp.Body)
+		zhtest.AssertNoError(t, err)
+		zhtest.AssertEqual(t, http.StatusOK, resp.StatusCode)
+		zhtest.AssertEqual(t, "203.0.113.7:56324", string(body))
+	})
+
+	t.Run("rejects connections without a header", func(t *testing.T) {
+		client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+		resp, err := client.Get("http://" + addr + "/ip")
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		zhtest.AssertError(t, err)
+	})
+}
@@ -0,0 +1,80 @@
+// Package zerohttp provides SO_REUSEPORT listeners and draining. See [Config.ReusePort].
+package zerohttp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/alexferl/zerohttp/log"
+)
+
+// errReusePortUnsupported is returned when listening with [Config.ReusePort]
+// on a platform without SO_REUSEPORT.
+var errReusePortUnsupported = errors.New("zerohttp: SO_REUSEPORT is not supported on this platform")
+
+// listenTCP listens on the TCP address addr, with SO_REUSEPORT set on the
+// socket when the server was configured with ReusePort.
+func (s *Server) listenTCP(addr string) (net.Listener, error) {
+	if !s.reusePort {
+		return net.Listen("tcp", addr)
+	}
+
+	lc := net.ListenConfig{Control: reusePortControl}
+	return lc.Listen(context.Background(), "tcp", addr)
+}
+
+// Drain stops the HTTP and HTTPS servers from accepting new connections and
+// waits for the active ones to finish their requests, closing idle
+// keep-alive connections. Unlike Shutdown, it doesn't run shutdown hooks or
+// cancel the base context, and the metrics, HTTP/3 and WebTransport servers
+// keep running.
+//
+// Combined with [Config.ReusePort], Drain hands the address over to a new
+// process without refusing connections: the new process binds the same
+// address and starts accepting, then the old one drains and shuts down.
+//
+// Parameters:
+//   - ctx: Context that limits how long to wait for active connections.
+//     If it is cancelled first, the remaining connections are left open
+//     and ctx's error is returned.
+//
+// Example:
+//
+//	// In the old process, once the new one is ready (e.g. on SIGUSR2)
+//	if err := app.Drain(ctx); err != nil {
+//	    log.Println("drain:", err)
+//	}
+//	_ = app.Shutdown(ctx) // Run shutdown hooks and stop the remaining servers
+func (s *Server) Drain(ctx context.Context) error {
+	s.logger.Info("Draining server connections...")
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, 2)
+
+	for _, srv := range []*http.Server{s.server, s.tlsServer} {
+		if srv == nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := srv.Shutdown(ctx); err != nil {
+				errCh <- err
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	if err := <-errCh; err != nil {
+		s.logger.Warn("Drain did not complete", log.E(err))
+		return err
+	}
+
+	s.logger.Info("Server connections drained")
+	return nil
+}
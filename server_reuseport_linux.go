@@ -0,0 +1,7 @@
+//go:build linux && (386 || amd64 || arm)
+
+package zerohttp
+
+// soReusePort is SO_REUSEPORT, which the syscall package doesn't define
+// for these architectures.
+const soReusePort = 0xf
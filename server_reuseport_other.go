@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package zerohttp
+
+import "syscall"
+
+// reusePortControl fails on platforms without SO_REUSEPORT.
+func reusePortControl(_, _ string, _ syscall.RawConn) error {
+	return errReusePortUnsupported
+}
@@ -0,0 +1,153 @@
+package zerohttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestServer_ReusePort(t *testing.T) {
+	first := New(Config{ReusePort: true})
+	ln1, err := first.listenTCP("127.0.0.1:0")
+	if errors.Is(err, errReusePortUnsupported) {
+		t.Skip("SO_REUSEPORT not supported on this platform")
+	}
+	zhtest.AssertNoError(t, err)
+	defer func() { _ = ln1.Close() }()
+
+	second := New(Config{ReusePort: true})
+	ln2, err := second.listenTCP(ln1.Addr().String())
+	zhtest.AssertNoError(t, err)
+	_ = ln2.Close()
+
+	without := New()
+	_, err = without.listenTCP(ln1.Addr().String())
+	zhtest.AssertError(t, err)
+}
+
+func TestServer_ReusePort_ListenAndServe(t *testing.T) {
+	server := New(Config{Addr: "127.0.0.1:0", ReusePort: true})
+	server.GET("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("reused"))
+	}))
+
+	done := make(chan error, 1)
+	go func() { done <- server.ListenAndServe() }()
+
+	var addr string
+	for range 100 {
+		if addr = server.ListenerAddr(); addr != "127.0.0.1:0" {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	resp, err := http.Get("http://" + addr + "/")
+	zhtest.AssertNoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	zhtest.AssertEqual(t, "reused", string(body))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	zhtest.AssertNoError(t, server.Shutdown(ctx))
+	zhtest.AssertErrorIs(t, <-done, http.ErrServerClosed)
+}
+
+func TestServer_Drain(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	server := New(Config{Addr: "127.0.0.1:0"})
+	server.GET("/slow", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = w.Write([]byte("finished"))
+	}))
+
+	done := make(chan error, 1)
+	go func() { done <- server.ListenAndServe() }()
+
+	var addr string
+	for range 100 {
+		if addr = server.ListenerAddr(); addr != "127.0.0.1:0" {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	type result struct {
+		body string
+		err  error
+	}
+	inFlight := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/slow")
+		if err != nil {
+			inFlight <- result{err: err}
+			return
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		inFlight <- result{body: string(body)}
+	}()
+	<-started
+
+	drained := make(chan error, 1)
+	go func() { drained <- server.Drain(context.Background()) }()
+
+	// Serve returns as soon as the listener is closed
+	zhtest.AssertErrorIs(t, <-done, http.ErrServerClosed)
+	_, err := http.Get("http://" + addr + "/slow")
+	zhtest.AssertError(t, err)
+
+	select {
+	case <-drained:
+		zhtest.AssertFail(t, "Drain returned before the active request finished")
+	default:
+	}
+
+	close(release)
+	res := <-inFlight
+	zhtest.AssertNoError(t, res.err)
+	zhtest.AssertEqual(t, "finished", res.body)
+	zhtest.AssertNoError(t, <-drained)
+}
+
+func TestServer_Drain_ContextDone(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	server := New(Config{Addr: "127.0.0.1:0"})
+	server.GET("/slow", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+
+	go func() { _ = server.ListenAndServe() }()
+
+	var addr string
+	for range 100 {
+		if addr = server.ListenerAddr(); addr != "127.0.0.1:0" {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	go func() {
+		if resp, err := http.Get("http://" + addr + "/slow"); err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	zhtest.AssertErrorIs(t, server.Drain(ctx), context.DeadlineExceeded)
+}
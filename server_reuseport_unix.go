@@ -0,0 +1,16 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package zerohttp
+
+import "syscall"
+
+// reusePortControl sets SO_REUSEPORT on a socket before it is bound.
+func reusePortControl(_, _ string, c syscall.RawConn) error {
+	var sockErr error
+	if err := c.Control(func(fd uintptr) {
+		sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, soReusePort, 1)
+	}); err != nil {
+		return err
+	}
+	return sockErr
+}
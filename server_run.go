@@ -0,0 +1,70 @@
+// Package zerohttp provides running the server until a shutdown signal. See [Server.Run].
+package zerohttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/alexferl/zerohttp/log"
+)
+
+// Run starts the server and blocks until it stops, shutting it down
+// gracefully when SIGINT or SIGTERM is received or ctx is cancelled.
+// In-flight requests are given Lifecycle.ShutdownTimeout to complete.
+//
+// Errors from starting the server, such as an address already in use, are
+// returned immediately. Run returns nil after a successful graceful shutdown,
+// or the shutdown error if it failed or timed out.
+//
+// Once a signal is received, the default signal handling is restored, so a
+// second signal terminates the process without waiting for shutdown.
+//
+// Example:
+//
+//	app := zh.New()
+//	if err := app.Run(context.Background()); err != nil {
+//	    log.Fatal(err)
+//	}
+func (s *Server) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.Start()
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+		stop()
+	}
+
+	s.logger.Info("Shutdown requested", log.F("timeout", s.shutdownTimeout.String()))
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	if err := s.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	// Wait for Start to return so servers are fully stopped
+	select {
+	case err := <-errCh:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	case <-shutdownCtx.Done():
+		return shutdownCtx.Err()
+	}
+}
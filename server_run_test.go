@@ -0,0 +1,106 @@
+package zerohttp
+
+import (
+	"context"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+// runServer starts server.Run in the background and waits until the
+// server has started, which is after Run has begun listening for signals.
+func runServer(t *testing.T, ctx context.Context, cfg Config) <-chan error {
+	t.Helper()
+
+	started := make(chan struct{})
+	cfg.Lifecycle.PostStartupHooks = append(cfg.Lifecycle.PostStartupHooks, StartupHookConfig{
+		Name: "started",
+		Hook: func(ctx context.Context) error {
+			close(started)
+			return nil
+		},
+	})
+	server := New(cfg)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Run(ctx) }()
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		zhtest.AssertFail(t, "timeout waiting for server to start")
+	}
+	return errCh
+}
+
+func waitRun(t *testing.T, errCh <-chan error) error {
+	t.Helper()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-time.After(2 * time.Second):
+		zhtest.AssertFail(t, "timeout waiting for Run to return")
+		return nil
+	}
+}
+
+func TestServer_Run_ContextCancel(t *testing.T) {
+	shutdown := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := runServer(t, ctx, Config{
+		Addr: "127.0.0.1:0",
+		Lifecycle: LifecycleConfig{
+			PostShutdownHooks: []ShutdownHookConfig{{
+				Name: "done",
+				Hook: func(ctx context.Context) error {
+					close(shutdown)
+					return nil
+				},
+			}},
+		},
+	})
+	cancel()
+
+	zhtest.AssertNoError(t, waitRun(t, errCh))
+	select {
+	case <-shutdown:
+	default:
+		zhtest.AssertFail(t, "expected graceful shutdown")
+	}
+}
+
+func TestServer_Run_Signal(t *testing.T) {
+	errCh := runServer(t, context.Background(), Config{Addr: "127.0.0.1:0"})
+
+	p, err := os.FindProcess(os.Getpid())
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertNoError(t, p.Signal(syscall.SIGTERM))
+
+	zhtest.AssertNoError(t, waitRun(t, errCh))
+}
+
+func TestServer_Run_StartupError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	zhtest.AssertNoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	// The address is already in use
+	server := New(Config{Addr: listener.Addr().String()})
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Run(context.Background()) }()
+
+	zhtest.AssertError(t, waitRun(t, errCh))
+}
+
+func TestServer_Run_ShutdownTimeout(t *testing.T) {
+	zhtest.AssertEqual(t, 30*time.Second, New().shutdownTimeout)
+
+	server := New(Config{Lifecycle: LifecycleConfig{ShutdownTimeout: 5 * time.Second}})
+	zhtest.AssertEqual(t, 5*time.Second, server.shutdownTimeout)
+}
@@ -0,0 +1,68 @@
+// Package zerohttp provides the page served during startup. See [Server.MarkStarted].
+package zerohttp
+
+import (
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/internal/mwutil"
+)
+
+// startupGate serves the startup page until the server is marked as started.
+type startupGate struct {
+	started    atomic.Bool
+	config     StartupConfig
+	retryAfter string
+}
+
+func newStartupGate(c StartupConfig) *startupGate {
+	g := &startupGate{config: c}
+	if c.RetryAfter >= 0 {
+		g.retryAfter = strconv.Itoa(int(math.Ceil(c.RetryAfter.Seconds())))
+	}
+	return g
+}
+
+// middleware answers requests with the startup page or a problem detail
+// until the server is started. Excluded paths are always served.
+func (g *startupGate) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if g.started.Load() || !mwutil.ShouldProcessMiddleware(r.URL.Path, nil, g.config.ExcludedPaths) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if g.retryAfter != "" {
+			w.Header().Set(httpx.HeaderRetryAfter, g.retryAfter)
+		}
+		w.Header().Set(httpx.HeaderCacheControl, httpx.CacheControlNoStore)
+
+		isAPI := slices.ContainsFunc(g.config.APIPaths, func(p string) bool {
+			return mwutil.PathMatches(r.URL.Path, p)
+		})
+		if isAPI {
+			_ = NewProblemDetail(http.StatusServiceUnavailable, "Service is starting up").RenderAuto(w, r)
+			return
+		}
+		_ = R.Blob(w, http.StatusServiceUnavailable, httpx.MIMETextHTMLCharset, g.config.Page)
+	})
+}
+
+// MarkStarted signals that the application has finished starting up, so
+// requests are no longer answered with the startup page configured in
+// [StartupConfig]. Call it once dependencies are ready, e.g. from a
+// post-startup hook. It is safe to call concurrently and more than once.
+func (s *Server) MarkStarted() {
+	if !s.startupGate.started.Swap(true) {
+		s.logger.Info("Application started, serving all routes")
+	}
+}
+
+// Started reports whether [Server.MarkStarted] has been called.
+func (s *Server) Started() bool {
+	return s.startupGate.started.Load()
+}
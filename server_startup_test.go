@@ -0,0 +1,90 @@
+package zerohttp
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestServer_StartupPage(t *testing.T) {
+	page := []byte("<!DOCTYPE html><title>Starting up</title>")
+	app := New(Config{Startup: StartupConfig{Page: page}})
+	for _, path := range []string{"/", "/api/users", "/livez"} {
+		app.GET(path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}))
+	}
+
+	zhtest.AssertFalse(t, app.Started())
+
+	w := zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/").Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusServiceUnavailable).
+		Header(httpx.HeaderContentType, httpx.MIMETextHTMLCharset).
+		Header(httpx.HeaderRetryAfter, "5").
+		Header(httpx.HeaderCacheControl, httpx.CacheControlNoStore).
+		Body(string(page))
+
+	w = zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/api/users").Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusServiceUnavailable).
+		Header(httpx.HeaderContentType, httpx.MIMEApplicationProblemJSON).
+		Header(httpx.HeaderRetryAfter, "5").
+		BodyContains("Service is starting up")
+
+	// Health checks are served during startup
+	w = zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/livez").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusOK).Body("ok")
+
+	app.MarkStarted()
+	app.MarkStarted()
+	zhtest.AssertTrue(t, app.Started())
+
+	for _, path := range []string{"/", "/api/users"} {
+		w = zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, path).Build())
+		zhtest.AssertWith(t, w).
+			Status(http.StatusOK).
+			HeaderNotExists(httpx.HeaderRetryAfter).
+			Body("ok")
+	}
+}
+
+func TestServer_StartupPage_Config(t *testing.T) {
+	app := New(Config{Startup: StartupConfig{
+		Page:       []byte("starting"),
+		APIPaths:   []string{"/v1/"},
+		RetryAfter: -1,
+	}})
+	app.GET("/v1/users", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	w := zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/v1/users").Build())
+	zhtest.AssertWith(t, w).
+		Status(http.StatusServiceUnavailable).
+		Header(httpx.HeaderContentType, httpx.MIMEApplicationProblemJSON).
+		HeaderNotExists(httpx.HeaderRetryAfter)
+
+	// Unknown routes get the page too rather than a 404
+	w = zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/missing").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusServiceUnavailable).Body("starting")
+}
+
+func TestServer_StartupPage_Disabled(t *testing.T) {
+	app := New()
+	app.GET("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	w := zhtest.Serve(app, zhtest.NewRequest(http.MethodGet, "/").Build())
+	zhtest.AssertWith(t, w).Status(http.StatusOK).Body("ok")
+	zhtest.AssertFalse(t, app.Started())
+}
+
+func TestStartupConfig_Defaults(t *testing.T) {
+	zhtest.AssertNil(t, DefaultConfig.Startup.Page)
+	zhtest.AssertEqual(t, []string{"/api/"}, DefaultConfig.Startup.APIPaths)
+	zhtest.AssertEqual(t, []string{"/livez", "/readyz", "/startupz"}, DefaultConfig.Startup.ExcludedPaths)
+	zhtest.AssertEqual(t, 5*time.Second, DefaultConfig.Startup.RetryAfter)
+}
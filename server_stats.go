@@ -0,0 +1,168 @@
+// Package zerohttp provides lightweight server statistics. See [Server.Stats].
+package zerohttp
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+)
+
+// ServerStats is a snapshot of the server's connection and request counters.
+// Counters start at zero when the server is created and cover both the HTTP
+// and HTTPS servers.
+type ServerStats struct {
+	// ActiveConnections is the number of open client connections.
+	ActiveConnections int64 `json:"active_connections"`
+
+	// TotalConnections is the number of connections accepted.
+	TotalConnections int64 `json:"total_connections"`
+
+	// ActiveRequests is the number of requests being served.
+	ActiveRequests int64 `json:"active_requests"`
+
+	// TotalRequests is the number of requests received.
+	TotalRequests int64 `json:"total_requests"`
+
+	// BytesIn is the number of request body bytes read by handlers.
+	BytesIn int64 `json:"bytes_in"`
+
+	// BytesOut is the number of response body bytes written to the client,
+	// after compression by middleware such as compress.
+	BytesOut int64 `json:"bytes_out"`
+}
+
+// serverStats holds the counters reported by [Server.Stats].
+type serverStats struct {
+	activeConns   atomic.Int64
+	totalConns    atomic.Int64
+	totalRequests atomic.Int64
+	bytesIn       atomic.Int64
+	bytesOut      atomic.Int64
+}
+
+// connState counts connections as the HTTP server reports their state.
+// Hijacked connections, such as WebSockets, are no longer tracked by the
+// server and are counted as closed.
+func (st *serverStats) connState(_ net.Conn, state http.ConnState) {
+	switch state {
+	case http.StateNew:
+		st.activeConns.Add(1)
+		st.totalConns.Add(1)
+	case http.StateClosed, http.StateHijacked:
+		st.activeConns.Add(-1)
+	}
+}
+
+// trackConnState sets the ConnState hook of srv to count its connections,
+// calling any hook already set.
+func (st *serverStats) trackConnState(srv *http.Server) {
+	prev := srv.ConnState
+	srv.ConnState = func(c net.Conn, state http.ConnState) {
+		st.connState(c, state)
+		if prev != nil {
+			prev(c, state)
+		}
+	}
+}
+
+// middleware counts the request and the body bytes read and written.
+func (st *serverStats) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		st.totalRequests.Add(1)
+
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = &statsBody{ReadCloser: r.Body, n: &st.bytesIn}
+		}
+
+		next.ServeHTTP(&statsResponseWriter{ResponseWriter: w, n: &st.bytesOut}, r)
+	})
+}
+
+// statsBody counts the bytes read from a request body.
+type statsBody struct {
+	io.ReadCloser
+	n *atomic.Int64
+}
+
+func (b *statsBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n.Add(int64(n))
+	return n, err
+}
+
+// statsResponseWriter counts the bytes written to a response body.
+type statsResponseWriter struct {
+	http.ResponseWriter
+	n *atomic.Int64
+}
+
+func (w *statsResponseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.n.Add(int64(n))
+	return n, err
+}
+
+// ReadFrom implements io.ReaderFrom so that responses such as static files
+// keep the sendfile path of the underlying ResponseWriter.
+func (w *statsResponseWriter) ReadFrom(r io.Reader) (int64, error) {
+	var n int64
+	var err error
+	if rf, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(r)
+	} else {
+		n, err = io.Copy(w.ResponseWriter, r)
+	}
+	w.n.Add(n)
+	return n, err
+}
+
+// Flush implements http.Flusher to support streaming responses like SSE.
+func (w *statsResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker to support WebSocket upgrades.
+func (w *statsResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (w *statsResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Stats returns a snapshot of the server's connection, request and traffic
+// counters. They are cheap to collect and read, so they can be served
+// without a metrics stack, unlike the Prometheus metrics of [Config.Metrics].
+// Requests and their bytes are counted by a default middleware, so only
+// connections are reported with [Config.DisableDefaultMiddlewares].
+//
+// This method is thread-safe and can be called concurrently.
+//
+// Example:
+//
+//	app.GET("/debug/stats", zh.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+//	    return zh.R.JSON(w, http.StatusOK, app.Stats())
+//	}))
+func (s *Server) Stats() ServerStats {
+	_, active := s.activeRequests.snapshot()
+	return ServerStats{
+		ActiveConnections: s.stats.activeConns.Load(),
+		TotalConnections:  s.stats.totalConns.Load(),
+		ActiveRequests:    int64(active),
+		TotalRequests:     s.stats.totalRequests.Load(),
+		BytesIn:           s.stats.bytesIn.Load(),
+		BytesOut:          s.stats.bytesOut.Load(),
+	}
+}
+
+// Ensure interface compliance at compile time.
+var (
+	_ http.Flusher  = (*statsResponseWriter)(nil)
+	_ http.Hijacker = (*statsResponseWriter)(nil)
+	_ io.ReaderFrom = (*statsResponseWriter)(nil)
+)
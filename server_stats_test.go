@@ -0,0 +1,147 @@
+package zerohttp
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func TestServer_Stats_Requests(t *testing.T) {
+	server := New()
+	server.POST("/echo", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+		_, _ = w.Write([]byte("!"))
+	}))
+
+	zhtest.AssertEqual(t, ServerStats{}, server.Stats())
+
+	for range 2 {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("hello")))
+		zhtest.AssertEqual(t, "hello!", w.Body.String())
+	}
+	server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	stats := server.Stats()
+	zhtest.AssertEqual(t, int64(3), stats.TotalRequests)
+	zhtest.AssertEqual(t, int64(0), stats.ActiveRequests)
+	zhtest.AssertEqual(t, int64(10), stats.BytesIn)
+	zhtest.AssertTrue(t, stats.BytesOut > 12) // Both echoes and the 404 body
+
+	t.Run("without default middlewares", func(t *testing.T) {
+		server := New(Config{DisableDefaultMiddlewares: true})
+		server.GET("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}))
+		server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		zhtest.AssertEqual(t, ServerStats{}, server.Stats())
+	})
+}
+
+func TestServer_Stats_Connections(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	zhtest.AssertNoError(t, err)
+
+	var userHookCalls atomic.Int32
+	userServer := &http.Server{ConnState: func(net.Conn, http.ConnState) { userHookCalls.Add(1) }}
+	server := New(Config{Listener: listener, Server: userServer})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server.GET("/slow", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+	server.GET("/stats", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = R.JSON(w, http.StatusOK, server.Stats())
+	}))
+
+	go func() { _ = server.ListenAndServe() }()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+	}()
+
+	url := "http://" + listener.Addr().String()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if resp, err := http.Get(url + "/slow"); err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	<-started
+
+	// A second client forces a separate connection
+	client := &http.Client{Transport: &http.Transport{}}
+	resp, err := client.Get(url + "/stats")
+	zhtest.AssertNoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	zhtest.AssertTrue(t, strings.Contains(string(body), `"active_connections":2`))
+	zhtest.AssertTrue(t, strings.Contains(string(body), `"total_connections":2`))
+	zhtest.AssertTrue(t, strings.Contains(string(body), `"active_requests":2`))
+
+	close(release)
+	<-done
+	client.CloseIdleConnections()
+	http.DefaultClient.CloseIdleConnections()
+
+	for range 100 {
+		if server.Stats().ActiveConnections == 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	stats := server.Stats()
+	zhtest.AssertEqual(t, int64(0), stats.ActiveConnections)
+	zhtest.AssertEqual(t, int64(2), stats.TotalConnections)
+	zhtest.AssertTrue(t, userHookCalls.Load() > 0)
+}
+
+// readerFromRecorder records whether ReadFrom was used to write the body.
+type readerFromRecorder struct {
+	*httptest.ResponseRecorder
+	usedReadFrom bool
+}
+
+func (w *readerFromRecorder) ReadFrom(r io.Reader) (int64, error) {
+	w.usedReadFrom = true
+	return io.Copy(w.ResponseRecorder, r)
+}
+
+func TestStatsResponseWriter_ReadFrom(t *testing.T) {
+	var n atomic.Int64
+	rec := &readerFromRecorder{ResponseRecorder: httptest.NewRecorder()}
+	w := &statsResponseWriter{ResponseWriter: rec, n: &n}
+
+	written, err := w.ReadFrom(strings.NewReader("hello"))
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertEqual(t, int64(5), written)
+	zhtest.AssertTrue(t, rec.usedReadFrom)
+	zhtest.AssertEqual(t, int64(5), n.Load())
+	zhtest.AssertEqual(t, "hello", rec.Body.String())
+
+	t.Run("without ReaderFrom", func(t *testing.T) {
+		var n atomic.Int64
+		rec := httptest.NewRecorder()
+		w := &statsResponseWriter{ResponseWriter: rec, n: &n}
+
+		_, err := w.ReadFrom(strings.NewReader("hello"))
+		zhtest.AssertNoError(t, err)
+		zhtest.AssertEqual(t, int64(5), n.Load())
+		zhtest.AssertEqual(t, "hello", rec.Body.String())
+	})
+}
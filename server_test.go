@@ -4,15 +4,19 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"io"
 	"net"
 	"net/http"
 	"os"
 	"reflect"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/alexferl/zerohttp/log"
+	"github.com/alexferl/zerohttp/middleware/requestid"
 	"github.com/alexferl/zerohttp/zhtest"
 )
 
@@ -86,6 +90,7 @@ func writeTestCertFiles(t *testing.T) (certFile, keyFile string) {
 
 // Mock logger for testing
 type mockServerLogger struct {
+	mu   sync.Mutex
 	logs []logEntry
 }
 
@@ -95,20 +100,34 @@ type logEntry struct {
 	fields  []log.Field
 }
 
+func (m *mockServerLogger) log(level, msg string, fields []log.Field) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logs = append(m.logs, logEntry{level: level, message: msg, fields: fields})
+}
+
+// entries returns a copy of the logged entries, for tests logging from
+// other goroutines.
+func (m *mockServerLogger) entries() []logEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.logs)
+}
+
 func (m *mockServerLogger) Debug(msg string, fields ...log.Field) {
-	m.logs = append(m.logs, logEntry{level: "debug", message: msg, fields: fields})
+	m.log("debug", msg, fields)
 }
 
 func (m *mockServerLogger) Info(msg string, fields ...log.Field) {
-	m.logs = append(m.logs, logEntry{level: "info", message: msg, fields: fields})
+	m.log("info", msg, fields)
 }
 
 func (m *mockServerLogger) Warn(msg string, fields ...log.Field) {
-	m.logs = append(m.logs, logEntry{level: "warn", message: msg, fields: fields})
+	m.log("warn", msg, fields)
 }
 
 func (m *mockServerLogger) Error(msg string, fields ...log.Field) {
-	m.logs = append(m.logs, logEntry{level: "error", message: msg, fields: fields})
+	m.log("error", msg, fields)
 }
 
 func (m *mockServerLogger) Panic(msg string, fields ...log.Field) {}
@@ -148,6 +167,40 @@ func TestNew_MiddlewareScenarios(t *testing.T) {
 	zhtest.AssertNotNil(t, server2)
 }
 
+func TestNew_PrependMiddlewares(t *testing.T) {
+	var calls []string
+	record := func(name string) MiddlewareFunc {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if requestid.Get(r.Context()) == "" {
+					name += " (no request id)"
+				}
+				calls = append(calls, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	server := New(Config{
+		PrependMiddlewares: []MiddlewareFunc{record("tracing"), record("second")},
+		DefaultMiddlewares: []MiddlewareFunc{record("appended")},
+	})
+	server.GET("/", testHandler("ok"))
+
+	zhtest.Serve(server, zhtest.NewRequest(http.MethodGet, "/").Build())
+	zhtest.AssertEqual(t, []string{"tracing (no request id)", "second (no request id)", "appended"}, calls)
+
+	calls = nil
+	bare := New(Config{
+		DisableDefaultMiddlewares: true,
+		PrependMiddlewares:        []MiddlewareFunc{record("tracing")},
+	})
+	bare.GET("/", testHandler("ok"))
+
+	zhtest.Serve(bare, zhtest.NewRequest(http.MethodGet, "/").Build())
+	zhtest.AssertEqual(t, []string{"tracing (no request id)"}, calls)
+}
+
 func TestServer_ListenerAddr(t *testing.T) {
 	server := New()
 
@@ -587,6 +640,37 @@ func TestServer_DefaultTimeoutsApplied(t *testing.T) {
 	zhtest.AssertEqual(t, DefaultIdleTimeout, server.server.IdleTimeout)
 }
 
+func TestServer_ConfiguredTimeouts(t *testing.T) {
+	server := New(Config{
+		ReadTimeout:       30 * time.Second,
+		ReadHeaderTimeout: 2 * time.Second,
+		WriteTimeout:      -1,
+		IdleTimeout:       2 * time.Minute,
+		MaxHeaderBytes:    64 << 10,
+		TLS:               TLSConfig{CertFile: "cert.pem", KeyFile: "key.pem"},
+	})
+
+	for _, srv := range []*http.Server{server.server, server.tlsServer} {
+		zhtest.AssertEqual(t, 30*time.Second, srv.ReadTimeout)
+		zhtest.AssertEqual(t, 2*time.Second, srv.ReadHeaderTimeout)
+		zhtest.AssertEqual(t, time.Duration(0), srv.WriteTimeout)
+		zhtest.AssertEqual(t, 2*time.Minute, srv.IdleTimeout)
+		zhtest.AssertEqual(t, 64<<10, srv.MaxHeaderBytes)
+	}
+
+	// Unset fields keep the defaults
+	server = New(Config{ReadTimeout: 30 * time.Second})
+	zhtest.AssertEqual(t, 30*time.Second, server.server.ReadTimeout)
+	zhtest.AssertEqual(t, DefaultWriteTimeout, server.server.WriteTimeout)
+	zhtest.AssertEqual(t, DefaultMaxHeaderBytes, server.server.MaxHeaderBytes)
+
+	// A custom server is used as is
+	custom := &http.Server{ReadTimeout: time.Second}
+	server = New(Config{Server: custom, ReadTimeout: 30 * time.Second})
+	zhtest.AssertEqual(t, time.Second, server.server.ReadTimeout)
+	zhtest.AssertEqual(t, time.Duration(0), server.server.WriteTimeout)
+}
+
 func TestServer_RedirectHTTPConfig(t *testing.T) {
 	// Test that RedirectHTTP is stored correctly
 	server := New(Config{
@@ -652,3 +736,61 @@ func TestDefaultTLSServer(t *testing.T) {
 	zhtest.AssertEqual(t, DefaultReadTimeout, srv2.ReadTimeout)
 	zhtest.AssertEqual(t, uint16(tls.VersionTLS12), srv2.TLSConfig.MinVersion)
 }
+
+func TestServer_H2C(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	zhtest.AssertNoError(t, err)
+
+	server := New(Config{Listener: listener, H2C: true})
+	server.GET("/proto", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	}))
+
+	go func() { _ = server.ListenAndServe() }()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+	}()
+
+	url := "http://" + listener.Addr().String() + "/proto"
+
+	h2cProtocols := new(http.Protocols)
+	h2cProtocols.SetUnencryptedHTTP2(true)
+	h2cClient := &http.Client{Transport: &http.Transport{Protocols: h2cProtocols}}
+
+	for name, tt := range map[string]struct {
+		client *http.Client
+		proto  string
+	}{
+		"HTTP/2 with prior knowledge": {h2cClient, "HTTP/2.0"},
+		"HTTP/1.1 on the same port":   {&http.Client{Transport: &http.Transport{}}, "HTTP/1.1"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp, err := tt.client.Get(url)
+			zhtest.AssertNoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			body, err := io.ReadAll(resp.Body)
+			zhtest.AssertNoError(t, err)
+			zhtest.AssertEqual(t, tt.proto, resp.Proto)
+			zhtest.AssertEqual(t, tt.proto, string(body))
+		})
+	}
+}
+
+func TestServer_H2C_KeepsCustomProtocols(t *testing.T) {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	srv := &http.Server{Protocols: protocols}
+
+	server := New(Config{Server: srv, H2C: true})
+
+	zhtest.AssertTrue(t, server.server.Protocols.HTTP1())
+	zhtest.AssertFalse(t, server.server.Protocols.HTTP2())
+	zhtest.AssertTrue(t, server.server.Protocols.UnencryptedHTTP2())
+
+	// Without H2C the server protocols are left alone
+	server = New()
+	zhtest.AssertNil(t, server.server.Protocols)
+}
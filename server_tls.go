@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"slices"
 	"sync"
 	"time"
 
+	"github.com/alexferl/zerohttp/extensions/autocert"
 	"github.com/alexferl/zerohttp/extensions/http3"
 	"github.com/alexferl/zerohttp/extensions/webtransport"
 	"github.com/alexferl/zerohttp/log"
@@ -54,7 +56,7 @@ func (s *Server) ListenAndServeTLS(certFile, keyFile string) error {
 	var err error
 	if s.tlsListener == nil {
 		s.logger.Debug("Creating TLS listener", log.F("addr", s.tlsServer.Addr))
-		s.tlsListener, err = tls.Listen("tcp", s.tlsServer.Addr, s.tlsServer.TLSConfig)
+		s.tlsListener, err = s.listenTLS()
 		if err != nil {
 			s.logger.Error("Failed to create TLS listener", log.E(err))
 			s.mu.Unlock()
@@ -98,6 +100,37 @@ func (s *Server) ListenAndServeTLS(certFile, keyFile string) error {
 	return s.tlsServer.Serve(s.tlsListener)
 }
 
+// listenTLS creates the HTTPS listener. With PROXY protocol enabled, the
+// header is read from the connection before the TLS handshake.
+func (s *Server) listenTLS() (net.Listener, error) {
+	if !s.proxyProtocol && !s.reusePort {
+		return tls.Listen("tcp", s.tlsServer.Addr, s.tlsServer.TLSConfig)
+	}
+
+	ln, err := s.listenTCP(s.tlsServer.Addr)
+	if err != nil {
+		return nil, err
+	}
+	if s.proxyProtocol {
+		ln = newProxyListener(ln, s.tlsServer.ReadHeaderTimeout, s.logger)
+	}
+	return tls.NewListener(ln, s.tlsServer.TLSConfig), nil
+}
+
+// serveTLS starts the HTTPS server for Start, whose certificates are
+// already loaded in TLSConfig.
+func (s *Server) serveTLS() error {
+	if !s.proxyProtocol && !s.reusePort {
+		return s.tlsServer.ListenAndServeTLS("", "")
+	}
+
+	ln, err := s.listenTLS()
+	if err != nil {
+		return err
+	}
+	return s.tlsServer.Serve(ln)
+}
+
 // StartTLS is a convenience method that starts only the HTTPS server with
 // the specified certificate files. If the TLS server is not configured,
 // this method returns nil without error.
@@ -116,6 +149,26 @@ func (s *Server) StartTLS(certFile, keyFile string) error {
 	return s.ListenAndServeTLS(certFile, keyFile)
 }
 
+// configureAutoTLS makes the HTTPS server get its certificates from the
+// autocert manager, calling onCert after each successful retrieval. It also
+// advertises the TLS-ALPN-01 challenge protocol, so the manager can answer
+// challenges on the HTTPS port when the HTTP port isn't reachable.
+func (s *Server) configureAutoTLS(onCert func()) {
+	if s.tlsServer.TLSConfig == nil {
+		s.tlsServer.TLSConfig = &tls.Config{}
+	}
+	s.tlsServer.TLSConfig.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		cert, err := s.autocertManager.GetCertificate(hello)
+		if err == nil {
+			onCert()
+		}
+		return cert, err
+	}
+	if !slices.Contains(s.tlsServer.TLSConfig.NextProtos, autocert.ALPNProto) {
+		s.tlsServer.TLSConfig.NextProtos = append(s.tlsServer.TLSConfig.NextProtos, autocert.ALPNProto)
+	}
+}
+
 // StartAutoTLS starts the server with automatic TLS certificate management using Let's Encrypt.
 // It starts both HTTP (for ACME challenges) and HTTPS servers.
 // The HTTP server redirects to HTTPS and handles ACME challenges.
@@ -128,13 +181,18 @@ func (s *Server) StartTLS(certFile, keyFile string) error {
 //	    Prompt:     autocert.AcceptTOS,
 //	    HostPolicy: autocert.HostWhitelist("example.com"),
 //	}
-//	srv := zerohttp.New(WithAutocertManager(mgr))
+//	srv := zerohttp.New(zerohttp.Config{
+//	    Extensions: zerohttp.ExtensionsConfig{AutocertManager: mgr},
+//	})
 //	srv.StartAutoTLS()
 //
 // The HTTP server handles:
-//   - ACME challenge requests from Let's Encrypt
+//   - ACME HTTP-01 challenge requests from Let's Encrypt
 //   - Redirects all other HTTP traffic to HTTPS
 //
+// The HTTPS server also answers TLS-ALPN-01 challenges, which only need the
+// HTTPS port to be reachable.
+//
 // Returns an error if the autocert manager is not configured or if any server fails to start.
 func (s *Server) StartAutoTLS() error {
 	if s.autocertManager == nil {
@@ -159,7 +217,7 @@ func (s *Server) StartAutoTLS() error {
 				Handler: s.autocertManager.HTTPHandler(s.createHTTPSRedirectHandler()),
 			}
 
-			ln, err := net.Listen("tcp", httpServer.Addr)
+			ln, err := s.listenTCP(httpServer.Addr)
 			if err != nil {
 				s.logger.Error("Failed to bind HTTP listener", log.E(err))
 				errCh <- err
@@ -185,18 +243,7 @@ func (s *Server) StartAutoTLS() error {
 	// Start HTTPS server with autocert
 	if s.tlsServer != nil {
 		go func() {
-			// Configure TLS with autocert
-			if s.tlsServer.TLSConfig == nil {
-				s.tlsServer.TLSConfig = &tls.Config{}
-			}
-			s.tlsServer.TLSConfig.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
-				cert, err := s.autocertManager.GetCertificate(hello)
-				if err == nil {
-					// Signal that cert is ready (first successful retrieval)
-					signalCertReady()
-				}
-				return cert, err
-			}
+			s.configureAutoTLS(signalCertReady)
 
 			s.logger.Info("Starting HTTPS server with AutoTLS",
 				log.F("addr", fmtHTTPSAddr(s.tlsServer.Addr)))
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/alexferl/zerohttp/extensions/autocert"
 	"github.com/alexferl/zerohttp/httpx"
 	"github.com/alexferl/zerohttp/zhtest"
 )
@@ -494,3 +495,91 @@ func TestServer_WithCertFile_TLSServerCreated(t *testing.T) {
 	// tlsServer should be created when cert files are provided
 	zhtest.AssertNotNil(t, server.tlsServer)
 }
+
+func TestServer_TLSCertificates(t *testing.T) {
+	cert, err := tls.X509KeyPair([]byte(testCertPEM), []byte(testKeyPEM))
+	zhtest.AssertNoError(t, err)
+
+	t.Run("certificates", func(t *testing.T) {
+		server := New(Config{TLS: TLSConfig{Certificates: []tls.Certificate{cert}}})
+
+		zhtest.AssertNotNil(t, server.tlsServer)
+		zhtest.AssertEqual(t, 1, len(server.tlsServer.TLSConfig.Certificates))
+		zhtest.AssertEqual(t, uint16(tls.VersionTLS12), server.tlsServer.TLSConfig.MinVersion)
+	})
+
+	t.Run("get certificate", func(t *testing.T) {
+		var requested string
+		server := New(Config{TLS: TLSConfig{
+			GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+				requested = hello.ServerName
+				return &cert, nil
+			},
+		}})
+
+		zhtest.AssertNotNil(t, server.tlsServer)
+		got, err := server.tlsServer.TLSConfig.GetCertificate(&tls.ClientHelloInfo{ServerName: "tenant.example.com"})
+		zhtest.AssertNoError(t, err)
+		zhtest.AssertEqual(t, &cert, got)
+		zhtest.AssertEqual(t, "tenant.example.com", requested)
+	})
+
+	t.Run("custom TLS server", func(t *testing.T) {
+		server := New(Config{TLS: TLSConfig{
+			Server:       &http.Server{},
+			Certificates: []tls.Certificate{cert},
+		}})
+
+		zhtest.AssertEqual(t, 1, len(server.tlsServer.TLSConfig.Certificates))
+	})
+
+	t.Run("take precedence over cert files", func(t *testing.T) {
+		server := New(Config{
+			Logger: &mockServerLogger{},
+			TLS: TLSConfig{
+				CertFile:     "missing.crt",
+				KeyFile:      "missing.key",
+				Certificates: []tls.Certificate{cert},
+			},
+		})
+		server.server = nil
+		server.tlsServer.Addr = "127.0.0.1:0"
+
+		errCh := make(chan error, 1)
+		go func() { errCh <- server.Start() }()
+		time.Sleep(50 * time.Millisecond)
+		closeErr := server.Close()
+
+		select {
+		case err := <-errCh:
+			zhtest.AssertNoError(t, err)
+			zhtest.AssertNoError(t, closeErr)
+		case <-time.After(time.Second):
+			zhtest.AssertFail(t, "timeout waiting for Start to return")
+		}
+	})
+
+	t.Run("conflicts with autocert", func(t *testing.T) {
+		cfg := DefaultConfig
+		cfg.TLS.Certificates = []tls.Certificate{cert}
+		cfg.Extensions.AutocertManager = &mockAutocertManager{}
+		zhtest.AssertError(t, cfg.Validate())
+	})
+}
+
+func TestServer_ConfigureAutoTLS(t *testing.T) {
+	mgr := &mockAutocertManager{}
+	server := New(Config{Extensions: ExtensionsConfig{AutocertManager: mgr}})
+
+	var ready int
+	server.configureAutoTLS(func() { ready++ })
+	server.configureAutoTLS(func() { ready++ })
+
+	cfg := server.tlsServer.TLSConfig
+	zhtest.AssertEqual(t, []string{"h2", "http/1.1", autocert.ALPNProto}, cfg.NextProtos)
+
+	_, err := cfg.GetCertificate(&tls.ClientHelloInfo{ServerName: "example.com"})
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertTrue(t, mgr.getCertificateCalled)
+	zhtest.AssertEqual(t, 1, ready)
+}
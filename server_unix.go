@@ -0,0 +1,55 @@
+// Package zerohttp provides Unix domain socket listeners. See [Config.UnixSocket].
+package zerohttp
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"time"
+)
+
+// listenUnix listens on the Unix domain socket at path with the given file
+// mode. A stale socket left by a previous run is removed first, but a socket
+// that still accepts connections or a file that isn't a socket is not.
+// The socket file is removed when the listener is closed.
+func listenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	ln.(*net.UnixListener).SetUnlinkOnClose(true)
+
+	if err := os.Chmod(path, mode); err != nil {
+		_ = ln.Close()
+		return nil, fmt.Errorf("zerohttp: setting unix socket permissions: %w", err)
+	}
+	return ln, nil
+}
+
+// removeStaleSocket removes the socket file at path if nothing listens on it.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if info.Mode()&fs.ModeSocket == 0 {
+		return fmt.Errorf("zerohttp: unix socket path %q exists and is not a socket", path)
+	}
+
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("zerohttp: unix socket %q is already in use", path)
+	}
+
+	return os.Remove(path)
+}
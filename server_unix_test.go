@@ -0,0 +1,137 @@
+package zerohttp
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+// unixClient returns an HTTP client that connects to the socket at path.
+func unixClient(path string) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		},
+	}
+}
+
+// waitForSocket waits until the socket file at path exists.
+func waitForSocket(t *testing.T, path string) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(path); err == nil {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	zhtest.AssertFail(t, "timeout waiting for unix socket")
+}
+
+func TestServer_UnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+
+	server := New(Config{UnixSocket: path})
+	server.GET("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("over unix"))
+	}))
+
+	zhtest.AssertEqual(t, path, server.ListenerAddr())
+
+	done := make(chan error, 1)
+	go func() { done <- server.ListenAndServe() }()
+	waitForSocket(t, path)
+
+	zhtest.AssertEqual(t, path, server.ListenerAddr())
+
+	info, err := os.Stat(path)
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertTrue(t, info.Mode()&fs.ModeSocket != 0)
+	zhtest.AssertEqual(t, fs.FileMode(0o660), info.Mode().Perm())
+
+	resp, err := unixClient(path).Get("http://unix/")
+	zhtest.AssertNoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	zhtest.AssertEqual(t, http.StatusOK, resp.StatusCode)
+	zhtest.AssertEqual(t, "over unix", string(body))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	zhtest.AssertNoError(t, server.Shutdown(ctx))
+	zhtest.AssertErrorIs(t, <-done, http.ErrServerClosed)
+
+	_, err = os.Stat(path)
+	zhtest.AssertErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestServer_UnixSocket_Start(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+
+	server := New(Config{UnixSocket: path, UnixSocketMode: 0o600})
+
+	done := make(chan error, 1)
+	go func() { done <- server.Start() }()
+	waitForSocket(t, path)
+
+	info, err := os.Stat(path)
+	zhtest.AssertNoError(t, err)
+	zhtest.AssertEqual(t, fs.FileMode(0o600), info.Mode().Perm())
+
+	zhtest.AssertNoError(t, server.Close())
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		zhtest.AssertFail(t, "timeout waiting for Start() to return after close")
+	}
+
+	_, err = os.Stat(path)
+	zhtest.AssertErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestServer_UnixSocket_ExistingFile(t *testing.T) {
+	t.Run("stale socket is removed", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.sock")
+		ln, err := net.Listen("unix", path)
+		zhtest.AssertNoError(t, err)
+		ln.(*net.UnixListener).SetUnlinkOnClose(false)
+		zhtest.AssertNoError(t, ln.Close())
+
+		ln, err = listenUnix(path, 0o660)
+		zhtest.AssertNoError(t, err)
+		zhtest.AssertNoError(t, ln.Close())
+	})
+
+	t.Run("socket in use is kept", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.sock")
+		ln, err := net.Listen("unix", path)
+		zhtest.AssertNoError(t, err)
+		defer func() { _ = ln.Close() }()
+
+		_, err = listenUnix(path, 0o660)
+		zhtest.AssertError(t, err)
+	})
+
+	t.Run("regular file is kept", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.sock")
+		zhtest.AssertNoError(t, os.WriteFile(path, []byte("data"), 0o600))
+
+		server := New(Config{UnixSocket: path})
+		zhtest.AssertError(t, server.ListenAndServe())
+
+		data, err := os.ReadFile(path)
+		zhtest.AssertNoError(t, err)
+		zhtest.AssertEqual(t, "data", string(data))
+	})
+}
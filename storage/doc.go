@@ -1,8 +1,8 @@
 // Package storage provides a shared storage interface for middleware backends.
 //
 // This package defines the Storage interface that users implement to provide
-// custom storage backends (Redis, PostgreSQL, etc.) for middlewares like cache
-// and idempotency.
+// custom storage backends (Redis, PostgreSQL, etc.) for middlewares like cache,
+// idempotency, session and rate limiting.
 //
 // Example user implementation:
 //
@@ -11,6 +11,10 @@
 //	func (s *MyRedisStorage) Set(ctx context.Context, key string, val []byte, ttl time.Duration) error { ... }
 //	func (s *MyRedisStorage) Delete(ctx context.Context, key string) error { ... }
 //
+// Some middlewares need more than plain key-value access. Backends can opt in
+// by also implementing [Locker] (idempotency, token bucket rate limiting) or
+// [Counter] (fixed and sliding window rate limiting).
+//
 // Middlewares provide adapter types that wrap storage.Storage and handle
 // serialization of their specific Record types.
 package storage
@@ -10,6 +10,10 @@ import (
 // storage backend does not implement storage.Locker.
 var ErrLockNotSupported = errors.New("storage: locking not supported by backend")
 
+// ErrCounterNotSupported is returned by NewStorageAdapter when the provided
+// storage backend does not implement storage.Counter.
+var ErrCounterNotSupported = errors.New("storage: counters not supported by backend")
+
 // Storage is the low-level key-value interface for storage backends.
 // Users implement this interface to provide their own storage (Redis, PostgreSQL, etc.)
 type Storage interface {
@@ -47,6 +51,17 @@ type Locker interface {
 	Unlock(ctx context.Context, key string) error
 }
 
+// Counter is an optional interface for Storage implementations that support
+// atomic counters (required by the rate limit fixed and sliding window algorithms).
+type Counter interface {
+	// Incr atomically increments the integer value stored at key by one and
+	// returns the new value. A missing key is created with a value of 1 and
+	// the given TTL; later increments don't extend the TTL.
+	// Get on a counter key must return the value as a decimal string
+	// (e.g., Redis INCR semantics).
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+}
+
 // Inspector is an optional interface for Storage implementations that support
 // TTL introspection. Not used by current middleware adapters, but available
 // for custom Store implementations that need stale-while-revalidate behavior.
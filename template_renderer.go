@@ -11,14 +11,40 @@
 //	app.GET("/", func(w http.ResponseWriter, r *http.Request) error {
 //	    return tmpl.Render(w, http.StatusOK, "index.html", zh.M{"title": "Home"})
 //	})
+//
+// Helper functions are registered with TemplateConfig.Funcs, since
+// templates using them can't be parsed without them:
+//
+//	tmpl := zh.NewTemplateManager(templatesFS, "templates/*.html", zh.TemplateConfig{
+//	    Funcs: template.FuncMap{"upper": strings.ToUpper},
+//	})
+//
+// Pages can share a layout that renders the page in its "body" block:
+//
+//	<!-- templates/base.html -->
+//	<html><body>{{block "body" .}}{{end}}</body></html>
+//
+//	<!-- templates/index.html -->
+//	<h1>{{.title}}</h1>
+//
+//	tmpl := zh.NewTemplateManager(templatesFS, "templates/*.html", zh.TemplateConfig{
+//	    Layout: "base.html",
+//	})
+//	tmpl.Render(w, http.StatusOK, "index.html", data) // index.html inside base.html
 package zerohttp
 
 import (
 	"embed"
+	"fmt"
 	"html/template"
 	"net/http"
+	"sync"
 )
 
+// LayoutBodyBlock is the name of the block in a layout template that
+// content templates are rendered into.
+const LayoutBodyBlock = "body"
+
 // TemplateRenderer defines the interface for rendering HTML templates
 type TemplateRenderer interface {
 	Render(w http.ResponseWriter, code int, name string, data any) error
@@ -30,15 +56,123 @@ var _ TemplateRenderer = (*TemplateManager)(nil)
 // TemplateManager implements TemplateRenderer using html/template
 type TemplateManager struct {
 	templates *template.Template
+
+	// base is an unexecuted copy of the templates that layouts are cloned
+	// from, since html/template can't clone templates once executed
+	base   *template.Template
+	layout string
+
+	mu      sync.RWMutex
+	layouts map[[2]string]*template.Template
+}
+
+// TemplateConfig configures a [TemplateManager].
+type TemplateConfig struct {
+	// Funcs are the functions available to all templates. They are
+	// installed before the templates are parsed, as html/template requires.
+	// Default: nil
+	Funcs template.FuncMap
+
+	// Layout is the name of the template that Render renders templates
+	// into, in its "body" block, following the common "base.html +
+	// page.html" pattern. Templates are named after their file names.
+	// Default: "" (templates are rendered as is)
+	Layout string
+}
+
+// NewTemplateManager creates a new TemplateManager with parsed templates from
+// the embedded filesystem and the optional configuration. Panics if the
+// templates can't be parsed or if no template is named after the layout.
+func NewTemplateManager(templatesFS embed.FS, pattern string, cfg ...TemplateConfig) *TemplateManager {
+	var c TemplateConfig
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	tmpl := template.Must(template.New("").Funcs(c.Funcs).ParseFS(templatesFS, pattern))
+	if c.Layout != "" && tmpl.Lookup(c.Layout) == nil {
+		panic(fmt.Sprintf("zerohttp: layout template %q not found", c.Layout))
+	}
+	return &TemplateManager{
+		templates: tmpl,
+		base:      template.Must(tmpl.Clone()),
+		layout:    c.Layout,
+		layouts:   make(map[[2]string]*template.Template),
+	}
+}
+
+// NewTemplateManagerWithFuncs creates a TemplateManager with the functions in
+// funcs available to all templates. It is a shorthand for NewTemplateManager
+// with TemplateConfig.Funcs.
+func NewTemplateManagerWithFuncs(templatesFS embed.FS, pattern string, funcs template.FuncMap) *TemplateManager {
+	return NewTemplateManager(templatesFS, pattern, TemplateConfig{Funcs: funcs})
 }
 
-// NewTemplateManager creates a new TemplateManager with parsed templates from the embedded filesystem
-func NewTemplateManager(templatesFS embed.FS, pattern string) TemplateRenderer {
-	tmpl := template.Must(template.ParseFS(templatesFS, pattern))
-	return &TemplateManager{templates: tmpl}
+// NewTemplateManagerWithLayout creates a TemplateManager whose Render renders
+// templates into the "body" block of the layout template. It is a shorthand
+// for NewTemplateManager with TemplateConfig.Layout.
+func NewTemplateManagerWithLayout(templatesFS embed.FS, pattern, layout string) *TemplateManager {
+	return NewTemplateManager(templatesFS, pattern, TemplateConfig{Layout: layout})
 }
 
-// Render renders the specified template with the given data and status code
+// Render renders the specified template with the given data and status code.
+// If the manager was created with a layout, the template is rendered inside it.
 func (tm *TemplateManager) Render(w http.ResponseWriter, code int, name string, data any) error {
+	if tm.layout != "" {
+		return tm.RenderWithLayout(w, code, tm.layout, name, data)
+	}
 	return R.Template(w, code, tm.templates, name, data)
 }
+
+// RenderWithLayout renders the layout template with the content template as
+// its "body" block, with the given data and status code. The layout renders
+// the content with {{block "body" .}}{{end}} or {{template "body" .}}.
+//
+// The combined template is built on first use and cached.
+func (tm *TemplateManager) RenderWithLayout(w http.ResponseWriter, code int, layout, content string, data any) error {
+	tmpl, err := tm.layoutTemplate(layout, content)
+	if err != nil {
+		return err
+	}
+	return R.Template(w, code, tmpl, layout, data)
+}
+
+// layoutTemplate returns the templates with the body block of layout
+// defined as the content template.
+func (tm *TemplateManager) layoutTemplate(layout, content string) (*template.Template, error) {
+	key := [2]string{layout, content}
+
+	tm.mu.RLock()
+	tmpl, ok := tm.layouts[key]
+	tm.mu.RUnlock()
+	if ok {
+		return tmpl, nil
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if tmpl, ok := tm.layouts[key]; ok {
+		return tmpl, nil
+	}
+
+	if tm.base.Lookup(layout) == nil {
+		return nil, fmt.Errorf("zerohttp: layout template %q not found", layout)
+	}
+	page := tm.base.Lookup(content)
+	if page == nil {
+		return nil, fmt.Errorf("zerohttp: template %q not found", content)
+	}
+
+	tmpl, err := tm.base.Clone()
+	if err != nil {
+		return nil, err
+	}
+	// Copy the tree, since escaping the combined template modifies it
+	if _, err := tmpl.AddParseTree(LayoutBodyBlock, page.Tree.Copy()); err != nil {
+		return nil, err
+	}
+
+	tm.layouts[key] = tmpl
+	return tmpl, nil
+}
@@ -2,9 +2,12 @@ package zerohttp
 
 import (
 	"embed"
+	"html/template"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/alexferl/zerohttp/httpx"
 	"github.com/alexferl/zerohttp/zhtest"
@@ -50,3 +53,128 @@ func TestTemplateManager_Render(t *testing.T) {
 		zhtest.AssertWith(t, w).Status(http.StatusCreated)
 	})
 }
+
+//go:embed testdata/funcs/*.html
+var testFuncTemplates embed.FS
+
+func TestNewTemplateManager_Funcs(t *testing.T) {
+	funcs := template.FuncMap{
+		"upper": strings.ToUpper,
+		"formatDate": func(t time.Time) string {
+			return t.Format("2006-01-02")
+		},
+	}
+	tm := NewTemplateManager(testFuncTemplates, "testdata/funcs/*.html", TemplateConfig{Funcs: funcs})
+
+	w := httptest.NewRecorder()
+	data := map[string]any{"Name": "ada", "Joined": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	zhtest.AssertNoError(t, tm.Render(w, http.StatusOK, "page.html", data))
+
+	zhtest.AssertWith(t, w).
+		Status(http.StatusOK).
+		BodyContains("<p>ADA joined 2024-03-01</p>")
+
+	// The shorthand installs the same functions
+	w = httptest.NewRecorder()
+	tm = NewTemplateManagerWithFuncs(testFuncTemplates, "testdata/funcs/*.html", funcs)
+	zhtest.AssertNoError(t, tm.Render(w, http.StatusOK, "page.html", data))
+	zhtest.AssertWith(t, w).BodyContains("<p>ADA joined 2024-03-01</p>")
+
+	// Templates using undefined functions fail to parse
+	zhtest.AssertPanic(t, func() {
+		NewTemplateManager(testFuncTemplates, "testdata/funcs/*.html")
+	})
+}
+
+//go:embed testdata/layouts/*.html
+var testLayouts embed.FS
+
+func TestNewTemplateManager_Layout(t *testing.T) {
+	tm := NewTemplateManager(testLayouts, "testdata/layouts/*.html", TemplateConfig{Layout: "base.html"})
+	zhtest.AssertNotNil(t, tm)
+
+	zhtest.AssertPanic(t, func() {
+		NewTemplateManager(testLayouts, "testdata/layouts/*.html", TemplateConfig{Layout: "missing.html"})
+	})
+
+	t.Run("shorthand", func(t *testing.T) {
+		tm := NewTemplateManagerWithLayout(testLayouts, "testdata/layouts/*.html", "base.html")
+
+		w := httptest.NewRecorder()
+		zhtest.AssertNoError(t, tm.Render(w, http.StatusOK, "index.html", map[string]string{"Title": "Home", "Name": "Ada"}))
+		zhtest.AssertWith(t, w).BodyContains("<main><h1>Welcome Ada</h1>")
+	})
+
+	t.Run("with funcs", func(t *testing.T) {
+		tm := NewTemplateManager(testLayouts, "testdata/layouts/*.html", TemplateConfig{
+			Funcs:  template.FuncMap{"upper": strings.ToUpper},
+			Layout: "base.html",
+		})
+
+		w := httptest.NewRecorder()
+		zhtest.AssertNoError(t, tm.Render(w, http.StatusOK, "index.html", map[string]string{"Title": "Home", "Name": "Ada"}))
+		zhtest.AssertWith(t, w).BodyContains("<main><h1>Welcome Ada</h1>")
+	})
+}
+
+func TestTemplateManager_RenderWithLayout(t *testing.T) {
+	tm := NewTemplateManager(testLayouts, "testdata/layouts/*.html", TemplateConfig{Layout: "base.html"})
+	data := map[string]string{"Title": "Home", "Name": "<Ada>"}
+
+	t.Run("render uses the default layout", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		err := tm.Render(w, http.StatusOK, "index.html", data)
+		zhtest.AssertNoError(t, err)
+
+		zhtest.AssertWith(t, w).
+			Status(http.StatusOK).
+			Header(httpx.HeaderContentType, httpx.MIMETextHTMLCharset).
+			BodyContains("<title>Home</title>").
+			BodyContains("<main><h1>Welcome &lt;Ada&gt;</h1>").
+			BodyContains("</main>")
+	})
+
+	t.Run("renders each page in the layout", func(t *testing.T) {
+		for range 2 {
+			for _, page := range []string{"index.html", "about.html"} {
+				w := httptest.NewRecorder()
+				err := tm.RenderWithLayout(w, http.StatusOK, "base.html", page, data)
+				zhtest.AssertNoError(t, err)
+				zhtest.AssertWith(t, w).BodyContains("<title>Home</title>")
+			}
+		}
+
+		w := httptest.NewRecorder()
+		zhtest.AssertNoError(t, tm.RenderWithLayout(w, http.StatusOK, "base.html", "about.html", data))
+		zhtest.AssertWith(t, w).BodyContains("<main><p>About &lt;Ada&gt;</p>")
+	})
+
+	t.Run("renders with another layout", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		err := tm.RenderWithLayout(w, http.StatusCreated, "admin.html", "about.html", data)
+		zhtest.AssertNoError(t, err)
+
+		zhtest.AssertWith(t, w).
+			Status(http.StatusCreated).
+			BodyContains(`<div class="admin"><p>About &lt;Ada&gt;</p>`)
+	})
+
+	t.Run("returns error for missing templates", func(t *testing.T) {
+		zhtest.AssertError(t, tm.RenderWithLayout(httptest.NewRecorder(), http.StatusOK, "base.html", "missing.html", nil))
+		zhtest.AssertError(t, tm.RenderWithLayout(httptest.NewRecorder(), http.StatusOK, "missing.html", "index.html", nil))
+	})
+
+	t.Run("without a default layout renders templates as is", func(t *testing.T) {
+		tm := NewTemplateManager(testLayouts, "testdata/layouts/*.html")
+
+		w := httptest.NewRecorder()
+		zhtest.AssertNoError(t, tm.Render(w, http.StatusOK, "base.html", data))
+		zhtest.AssertWith(t, w).BodyContains("<main>default body</main>")
+
+		w = httptest.NewRecorder()
+		zhtest.AssertNoError(t, tm.RenderWithLayout(w, http.StatusOK, "base.html", "index.html", data))
+		zhtest.AssertWith(t, w).BodyContains("<main><h1>Welcome &lt;Ada&gt;</h1>")
+	})
+}
@@ -0,0 +1,53 @@
+package zerohttp
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+)
+
+// SaveUploadedFile copies an uploaded file to dst, creating or truncating
+// it. If the copy fails, the partially written file is removed, so dst
+// either holds the whole upload or doesn't exist. The directory of dst must
+// exist. fh.Filename comes from the client: don't use it in dst without
+// sanitizing it, e.g. with [filepath.Base].
+//
+// Example:
+//
+//	files, _, err := zh.B.MultipartFiles(r, 32<<20)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, fh := range files {
+//	    dst := filepath.Join(uploadDir, uuid.NewString()+filepath.Ext(fh.Filename))
+//	    if err := zh.SaveUploadedFile(fh, dst); err != nil {
+//	        return err
+//	    }
+//	}
+func SaveUploadedFile(fh *multipart.FileHeader, dst string) (err error) {
+	src, err := fh.Open()
+	if err != nil {
+		return fmt.Errorf("open uploaded file %s: %w", fh.Filename, err)
+	}
+	defer func() { _ = src.Close() }()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", dst, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(dst)
+		}
+	}()
+
+	if _, err = io.Copy(out, src); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("save uploaded file %s: %w", fh.Filename, err)
+	}
+	if err = out.Close(); err != nil {
+		return fmt.Errorf("save uploaded file %s: %w", fh.Filename, err)
+	}
+	return nil
+}
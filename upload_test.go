@@ -0,0 +1,99 @@
+package zerohttp
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexferl/zerohttp/httpx"
+	"github.com/alexferl/zerohttp/zhtest"
+)
+
+func newUploadedFile(t *testing.T, name, content string) *multipart.FileHeader {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	fileWriter, _ := writer.CreateFormFile("file", name)
+	_, _ = fileWriter.Write([]byte(content))
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set(httpx.HeaderContentType, writer.FormDataContentType())
+	zhtest.AssertNoError(t, req.ParseMultipartForm(32<<20))
+	t.Cleanup(func() { _ = req.MultipartForm.RemoveAll() })
+
+	return req.MultipartForm.File["file"][0]
+}
+
+func TestSaveUploadedFile(t *testing.T) {
+	t.Run("saves the file", func(t *testing.T) {
+		fh := newUploadedFile(t, "report.csv", "id,name\n1,alice\n")
+		dst := filepath.Join(t.TempDir(), "saved.csv")
+
+		zhtest.AssertNoError(t, SaveUploadedFile(fh, dst))
+
+		data, err := os.ReadFile(dst)
+		zhtest.AssertNoError(t, err)
+		zhtest.AssertEqual(t, "id,name\n1,alice\n", string(data))
+	})
+
+	t.Run("truncates an existing file", func(t *testing.T) {
+		dst := filepath.Join(t.TempDir(), "saved.txt")
+		zhtest.AssertNoError(t, os.WriteFile(dst, []byte("a much longer previous content"), 0o644))
+
+		zhtest.AssertNoError(t, SaveUploadedFile(newUploadedFile(t, "new.txt", "new"), dst))
+
+		data, err := os.ReadFile(dst)
+		zhtest.AssertNoError(t, err)
+		zhtest.AssertEqual(t, "new", string(data))
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		dst := filepath.Join(t.TempDir(), "missing", "saved.txt")
+
+		err := SaveUploadedFile(newUploadedFile(t, "file.txt", "content"), dst)
+		zhtest.AssertError(t, err)
+
+		_, statErr := os.Stat(dst)
+		zhtest.AssertTrue(t, os.IsNotExist(statErr))
+	})
+
+	t.Run("destination is a directory", func(t *testing.T) {
+		dst := t.TempDir()
+
+		err := SaveUploadedFile(newUploadedFile(t, "file.txt", "content"), dst)
+		zhtest.AssertError(t, err)
+
+		// The existing directory is left alone
+		stat, statErr := os.Stat(dst)
+		zhtest.AssertNoError(t, statErr)
+		zhtest.AssertTrue(t, stat.IsDir())
+	})
+
+	t.Run("upload no longer available", func(t *testing.T) {
+		// Stored on disk with maxMemory 0, then removed
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		fileWriter, _ := writer.CreateFormFile("file", "big.bin")
+		_, _ = fileWriter.Write(bytes.Repeat([]byte("x"), 1024))
+		_ = writer.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/", &body)
+		req.Header.Set(httpx.HeaderContentType, writer.FormDataContentType())
+		zhtest.AssertNoError(t, req.ParseMultipartForm(0))
+		fh := req.MultipartForm.File["file"][0]
+		zhtest.AssertNoError(t, req.MultipartForm.RemoveAll())
+
+		dst := filepath.Join(t.TempDir(), "saved.bin")
+		err := SaveUploadedFile(fh, dst)
+		zhtest.AssertError(t, err)
+
+		_, statErr := os.Stat(dst)
+		zhtest.AssertTrue(t, os.IsNotExist(statErr))
+	})
+}
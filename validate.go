@@ -85,7 +85,10 @@ func BindAndValidate(r *http.Request, dst any) error {
 	}
 
 	if bindErr != nil {
-		// Wrap as binding error (400)
+		// Wrap as binding error (400), unless Bind.JSON already did
+		if IsBindError(bindErr) {
+			return bindErr
+		}
 		return &validator.BindError{Err: bindErr}
 	}
 